@@ -0,0 +1,468 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	authHelper "github.com/IsuruHaupe/web-api/auth"
+	auth "github.com/IsuruHaupe/web-api/auth/token"
+	db "github.com/IsuruHaupe/web-api/db/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+// Actions written to the audit log by the admin routes.
+const (
+	auditActionUnlockUser     = "user.unlock"
+	auditActionDisableUser    = "user.disable"
+	auditActionEnableUser     = "user.enable"
+	auditActionRevokeSessions = "user.revoke_sessions"
+	auditActionResetPassword  = "user.reset_password"
+)
+
+var errAccountDisabled = errors.New("account has been disabled")
+
+// This function returns the audit record of an action of the logged in admin on an user.
+func newAuditLog(ctx *gin.Context, action string, target string, details string) db.CreateAuditLogParams {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Payload)
+	return db.CreateAuditLogParams{
+		Actor:   authPayload.Username,
+		Action:  action,
+		Target:  target,
+		Details: details,
+	}
+}
+
+// This is the expected returned response when admins get users, along with the number of contacts and skills they own.
+type adminUserResponse struct {
+	Username         string     `json:"username"`
+	Fullname         string     `json:"fullname"`
+	Email            string     `json:"email"`
+	Role             string     `json:"role"`
+	EmailVerified    bool       `json:"email_verified"`
+	TwoFactorEnabled bool       `json:"two_factor_enabled"`
+	Disabled         bool       `json:"disabled"`
+	DisabledAt       *time.Time `json:"disabled_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+	ContactCount     int64      `json:"contact_count"`
+	SkillCount       int64      `json:"skill_count"`
+}
+
+func newAdminUserResponse(user db.User, counts db.GetUserCountsRow) adminUserResponse {
+	rsp := adminUserResponse{
+		Username:         user.Username,
+		Fullname:         user.Fullname,
+		Email:            user.Email,
+		Role:             user.Role,
+		EmailVerified:    user.EmailVerified,
+		TwoFactorEnabled: user.TotpEnabled,
+		Disabled:         user.DisabledAt.Valid,
+		CreatedAt:        user.CreateAt,
+		ContactCount:     counts.ContactCount,
+		SkillCount:       counts.SkillCount,
+	}
+	if user.DisabledAt.Valid {
+		rsp.DisabledAt = &user.DisabledAt.Time
+	}
+	return rsp
+}
+
+// Request holder for listing users request.
+type listUsersRequest struct {
+	PageID   int32  `form:"page_id" binding:"required,min=1"`
+	PageSize int32  `form:"page_size" binding:"required,min=1,max=100"`
+	Search   string `form:"search"`
+}
+
+// listUsers godoc
+// @Security bearerAuth
+// @Summary List users
+// @Description This function is used by admins to list users, optionally searching their username, full name or email.
+// @Tags admin
+// @Accept application/x-www-form-urlencoded
+// @Produce json
+// @Param page_id query int true "page_id"
+// @Param page_size query int true "page_size"
+// @Param search query string false "search"
+// @Success 200 {array} api.adminUserResponse
+// @Router /admin/users [get]
+func (server *Server) listUsers(ctx *gin.Context) {
+	var req listUsersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	users, err := server.database.ListUsers(ctx, db.ListUsersParams{
+		Search:      req.Search,
+		LimitCount:  req.PageSize,
+		OffsetCount: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := make([]adminUserResponse, 0, len(users))
+	for _, user := range users {
+		rsp := adminUserResponse{
+			Username:         user.Username,
+			Fullname:         user.Fullname,
+			Email:            user.Email,
+			Role:             user.Role,
+			EmailVerified:    user.EmailVerified,
+			TwoFactorEnabled: user.TotpEnabled,
+			Disabled:         user.DisabledAt.Valid,
+			CreatedAt:        user.CreateAt,
+			ContactCount:     user.ContactCount,
+			SkillCount:       user.SkillCount,
+		}
+		if user.DisabledAt.Valid {
+			rsp.DisabledAt = &user.DisabledAt.Time
+		}
+		response = append(response, rsp)
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+// Request holder of the admin routes acting on an user.
+type adminUserRequest struct {
+	Username string `uri:"username" binding:"required,alphanum"`
+}
+
+// This function will bind the username of the admin routes and get its user, it responds to the request on error.
+func (server *Server) getAdminTarget(ctx *gin.Context) (db.User, bool) {
+	var req adminUserRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.User{}, false
+	}
+
+	user, err := server.database.GetUser(ctx, req.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return db.User{}, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return db.User{}, false
+	}
+
+	return user, true
+}
+
+// getUser godoc
+// @Security bearerAuth
+// @Summary Get an user
+// @Description This function is used by admins to get an user along with the number of contacts and skills it owns.
+// @Tags admin
+// @Accept application/x-www-form-urlencoded
+// @Produce json
+// @Param username path string true "username"
+// @Success 200 {object} api.adminUserResponse
+// @Router /admin/users/{username} [get]
+func (server *Server) getUser(ctx *gin.Context) {
+	user, ok := server.getAdminTarget(ctx)
+	if !ok {
+		return
+	}
+
+	counts, err := server.database.GetUserCounts(ctx, user.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newAdminUserResponse(user, counts))
+}
+
+// This is the expected request to unlock an user. IP is the client IP the user logs in from, its failed logins are
+// kept otherwise since they are shared with the other users of the IP.
+type unlockUserRequest struct {
+	IP string `form:"ip" binding:"omitempty,ip"`
+}
+
+// unlockUser godoc
+// @Security bearerAuth
+// @Summary Unlock the login of an user
+// @Description This function is used by admins to forget the failed logins of an user so that they can login again right away.
+// @Description Only the failures of the username are forgotten, those of the client IP the user logs in from are forgotten
+// @Description as well when it is given.
+// @Tags admin
+// @Accept application/x-www-form-urlencoded
+// @Produce json
+// @Param username path string true "username"
+// @Param ip query string false "ip"
+// @Success 200 {string} string "Successfully unlocked user."
+// @Router /admin/users/{username}/unlock [post]
+func (server *Server) unlockUser(ctx *gin.Context) {
+	var req unlockUserRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, ok := server.getAdminTarget(ctx)
+	if !ok {
+		return
+	}
+
+	var details string
+	if req.IP != "" {
+		details = fmt.Sprintf("ip %s", req.IP)
+	}
+	// The login attempts are not kept in the database of the audit log, so they can't be reset in its transaction.
+	// The audit record is written first, an user is never unlocked without it.
+	_, err := server.database.CreateAuditLog(ctx, newAuditLog(ctx, auditActionUnlockUser, user.Username, details))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	err = server.resetLoginFailures(ctx, user.Username)
+	if err == nil && req.IP != "" {
+		err = server.resetIPLoginFailures(ctx, req.IP)
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, "Successfully unlocked user.")
+}
+
+// disableUser godoc
+// @Security bearerAuth
+// @Summary Disable an user
+// @Description This function is used by admins to disable an account, every session of the user is revoked and it can not login anymore.
+// @Description Its API keys stop working as well. Admins can not disable their own account.
+// @Tags admin
+// @Accept application/x-www-form-urlencoded
+// @Produce json
+// @Param username path string true "username"
+// @Success 200 {object} api.adminUserResponse
+// @Router /admin/users/{username}/disable [post]
+func (server *Server) disableUser(ctx *gin.Context) {
+	user, ok := server.getAdminTarget(ctx)
+	if !ok {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Payload)
+	if user.Username == authPayload.Username {
+		err := errors.New("admins can not disable their own account")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	_, err := server.database.AuditTx(ctx, newAuditLog(ctx, auditActionDisableUser, user.Username, ""), func(q db.Querier) error {
+		var err error
+		user, err = q.DisableUser(ctx, user.Username)
+		if err != nil {
+			return err
+		}
+		return q.BlockUserSessions(ctx, user.Username)
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err := errors.New("user is already disabled")
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	server.respondAdminUser(ctx, user)
+}
+
+// enableUser godoc
+// @Security bearerAuth
+// @Summary Enable an user
+// @Description This function is used by admins to enable a disabled account again, the user has to login again.
+// @Tags admin
+// @Accept application/x-www-form-urlencoded
+// @Produce json
+// @Param username path string true "username"
+// @Success 200 {object} api.adminUserResponse
+// @Router /admin/users/{username}/enable [post]
+func (server *Server) enableUser(ctx *gin.Context) {
+	user, ok := server.getAdminTarget(ctx)
+	if !ok {
+		return
+	}
+
+	_, err := server.database.AuditTx(ctx, newAuditLog(ctx, auditActionEnableUser, user.Username, ""), func(q db.Querier) error {
+		var err error
+		user, err = q.EnableUser(ctx, user.Username)
+		return err
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err := errors.New("user is not disabled")
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	server.respondAdminUser(ctx, user)
+}
+
+// This function responds with the user along with the number of contacts and skills it owns.
+func (server *Server) respondAdminUser(ctx *gin.Context, user db.User) {
+	counts, err := server.database.GetUserCounts(ctx, user.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newAdminUserResponse(user, counts))
+}
+
+// revokeUserSessions godoc
+// @Security bearerAuth
+// @Summary Revoke the sessions of an user
+// @Description This function is used by admins to revoke every session of an user along with the access tokens issued from them.
+// @Tags admin
+// @Accept application/x-www-form-urlencoded
+// @Produce json
+// @Param username path string true "username"
+// @Success 200 {string} string "Successfully revoked sessions."
+// @Router /admin/users/{username}/sessions/revoke [post]
+func (server *Server) revokeUserSessions(ctx *gin.Context) {
+	user, ok := server.getAdminTarget(ctx)
+	if !ok {
+		return
+	}
+
+	_, err := server.database.AuditTx(ctx, newAuditLog(ctx, auditActionRevokeSessions, user.Username, ""), func(q db.Querier) error {
+		return q.BlockUserSessions(ctx, user.Username)
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, "Successfully revoked sessions.")
+}
+
+// resetUserPassword godoc
+// @Security bearerAuth
+// @Summary Reset the password of an user
+// @Description This function is used by admins to reset the password of an user. The current password stops working,
+// @Description every session is revoked and a password reset token is sent to the email of the user to choose a new one.
+// @Tags admin
+// @Accept application/x-www-form-urlencoded
+// @Produce json
+// @Param username path string true "username"
+// @Success 200 {string} string "Successfully reset password."
+// @Router /admin/users/{username}/password/reset [post]
+func (server *Server) resetUserPassword(ctx *gin.Context) {
+	user, ok := server.getAdminTarget(ctx)
+	if !ok {
+		return
+	}
+
+	// The password is replaced by a random one nobody knows.
+	unusablePassword, err := authHelper.GenerateSecret(passwordResetTokenSize)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	hashedPassword, err := server.passwordHasher.Hash(unusablePassword)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	resetToken, err := authHelper.GenerateSecret(passwordResetTokenSize)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	var passwordReset db.PasswordReset
+	_, err = server.database.AuditTx(ctx, newAuditLog(ctx, auditActionResetPassword, user.Username, ""), func(q db.Querier) error {
+		_, err := q.UpdateUserPassword(ctx, db.UpdateUserPasswordParams{
+			Username:       user.Username,
+			HashedPassword: hashedPassword,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = q.BlockUserSessions(ctx, user.Username)
+		if err != nil {
+			return err
+		}
+
+		err = q.InvalidatePasswordResets(ctx, user.Username)
+		if err != nil {
+			return err
+		}
+
+		passwordReset, err = q.CreatePasswordReset(ctx, db.CreatePasswordResetParams{
+			HashedToken: authHelper.HashSecret(resetToken),
+			Username:    user.Username,
+			ExpiresAt:   time.Now().Add(server.config.PasswordResetDuration),
+		})
+		return err
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	err = server.sendPasswordResetEmail(user, resetToken, passwordReset)
+	if err != nil {
+		err := fmt.Errorf("password has been reset but the email could not be sent, the user can request a new token : %w", err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, "Successfully reset password.")
+}
+
+// Request holder for listing audit logs request.
+type listAuditLogsRequest struct {
+	PageID   int32  `form:"page_id" binding:"required,min=1"`
+	PageSize int32  `form:"page_size" binding:"required,min=1,max=100"`
+	Target   string `form:"target"`
+}
+
+// listAuditLogs godoc
+// @Security bearerAuth
+// @Summary List audit logs
+// @Description This function is used by admins to list the actions made through the admin routes, most recent first.
+// @Description The list can be restricted to the actions on an user.
+// @Tags admin
+// @Accept application/x-www-form-urlencoded
+// @Produce json
+// @Param page_id query int true "page_id"
+// @Param page_size query int true "page_size"
+// @Param target query string false "target"
+// @Success 200 {array} db.AuditLog
+// @Router /admin/audit-logs [get]
+func (server *Server) listAuditLogs(ctx *gin.Context) {
+	var req listAuditLogsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	auditLogs, err := server.database.ListAuditLogs(ctx, db.ListAuditLogsParams{
+		Target:      req.Target,
+		LimitCount:  req.PageSize,
+		OffsetCount: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, auditLogs)
+}
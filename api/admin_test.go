@@ -0,0 +1,810 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	authHelper "github.com/IsuruHaupe/web-api/auth"
+	auth "github.com/IsuruHaupe/web-api/auth/token"
+	mockdb "github.com/IsuruHaupe/web-api/db/mock"
+	db "github.com/IsuruHaupe/web-api/db/sqlc"
+	mockmail "github.com/IsuruHaupe/web-api/mail/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// This function expects an admin action to be audited, the action runs against the mock database as if it
+// was the transaction.
+func expectAuditTx(database *mockdb.MockDatabase, actor string, action string, target string) *gomock.Call {
+	arg := db.CreateAuditLogParams{
+		Actor:  actor,
+		Action: action,
+		Target: target,
+	}
+	return database.EXPECT().
+		AuditTx(gomock.Any(), gomock.Eq(arg), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ interface{}, arg db.CreateAuditLogParams, action func(q db.Querier) error) (db.AuditLog, error) {
+			if err := action(database); err != nil {
+				return db.AuditLog{}, err
+			}
+			return db.AuditLog{
+				ID:        1,
+				Actor:     arg.Actor,
+				Action:    arg.Action,
+				Target:    arg.Target,
+				CreatedAt: time.Now(),
+			}, nil
+		})
+}
+
+func TestUnlockUserAPI(t *testing.T) {
+	admin, _ := randomUser(t)
+	admin.Role = auth.RoleAdmin
+	user, _ := randomUser(t)
+	ip := "203.0.113.7"
+
+	testCases := []struct {
+		name          string
+		username      string
+		query         string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker auth.Maker)
+		buildStubs    func(database *mockdb.MockDatabase)
+		checkResponse func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			username: user.Username,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.Username, auth.RoleAdmin, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				database.EXPECT().
+					CreateAuditLog(gomock.Any(), gomock.Eq(db.CreateAuditLogParams{
+						Actor:  admin.Username,
+						Action: auditActionUnlockUser,
+						Target: user.Username,
+					})).
+					Times(1).
+					Return(db.AuditLog{ID: 1}, nil)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				attempts, err := server.loginAttempts.Get(context.Background(), loginAttemptUserPrefix+user.Username)
+				require.NoError(t, err)
+				require.Zero(t, attempts.Failures)
+
+				attempts, err = server.loginAttempts.Get(context.Background(), loginAttemptIPPrefix+ip)
+				require.NoError(t, err)
+				require.Equal(t, 1, attempts.Failures)
+			},
+		},
+		{
+			name:     "With IP",
+			username: user.Username,
+			query:    "?ip=" + ip,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.Username, auth.RoleAdmin, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				database.EXPECT().
+					CreateAuditLog(gomock.Any(), gomock.Eq(db.CreateAuditLogParams{
+						Actor:   admin.Username,
+						Action:  auditActionUnlockUser,
+						Target:  user.Username,
+						Details: "ip " + ip,
+					})).
+					Times(1).
+					Return(db.AuditLog{ID: 1}, nil)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				attempts, err := server.loginAttempts.Get(context.Background(), loginAttemptUserPrefix+user.Username)
+				require.NoError(t, err)
+				require.Zero(t, attempts.Failures)
+
+				attempts, err = server.loginAttempts.Get(context.Background(), loginAttemptIPPrefix+ip)
+				require.NoError(t, err)
+				require.Zero(t, attempts.Failures)
+			},
+		},
+		{
+			name:     "Invalid IP",
+			username: user.Username,
+			query:    "?ip=localhost",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.Username, auth.RoleAdmin, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+				database.EXPECT().
+					CreateAuditLog(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "Audit Failure",
+			username: user.Username,
+			query:    "?ip=" + ip,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.Username, auth.RoleAdmin, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				database.EXPECT().
+					CreateAuditLog(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AuditLog{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+
+				// Nothing is unlocked without its audit record.
+				attempts, err := server.loginAttempts.Get(context.Background(), loginAttemptUserPrefix+user.Username)
+				require.NoError(t, err)
+				require.Equal(t, 1, attempts.Failures)
+
+				attempts, err = server.loginAttempts.Get(context.Background(), loginAttemptIPPrefix+ip)
+				require.NoError(t, err)
+				require.Equal(t, 1, attempts.Failures)
+			},
+		},
+		{
+			name:     "Not Found",
+			username: "unknown",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.Username, auth.RoleAdmin, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq("unknown")).
+					Times(1).
+					Return(db.User{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "Not Admin",
+			username: user.Username,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+
+				attempts, err := server.loginAttempts.Get(context.Background(), loginAttemptUserPrefix+user.Username)
+				require.NoError(t, err)
+				require.Equal(t, 1, attempts.Failures)
+			},
+		},
+		{
+			name:      "No Authorization",
+			username:  user.Username,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		currentTest := testCases[i]
+
+		t.Run(currentTest.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			database := mockdb.NewMockDatabase(ctrl)
+			currentTest.buildStubs(database)
+
+			server := newTestServer(t, database)
+			_, err := server.loginAttempts.RecordFailure(context.Background(), loginAttemptUserPrefix+user.Username, time.Hour)
+			require.NoError(t, err)
+			_, err = server.loginAttempts.RecordFailure(context.Background(), loginAttemptIPPrefix+ip, time.Hour)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/admin/users/%s/unlock%s", currentTest.username, currentTest.query)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			currentTest.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			currentTest.checkResponse(t, server, recorder)
+		})
+	}
+}
+
+func TestListUsersAPI(t *testing.T) {
+	admin, _ := randomUser(t)
+	user, _ := randomUser(t)
+	row := db.ListUsersRow{
+		Username:     user.Username,
+		Fullname:     user.Fullname,
+		Email:        user.Email,
+		Role:         user.Role,
+		CreateAt:     user.CreateAt,
+		ContactCount: 3,
+		SkillCount:   2,
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		role          string
+		buildStubs    func(database *mockdb.MockDatabase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: "page_id=2&page_size=5&search=" + user.Username,
+			role:  auth.RoleAdmin,
+			buildStubs: func(database *mockdb.MockDatabase) {
+				arg := db.ListUsersParams{
+					Search:      user.Username,
+					LimitCount:  5,
+					OffsetCount: 5,
+				}
+				database.EXPECT().
+					ListUsers(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.ListUsersRow{row}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var users []adminUserResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &users))
+				require.Len(t, users, 1)
+				require.Equal(t, user.Username, users[0].Username)
+				require.Equal(t, int64(3), users[0].ContactCount)
+				require.Equal(t, int64(2), users[0].SkillCount)
+				require.False(t, users[0].Disabled)
+			},
+		},
+		{
+			name:  "Invalid Page Size",
+			query: "page_id=1&page_size=1000",
+			role:  auth.RoleAdmin,
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					ListUsers(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "Not Admin",
+			query: "page_id=1&page_size=5",
+			role:  auth.RoleEditor,
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					ListUsers(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:  "Internal Error",
+			query: "page_id=1&page_size=5",
+			role:  auth.RoleAdmin,
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					ListUsers(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		currentTest := testCases[i]
+
+		t.Run(currentTest.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			database := mockdb.NewMockDatabase(ctrl)
+			currentTest.buildStubs(database)
+
+			server := newTestServer(t, database)
+			recorder := httptest.NewRecorder()
+
+			url := "/admin/users?" + currentTest.query
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, admin.Username, currentTest.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			currentTest.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetUserAdminAPI(t *testing.T) {
+	admin, _ := randomUser(t)
+	user, _ := randomUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	database := mockdb.NewMockDatabase(ctrl)
+	database.EXPECT().
+		GetUser(gomock.Any(), gomock.Eq(user.Username)).
+		Times(1).
+		Return(user, nil)
+	database.EXPECT().
+		GetUserCounts(gomock.Any(), gomock.Eq(user.Username)).
+		Times(1).
+		Return(db.GetUserCountsRow{ContactCount: 4, SkillCount: 1}, nil)
+
+	server := newTestServer(t, database)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/admin/users/"+user.Username, nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, admin.Username, auth.RoleAdmin, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var gotUser adminUserResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &gotUser))
+	require.Equal(t, user.Username, gotUser.Username)
+	require.Equal(t, user.Email, gotUser.Email)
+	require.Equal(t, int64(4), gotUser.ContactCount)
+	require.Equal(t, int64(1), gotUser.SkillCount)
+}
+
+func TestDisableUserAPI(t *testing.T) {
+	admin, _ := randomUser(t)
+	user, _ := randomUser(t)
+	disabledUser := user
+	disabledUser.DisabledAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	testCases := []struct {
+		name          string
+		username      string
+		buildStubs    func(database *mockdb.MockDatabase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			username: user.Username,
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				expectAuditTx(database, admin.Username, auditActionDisableUser, user.Username)
+				database.EXPECT().
+					DisableUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(disabledUser, nil)
+				database.EXPECT().
+					BlockUserSessions(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(nil)
+				database.EXPECT().
+					GetUserCounts(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(db.GetUserCountsRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var gotUser adminUserResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &gotUser))
+				require.True(t, gotUser.Disabled)
+				require.NotNil(t, gotUser.DisabledAt)
+			},
+		},
+		{
+			name:     "Already Disabled",
+			username: user.Username,
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(disabledUser, nil)
+				expectAuditTx(database, admin.Username, auditActionDisableUser, user.Username)
+				database.EXPECT().
+					DisableUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(db.User{}, sql.ErrNoRows)
+				database.EXPECT().
+					BlockUserSessions(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:     "Own Account",
+			username: admin.Username,
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(admin.Username)).
+					Times(1).
+					Return(admin, nil)
+				database.EXPECT().
+					AuditTx(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "Not Found",
+			username: "unknown",
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq("unknown")).
+					Times(1).
+					Return(db.User{}, sql.ErrNoRows)
+				database.EXPECT().
+					AuditTx(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "Internal Error",
+			username: user.Username,
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				expectAuditTx(database, admin.Username, auditActionDisableUser, user.Username)
+				database.EXPECT().
+					DisableUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(disabledUser, nil)
+				database.EXPECT().
+					BlockUserSessions(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		currentTest := testCases[i]
+
+		t.Run(currentTest.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			database := mockdb.NewMockDatabase(ctrl)
+			currentTest.buildStubs(database)
+
+			server := newTestServer(t, database)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/admin/users/%s/disable", currentTest.username)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, admin.Username, auth.RoleAdmin, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			currentTest.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestEnableUserAPI(t *testing.T) {
+	admin, _ := randomUser(t)
+	user, _ := randomUser(t)
+	disabledUser := user
+	disabledUser.DisabledAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(database *mockdb.MockDatabase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(disabledUser, nil)
+				expectAuditTx(database, admin.Username, auditActionEnableUser, user.Username)
+				database.EXPECT().
+					EnableUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				database.EXPECT().
+					GetUserCounts(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(db.GetUserCountsRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var gotUser adminUserResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &gotUser))
+				require.False(t, gotUser.Disabled)
+				require.Nil(t, gotUser.DisabledAt)
+			},
+		},
+		{
+			name: "Not Disabled",
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				expectAuditTx(database, admin.Username, auditActionEnableUser, user.Username)
+				database.EXPECT().
+					EnableUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(db.User{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		currentTest := testCases[i]
+
+		t.Run(currentTest.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			database := mockdb.NewMockDatabase(ctrl)
+			currentTest.buildStubs(database)
+
+			server := newTestServer(t, database)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/admin/users/%s/enable", user.Username)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, admin.Username, auth.RoleAdmin, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			currentTest.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestRevokeUserSessionsAPI(t *testing.T) {
+	admin, _ := randomUser(t)
+	user, _ := randomUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	database := mockdb.NewMockDatabase(ctrl)
+	database.EXPECT().
+		GetUser(gomock.Any(), gomock.Eq(user.Username)).
+		Times(1).
+		Return(user, nil)
+	expectAuditTx(database, admin.Username, auditActionRevokeSessions, user.Username)
+	database.EXPECT().
+		BlockUserSessions(gomock.Any(), gomock.Eq(user.Username)).
+		Times(1).
+		Return(nil)
+
+	server := newTestServer(t, database)
+	recorder := httptest.NewRecorder()
+
+	url := fmt.Sprintf("/admin/users/%s/sessions/revoke", user.Username)
+	request, err := http.NewRequest(http.MethodPost, url, nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, admin.Username, auth.RoleAdmin, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestResetUserPasswordAPI(t *testing.T) {
+	admin, _ := randomUser(t)
+	user, password := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(database *mockdb.MockDatabase, mailer *mockmail.MockMailer)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(database *mockdb.MockDatabase, mailer *mockmail.MockMailer) {
+				var hashedToken string
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				expectAuditTx(database, admin.Username, auditActionResetPassword, user.Username)
+				database.EXPECT().
+					UpdateUserPassword(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.UpdateUserPasswordParams) (db.User, error) {
+						require.Equal(t, user.Username, arg.Username)
+						// The previous password does not work anymore.
+						require.Error(t, authHelper.CheckPassword(password, arg.HashedPassword))
+						return user, nil
+					})
+				database.EXPECT().
+					BlockUserSessions(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(nil)
+				database.EXPECT().
+					InvalidatePasswordResets(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(nil)
+				database.EXPECT().
+					CreatePasswordReset(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CreatePasswordResetParams) (db.PasswordReset, error) {
+						require.Equal(t, user.Username, arg.Username)
+						hashedToken = arg.HashedToken
+						return db.PasswordReset{
+							HashedToken: arg.HashedToken,
+							Username:    arg.Username,
+							ExpiresAt:   arg.ExpiresAt,
+						}, nil
+					})
+				mailer.EXPECT().
+					SendEmail(gomock.Eq(user.Email), gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(to string, subject string, content string) error {
+						found := false
+						for _, word := range strings.Fields(content) {
+							if authHelper.HashSecret(word) == hashedToken {
+								found = true
+							}
+						}
+						require.True(t, found)
+						return nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "Internal Error",
+			buildStubs: func(database *mockdb.MockDatabase, mailer *mockmail.MockMailer) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				expectAuditTx(database, admin.Username, auditActionResetPassword, user.Username)
+				database.EXPECT().
+					UpdateUserPassword(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, sql.ErrConnDone)
+				mailer.EXPECT().
+					SendEmail(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		currentTest := testCases[i]
+
+		t.Run(currentTest.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			database := mockdb.NewMockDatabase(ctrl)
+			mailer := mockmail.NewMockMailer(ctrl)
+			currentTest.buildStubs(database, mailer)
+
+			server := newTestServerWithMailer(t, database, mailer)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/admin/users/%s/password/reset", user.Username)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, admin.Username, auth.RoleAdmin, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			currentTest.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListAuditLogsAPI(t *testing.T) {
+	admin, _ := randomUser(t)
+	user, _ := randomUser(t)
+	auditLog := db.AuditLog{
+		ID:        1,
+		Actor:     admin.Username,
+		Action:    auditActionDisableUser,
+		Target:    user.Username,
+		CreatedAt: time.Now(),
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	database := mockdb.NewMockDatabase(ctrl)
+	arg := db.ListAuditLogsParams{
+		Target:      user.Username,
+		LimitCount:  10,
+		OffsetCount: 0,
+	}
+	database.EXPECT().
+		ListAuditLogs(gomock.Any(), gomock.Eq(arg)).
+		Times(1).
+		Return([]db.AuditLog{auditLog}, nil)
+
+	server := newTestServer(t, database)
+	recorder := httptest.NewRecorder()
+
+	url := "/admin/audit-logs?page_id=1&page_size=10&target=" + user.Username
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, admin.Username, auth.RoleAdmin, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var auditLogs []db.AuditLog
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &auditLogs))
+	require.Len(t, auditLogs, 1)
+	require.Equal(t, auditLog.Action, auditLogs[0].Action)
+	require.Equal(t, auditLog.Target, auditLogs[0].Target)
+}
@@ -0,0 +1,204 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	authHelper "github.com/IsuruHaupe/web-api/auth"
+	auth "github.com/IsuruHaupe/web-api/auth/token"
+	db "github.com/IsuruHaupe/web-api/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Scopes an API key can be given.
+const (
+	scopeContactsRead  = "contacts:read"
+	scopeContactsWrite = "contacts:write"
+	scopeSkillsRead    = "skills:read"
+	scopeSkillsWrite   = "skills:write"
+)
+
+// This function checks that a role can be given the scopes, only admins and editors can be given write scopes.
+func checkScopesAllowed(role string, scopes []string) error {
+	if role == auth.RoleAdmin || role == auth.RoleEditor {
+		return nil
+	}
+	for _, scope := range scopes {
+		if scope == scopeContactsWrite || scope == scopeSkillsWrite {
+			return fmt.Errorf("role %s can not be given the %s scope", role, scope)
+		}
+	}
+	return nil
+}
+
+// Request holder when receiving a create API key request.
+type createAPIKeyRequest struct {
+	Name   string   `json:"name" binding:"required"`
+	Scopes []string `json:"scopes" binding:"required,min=1,dive,oneof=contacts:read contacts:write skills:read skills:write"`
+}
+
+// This is the expected returned response when listing API keys, only the prefix of the key is exposed.
+type apiKeyResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func newAPIKeyResponse(apiKey db.ApiKey) apiKeyResponse {
+	rsp := apiKeyResponse{
+		ID:        apiKey.ID,
+		Name:      apiKey.Name,
+		Prefix:    apiKey.Prefix,
+		Scopes:    apiKey.Scopes,
+		CreatedAt: apiKey.CreatedAt,
+	}
+	if apiKey.LastUsedAt.Valid {
+		rsp.LastUsedAt = &apiKey.LastUsedAt.Time
+	}
+	return rsp
+}
+
+// This is the expected returned response on succesful creation, the key is only shown this time.
+type createAPIKeyResponse struct {
+	apiKeyResponse
+	Key string `json:"key"`
+}
+
+// createAPIKey godoc
+// @Security bearerAuth
+// @Summary Create an API key
+// @Description This function is used to create a long-lived API key for machine clients.
+// @Description The key is only returned once, it must be sent in the Authorization header as "ApiKey <key>".
+// @Description Scopes are contacts:read, contacts:write, skills:read and skills:write, viewers can only request read scopes.
+// @Tags api-key
+// @Accept json
+// @Produce json
+// @Param apiKey body api.createAPIKeyRequest true "Create API key"
+// @Success 200 {object} api.createAPIKeyResponse
+// @Router /api-keys [post]
+func (server *Server) createAPIKey(ctx *gin.Context) {
+	var req createAPIKeyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// A key can not be given more rights than its owner.
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Payload)
+	if err := checkScopesAllowed(authPayload.Role, req.Scopes); err != nil {
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	key, prefix, hashedKey, err := authHelper.GenerateAPIKey()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	id, err := uuid.NewRandom()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	apiKey, err := server.database.CreateAPIKey(ctx, db.CreateAPIKeyParams{
+		ID:        id,
+		Username:  authPayload.Username,
+		Name:      req.Name,
+		Prefix:    prefix,
+		HashedKey: hashedKey,
+		Scopes:    req.Scopes,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := createAPIKeyResponse{
+		apiKeyResponse: newAPIKeyResponse(apiKey),
+		Key:            key,
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// listAPIKeys godoc
+// @Security bearerAuth
+// @Summary List API keys
+// @Tags api-key
+// @Description This function is used to list the API keys of an user.
+// @Produce json
+// @Success 200 {array} api.apiKeyResponse
+// @Router /api-keys [get]
+func (server *Server) listAPIKeys(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Payload)
+	apiKeys, err := server.database.ListAPIKeys(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := make([]apiKeyResponse, 0, len(apiKeys))
+	for _, apiKey := range apiKeys {
+		response = append(response, newAPIKeyResponse(apiKey))
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+// Request holder for deleting API key request.
+type deleteAPIKeyRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// deleteAPIKey godoc
+// @Security bearerAuth
+// @Summary Revoke an API key
+// @Tags api-key
+// @Description This function is used to revoke an API key, it can not be used anymore.
+// @Accept application/x-www-form-urlencoded
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {string} string "Successfully deleted API key."
+// @Router /api-keys/{id} [delete]
+func (server *Server) deleteAPIKey(ctx *gin.Context) {
+	var req deleteAPIKeyRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	apiKeyID := uuid.MustParse(req.ID)
+
+	// Get the API key to check ownership before deletion.
+	apiKey, err := server.database.GetAPIKey(ctx, apiKeyID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// Check for owernership.
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Payload)
+	if apiKey.Username != authPayload.Username {
+		err := errors.New("API key doesn't belong to the user")
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	err = server.database.DeleteAPIKey(ctx, apiKeyID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, "Successfully deleted API key.")
+}
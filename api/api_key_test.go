@@ -0,0 +1,448 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	authHelper "github.com/IsuruHaupe/web-api/auth"
+	auth "github.com/IsuruHaupe/web-api/auth/token"
+	mockdb "github.com/IsuruHaupe/web-api/db/mock"
+	db "github.com/IsuruHaupe/web-api/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func addAPIKeyAuthorization(request *http.Request, key string) {
+	authorizationHeader := fmt.Sprintf("%s %s", "ApiKey", key)
+	request.Header.Set(authorizationHeaderKey, authorizationHeader)
+}
+
+func TestCreateAPIKeyAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	apiKey := randomAPIKey(user.Username)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker auth.Maker)
+		buildStubs    func(database *mockdb.MockDatabase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"name":   apiKey.Name,
+				"scopes": apiKey.Scopes,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					CreateAPIKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CreateAPIKeyParams) (db.ApiKey, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.Equal(t, apiKey.Name, arg.Name)
+						require.Equal(t, apiKey.Scopes, arg.Scopes)
+						require.NotEmpty(t, arg.HashedKey)
+
+						return db.ApiKey{
+							ID:        arg.ID,
+							Username:  arg.Username,
+							Name:      arg.Name,
+							Prefix:    arg.Prefix,
+							HashedKey: arg.HashedKey,
+							Scopes:    arg.Scopes,
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				data, err := ioutil.ReadAll(recorder.Body)
+				require.NoError(t, err)
+
+				var response createAPIKeyResponse
+				err = json.Unmarshal(data, &response)
+				require.NoError(t, err)
+				require.NotEmpty(t, response.Key)
+				require.Contains(t, response.Key, response.Prefix)
+				require.Equal(t, apiKey.Scopes, response.Scopes)
+				require.Nil(t, response.LastUsedAt)
+			},
+		},
+		{
+			name: "Viewer Write Scope",
+			body: gin.H{
+				"name":   apiKey.Name,
+				"scopes": []string{scopeContactsRead, scopeContactsWrite},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleViewer, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					CreateAPIKey(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "Invalid Scope",
+			body: gin.H{
+				"name":   apiKey.Name,
+				"scopes": []string{"users:write"},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					CreateAPIKey(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "No Scope",
+			body: gin.H{
+				"name":   apiKey.Name,
+				"scopes": []string{},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					CreateAPIKey(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "API Key Authorization",
+			body: gin.H{
+				"name":   apiKey.Name,
+				"scopes": apiKey.Scopes,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAPIKeyAuthorization(request, "wak_key")
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					UseAPIKey(gomock.Any(), gomock.Eq(authHelper.HashAPIKey("wak_key"))).
+					Times(1).
+					Return(db.UseAPIKeyRow{ID: apiKey.ID, Username: user.Username, Scopes: apiKey.Scopes, Role: auth.RoleEditor}, nil)
+				database.EXPECT().
+					CreateAPIKey(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "No Authorization",
+			body: gin.H{
+				"name":   apiKey.Name,
+				"scopes": apiKey.Scopes,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					CreateAPIKey(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Internal Error",
+			body: gin.H{
+				"name":   apiKey.Name,
+				"scopes": apiKey.Scopes,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					CreateAPIKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ApiKey{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		currentTest := testCases[i]
+
+		t.Run(currentTest.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			database := mockdb.NewMockDatabase(ctrl)
+			currentTest.buildStubs(database)
+
+			server := newTestServer(t, database)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(currentTest.body)
+			require.NoError(t, err)
+
+			url := "/api-keys"
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			currentTest.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			currentTest.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListAPIKeysAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	n := 5
+	apiKeys := make([]db.ApiKey, n)
+	for i := 0; i < n; i++ {
+		apiKeys[i] = randomAPIKey(user.Username)
+	}
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker auth.Maker)
+		buildStubs    func(database *mockdb.MockDatabase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					ListAPIKeys(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(apiKeys, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchAPIKeys(t, recorder.Body, apiKeys)
+			},
+		},
+		{
+			name: "No Authorization",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					ListAPIKeys(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Internal Error",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					ListAPIKeys(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.ApiKey{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		currentTest := testCases[i]
+
+		t.Run(currentTest.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			database := mockdb.NewMockDatabase(ctrl)
+			currentTest.buildStubs(database)
+
+			server := newTestServer(t, database)
+			recorder := httptest.NewRecorder()
+
+			url := "/api-keys"
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			currentTest.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			currentTest.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestDeleteAPIKeyAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	apiKey := randomAPIKey(user.Username)
+
+	testCases := []struct {
+		name          string
+		apiKeyID      string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker auth.Maker)
+		buildStubs    func(database *mockdb.MockDatabase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			apiKeyID: apiKey.ID.String(),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetAPIKey(gomock.Any(), gomock.Eq(apiKey.ID)).
+					Times(1).
+					Return(apiKey, nil)
+				database.EXPECT().
+					DeleteAPIKey(gomock.Any(), gomock.Eq(apiKey.ID)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "Unauthorized User",
+			apiKeyID: apiKey.ID.String(),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "unauthorized_user", auth.RoleEditor, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetAPIKey(gomock.Any(), gomock.Eq(apiKey.ID)).
+					Times(1).
+					Return(apiKey, nil)
+				database.EXPECT().
+					DeleteAPIKey(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "Not Found",
+			apiKeyID: apiKey.ID.String(),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetAPIKey(gomock.Any(), gomock.Eq(apiKey.ID)).
+					Times(1).
+					Return(db.ApiKey{}, sql.ErrNoRows)
+				database.EXPECT().
+					DeleteAPIKey(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "Invalid ID",
+			apiKeyID: "invalid",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetAPIKey(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		currentTest := testCases[i]
+
+		t.Run(currentTest.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			database := mockdb.NewMockDatabase(ctrl)
+			currentTest.buildStubs(database)
+
+			server := newTestServer(t, database)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api-keys/%s", currentTest.apiKeyID)
+			request, err := http.NewRequest(http.MethodDelete, url, nil)
+			require.NoError(t, err)
+
+			currentTest.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			currentTest.checkResponse(t, recorder)
+		})
+	}
+}
+
+func randomAPIKey(username string) db.ApiKey {
+	_, prefix, hashedKey, _ := authHelper.GenerateAPIKey()
+	return db.ApiKey{
+		ID:        uuid.New(),
+		Username:  username,
+		Name:      authHelper.RandomString(8),
+		Prefix:    prefix,
+		HashedKey: hashedKey,
+		Scopes:    []string{scopeContactsRead, scopeSkillsRead},
+		CreatedAt: time.Now(),
+	}
+}
+
+func requireBodyMatchAPIKeys(t *testing.T, body *bytes.Buffer, apiKeys []db.ApiKey) {
+	data, err := ioutil.ReadAll(body)
+	require.NoError(t, err)
+
+	var gotAPIKeys []apiKeyResponse
+	err = json.Unmarshal(data, &gotAPIKeys)
+	require.NoError(t, err)
+
+	require.Len(t, gotAPIKeys, len(apiKeys))
+	for i, apiKey := range apiKeys {
+		require.Equal(t, apiKey.ID, gotAPIKeys[i].ID)
+		require.Equal(t, apiKey.Prefix, gotAPIKeys[i].Prefix)
+		require.Equal(t, apiKey.Scopes, gotAPIKeys[i].Scopes)
+	}
+	require.NotContains(t, string(data), "hashed_key")
+}
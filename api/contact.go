@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"errors"
 	"net/http"
+	"strings"
 
 	auth "github.com/IsuruHaupe/web-api/auth/token"
 	db "github.com/IsuruHaupe/web-api/db/sqlc"
@@ -84,6 +85,12 @@ type getContactRequest struct {
 // @Success 200 {object} db.Contact
 // @Router /contacts/{id} [get]
 func (server *Server) getContact(ctx *gin.Context) {
+	// The contact is asked as a vCard : /contacts/{id}.vcf.
+	if strings.HasSuffix(ctx.Param("id"), vcardExtension) {
+		server.getContactVCard(ctx)
+		return
+	}
+
 	var req getContactRequest
 	// We verify that the JSON is correct, i.e : all fields are present.
 	if err := ctx.ShouldBindUri(&req); err != nil {
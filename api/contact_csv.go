@@ -0,0 +1,554 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	auth "github.com/IsuruHaupe/web-api/auth/token"
+	db "github.com/IsuruHaupe/web-api/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const (
+	// Uploads bigger than this are refused.
+	maxCSVImportSize = 10 << 20
+	// Files with more rows than this are imported in the background, unless configured otherwise.
+	defaultCSVImportBackgroundRows = 500
+	// At most this many row errors are reported, the failed rows are still counted.
+	maxCSVImportErrors = 1000
+	// Background imports save their progress every this many rows.
+	csvImportProgressInterval = 100
+	// Running import jobs are kept alive this often by the instance importing them.
+	importJobHeartbeatInterval = 20 * time.Second
+	// Running import jobs which were not kept alive for this long are abandoned, the instance importing them is gone.
+	importJobLeaseDuration = 2 * time.Minute
+)
+
+// Statuses of an import job.
+const (
+	importJobRunning   = "running"
+	importJobCompleted = "completed"
+	importJobFailed    = "failed"
+)
+
+// Error of the import jobs that were still running when the server stopped, the rows imported until then are kept.
+var errImportInterrupted = errors.New("the import was interrupted by a restart of the server")
+
+// errImportJobStopped is returned when an import job is not running anymore, it was failed as interrupted meanwhile.
+var errImportJobStopped = errors.New("the import job is not running anymore")
+
+// Columns of a CSV file can be mapped to these fields, they are the fields of createContactRequest.
+var csvContactFields = []string{"firstname", "lastname", "fullname", "home_address", "email", "phone_number"}
+
+var csvDelimiters = map[string]rune{
+	"":          ',',
+	"comma":     ',',
+	"semicolon": ';',
+	"tab":       '\t',
+}
+
+// Request holder for importing contacts from a CSV file request, the file and its mapping are sent as a form.
+type importContactsCSVRequest struct {
+	DryRun    bool   `form:"dry_run"`
+	Delimiter string `form:"delimiter" binding:"omitempty,oneof=comma semicolon tab"`
+}
+
+// csvRow is a row of an imported file, err is set when the row can't be read.
+type csvRow struct {
+	line    int
+	contact createContactRequest
+	err     error
+}
+
+// This is the expected returned response for each row that was not imported, Line is the line of the row in the file.
+type csvRowError struct {
+	Line  int32  `json:"line"`
+	Error string `json:"error"`
+}
+
+// This is the expected returned response after importing a CSV file. On a dry run, nothing is created and the imported
+// rows are the ones that would have been.
+type csvImportResponse struct {
+	DryRun       bool          `json:"dry_run"`
+	TotalRows    int32         `json:"total_rows"`
+	ImportedRows int32         `json:"imported_rows"`
+	FailedRows   int32         `json:"failed_rows"`
+	Errors       []csvRowError `json:"errors"`
+}
+
+// This is the expected returned response for an import running in the background.
+type importJobResponse struct {
+	ID            uuid.UUID     `json:"id"`
+	Status        string        `json:"status"`
+	DryRun        bool          `json:"dry_run"`
+	TotalRows     int32         `json:"total_rows"`
+	ProcessedRows int32         `json:"processed_rows"`
+	ImportedRows  int32         `json:"imported_rows"`
+	FailedRows    int32         `json:"failed_rows"`
+	Error         string        `json:"error,omitempty"`
+	Errors        []csvRowError `json:"errors"`
+	CreatedAt     time.Time     `json:"created_at"`
+	FinishedAt    *time.Time    `json:"finished_at,omitempty"`
+}
+
+func newImportJobResponse(job db.ImportJob, rowErrors []csvRowError) importJobResponse {
+	rsp := importJobResponse{
+		ID:            job.ID,
+		Status:        job.Status,
+		DryRun:        job.DryRun,
+		TotalRows:     job.TotalRows,
+		ProcessedRows: job.ProcessedRows,
+		ImportedRows:  job.ImportedRows,
+		FailedRows:    job.FailedRows,
+		Error:         job.Error,
+		Errors:        rowErrors,
+		CreatedAt:     job.CreatedAt,
+	}
+	if job.FinishedAt.Valid {
+		rsp.FinishedAt = &job.FinishedAt.Time
+	}
+	return rsp
+}
+
+// importContactsCSV godoc
+// @Security bearerAuth
+// @Summary Import contacts from a CSV file
+// @Tags Contact
+// @Description This function is used to create contacts from the rows of a CSV file. The mapping is a JSON object giving
+// @Description the contact field of the columns, e.g : {"E-mail": "email"}. When it is left out, the columns must be named
+// @Description after the fields. Every row is validated as a contact created with the API. With dry_run, nothing is
+// @Description created and the response tells which rows would fail. Big files are imported in the background : the
+// @Description response is then a job whose progress is polled with /contacts/import/jobs/{id}.
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "CSV file"
+// @Param mapping formData string false "mapping"
+// @Param dry_run query bool false "dry_run"
+// @Param delimiter query string false "delimiter" Enums(comma, semicolon, tab)
+// @Success 200 {object} api.csvImportResponse
+// @Success 202 {object} api.importJobResponse
+// @Router /contacts/import/csv [post]
+func (server *Server) importContactsCSV(ctx *gin.Context) {
+	var req importContactsCSVRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxCSVImportSize)
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	defer file.Close()
+
+	rows, err := readCSVContacts(file, csvDelimiters[req.Delimiter], ctx.PostForm("mapping"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	backgroundRows := server.config.CSVImportBackgroundRows
+	if backgroundRows == 0 {
+		backgroundRows = defaultCSVImportBackgroundRows
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Payload)
+	if len(rows) > backgroundRows {
+		job, err := server.database.CreateImportJob(ctx, db.CreateImportJobParams{
+			ID:        uuid.New(),
+			Owner:     authPayload.Username,
+			DryRun:    req.DryRun,
+			TotalRows: int32(len(rows)),
+		})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		server.importJobs.Add(1)
+		go server.runImportJob(job, rows)
+
+		ctx.JSON(http.StatusAccepted, newImportJobResponse(job, []csvRowError{}))
+		return
+	}
+
+	rsp, err := server.importCSVRows(ctx, authPayload.Username, rows, req.DryRun, nil)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// This function will import the rows in the background, the progress and the errors are saved in the job along the way.
+// The job is kept alive while it runs, it is stopped if it was failed as interrupted meanwhile.
+func (server *Server) runImportJob(job db.ImportJob, rows []csvRow) {
+	defer server.importJobs.Done()
+	// The request is over, the import must not be canceled with it.
+	ctx, cancel := context.WithCancel(context.Background())
+	heartbeatDone := make(chan struct{})
+	go func() {
+		defer close(heartbeatDone)
+		server.keepImportJobAlive(ctx, cancel, job.ID)
+	}()
+	defer func() {
+		cancel()
+		<-heartbeatDone
+	}()
+
+	rsp, err := server.importCSVRows(ctx, job.Owner, rows, job.DryRun, func(rsp csvImportResponse, rowErr *csvRowError) error {
+		if rowErr != nil {
+			err := server.database.CreateImportJobError(ctx, db.CreateImportJobErrorParams{
+				JobID:   job.ID,
+				Line:    rowErr.Line,
+				Message: rowErr.Error,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		processed := rsp.ImportedRows + rsp.FailedRows
+		if processed%csvImportProgressInterval != 0 {
+			return nil
+		}
+		updated, err := server.database.UpdateImportJobProgress(ctx, db.UpdateImportJobProgressParams{
+			ID:            job.ID,
+			ProcessedRows: processed,
+			ImportedRows:  rsp.ImportedRows,
+			FailedRows:    rsp.FailedRows,
+		})
+		if err == nil && updated == 0 {
+			err = errImportJobStopped
+		}
+		return err
+	})
+	if errors.Is(err, errImportJobStopped) || ctx.Err() != nil {
+		log.Printf("import job %s stopped : %v", job.ID, errImportJobStopped)
+		return
+	}
+
+	arg := db.FinishImportJobParams{
+		ID:            job.ID,
+		ProcessedRows: rsp.ImportedRows + rsp.FailedRows,
+		ImportedRows:  rsp.ImportedRows,
+		FailedRows:    rsp.FailedRows,
+		Status:        importJobCompleted,
+	}
+	if err != nil {
+		arg.Status = importJobFailed
+		arg.Error = err.Error()
+	}
+	_, err = server.database.FinishImportJob(ctx, arg)
+	if err == sql.ErrNoRows {
+		err = errImportJobStopped
+	}
+	if err != nil {
+		log.Printf("cannot finish import job %s : %v", job.ID, err)
+	}
+}
+
+// This function will keep an import job alive until ctx is done. cancel is called when the job is not running anymore.
+func (server *Server) keepImportJobAlive(ctx context.Context, cancel context.CancelFunc, id uuid.UUID) {
+	ticker := time.NewTicker(importJobHeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		updated, err := server.database.KeepImportJobAlive(ctx, id)
+		if err != nil {
+			// The job is kept running, it can still be kept alive by the next heartbeats.
+			log.Printf("cannot keep import job %s alive : %v", id, err)
+			continue
+		}
+		if updated == 0 {
+			cancel()
+			return
+		}
+	}
+}
+
+// FailInterruptedImportJobs marks the running import jobs which were not kept alive for importJobLeaseDuration as
+// failed, the instance importing them is gone and nothing will finish them anymore. The jobs of the other instances
+// sharing the database are kept alive and left running.
+func (server *Server) FailInterruptedImportJobs(ctx context.Context) (int64, error) {
+	return server.database.FailInterruptedImportJobs(ctx, db.FailInterruptedImportJobsParams{
+		Error:           errImportInterrupted.Error(),
+		HeartbeatBefore: time.Now().Add(-importJobLeaseDuration),
+	})
+}
+
+// This function will validate and create the contacts of the rows. The rows that fail are reported, an error is only
+// returned when the database can't be used. onRow, if set, is called after each row with the counts so far and the
+// error of the row if it failed and is reported.
+func (server *Server) importCSVRows(
+	ctx context.Context,
+	owner string,
+	rows []csvRow,
+	dryRun bool,
+	onRow func(rsp csvImportResponse, rowErr *csvRowError) error,
+) (csvImportResponse, error) {
+	rsp := csvImportResponse{
+		DryRun:    dryRun,
+		TotalRows: int32(len(rows)),
+		Errors:    []csvRowError{},
+	}
+
+	for _, row := range rows {
+		err := row.err
+		if err == nil {
+			err = binding.Validator.ValidateStruct(&row.contact)
+		}
+		if err == nil && !dryRun {
+			_, err = server.database.CreateContact(ctx, db.CreateContactParams{
+				Owner:       owner,
+				Firstname:   row.contact.Firstname,
+				Lastname:    row.contact.Lastname,
+				Fullname:    row.contact.Fullname,
+				HomeAddress: row.contact.HomeAddress,
+				Email:       row.contact.Email,
+				PhoneNumber: row.contact.PhoneNumber,
+			})
+			// Constraint violations only fail the row, like they fail the creation of a contact.
+			if err != nil {
+				pqErr, ok := err.(*pq.Error)
+				if !ok || (pqErr.Code.Name() != "foreign_key_violation" && pqErr.Code.Name() != "unique_violation") {
+					return rsp, err
+				}
+			}
+		}
+
+		var rowErr *csvRowError
+		if err != nil {
+			rsp.FailedRows++
+			if len(rsp.Errors) < maxCSVImportErrors {
+				rsp.Errors = append(rsp.Errors, csvRowError{Line: int32(row.line), Error: err.Error()})
+				rowErr = &rsp.Errors[len(rsp.Errors)-1]
+			}
+		} else {
+			rsp.ImportedRows++
+		}
+
+		if onRow != nil {
+			if err := onRow(rsp, rowErr); err != nil {
+				return rsp, err
+			}
+		}
+	}
+
+	return rsp, nil
+}
+
+// This function will read the contacts of a CSV file whose first row is the header. The rows which don't have as many
+// fields as the header are kept with an error, blank rows are skipped.
+func readCSVContacts(r io.Reader, delimiter rune, mapping string) ([]csvRow, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = delimiter
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("the file is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Spreadsheets often save a byte order mark at the beginning of the file.
+	header[0] = strings.TrimPrefix(header[0], "\ufeff")
+
+	columns, err := csvColumns(header, mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []csvRow{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) && errors.Is(parseErr.Err, csv.ErrFieldCount) {
+			rows = append(rows, csvRow{
+				line: parseErr.StartLine,
+				err:  fmt.Errorf("expected %d fields, got %d", len(header), len(record)),
+			})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+
+		line, _ := reader.FieldPos(0)
+		value := func(field string) string {
+			return strings.TrimSpace(record[columns[field]])
+		}
+		rows = append(rows, csvRow{
+			line: line,
+			contact: createContactRequest{
+				Firstname:   value("firstname"),
+				Lastname:    value("lastname"),
+				Fullname:    value("fullname"),
+				HomeAddress: value("home_address"),
+				Email:       value("email"),
+				PhoneNumber: value("phone_number"),
+			},
+		})
+	}
+}
+
+// This function will return the column of each contact field. The mapping is a JSON object giving the field of the
+// columns, the columns named after a field are used when it is empty. Every field must have a column.
+func csvColumns(header []string, mapping string) (map[string]int, error) {
+	headerColumns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if _, ok := headerColumns[name]; ok {
+			// Ambiguous columns can't be mapped.
+			headerColumns[name] = -1
+			continue
+		}
+		headerColumns[name] = i
+	}
+
+	fields := map[string]string{}
+	if mapping == "" {
+		for name := range headerColumns {
+			fields[name] = strings.ToLower(name)
+		}
+	} else if err := json.Unmarshal([]byte(mapping), &fields); err != nil {
+		return nil, fmt.Errorf("invalid mapping : %w", err)
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	columns := map[string]int{}
+	for _, name := range names {
+		field := fields[name]
+		if !isCSVContactField(field) {
+			if mapping == "" {
+				// Without mapping, the columns which are not fields are ignored.
+				continue
+			}
+			return nil, fmt.Errorf("column %q is mapped to unknown field %q", name, field)
+		}
+		column, ok := headerColumns[name]
+		if !ok {
+			return nil, fmt.Errorf("column %q is not in the header", name)
+		}
+		if column < 0 {
+			return nil, fmt.Errorf("column %q appears more than once in the header", name)
+		}
+		if _, ok := columns[field]; ok {
+			return nil, fmt.Errorf("field %q is mapped more than once", field)
+		}
+		columns[field] = column
+	}
+
+	for _, field := range csvContactFields {
+		if _, ok := columns[field]; !ok {
+			return nil, fmt.Errorf("field %q is not mapped to a column", field)
+		}
+	}
+	return columns, nil
+}
+
+func isCSVContactField(field string) bool {
+	for _, contactField := range csvContactFields {
+		if field == contactField {
+			return true
+		}
+	}
+	return false
+}
+
+// Request holder for get import job request.
+type getImportJobRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// getImportJob godoc
+// @Security bearerAuth
+// @Summary Get an import job
+// @Tags Contact
+// @Description This function is used to follow the progress of a CSV import running in the background, the errors of
+// @Description the rows that failed so far are listed.
+// @Accept application/x-www-form-urlencoded
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} api.importJobResponse
+// @Router /contacts/import/jobs/{id} [get]
+func (server *Server) getImportJob(ctx *gin.Context) {
+	var req getImportJobRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	job, err := server.database.GetImportJob(ctx, uuid.MustParse(req.ID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Payload)
+	if job.Owner != authPayload.Username {
+		err := errors.New("import job doesn't belong to the user")
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	rowErrs, err := server.database.ListImportJobErrors(ctx, db.ListImportJobErrorsParams{
+		JobID: job.ID,
+		Limit: maxCSVImportErrors,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rowErrors := make([]csvRowError, len(rowErrs))
+	for i, rowErr := range rowErrs {
+		rowErrors[i] = csvRowError{Line: rowErr.Line, Error: rowErr.Message}
+	}
+
+	ctx.JSON(http.StatusOK, newImportJobResponse(job, rowErrors))
+}
@@ -0,0 +1,558 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	auth "github.com/IsuruHaupe/web-api/auth/token"
+	mockdb "github.com/IsuruHaupe/web-api/db/mock"
+	db "github.com/IsuruHaupe/web-api/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportContactsCSVAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	file := "\ufeffFirst Name,Last Name,Name,Address,E-mail,Phone,Notes\n" +
+		"Ada,Lovelace,Ada Lovelace,\"12 St James's Square, London\",ada@example.com,+44 20 7946 0000,\n" +
+		"\n" +
+		"Grace,Hopper,Grace Hopper,3 Main Street,not an email,+1 703 555 0100,Navy\n" +
+		"Alan,Turing,Alan Turing,Wilmslow,alan@example.com,+44 161 496 0000,\n" +
+		"Too,Short\n"
+	mapping := `{"First Name": "firstname", "Last Name": "lastname", "Name": "fullname", "Address": "home_address", "E-mail": "email", "Phone": "phone_number"}`
+
+	ada := db.CreateContactParams{
+		Owner:       user.Username,
+		Firstname:   "Ada",
+		Lastname:    "Lovelace",
+		Fullname:    "Ada Lovelace",
+		HomeAddress: "12 St James's Square, London",
+		Email:       "ada@example.com",
+		PhoneNumber: "+44 20 7946 0000",
+	}
+	alan := db.CreateContactParams{
+		Owner:       user.Username,
+		Firstname:   "Alan",
+		Lastname:    "Turing",
+		Fullname:    "Alan Turing",
+		HomeAddress: "Wilmslow",
+		Email:       "alan@example.com",
+		PhoneNumber: "+44 161 496 0000",
+	}
+	checkReport := func(t *testing.T, rsp csvImportResponse) {
+		require.Equal(t, int32(4), rsp.TotalRows)
+		require.Equal(t, int32(2), rsp.ImportedRows)
+		require.Equal(t, int32(2), rsp.FailedRows)
+		require.Len(t, rsp.Errors, 2)
+		require.Equal(t, int32(4), rsp.Errors[0].Line)
+		require.Contains(t, rsp.Errors[0].Error, "Email")
+		require.Equal(t, int32(6), rsp.Errors[1].Line)
+		require.Equal(t, "expected 7 fields, got 2", rsp.Errors[1].Error)
+	}
+
+	job := db.ImportJob{
+		ID:        uuid.New(),
+		Owner:     user.Username,
+		Status:    importJobRunning,
+		TotalRows: 4,
+		CreatedAt: time.Now(),
+	}
+
+	testCases := []struct {
+		name           string
+		query          string
+		file           string
+		mapping        string
+		backgroundRows int
+		setupAuth      func(t *testing.T, request *http.Request, tokenMaker auth.Maker)
+		buildStubs     func(database *mockdb.MockDatabase)
+		checkResponse  func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			file:    file,
+			mapping: mapping,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				gomock.InOrder(
+					database.EXPECT().CreateContact(gomock.Any(), gomock.Eq(ada)).Times(1).Return(db.Contact{ID: 1}, nil),
+					database.EXPECT().CreateContact(gomock.Any(), gomock.Eq(alan)).Times(1).Return(db.Contact{ID: 2}, nil),
+				)
+				database.EXPECT().CreateImportJob(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp csvImportResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.False(t, rsp.DryRun)
+				checkReport(t, rsp)
+			},
+		},
+		{
+			name:    "Dry Run",
+			query:   "dry_run=true",
+			file:    file,
+			mapping: mapping,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().CreateContact(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp csvImportResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.True(t, rsp.DryRun)
+				checkReport(t, rsp)
+			},
+		},
+		{
+			name:  "Columns Named After Fields",
+			query: "delimiter=semicolon",
+			file: "firstname;lastname;fullname;home_address;email;phone_number\n" +
+				"Ada;Lovelace;Ada Lovelace;12 St James's Square, London;ada@example.com;+44 20 7946 0000\n",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().CreateContact(gomock.Any(), gomock.Eq(ada)).Times(1).Return(db.Contact{ID: 1}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp csvImportResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, int32(1), rsp.ImportedRows)
+				require.Empty(t, rsp.Errors)
+			},
+		},
+		{
+			name:    "Field Not Mapped",
+			file:    file,
+			mapping: `{"First Name": "firstname", "Last Name": "lastname", "Name": "fullname", "E-mail": "email", "Phone": "phone_number"}`,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().CreateContact(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `field \"home_address\" is not mapped to a column`)
+			},
+		},
+		{
+			name:    "Unknown Column",
+			file:    file,
+			mapping: `{"Mail": "email"}`,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().CreateContact(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `column \"Mail\" is not in the header`)
+			},
+		},
+		{
+			name:    "Unknown Field",
+			file:    file,
+			mapping: `{"Notes": "notes"}`,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().CreateContact(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "Invalid Delimiter",
+			query:   "delimiter=pipe",
+			file:    file,
+			mapping: mapping,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().CreateContact(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "Viewer",
+			file:    file,
+			mapping: mapping,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleViewer, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().CreateContact(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:    "Internal Error",
+			file:    file,
+			mapping: mapping,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().CreateContact(gomock.Any(), gomock.Any()).Times(1).Return(db.Contact{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:           "Background Job",
+			file:           file,
+			mapping:        mapping,
+			backgroundRows: 3,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					CreateImportJob(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CreateImportJobParams) (db.ImportJob, error) {
+						require.Equal(t, user.Username, arg.Owner)
+						require.False(t, arg.DryRun)
+						require.Equal(t, int32(4), arg.TotalRows)
+						return job, nil
+					})
+				database.EXPECT().CreateContact(gomock.Any(), gomock.Eq(ada)).Times(1).Return(db.Contact{ID: 1}, nil)
+				database.EXPECT().CreateContact(gomock.Any(), gomock.Eq(alan)).Times(1).Return(db.Contact{ID: 2}, nil)
+				database.EXPECT().
+					CreateImportJobError(gomock.Any(), gomock.Any()).
+					Times(2).
+					Return(nil)
+				database.EXPECT().UpdateImportJobProgress(gomock.Any(), gomock.Any()).Times(0)
+
+				finished := job
+				finished.Status = importJobCompleted
+				database.EXPECT().
+					FinishImportJob(gomock.Any(), gomock.Eq(db.FinishImportJobParams{
+						ID:            job.ID,
+						ProcessedRows: 4,
+						ImportedRows:  2,
+						FailedRows:    2,
+						Status:        importJobCompleted,
+					})).
+					Times(1).
+					Return(finished, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+
+				var rsp importJobResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, job.ID, rsp.ID)
+				require.Equal(t, importJobRunning, rsp.Status)
+				require.Equal(t, int32(4), rsp.TotalRows)
+				require.Nil(t, rsp.FinishedAt)
+			},
+		},
+		{
+			name:           "Background Job Failure",
+			file:           file,
+			mapping:        mapping,
+			backgroundRows: 3,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().CreateImportJob(gomock.Any(), gomock.Any()).Times(1).Return(job, nil)
+				database.EXPECT().CreateContact(gomock.Any(), gomock.Eq(ada)).Times(1).Return(db.Contact{ID: 1}, nil)
+				database.EXPECT().CreateImportJobError(gomock.Any(), gomock.Any()).Times(1).Return(sql.ErrConnDone)
+				database.EXPECT().
+					FinishImportJob(gomock.Any(), gomock.Eq(db.FinishImportJobParams{
+						ID:            job.ID,
+						ProcessedRows: 2,
+						ImportedRows:  1,
+						FailedRows:    1,
+						Status:        importJobFailed,
+						Error:         sql.ErrConnDone.Error(),
+					})).
+					Times(1).
+					Return(job, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		currentTest := testCases[i]
+
+		t.Run(currentTest.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			database := mockdb.NewMockDatabase(ctrl)
+			currentTest.buildStubs(database)
+
+			server := newTestServer(t, database)
+			server.config.CSVImportBackgroundRows = currentTest.backgroundRows
+			recorder := httptest.NewRecorder()
+
+			var body bytes.Buffer
+			writer := multipart.NewWriter(&body)
+			part, err := writer.CreateFormFile("file", "contacts.csv")
+			require.NoError(t, err)
+			_, err = part.Write([]byte(currentTest.file))
+			require.NoError(t, err)
+			if currentTest.mapping != "" {
+				require.NoError(t, writer.WriteField("mapping", currentTest.mapping))
+			}
+			require.NoError(t, writer.Close())
+
+			url := fmt.Sprintf("/contacts/import/csv?%s", currentTest.query)
+			request, err := http.NewRequest(http.MethodPost, url, &body)
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", writer.FormDataContentType())
+
+			currentTest.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			server.importJobs.Wait()
+			currentTest.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetImportJobAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	job := db.ImportJob{
+		ID:            uuid.New(),
+		Owner:         user.Username,
+		Status:        importJobCompleted,
+		TotalRows:     600,
+		ProcessedRows: 600,
+		ImportedRows:  599,
+		FailedRows:    1,
+		CreatedAt:     time.Now().Add(-time.Minute),
+		FinishedAt:    sql.NullTime{Time: time.Now(), Valid: true},
+	}
+	otherJob := job
+	otherJob.ID = uuid.New()
+	otherJob.Owner = "other"
+
+	testCases := []struct {
+		name          string
+		id            string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker auth.Maker)
+		buildStubs    func(database *mockdb.MockDatabase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			id:   job.ID.String(),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleViewer, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().GetImportJob(gomock.Any(), gomock.Eq(job.ID)).Times(1).Return(job, nil)
+				database.EXPECT().
+					ListImportJobErrors(gomock.Any(), gomock.Eq(db.ListImportJobErrorsParams{JobID: job.ID, Limit: maxCSVImportErrors})).
+					Times(1).
+					Return([]db.ListImportJobErrorsRow{{Line: 42, Message: "invalid email"}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp importJobResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, job.ID, rsp.ID)
+				require.Equal(t, importJobCompleted, rsp.Status)
+				require.Equal(t, job.ProcessedRows, rsp.ProcessedRows)
+				require.Equal(t, job.ImportedRows, rsp.ImportedRows)
+				require.NotNil(t, rsp.FinishedAt)
+				require.Equal(t, []csvRowError{{Line: 42, Error: "invalid email"}}, rsp.Errors)
+			},
+		},
+		{
+			name: "Invalid ID",
+			id:   "not-a-uuid",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleViewer, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().GetImportJob(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Not Found",
+			id:   job.ID.String(),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleViewer, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().GetImportJob(gomock.Any(), gomock.Eq(job.ID)).Times(1).Return(db.ImportJob{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "Job Of Another User",
+			id:   otherJob.ID.String(),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleViewer, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().GetImportJob(gomock.Any(), gomock.Eq(otherJob.ID)).Times(1).Return(otherJob, nil)
+				database.EXPECT().ListImportJobErrors(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Internal Error",
+			id:   job.ID.String(),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleViewer, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().GetImportJob(gomock.Any(), gomock.Eq(job.ID)).Times(1).Return(job, nil)
+				database.EXPECT().
+					ListImportJobErrors(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.ListImportJobErrorsRow{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		currentTest := testCases[i]
+
+		t.Run(currentTest.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			database := mockdb.NewMockDatabase(ctrl)
+			currentTest.buildStubs(database)
+
+			server := newTestServer(t, database)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/contacts/import/jobs/"+currentTest.id, nil)
+			require.NoError(t, err)
+
+			currentTest.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			currentTest.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestFailInterruptedImportJobs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	database := mockdb.NewMockDatabase(ctrl)
+	server := newTestServer(t, database)
+
+	database.EXPECT().
+		FailInterruptedImportJobs(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ interface{}, arg db.FailInterruptedImportJobsParams) (int64, error) {
+			require.Equal(t, errImportInterrupted.Error(), arg.Error)
+			require.WithinDuration(t, time.Now().Add(-importJobLeaseDuration), arg.HeartbeatBefore, time.Second)
+			return 2, nil
+		})
+
+	failed, err := server.FailInterruptedImportJobs(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(2), failed)
+}
+
+func TestRunImportJobStopped(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user, _ := randomUser(t)
+	job := db.ImportJob{ID: uuid.New(), Owner: user.Username, Status: importJobRunning, TotalRows: 150}
+	rows := make([]csvRow, job.TotalRows)
+	for i := range rows {
+		rows[i] = csvRow{line: i + 2, err: fmt.Errorf("expected 7 fields, got 2")}
+	}
+
+	// The job was failed as interrupted by another instance, it stops at its first progress.
+	database := mockdb.NewMockDatabase(ctrl)
+	database.EXPECT().
+		CreateImportJobError(gomock.Any(), gomock.Any()).
+		Times(csvImportProgressInterval).
+		Return(nil)
+	database.EXPECT().
+		UpdateImportJobProgress(gomock.Any(), gomock.Eq(db.UpdateImportJobProgressParams{
+			ID:            job.ID,
+			ProcessedRows: csvImportProgressInterval,
+			FailedRows:    csvImportProgressInterval,
+		})).
+		Times(1).
+		Return(int64(0), nil)
+	database.EXPECT().FinishImportJob(gomock.Any(), gomock.Any()).Times(0)
+
+	server := newTestServer(t, database)
+	server.importJobs.Add(1)
+	server.runImportJob(job, rows)
+}
+
+func TestShutdownWaitsForImportJobs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServer(t, mockdb.NewMockDatabase(ctrl))
+	server.importJobs.Add(1)
+
+	// The job is still running when the time given to the shutdown is over.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, server.Shutdown(ctx), context.DeadlineExceeded)
+
+	finished := make(chan error, 1)
+	go func() {
+		finished <- server.Shutdown(context.Background())
+	}()
+	select {
+	case <-finished:
+		t.Fatal("shutdown returned before the import job finished")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	server.importJobs.Done()
+	require.NoError(t, <-finished)
+}
@@ -0,0 +1,194 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	auth "github.com/IsuruHaupe/web-api/auth/token"
+	"github.com/IsuruHaupe/web-api/db/database"
+	db "github.com/IsuruHaupe/web-api/db/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+// Pairs scoring below this are not reported unless asked otherwise, an identical name alone reaches it.
+const defaultDuplicateMinScore = 0.3
+
+// Request holder for listing duplicate contacts request.
+type listDuplicateContactsRequest struct {
+	MinScore *float32 `form:"min_score" binding:"omitempty,min=0,max=1"`
+	PageID   int32    `form:"page_id" binding:"required,min=1"`
+	PageSize int32    `form:"page_size" binding:"required,min=1,max=10"`
+}
+
+// This is the expected returned response for each pair of probable duplicates. The score goes from 0 to 1, a same
+// email counts for 0.4, a same phone number for 0.3 and the similarity of the names for up to 0.3.
+type duplicateContactsResponse struct {
+	ContactID      int64   `json:"contact_id"`
+	DuplicateID    int64   `json:"duplicate_id"`
+	Score          float32 `json:"score"`
+	EmailMatch     bool    `json:"email_match"`
+	PhoneMatch     bool    `json:"phone_match"`
+	NameSimilarity float32 `json:"name_similarity"`
+}
+
+// listDuplicateContacts godoc
+// @Security bearerAuth
+// @Summary List probable duplicate contacts
+// @Tags Contact
+// @Description This function is used to find the pairs of contacts of an user that are probably the same person.
+// @Description Emails are compared case insensitively, phone numbers on their digits only and names on their similarity.
+// @Accept application/x-www-form-urlencoded
+// @Produce json
+// @Param min_score query number false "min_score"
+// @Param page_id query int true "page_id"
+// @Param page_size query int true "page_size"
+// @Success 200 {array} api.duplicateContactsResponse
+// @Router /contacts/duplicates [get]
+func (server *Server) listDuplicateContacts(ctx *gin.Context) {
+	var req listDuplicateContactsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	minScore := float32(defaultDuplicateMinScore)
+	if req.MinScore != nil {
+		minScore = *req.MinScore
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Payload)
+	pairs, err := server.database.ListDuplicateContacts(ctx, db.ListDuplicateContactsParams{
+		Owner:       authPayload.Username,
+		MinScore:    minScore,
+		LimitCount:  req.PageSize,
+		OffsetCount: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := make([]duplicateContactsResponse, 0, len(pairs))
+	for _, pair := range pairs {
+		rsp = append(rsp, duplicateContactsResponse{
+			ContactID:      pair.ContactID,
+			DuplicateID:    pair.DuplicateID,
+			Score:          pair.Score,
+			EmailMatch:     pair.EmailMatch,
+			PhoneMatch:     pair.PhoneMatch,
+			NameSimilarity: pair.NameSimilarity,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// Request holder for merge contacts request. The surviving contact keeps its own values except for the fields
+// listed in take_from_merged, and the empty ones which are filled with the values of the merged contact.
+type mergeContactsRequest struct {
+	SurvivorID     int64    `json:"survivor_id" binding:"required,min=1"`
+	MergedID       int64    `json:"merged_id" binding:"required,min=1,nefield=SurvivorID"`
+	TakeFromMerged []string `json:"take_from_merged" binding:"dive,oneof=firstname lastname fullname home_address email phone_number"`
+}
+
+// This is the expected returned response after merging contacts.
+type mergeContactsResponse struct {
+	Contact     db.Contact `json:"contact"`
+	MovedSkills int64      `json:"moved_skills"`
+}
+
+// mergeContacts godoc
+// @Security bearerAuth
+// @Summary Merge two contacts
+// @Tags Contact
+// @Description This function is used to merge a duplicate contact into another one. The skills of the merged contact
+// @Description are moved to the surviving contact and the merged contact is deleted, all at once.
+// @Accept json
+// @Produce json
+// @Param merge body api.mergeContactsRequest true "Merge Contacts"
+// @Success 200 {object} api.mergeContactsResponse
+// @Router /contacts/merge [post]
+func (server *Server) mergeContacts(ctx *gin.Context) {
+	var req mergeContactsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	survivor, valid := server.getOwnedContact(ctx, req.SurvivorID)
+	if !valid {
+		return
+	}
+	merged, valid := server.getOwnedContact(ctx, req.MergedID)
+	if !valid {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Payload)
+	result, err := server.database.MergeContactsTx(ctx, database.MergeContactsTxParams{
+		Survivor: mergeContactValues(survivor, merged, req.TakeFromMerged),
+		MergedID: merged.ID,
+		Owner:    authPayload.Username,
+	})
+	if err != nil {
+		// One of the contacts was deleted in the meantime.
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, mergeContactsResponse{
+		Contact:     result.Contact,
+		MovedSkills: result.MovedSkills,
+	})
+}
+
+// This function will check that the contact exists and belongs to the user, it aborts the request otherwise.
+func (server *Server) getOwnedContact(ctx *gin.Context, id int64) (db.Contact, bool) {
+	contact, err := server.database.GetContact(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return contact, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return contact, false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Payload)
+	if contact.Owner != authPayload.Username {
+		err := errors.New("contact doesn't belong to the user")
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return contact, false
+	}
+
+	return contact, true
+}
+
+// This function will pick the surviving value of each field of the contacts being merged.
+func mergeContactValues(survivor, merged db.Contact, takeFromMerged []string) db.UpdateContactParams {
+	take := make(map[string]bool, len(takeFromMerged))
+	for _, field := range takeFromMerged {
+		take[field] = true
+	}
+	pick := func(field, survivorValue, mergedValue string) string {
+		if take[field] || survivorValue == "" {
+			return mergedValue
+		}
+		return survivorValue
+	}
+
+	return db.UpdateContactParams{
+		ID:          survivor.ID,
+		Firstname:   pick("firstname", survivor.Firstname, merged.Firstname),
+		Lastname:    pick("lastname", survivor.Lastname, merged.Lastname),
+		Fullname:    pick("fullname", survivor.Fullname, merged.Fullname),
+		HomeAddress: pick("home_address", survivor.HomeAddress, merged.HomeAddress),
+		Email:       pick("email", survivor.Email, merged.Email),
+		PhoneNumber: pick("phone_number", survivor.PhoneNumber, merged.PhoneNumber),
+	}
+}
@@ -0,0 +1,403 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	auth "github.com/IsuruHaupe/web-api/auth/token"
+	dbtx "github.com/IsuruHaupe/web-api/db/database"
+	mockdb "github.com/IsuruHaupe/web-api/db/mock"
+	db "github.com/IsuruHaupe/web-api/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListDuplicateContactsAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	pair := db.ListDuplicateContactsRow{
+		ContactID:      1,
+		DuplicateID:    2,
+		EmailMatch:     true,
+		PhoneMatch:     false,
+		NameSimilarity: 0.5,
+		Score:          0.55,
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker auth.Maker)
+		buildStubs    func(database *mockdb.MockDatabase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: "page_id=2&page_size=5",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleViewer, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				arg := db.ListDuplicateContactsParams{
+					Owner:       user.Username,
+					MinScore:    defaultDuplicateMinScore,
+					LimitCount:  5,
+					OffsetCount: 5,
+				}
+				database.EXPECT().
+					ListDuplicateContacts(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.ListDuplicateContactsRow{pair}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp []duplicateContactsResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, []duplicateContactsResponse{{
+					ContactID:      pair.ContactID,
+					DuplicateID:    pair.DuplicateID,
+					Score:          pair.Score,
+					EmailMatch:     pair.EmailMatch,
+					PhoneMatch:     pair.PhoneMatch,
+					NameSimilarity: pair.NameSimilarity,
+				}}, rsp)
+			},
+		},
+		{
+			name:  "Min Score",
+			query: "min_score=0.7&page_id=1&page_size=5",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleViewer, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				arg := db.ListDuplicateContactsParams{
+					Owner:       user.Username,
+					MinScore:    0.7,
+					LimitCount:  5,
+					OffsetCount: 0,
+				}
+				database.EXPECT().
+					ListDuplicateContacts(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.ListDuplicateContactsRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, "[]", recorder.Body.String())
+			},
+		},
+		{
+			name:  "Invalid Min Score",
+			query: "min_score=2&page_id=1&page_size=5",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleViewer, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					ListDuplicateContacts(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "No Authorization",
+			query: "page_id=1&page_size=5",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					ListDuplicateContacts(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:  "Internal Error",
+			query: "page_id=1&page_size=5",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleViewer, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					ListDuplicateContacts(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.ListDuplicateContactsRow{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		currentTest := testCases[i]
+
+		t.Run(currentTest.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			database := mockdb.NewMockDatabase(ctrl)
+			currentTest.buildStubs(database)
+
+			server := newTestServer(t, database)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/contacts/duplicates?%s", currentTest.query)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			currentTest.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			currentTest.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestMergeContactsAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	survivor := randomContact(user.Username)
+	survivor.ID = 1
+	merged := randomContact(user.Username)
+	merged.ID = 2
+	foreign := randomContact("other")
+	foreign.ID = 3
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker auth.Maker)
+		buildStubs    func(database *mockdb.MockDatabase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"survivor_id":      survivor.ID,
+				"merged_id":        merged.ID,
+				"take_from_merged": []string{"email", "phone_number"},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().GetContact(gomock.Any(), gomock.Eq(survivor.ID)).Times(1).Return(survivor, nil)
+				database.EXPECT().GetContact(gomock.Any(), gomock.Eq(merged.ID)).Times(1).Return(merged, nil)
+
+				arg := dbtx.MergeContactsTxParams{
+					Survivor: db.UpdateContactParams{
+						ID:          survivor.ID,
+						Firstname:   survivor.Firstname,
+						Lastname:    survivor.Lastname,
+						Fullname:    survivor.Fullname,
+						HomeAddress: survivor.HomeAddress,
+						Email:       merged.Email,
+						PhoneNumber: merged.PhoneNumber,
+					},
+					MergedID: merged.ID,
+					Owner:    user.Username,
+				}
+				result := survivor
+				result.Email = merged.Email
+				result.PhoneNumber = merged.PhoneNumber
+				database.EXPECT().
+					MergeContactsTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(dbtx.MergeContactsTxResult{Contact: result, MovedSkills: 2}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp mergeContactsResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, survivor.ID, rsp.Contact.ID)
+				require.Equal(t, survivor.Firstname, rsp.Contact.Firstname)
+				require.Equal(t, merged.Email, rsp.Contact.Email)
+				require.Equal(t, int64(2), rsp.MovedSkills)
+			},
+		},
+		{
+			name: "Same Contact",
+			body: gin.H{
+				"survivor_id": survivor.ID,
+				"merged_id":   survivor.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().GetContact(gomock.Any(), gomock.Any()).Times(0)
+				database.EXPECT().MergeContactsTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Unknown Field",
+			body: gin.H{
+				"survivor_id":      survivor.ID,
+				"merged_id":        merged.ID,
+				"take_from_merged": []string{"owner"},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().GetContact(gomock.Any(), gomock.Any()).Times(0)
+				database.EXPECT().MergeContactsTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Contact Not Found",
+			body: gin.H{
+				"survivor_id": survivor.ID,
+				"merged_id":   merged.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().GetContact(gomock.Any(), gomock.Eq(survivor.ID)).Times(1).Return(survivor, nil)
+				database.EXPECT().GetContact(gomock.Any(), gomock.Eq(merged.ID)).Times(1).Return(db.Contact{}, sql.ErrNoRows)
+				database.EXPECT().MergeContactsTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "Contact Of Another User",
+			body: gin.H{
+				"survivor_id": survivor.ID,
+				"merged_id":   foreign.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().GetContact(gomock.Any(), gomock.Eq(survivor.ID)).Times(1).Return(survivor, nil)
+				database.EXPECT().GetContact(gomock.Any(), gomock.Eq(foreign.ID)).Times(1).Return(foreign, nil)
+				database.EXPECT().MergeContactsTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Viewer",
+			body: gin.H{
+				"survivor_id": survivor.ID,
+				"merged_id":   merged.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleViewer, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().GetContact(gomock.Any(), gomock.Any()).Times(0)
+				database.EXPECT().MergeContactsTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "Merged Concurrently",
+			body: gin.H{
+				"survivor_id": survivor.ID,
+				"merged_id":   merged.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().GetContact(gomock.Any(), gomock.Eq(survivor.ID)).Times(1).Return(survivor, nil)
+				database.EXPECT().GetContact(gomock.Any(), gomock.Eq(merged.ID)).Times(1).Return(merged, nil)
+				database.EXPECT().
+					MergeContactsTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(dbtx.MergeContactsTxResult{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "Internal Error",
+			body: gin.H{
+				"survivor_id": survivor.ID,
+				"merged_id":   merged.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().GetContact(gomock.Any(), gomock.Eq(survivor.ID)).Times(1).Return(survivor, nil)
+				database.EXPECT().GetContact(gomock.Any(), gomock.Eq(merged.ID)).Times(1).Return(merged, nil)
+				database.EXPECT().
+					MergeContactsTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(dbtx.MergeContactsTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		currentTest := testCases[i]
+
+		t.Run(currentTest.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			database := mockdb.NewMockDatabase(ctrl)
+			currentTest.buildStubs(database)
+
+			server := newTestServer(t, database)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(currentTest.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/contacts/merge", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			currentTest.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			currentTest.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestMergeContactValues(t *testing.T) {
+	survivor := db.Contact{ID: 1, Firstname: "Ada", Lastname: "Lovelace", Fullname: "Ada Lovelace", Email: "ada@example.com"}
+	merged := db.Contact{ID: 2, Firstname: "Augusta", Lastname: "King", Fullname: "Augusta Ada King", Email: "ada@example.org",
+		HomeAddress: "12 St James's Square, London", PhoneNumber: "+44 20 7946 0000"}
+
+	values := mergeContactValues(survivor, merged, []string{"fullname"})
+	require.Equal(t, db.UpdateContactParams{
+		ID:          survivor.ID,
+		Firstname:   survivor.Firstname,
+		Lastname:    survivor.Lastname,
+		Fullname:    merged.Fullname,
+		HomeAddress: merged.HomeAddress,
+		Email:       survivor.Email,
+		PhoneNumber: merged.PhoneNumber,
+	}, values)
+}
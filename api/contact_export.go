@@ -0,0 +1,239 @@
+package api
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	auth "github.com/IsuruHaupe/web-api/auth/token"
+	"github.com/IsuruHaupe/web-api/db/database"
+	db "github.com/IsuruHaupe/web-api/db/sqlc"
+	"github.com/IsuruHaupe/web-api/xlsx"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	exportFormatCSV  = "csv"
+	exportFormatXLSX = "xlsx"
+	csvContentType   = "text/csv; charset=utf-8"
+)
+
+// The columns of the export in their default order, skills holds all the skills of a contact as "name (level)".
+var exportColumns = []string{"id", "firstname", "lastname", "fullname", "home_address", "email", "phone_number", "skills"}
+
+// This is the expected request to export contacts. Columns is a comma separated list of the columns to export in
+// their order, all of them are exported when it is empty.
+type exportContactsRequest struct {
+	Format     string `form:"format" binding:"omitempty,oneof=csv xlsx"`
+	Columns    string `form:"columns"`
+	Search     string `form:"q"`
+	SkillName  string `form:"skill_name"`
+	SkillLevel string `form:"skill_level"`
+}
+
+// exportContacts godoc
+// @Security bearerAuth
+// @Summary Export contacts with their skills as CSV or XLSX
+// @Tags Contact
+// @Description This function is used to download the contacts of an user with their skills as a CSV file or an Excel
+// @Description workbook, one contact per row. The skills of a contact are written in a single column as
+// @Description "name (level)". The contacts can be filtered by a text found in their full name or email and by a
+// @Description skill they have. The file is streamed while the contacts are read.
+// @Accept application/x-www-form-urlencoded
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "format" Enums(csv, xlsx)
+// @Param columns query string false "comma separated columns, e.g : fullname,email,skills"
+// @Param q query string false "q"
+// @Param skill_name query string false "skill_name"
+// @Param skill_level query string false "skill_level"
+// @Success 200 {string} string
+// @Router /contacts/export [get]
+func (server *Server) exportContacts(ctx *gin.Context) {
+	var req exportContactsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	columns, err := parseExportColumns(req.Columns)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if req.Format == "" {
+		req.Format = exportFormatCSV
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Payload)
+	arg := database.ExportContactsParams{
+		Owner:      authPayload.Username,
+		Search:     strings.TrimSpace(req.Search),
+		SkillName:  strings.TrimSpace(req.SkillName),
+		SkillLevel: strings.TrimSpace(req.SkillLevel),
+	}
+
+	// The encoder is started with the first row so that an error of the query can still be answered with a status.
+	var enc contactEncoder
+	err = server.database.ExportContacts(ctx, arg, func(row db.ListContactsWithSkillsRow) error {
+		if enc == nil {
+			var err error
+			if enc, err = newContactEncoder(ctx, req.Format, columns); err != nil {
+				return err
+			}
+		}
+		return enc.encode(row)
+	})
+	if err == nil && enc == nil {
+		enc, err = newContactEncoder(ctx, req.Format, columns)
+	}
+	if err == nil {
+		err = enc.close()
+	}
+	if err != nil {
+		if ctx.Writer.Written() {
+			// The file is already on its way, it can only be cut short.
+			ctx.Error(err)
+			ctx.Abort()
+			return
+		}
+		ctx.Writer.Header().Del("Content-Type")
+		ctx.Writer.Header().Del("Content-Disposition")
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+	}
+}
+
+// parseExportColumns reads the list of columns to export, every column can be exported once.
+func parseExportColumns(list string) ([]string, error) {
+	if strings.TrimSpace(list) == "" {
+		return exportColumns, nil
+	}
+
+	var columns []string
+	seen := map[string]bool{}
+	for _, column := range strings.Split(list, ",") {
+		column = strings.ToLower(strings.TrimSpace(column))
+		if !isExportColumn(column) {
+			return nil, fmt.Errorf("unknown column %q, the columns are %s", column, strings.Join(exportColumns, ", "))
+		}
+		if seen[column] {
+			return nil, fmt.Errorf("column %q is listed twice", column)
+		}
+		seen[column] = true
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// isExportColumn tells whether the column can be exported.
+func isExportColumn(column string) bool {
+	for _, c := range exportColumns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+// exportValue returns the value of a column for a contact.
+func exportValue(row db.ListContactsWithSkillsRow, column string) string {
+	switch column {
+	case "id":
+		return strconv.FormatInt(row.ID, 10)
+	case "firstname":
+		return row.Firstname
+	case "lastname":
+		return row.Lastname
+	case "fullname":
+		return row.Fullname
+	case "home_address":
+		return row.HomeAddress
+	case "email":
+		return row.Email
+	case "phone_number":
+		return row.PhoneNumber
+	case "skills":
+		skills := make([]string, len(row.SkillNames))
+		for i := range row.SkillNames {
+			skills[i] = skillCategory(row.SkillNames[i], row.SkillLevels[i])
+		}
+		return strings.Join(skills, ", ")
+	}
+	return ""
+}
+
+// contactEncoder writes the exported contacts to the response, one row per contact.
+type contactEncoder interface {
+	encode(row db.ListContactsWithSkillsRow) error
+	// close writes what is left of the file.
+	close() error
+}
+
+// newContactEncoder sets the headers of the file to download and writes its header row.
+func newContactEncoder(ctx *gin.Context, format string, columns []string) (contactEncoder, error) {
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="contacts.%s"`, format))
+
+	if format == exportFormatXLSX {
+		ctx.Header("Content-Type", xlsx.MediaType)
+		enc := &xlsxContactEncoder{w: xlsx.NewWriter(ctx.Writer, "Contacts"), columns: columns}
+		cells := make([]xlsx.Cell, len(columns))
+		for i, column := range columns {
+			cells[i] = xlsx.String(column)
+		}
+		return enc, enc.w.WriteRow(cells...)
+	}
+
+	ctx.Header("Content-Type", csvContentType)
+	enc := &csvContactEncoder{w: csv.NewWriter(ctx.Writer), columns: columns}
+	return enc, enc.w.Write(columns)
+}
+
+type csvContactEncoder struct {
+	w       *csv.Writer
+	columns []string
+}
+
+func (enc *csvContactEncoder) encode(row db.ListContactsWithSkillsRow) error {
+	record := make([]string, len(enc.columns))
+	for i, column := range enc.columns {
+		record[i] = escapeCSVFormula(exportValue(row, column))
+	}
+	return enc.w.Write(record)
+}
+
+// escapeCSVFormula prefixes with a quote a value that a spreadsheet would read as a formula when the CSV file is
+// opened. The cells of a workbook are written as texts, they don't need it.
+func escapeCSVFormula(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func (enc *csvContactEncoder) close() error {
+	enc.w.Flush()
+	return enc.w.Error()
+}
+
+type xlsxContactEncoder struct {
+	w       *xlsx.Writer
+	columns []string
+}
+
+// encode writes the id as a number and the other columns as texts, so that phone numbers keep their leading zeros.
+func (enc *xlsxContactEncoder) encode(row db.ListContactsWithSkillsRow) error {
+	cells := make([]xlsx.Cell, len(enc.columns))
+	for i, column := range enc.columns {
+		if column == "id" {
+			cells[i] = xlsx.Int(row.ID)
+			continue
+		}
+		cells[i] = xlsx.String(exportValue(row, column))
+	}
+	return enc.w.WriteRow(cells...)
+}
+
+func (enc *xlsxContactEncoder) close() error {
+	return enc.w.Close()
+}
@@ -0,0 +1,292 @@
+package api
+
+import (
+	"archive/zip"
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	auth "github.com/IsuruHaupe/web-api/auth/token"
+	dbtx "github.com/IsuruHaupe/web-api/db/database"
+	mockdb "github.com/IsuruHaupe/web-api/db/mock"
+	db "github.com/IsuruHaupe/web-api/db/sqlc"
+	"github.com/IsuruHaupe/web-api/xlsx"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// exportRows stubs the export of the rows, the rows are handed one by one as the database does.
+func exportRows(rows []db.ListContactsWithSkillsRow, err error) func(interface{}, dbtx.ExportContactsParams, func(db.ListContactsWithSkillsRow) error) error {
+	return func(_ interface{}, _ dbtx.ExportContactsParams, fn func(db.ListContactsWithSkillsRow) error) error {
+		for _, row := range rows {
+			if err := fn(row); err != nil {
+				return err
+			}
+		}
+		return err
+	}
+}
+
+func readCSVExport(t *testing.T, body *bytes.Buffer) [][]string {
+	records, err := csv.NewReader(body).ReadAll()
+	require.NoError(t, err)
+	return records
+}
+
+func TestExportContactsAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	rows := []db.ListContactsWithSkillsRow{
+		{ID: 1, Owner: user.Username, Firstname: "Ada", Lastname: "Lovelace", Fullname: "Ada Lovelace",
+			HomeAddress: "12 St James's Square, London", Email: "ada@example.com", PhoneNumber: "020 7946 0000",
+			SkillNames: []string{"Mathematics", "Poetry"}, SkillLevels: []string{"expert", "beginner"}},
+		{ID: 2, Owner: user.Username, Firstname: "Grace", Lastname: "Hopper", Fullname: "Grace Hopper",
+			HomeAddress: "Arlington", Email: "grace@example.com", PhoneNumber: "+1 703 555 0100",
+			SkillNames: []string{}, SkillLevels: []string{}},
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker auth.Maker)
+		buildStubs    func(database *mockdb.MockDatabase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleViewer, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					ExportContacts(gomock.Any(), gomock.Eq(dbtx.ExportContactsParams{Owner: user.Username}), gomock.Any()).
+					Times(1).
+					DoAndReturn(exportRows(rows, nil))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, csvContentType, recorder.Header().Get("Content-Type"))
+				require.Equal(t, `attachment; filename="contacts.csv"`, recorder.Header().Get("Content-Disposition"))
+
+				require.Equal(t, [][]string{
+					exportColumns,
+					{"1", "Ada", "Lovelace", "Ada Lovelace", "12 St James's Square, London", "ada@example.com", "020 7946 0000",
+						"Mathematics (expert), Poetry (beginner)"},
+					{"2", "Grace", "Hopper", "Grace Hopper", "Arlington", "grace@example.com", "'+1 703 555 0100", ""},
+				}, readCSVExport(t, recorder.Body))
+			},
+		},
+		{
+			name:  "Columns And Filters",
+			query: "?columns=fullname,+Skills,email&q=+ada+&skill_name=mathematics&skill_level=expert",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleViewer, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				arg := dbtx.ExportContactsParams{
+					Owner:      user.Username,
+					Search:     "ada",
+					SkillName:  "mathematics",
+					SkillLevel: "expert",
+				}
+				database.EXPECT().
+					ExportContacts(gomock.Any(), gomock.Eq(arg), gomock.Any()).
+					Times(1).
+					DoAndReturn(exportRows(rows[:1], nil))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, [][]string{
+					{"fullname", "skills", "email"},
+					{"Ada Lovelace", "Mathematics (expert), Poetry (beginner)", "ada@example.com"},
+				}, readCSVExport(t, recorder.Body))
+			},
+		},
+		{
+			name:  "XLSX",
+			query: "?format=xlsx&columns=id,phone_number",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleViewer, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					ExportContacts(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(exportRows(rows, nil))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, xlsx.MediaType, recorder.Header().Get("Content-Type"))
+				require.Equal(t, `attachment; filename="contacts.xlsx"`, recorder.Header().Get("Content-Disposition"))
+
+				data := recorder.Body.Bytes()
+				zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+				require.NoError(t, err)
+				sheet, err := zr.Open("xl/worksheets/sheet1.xml")
+				require.NoError(t, err)
+				content, err := io.ReadAll(sheet)
+				require.NoError(t, err)
+
+				require.Contains(t, string(content), `<c r="B1" t="inlineStr"><is><t xml:space="preserve">phone_number</t></is></c>`)
+				require.Contains(t, string(content), `<c r="A2"><v>1</v></c>`)
+				require.Contains(t, string(content), `<c r="B2" t="inlineStr"><is><t xml:space="preserve">020 7946 0000</t></is></c>`)
+				require.Contains(t, string(content), `<row r="3">`)
+				require.NotContains(t, string(content), `<row r="4">`)
+			},
+		},
+		{
+			name:  "Formulas",
+			query: "?columns=firstname,lastname,fullname,home_address,email,phone_number",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleViewer, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				row := db.ListContactsWithSkillsRow{ID: 3, Owner: user.Username, Firstname: "=HYPERLINK(\"http://example.com\")",
+					Lastname: "-2+3", Fullname: "@SUM(A1:A2)", HomeAddress: "\tLondon", Email: "\rbob@example.com",
+					PhoneNumber: "0 = 1", SkillNames: []string{}, SkillLevels: []string{}}
+				database.EXPECT().
+					ExportContacts(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(exportRows([]db.ListContactsWithSkillsRow{row}, nil))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, [][]string{
+					{"firstname", "lastname", "fullname", "home_address", "email", "phone_number"},
+					{"'=HYPERLINK(\"http://example.com\")", "'-2+3", "'@SUM(A1:A2)", "'\tLondon", "'\rbob@example.com", "0 = 1"},
+				}, readCSVExport(t, recorder.Body))
+			},
+		},
+		{
+			name: "No Contacts",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleViewer, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					ExportContacts(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(exportRows(nil, nil))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, [][]string{exportColumns}, readCSVExport(t, recorder.Body))
+			},
+		},
+		{
+			name:  "Unknown Column",
+			query: "?columns=fullname,password",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleViewer, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().ExportContacts(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "Column Listed Twice",
+			query: "?columns=email,EMAIL",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleViewer, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().ExportContacts(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "Invalid Format",
+			query: "?format=pdf",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleViewer, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().ExportContacts(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "No Authorization",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().ExportContacts(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Internal Error",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleViewer, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					ExportContacts(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(exportRows(rows, sql.ErrConnDone))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				// The rows are still buffered when the query fails, so the error can be answered.
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+				require.Contains(t, recorder.Header().Get("Content-Type"), "application/json")
+				require.Empty(t, recorder.Header().Get("Content-Disposition"))
+			},
+		},
+		{
+			name: "Internal Error While Streaming",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleViewer, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				many := make([]db.ListContactsWithSkillsRow, 1000)
+				for i := range many {
+					many[i] = rows[0]
+				}
+				database.EXPECT().
+					ExportContacts(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(exportRows(many, sql.ErrConnDone))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, csvContentType, recorder.Header().Get("Content-Type"))
+				require.NotContains(t, recorder.Body.String(), sql.ErrConnDone.Error())
+			},
+		},
+	}
+
+	for i := range testCases {
+		currentTest := testCases[i]
+
+		t.Run(currentTest.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			database := mockdb.NewMockDatabase(ctrl)
+			currentTest.buildStubs(database)
+
+			server := newTestServer(t, database)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/contacts/export"+currentTest.query, nil)
+			require.NoError(t, err)
+
+			currentTest.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			currentTest.checkResponse(t, recorder)
+		})
+	}
+}
@@ -42,7 +42,7 @@ func TestCreateContactHasSkillAPI(t *testing.T) {
 				"skill_id":   skill.ID,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				arg := db.CreateContactHasSkillParams{
@@ -89,7 +89,7 @@ func TestCreateContactHasSkillAPI(t *testing.T) {
 				"skill_id":   skill.ID,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockDatabase) {
 				store.EXPECT().
@@ -108,7 +108,7 @@ func TestCreateContactHasSkillAPI(t *testing.T) {
 				"skill_id":   skill.ID,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockDatabase) {
 				store.EXPECT().
@@ -0,0 +1,157 @@
+package api
+
+import (
+	"html"
+	"net/http"
+	"sort"
+	"strings"
+
+	auth "github.com/IsuruHaupe/web-api/auth/token"
+	db "github.com/IsuruHaupe/web-api/db/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+// Request holder for searching contacts request.
+type searchContactsRequest struct {
+	Query    string `form:"q" binding:"required,max=200"`
+	PageID   int32  `form:"page_id" binding:"required,min=1"`
+	PageSize int32  `form:"page_size" binding:"required,min=1,max=10"`
+}
+
+// This is the expected returned response for each contact found. Highlights holds the fields containing
+// a searched term, HTML escaped and with the terms surrounded by <mark> tags.
+type contactSearchResult struct {
+	Contact    db.Contact        `json:"contact"`
+	Rank       float32           `json:"rank"`
+	Highlights map[string]string `json:"highlights"`
+}
+
+// searchContacts godoc
+// @Security bearerAuth
+// @Summary Search contacts
+// @Tags Contact
+// @Description This function is used to search the contacts of an user by name, email, phone number or address.
+// @Description The search supports the web search syntax ("quoted phrases", or, -excluded) and tolerates typos,
+// @Description results are ranked with matches on the name first.
+// @Accept application/x-www-form-urlencoded
+// @Produce json
+// @Param q query string true "q"
+// @Param page_id query int true "page_id"
+// @Param page_size query int true "page_size"
+// @Success 200 {array} api.contactSearchResult
+// @Router /contacts/search [get]
+func (server *Server) searchContacts(ctx *gin.Context) {
+	var req searchContactsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// The search is always restricted to the contacts of the user.
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Payload)
+	rows, err := server.database.SearchContacts(ctx, db.SearchContactsParams{
+		Query:       req.Query,
+		Owner:       authPayload.Username,
+		LimitCount:  req.PageSize,
+		OffsetCount: (req.PageID - 1) * req.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	terms := searchTerms(req.Query)
+	results := make([]contactSearchResult, 0, len(rows))
+	for _, row := range rows {
+		contact := db.Contact{
+			ID:          row.ID,
+			Owner:       row.Owner,
+			Firstname:   row.Firstname,
+			Lastname:    row.Lastname,
+			Fullname:    row.Fullname,
+			HomeAddress: row.HomeAddress,
+			Email:       row.Email,
+			PhoneNumber: row.PhoneNumber,
+		}
+		results = append(results, contactSearchResult{
+			Contact:    contact,
+			Rank:       row.Rank,
+			Highlights: highlightContact(contact, terms),
+		})
+	}
+
+	ctx.JSON(http.StatusOK, results)
+}
+
+// This function will extract the terms to highlight from a web search query, excluded terms and the or operator are skipped.
+// Longer terms come first so that they are preferred over the shorter terms they contain.
+func searchTerms(query string) []string {
+	var terms []string
+	for _, word := range strings.Fields(strings.ToLower(strings.ReplaceAll(query, `"`, " "))) {
+		if word == "or" || strings.HasPrefix(word, "-") {
+			continue
+		}
+		terms = append(terms, word)
+	}
+	sort.SliceStable(terms, func(i, j int) bool {
+		return len(terms[i]) > len(terms[j])
+	})
+	return terms
+}
+
+func highlightContact(contact db.Contact, terms []string) map[string]string {
+	fields := map[string]string{
+		"firstname":    contact.Firstname,
+		"lastname":     contact.Lastname,
+		"fullname":     contact.Fullname,
+		"email":        contact.Email,
+		"phone_number": contact.PhoneNumber,
+		"home_address": contact.HomeAddress,
+	}
+
+	highlights := map[string]string{}
+	for name, value := range fields {
+		if highlighted, ok := highlight(value, terms); ok {
+			highlights[name] = highlighted
+		}
+	}
+	return highlights
+}
+
+// This function will surround the occurrences of the terms in the value with <mark> tags, ignoring case.
+// The value is HTML escaped so that the result can be displayed as is.
+func highlight(value string, terms []string) (string, bool) {
+	lowerValue := strings.ToLower(value)
+	// Terms are matched on the lower cased value, whose length may differ from the original for some runes.
+	if len(lowerValue) != len(value) {
+		return "", false
+	}
+
+	var sb strings.Builder
+	found := false
+	start := 0
+	for i := 0; i < len(lowerValue); {
+		matched := ""
+		for _, term := range terms {
+			if strings.HasPrefix(lowerValue[i:], term) {
+				matched = term
+				break
+			}
+		}
+		if matched == "" {
+			i++
+			continue
+		}
+
+		sb.WriteString(html.EscapeString(value[start:i]))
+		sb.WriteString("<mark>")
+		sb.WriteString(html.EscapeString(value[i : i+len(matched)]))
+		sb.WriteString("</mark>")
+		i += len(matched)
+		start = i
+		found = true
+	}
+	sb.WriteString(html.EscapeString(value[start:]))
+
+	return sb.String(), found
+}
@@ -0,0 +1,213 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	auth "github.com/IsuruHaupe/web-api/auth/token"
+	mockdb "github.com/IsuruHaupe/web-api/db/mock"
+	db "github.com/IsuruHaupe/web-api/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchContactsAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	row := db.SearchContactsRow{
+		ID:          1,
+		Owner:       user.Username,
+		Firstname:   "Ada",
+		Lastname:    "Lovelace",
+		Fullname:    "Ada Lovelace",
+		HomeAddress: "12 St James's Square, London",
+		Email:       "ada@example.com",
+		PhoneNumber: "+44 20 7946 0000",
+		Rank:        0.8,
+	}
+
+	type Query struct {
+		q        string
+		pageID   int
+		pageSize int
+	}
+
+	testCases := []struct {
+		name          string
+		query         Query
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker auth.Maker)
+		buildStubs    func(database *mockdb.MockDatabase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: Query{q: "lovelace london", pageID: 2, pageSize: 5},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleViewer, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				arg := db.SearchContactsParams{
+					Query:       "lovelace london",
+					Owner:       user.Username,
+					LimitCount:  5,
+					OffsetCount: 5,
+				}
+				database.EXPECT().
+					SearchContacts(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.SearchContactsRow{row}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var results []contactSearchResult
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &results))
+				require.Len(t, results, 1)
+				require.Equal(t, row.ID, results[0].Contact.ID)
+				require.Equal(t, row.Email, results[0].Contact.Email)
+				require.Equal(t, row.Rank, results[0].Rank)
+				require.Equal(t, map[string]string{
+					"lastname":     "<mark>Lovelace</mark>",
+					"fullname":     "Ada <mark>Lovelace</mark>",
+					"home_address": "12 St James&#39;s Square, <mark>London</mark>",
+				}, results[0].Highlights)
+			},
+		},
+		{
+			name:  "No Results",
+			query: Query{q: "nobody", pageID: 1, pageSize: 5},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleViewer, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					SearchContacts(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.SearchContactsRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, "[]", recorder.Body.String())
+			},
+		},
+		{
+			name:  "Missing Query",
+			query: Query{pageID: 1, pageSize: 5},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleViewer, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					SearchContacts(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "Invalid Page Size",
+			query: Query{q: "ada", pageID: 1, pageSize: 100},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleViewer, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					SearchContacts(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "No Authorization",
+			query: Query{q: "ada", pageID: 1, pageSize: 5},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					SearchContacts(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:  "Internal Error",
+			query: Query{q: "ada", pageID: 1, pageSize: 5},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleViewer, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					SearchContacts(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.SearchContactsRow{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		currentTest := testCases[i]
+
+		t.Run(currentTest.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			database := mockdb.NewMockDatabase(ctrl)
+			currentTest.buildStubs(database)
+
+			server := newTestServer(t, database)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/contacts/search", nil)
+			require.NoError(t, err)
+
+			q := request.URL.Query()
+			if currentTest.query.q != "" {
+				q.Add("q", currentTest.query.q)
+			}
+			q.Add("page_id", fmt.Sprintf("%d", currentTest.query.pageID))
+			q.Add("page_size", fmt.Sprintf("%d", currentTest.query.pageSize))
+			request.URL.RawQuery = q.Encode()
+
+			currentTest.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			currentTest.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	testCases := []struct {
+		query       string
+		value       string
+		highlighted string
+		found       bool
+	}{
+		{`ada`, "Ada Lovelace", "<mark>Ada</mark> Lovelace", true},
+		{`"ada lovelace"`, "Ada Lovelace", "<mark>Ada</mark> <mark>Lovelace</mark>", true},
+		{`love lovelace`, "Ada Lovelace", "Ada <mark>Lovelace</mark>", true},
+		{`ada or grace -lovelace`, "Grace Lovelace", "<mark>Grace</mark> Lovelace", true},
+		{`<b>`, "<b>bold</b>", "<mark>&lt;b&gt;</mark>bold&lt;/b&gt;", true},
+		{`turing`, "Ada <Lovelace>", "Ada &lt;Lovelace&gt;", false},
+		{`zoé`, "Zoé Dupont", "<mark>Zoé</mark> Dupont", true},
+	}
+
+	for _, tc := range testCases {
+		highlighted, found := highlight(tc.value, searchTerms(tc.query))
+		require.Equal(t, tc.found, found, tc.query)
+		if found {
+			require.Equal(t, tc.highlighted, highlighted, tc.query)
+		}
+	}
+}
@@ -35,7 +35,24 @@ func TestGetContactAPI(t *testing.T) {
 			name:      "Pass",
 			contactID: contact.ID,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetContact(gomock.Any(), gomock.Eq(contact.ID)).
+					Times(1).
+					Return(contact, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchContact(t, recorder.Body, contact)
+			},
+		},
+		{
+			name:      "Viewer",
+			contactID: contact.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleViewer, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				database.EXPECT().
@@ -52,7 +69,7 @@ func TestGetContactAPI(t *testing.T) {
 			name:      "Unauthorized User",
 			contactID: contact.ID,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "unauthorized_user", time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "unauthorized_user", auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				database.EXPECT().
@@ -82,7 +99,7 @@ func TestGetContactAPI(t *testing.T) {
 			name:      "Not Found",
 			contactID: contact.ID,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				database.EXPECT().
@@ -98,7 +115,7 @@ func TestGetContactAPI(t *testing.T) {
 			name:      "Internal Error",
 			contactID: contact.ID,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				database.EXPECT().
@@ -114,7 +131,7 @@ func TestGetContactAPI(t *testing.T) {
 			name:      "Bad Request",
 			contactID: 0,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				database.EXPECT().
@@ -175,7 +192,7 @@ func TestGetContactSkillsAPI(t *testing.T) {
 			name:      "Pass",
 			contactID: contact.ID,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				database.EXPECT().
@@ -192,7 +209,7 @@ func TestGetContactSkillsAPI(t *testing.T) {
 			name:      "Unauthorized User",
 			contactID: contact.ID,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "unauthorized_user", time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "unauthorized_user", auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				database.EXPECT().
@@ -222,7 +239,7 @@ func TestGetContactSkillsAPI(t *testing.T) {
 			name:      "Internal Error",
 			contactID: contact.ID,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				database.EXPECT().
@@ -238,7 +255,7 @@ func TestGetContactSkillsAPI(t *testing.T) {
 			name:      "Bad Request",
 			contactID: 0,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				database.EXPECT().
@@ -253,7 +270,7 @@ func TestGetContactSkillsAPI(t *testing.T) {
 			name:      "Not Found",
 			contactID: contact.ID,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				database.EXPECT().
@@ -321,7 +338,7 @@ func TestGetContactsWithSkillAPI(t *testing.T) {
 				skillName: "Go",
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 
@@ -341,7 +358,7 @@ func TestGetContactsWithSkillAPI(t *testing.T) {
 				skillName: "Go",
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "unauthorized_user", time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "unauthorized_user", auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				database.EXPECT().
@@ -375,7 +392,7 @@ func TestGetContactsWithSkillAPI(t *testing.T) {
 				skillName: "10",
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				database.EXPECT().
@@ -393,7 +410,7 @@ func TestGetContactsWithSkillAPI(t *testing.T) {
 				skillName: "",
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				database.EXPECT().
@@ -410,7 +427,7 @@ func TestGetContactsWithSkillAPI(t *testing.T) {
 				skillName: "Scala",
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				database.EXPECT().
@@ -480,7 +497,7 @@ func TestGetContactsWithSkillAndLevelAPI(t *testing.T) {
 				skillLevel: "Proficient",
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				arg := db.GetContactsWithSkillAndLevelParams{
@@ -504,7 +521,7 @@ func TestGetContactsWithSkillAndLevelAPI(t *testing.T) {
 				skillLevel: "Proficient",
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "unauthorized_user", time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "unauthorized_user", auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				arg := db.GetContactsWithSkillAndLevelParams{
@@ -548,7 +565,7 @@ func TestGetContactsWithSkillAndLevelAPI(t *testing.T) {
 				skillLevel: "Proficient",
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				arg := db.GetContactsWithSkillAndLevelParams{
@@ -571,7 +588,7 @@ func TestGetContactsWithSkillAndLevelAPI(t *testing.T) {
 				skillLevel: "Proficient",
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				arg := db.GetContactsWithSkillAndLevelParams{
@@ -593,7 +610,7 @@ func TestGetContactsWithSkillAndLevelAPI(t *testing.T) {
 				skillLevel: "Proficient",
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				arg := db.GetContactsWithSkillAndLevelParams{
@@ -662,7 +679,7 @@ func TestCreateContactAPI(t *testing.T) {
 				"phone_number": contact.PhoneNumber,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				arg := db.CreateContactParams{
@@ -685,6 +702,28 @@ func TestCreateContactAPI(t *testing.T) {
 				requireBodyMatchContact(t, recorder.Body, contact)
 			},
 		},
+		{
+			name: "Viewer",
+			body: gin.H{
+				"firstname":    contact.Firstname,
+				"lastname":     contact.Lastname,
+				"fullname":     contact.Fullname,
+				"home_address": contact.HomeAddress,
+				"email":        contact.Email,
+				"phone_number": contact.PhoneNumber,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleViewer, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					CreateContact(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
 		{
 			name: "No Authorization",
 			body: gin.H{
@@ -727,7 +766,7 @@ func TestCreateContactAPI(t *testing.T) {
 				"phone_number": contact.PhoneNumber,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockDatabase) {
 				store.EXPECT().
@@ -750,7 +789,7 @@ func TestCreateContactAPI(t *testing.T) {
 				"phone_number": contact.PhoneNumber,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockDatabase) {
 				store.EXPECT().
@@ -820,7 +859,7 @@ func TestListContactsAPI(t *testing.T) {
 				pageSize: n,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				arg := db.ListContactsParams{
@@ -863,7 +902,7 @@ func TestListContactsAPI(t *testing.T) {
 				pageSize: n,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				database.EXPECT().
@@ -882,7 +921,7 @@ func TestListContactsAPI(t *testing.T) {
 				pageSize: n,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				database.EXPECT().
@@ -900,7 +939,7 @@ func TestListContactsAPI(t *testing.T) {
 				pageSize: 100000,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				database.EXPECT().
@@ -958,7 +997,7 @@ func TestDeleteContactAPI(t *testing.T) {
 			name:      "Pass",
 			contactID: contact.ID,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				database.EXPECT().
@@ -979,7 +1018,7 @@ func TestDeleteContactAPI(t *testing.T) {
 			name:      "Unauthorized User",
 			contactID: contact.ID,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "unauthorized_user", time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "unauthorized_user", auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				database.EXPECT().
@@ -1017,7 +1056,7 @@ func TestDeleteContactAPI(t *testing.T) {
 			name:      "Internal Error",
 			contactID: contact.ID,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				database.EXPECT().
@@ -1038,7 +1077,7 @@ func TestDeleteContactAPI(t *testing.T) {
 			name:      "Bad Request",
 			contactID: 0,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				database.EXPECT().
@@ -1097,7 +1136,7 @@ func TestUpdateContactAPI(t *testing.T) {
 				"firstname": "Isuru",
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				database.EXPECT().
@@ -1126,7 +1165,7 @@ func TestUpdateContactAPI(t *testing.T) {
 				"firstname": "Isuru",
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "unauthorized_user", time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "unauthorized_user", auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				database.EXPECT().
@@ -1178,7 +1217,7 @@ func TestUpdateContactAPI(t *testing.T) {
 				"firstname": "Isuru",
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				database.EXPECT().
@@ -1196,7 +1235,7 @@ func TestUpdateContactAPI(t *testing.T) {
 				"firstname": "Isuru",
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				database.EXPECT().
@@ -1222,7 +1261,7 @@ func TestUpdateContactAPI(t *testing.T) {
 				"firstname": "Isuru",
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				database.EXPECT().
@@ -0,0 +1,312 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	auth "github.com/IsuruHaupe/web-api/auth/token"
+	"github.com/IsuruHaupe/web-api/db/database"
+	db "github.com/IsuruHaupe/web-api/db/sqlc"
+	"github.com/IsuruHaupe/web-api/vcard"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/lib/pq"
+)
+
+const (
+	vcardExtension   = ".vcf"
+	vcardContentType = vcard.MediaType + "; charset=utf-8"
+	// Uploads bigger than this are refused, it is a few thousands of cards without photos.
+	maxVCardImportSize = 2 << 20
+)
+
+// contactCard converts a contact and its skills to a vCard, skills are written as categories.
+func contactCard(contact db.Contact, skillNames, skillLevels []string) vcard.Card {
+	card := vcard.Card{
+		FormattedName: contact.Fullname,
+		FamilyName:    contact.Lastname,
+		GivenName:     contact.Firstname,
+		Email:         contact.Email,
+		Telephone:     contact.PhoneNumber,
+		Address:       contact.HomeAddress,
+	}
+	for i := range skillNames {
+		card.Categories = append(card.Categories, skillCategory(skillNames[i], skillLevels[i]))
+	}
+	return card
+}
+
+// skillCategory writes a skill as a category : "name (level)".
+func skillCategory(name, level string) string {
+	return fmt.Sprintf("%s (%s)", name, level)
+}
+
+// parseSkillCategory reads a category written by skillCategory, ok is false for categories without a level.
+func parseSkillCategory(category string) (name string, level string, ok bool) {
+	open := strings.LastIndex(category, " (")
+	if open < 0 || !strings.HasSuffix(category, ")") {
+		return "", "", false
+	}
+	name = strings.TrimSpace(category[:open])
+	level = strings.TrimSpace(category[open+2 : len(category)-1])
+	return name, level, name != "" && level != ""
+}
+
+// writeVCards sends the cards as a vCard file to download.
+func writeVCards(ctx *gin.Context, filename string, cards []vcard.Card) {
+	var buf bytes.Buffer
+	enc := vcard.NewEncoder(&buf)
+	for _, card := range cards {
+		if err := enc.Encode(card); err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	ctx.Data(http.StatusOK, vcardContentType, buf.Bytes())
+}
+
+// getContactVCard godoc
+// @Security bearerAuth
+// @Summary Get a contact as a vCard
+// @Tags Contact
+// @Description This function is used to get a contact of an user as a vCard 4.0 file, its skills are written as categories.
+// @Accept application/x-www-form-urlencoded
+// @Produce text/vcard
+// @Param id path int true "id"
+// @Success 200 {string} string
+// @Router /contacts/{id}.vcf [get]
+func (server *Server) getContactVCard(ctx *gin.Context) {
+	id, err := strconv.ParseInt(strings.TrimSuffix(ctx.Param("id"), vcardExtension), 10, 64)
+	if err != nil || id < 1 {
+		err := errors.New("invalid contact id")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	contact, valid := server.getOwnedContact(ctx, id)
+	if !valid {
+		return
+	}
+
+	skills, err := server.database.GetContactSkills(ctx, int32(contact.ID))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	skillNames := make([]string, len(skills))
+	skillLevels := make([]string, len(skills))
+	for i, skill := range skills {
+		skillNames[i], skillLevels[i] = skill.SkillName, skill.SkillLevel
+	}
+
+	writeVCards(ctx, fmt.Sprintf("contact-%d%s", contact.ID, vcardExtension), []vcard.Card{
+		contactCard(contact, skillNames, skillLevels),
+	})
+}
+
+// exportContactsVCard godoc
+// @Security bearerAuth
+// @Summary Export all contacts as vCards
+// @Tags Contact
+// @Description This function is used to export all the contacts of an user in a single vCard 4.0 file, their skills are
+// @Description written as categories.
+// @Accept application/x-www-form-urlencoded
+// @Produce text/vcard
+// @Success 200 {string} string
+// @Router /contacts/export.vcf [get]
+func (server *Server) exportContactsVCard(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Payload)
+	rows, err := server.database.ListContactsWithSkills(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	cards := make([]vcard.Card, len(rows))
+	for i, row := range rows {
+		contact := db.Contact{
+			ID:          row.ID,
+			Owner:       row.Owner,
+			Firstname:   row.Firstname,
+			Lastname:    row.Lastname,
+			Fullname:    row.Fullname,
+			HomeAddress: row.HomeAddress,
+			Email:       row.Email,
+			PhoneNumber: row.PhoneNumber,
+		}
+		cards[i] = contactCard(contact, row.SkillNames, row.SkillLevels)
+	}
+
+	writeVCards(ctx, "contacts"+vcardExtension, cards)
+}
+
+// This is the expected returned response for each card of an imported file. Index is the position of the card in the
+// file starting at 1, Error is set when the card was not imported.
+type importedCardResponse struct {
+	Index    int         `json:"index"`
+	Contact  *db.Contact `json:"contact,omitempty"`
+	Skills   []db.Skill  `json:"skills,omitempty"`
+	Warnings []string    `json:"warnings,omitempty"`
+	Error    string      `json:"error,omitempty"`
+}
+
+// This is the expected returned response after importing a vCard file.
+type importContactsResponse struct {
+	Imported int                    `json:"imported"`
+	Failed   int                    `json:"failed"`
+	Cards    []importedCardResponse `json:"cards"`
+}
+
+// importContactsVCard godoc
+// @Security bearerAuth
+// @Summary Import contacts from vCards
+// @Tags Contact
+// @Description This function is used to create contacts from a vCard 3.0 or 4.0 file holding one or more cards. Categories
+// @Description written as "name (level)" are bound to the skill of that name and level, which is created if needed. They
+// @Description are ignored with a warning when the API key or OAuth2 client lacks the skills:write scope.
+// @Description Each card is imported on its own : the cards that can't be read or miss a field of a contact are reported
+// @Description and don't prevent the other cards from being imported.
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "vCard file"
+// @Success 200 {object} api.importContactsResponse
+// @Router /contacts/import [post]
+func (server *Server) importContactsVCard(ctx *gin.Context) {
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxVCardImportSize)
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	defer file.Close()
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Payload)
+	// The skills of the cards are created if needed, which the route alone doesn't allow.
+	canWriteSkills := hasScope(ctx, scopeSkillsWrite)
+	rsp := importContactsResponse{Cards: []importedCardResponse{}}
+	dec := vcard.NewDecoder(file)
+	for index := 1; ; index++ {
+		result := importedCardResponse{Index: index}
+
+		card, err := dec.Decode()
+		if err == io.EOF {
+			break
+		}
+		var cardErr *vcard.CardError
+		if errors.As(err, &cardErr) {
+			result.Error = cardErr.Error()
+			rsp.Failed++
+			rsp.Cards = append(rsp.Cards, result)
+			continue
+		}
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+
+		arg, warnings, err := cardImport(authPayload.Username, card)
+		result.Warnings = warnings
+		if err != nil {
+			result.Error = err.Error()
+			rsp.Failed++
+			rsp.Cards = append(rsp.Cards, result)
+			continue
+		}
+		if len(arg.Skills) > 0 && !canWriteSkills {
+			warning := fmt.Sprintf("skills are ignored, the credentials are missing the %s scope", scopeSkillsWrite)
+			result.Warnings = append(result.Warnings, warning)
+			arg.Skills = nil
+		}
+
+		imported, err := server.database.ImportContactTx(ctx, arg)
+		if err != nil {
+			if pqErr, ok := err.(*pq.Error); ok {
+				switch pqErr.Code.Name() {
+				case "foreign_key_violation", "unique_violation":
+					result.Error = err.Error()
+					rsp.Failed++
+					rsp.Cards = append(rsp.Cards, result)
+					continue
+				}
+			}
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		result.Contact = &imported.Contact
+		result.Skills = imported.Skills
+		rsp.Imported++
+		rsp.Cards = append(rsp.Cards, result)
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// cardImport converts a card to the contact and skills to create. The contact must have the same fields as one created
+// with the API, the first and last names are taken from the formatted name when the card has no structured name.
+// The categories which are not skills are reported in the warnings.
+func cardImport(owner string, card vcard.Card) (database.ImportContactTxParams, []string, error) {
+	firstname, lastname := card.GivenName, card.FamilyName
+	if firstname == "" && lastname == "" {
+		if names := strings.Fields(card.FormattedName); len(names) > 0 {
+			firstname, lastname = names[0], strings.Join(names[1:], " ")
+		}
+	}
+
+	req := createContactRequest{
+		Firstname:   firstname,
+		Lastname:    lastname,
+		Fullname:    card.FormattedName,
+		HomeAddress: card.Address,
+		Email:       card.Email,
+		PhoneNumber: card.Telephone,
+	}
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		return database.ImportContactTxParams{}, nil, err
+	}
+
+	arg := database.ImportContactTxParams{
+		Contact: db.CreateContactParams{
+			Owner:       owner,
+			Firstname:   req.Firstname,
+			Lastname:    req.Lastname,
+			Fullname:    req.Fullname,
+			HomeAddress: req.HomeAddress,
+			Email:       req.Email,
+			PhoneNumber: req.PhoneNumber,
+		},
+	}
+
+	var warnings []string
+	seen := map[string]bool{}
+	for _, category := range card.Categories {
+		name, level, ok := parseSkillCategory(category)
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("category %q is not a skill written as \"name (level)\", it is ignored", category))
+			continue
+		}
+		if key := skillCategory(name, level); !seen[key] {
+			seen[key] = true
+			arg.Skills = append(arg.Skills, db.GetOrCreateSkillParams{
+				Owner:      owner,
+				SkillName:  name,
+				SkillLevel: level,
+			})
+		}
+	}
+
+	return arg, warnings, nil
+}
@@ -0,0 +1,532 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	auth "github.com/IsuruHaupe/web-api/auth/token"
+	dbtx "github.com/IsuruHaupe/web-api/db/database"
+	mockdb "github.com/IsuruHaupe/web-api/db/mock"
+	db "github.com/IsuruHaupe/web-api/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetContactVCardAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	contact := db.Contact{
+		ID:          7,
+		Owner:       user.Username,
+		Firstname:   "Ada",
+		Lastname:    "Lovelace",
+		Fullname:    "Ada Lovelace",
+		HomeAddress: "12 St James's Square, London",
+		Email:       "ada@example.com",
+		PhoneNumber: "+44 20 7946 0000",
+	}
+	otherContact := randomContact("other")
+
+	testCases := []struct {
+		name          string
+		id            string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker auth.Maker)
+		buildStubs    func(database *mockdb.MockDatabase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			id:   "7.vcf",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleViewer, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().GetContact(gomock.Any(), gomock.Eq(contact.ID)).Times(1).Return(contact, nil)
+				database.EXPECT().
+					GetContactSkills(gomock.Any(), gomock.Eq(int32(contact.ID))).
+					Times(1).
+					Return([]db.Skill{{ID: 1, Owner: user.Username, SkillName: "Mathematics", SkillLevel: "expert"}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, vcardContentType, recorder.Header().Get("Content-Type"))
+				require.Equal(t, `attachment; filename="contact-7.vcf"`, recorder.Header().Get("Content-Disposition"))
+				require.Equal(t, "BEGIN:VCARD\r\n"+
+					"VERSION:4.0\r\n"+
+					"FN:Ada Lovelace\r\n"+
+					"N:Lovelace;Ada;;;\r\n"+
+					"EMAIL:ada@example.com\r\n"+
+					"TEL;VALUE=text:+44 20 7946 0000\r\n"+
+					"ADR;TYPE=home:;;12 St James's Square\\, London;;;;\r\n"+
+					"CATEGORIES:Mathematics (expert)\r\n"+
+					"END:VCARD\r\n", recorder.Body.String())
+			},
+		},
+		{
+			name: "Invalid ID",
+			id:   "0.vcf",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleViewer, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().GetContact(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Not Found",
+			id:   "7.vcf",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleViewer, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().GetContact(gomock.Any(), gomock.Eq(contact.ID)).Times(1).Return(db.Contact{}, sql.ErrNoRows)
+				database.EXPECT().GetContactSkills(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "Contact Of Another User",
+			id:   fmt.Sprintf("%d.vcf", otherContact.ID),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleViewer, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().GetContact(gomock.Any(), gomock.Eq(otherContact.ID)).Times(1).Return(otherContact, nil)
+				database.EXPECT().GetContactSkills(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "No Authorization",
+			id:   "7.vcf",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().GetContact(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		currentTest := testCases[i]
+
+		t.Run(currentTest.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			database := mockdb.NewMockDatabase(ctrl)
+			currentTest.buildStubs(database)
+
+			server := newTestServer(t, database)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/contacts/"+currentTest.id, nil)
+			require.NoError(t, err)
+
+			currentTest.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			currentTest.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestExportContactsVCardAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	rows := []db.ListContactsWithSkillsRow{
+		{ID: 1, Owner: user.Username, Firstname: "Ada", Lastname: "Lovelace", Fullname: "Ada Lovelace",
+			Email: "ada@example.com", SkillNames: []string{"Mathematics", "Poetry"}, SkillLevels: []string{"expert", "beginner"}},
+		{ID: 2, Owner: user.Username, Firstname: "Grace", Lastname: "Hopper", Fullname: "Grace Hopper",
+			Email: "grace@example.com", SkillNames: []string{}, SkillLevels: []string{}},
+	}
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker auth.Maker)
+		buildStubs    func(database *mockdb.MockDatabase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleViewer, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					ListContactsWithSkills(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(rows, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, vcardContentType, recorder.Header().Get("Content-Type"))
+				require.Equal(t, `attachment; filename="contacts.vcf"`, recorder.Header().Get("Content-Disposition"))
+
+				body := recorder.Body.String()
+				require.Equal(t, 2, strings.Count(body, "BEGIN:VCARD\r\n"))
+				require.Contains(t, body, "FN:Ada Lovelace\r\n")
+				require.Contains(t, body, "CATEGORIES:Mathematics (expert),Poetry (beginner)\r\n")
+				require.Contains(t, body, "FN:Grace Hopper\r\n")
+				require.Equal(t, 1, strings.Count(body, "CATEGORIES"))
+			},
+		},
+		{
+			name: "No Authorization",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().ListContactsWithSkills(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Internal Error",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleViewer, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					ListContactsWithSkills(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.ListContactsWithSkillsRow{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		currentTest := testCases[i]
+
+		t.Run(currentTest.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			database := mockdb.NewMockDatabase(ctrl)
+			currentTest.buildStubs(database)
+
+			server := newTestServer(t, database)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/contacts/export.vcf", nil)
+			require.NoError(t, err)
+
+			currentTest.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			currentTest.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestImportContactsVCardAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	file := "BEGIN:VCARD\r\n" +
+		"VERSION:4.0\r\n" +
+		"FN:Ada Lovelace\r\n" +
+		"N:Lovelace;Ada;;;\r\n" +
+		"EMAIL:ada@example.com\r\n" +
+		"TEL;VALUE=text:+44 20 7946 0000\r\n" +
+		"ADR;TYPE=home:;;12 St James's Square;London;;;\r\n" +
+		"CATEGORIES:Mathematics (expert),Friends,Mathematics (expert)\r\n" +
+		"END:VCARD\r\n" +
+		"BEGIN:VCARD\r\n" +
+		"VERSION:4.0\r\n" +
+		"FN:No Email\r\n" +
+		"TEL;VALUE=text:+1 703 555 0100\r\n" +
+		"ADR;TYPE=home:;;3 Main Street;;;;\r\n" +
+		"END:VCARD\r\n" +
+		"BEGIN:VCARD\r\n" +
+		"FN:No Version\r\n" +
+		"END:VCARD\r\n" +
+		"BEGIN:VCARD\r\n" +
+		"VERSION:3.0\r\n" +
+		"FN:Grace Hopper\r\n" +
+		"EMAIL:grace@example.com\r\n" +
+		"TEL:+1 703 555 0100\r\n" +
+		"ADR:;;3 Main Street;Arlington;;;\r\n" +
+		"END:VCARD\r\n"
+
+	adaArg := dbtx.ImportContactTxParams{
+		Contact: db.CreateContactParams{
+			Owner:       user.Username,
+			Firstname:   "Ada",
+			Lastname:    "Lovelace",
+			Fullname:    "Ada Lovelace",
+			HomeAddress: "12 St James's Square, London",
+			Email:       "ada@example.com",
+			PhoneNumber: "+44 20 7946 0000",
+		},
+		Skills: []db.GetOrCreateSkillParams{
+			{Owner: user.Username, SkillName: "Mathematics", SkillLevel: "expert"},
+		},
+	}
+	graceArg := dbtx.ImportContactTxParams{
+		Contact: db.CreateContactParams{
+			Owner:       user.Username,
+			Firstname:   "Grace",
+			Lastname:    "Hopper",
+			Fullname:    "Grace Hopper",
+			HomeAddress: "3 Main Street, Arlington",
+			Email:       "grace@example.com",
+			PhoneNumber: "+1 703 555 0100",
+		},
+	}
+	clientID := uuid.New()
+	importResult := func(arg dbtx.ImportContactTxParams, id int64) dbtx.ImportContactTxResult {
+		result := dbtx.ImportContactTxResult{
+			Contact: db.Contact{
+				ID:          id,
+				Owner:       arg.Contact.Owner,
+				Firstname:   arg.Contact.Firstname,
+				Lastname:    arg.Contact.Lastname,
+				Fullname:    arg.Contact.Fullname,
+				HomeAddress: arg.Contact.HomeAddress,
+				Email:       arg.Contact.Email,
+				PhoneNumber: arg.Contact.PhoneNumber,
+			},
+			Skills: []db.Skill{},
+		}
+		for i, skill := range arg.Skills {
+			result.Skills = append(result.Skills, db.Skill{ID: int64(i + 1), Owner: skill.Owner, SkillName: skill.SkillName, SkillLevel: skill.SkillLevel})
+		}
+		return result
+	}
+
+	testCases := []struct {
+		name          string
+		file          *string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker auth.Maker)
+		buildStubs    func(database *mockdb.MockDatabase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			file: &file,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				gomock.InOrder(
+					database.EXPECT().
+						ImportContactTx(gomock.Any(), gomock.Eq(adaArg)).
+						Times(1).
+						Return(importResult(adaArg, 1), nil),
+					database.EXPECT().
+						ImportContactTx(gomock.Any(), gomock.Eq(graceArg)).
+						Times(1).
+						Return(importResult(graceArg, 2), nil),
+				)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp importContactsResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, 2, rsp.Imported)
+				require.Equal(t, 2, rsp.Failed)
+				require.Len(t, rsp.Cards, 4)
+
+				require.Equal(t, 1, rsp.Cards[0].Index)
+				require.Equal(t, int64(1), rsp.Cards[0].Contact.ID)
+				require.Len(t, rsp.Cards[0].Skills, 1)
+				require.Len(t, rsp.Cards[0].Warnings, 1)
+				require.Contains(t, rsp.Cards[0].Warnings[0], `"Friends"`)
+				require.Empty(t, rsp.Cards[0].Error)
+
+				require.Nil(t, rsp.Cards[1].Contact)
+				require.Contains(t, rsp.Cards[1].Error, "Email")
+
+				require.Nil(t, rsp.Cards[2].Contact)
+				require.Equal(t, "card at line 16: missing VERSION", rsp.Cards[2].Error)
+
+				require.Equal(t, 4, rsp.Cards[3].Index)
+				require.Equal(t, int64(2), rsp.Cards[3].Contact.ID)
+				require.Empty(t, rsp.Cards[3].Skills)
+			},
+		},
+		{
+			name: "OAuth2 Client Without Skills Scope",
+			file: &file,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addClientAuthorization(t, request, tokenMaker, user.Username, clientID, []string{scopeContactsWrite})
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetActiveOAuthClientOwner(gomock.Any(), gomock.Eq(clientID)).
+					Times(1).
+					Return(user.Username, nil)
+
+				withoutSkills := adaArg
+				withoutSkills.Skills = nil
+				gomock.InOrder(
+					database.EXPECT().
+						ImportContactTx(gomock.Any(), gomock.Eq(withoutSkills)).
+						Times(1).
+						Return(importResult(withoutSkills, 1), nil),
+					database.EXPECT().
+						ImportContactTx(gomock.Any(), gomock.Eq(graceArg)).
+						Times(1).
+						Return(importResult(graceArg, 2), nil),
+				)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp importContactsResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, 2, rsp.Imported)
+				require.Empty(t, rsp.Cards[0].Skills)
+				require.Len(t, rsp.Cards[0].Warnings, 2)
+				require.Contains(t, rsp.Cards[0].Warnings[1], scopeSkillsWrite)
+				require.Empty(t, rsp.Cards[3].Warnings)
+			},
+		},
+		{
+			name: "Duplicate Contact",
+			file: &file,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					ImportContactTx(gomock.Any(), gomock.Eq(adaArg)).
+					Times(1).
+					Return(dbtx.ImportContactTxResult{}, &pq.Error{Code: "23505"})
+				database.EXPECT().
+					ImportContactTx(gomock.Any(), gomock.Eq(graceArg)).
+					Times(1).
+					Return(importResult(graceArg, 2), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp importContactsResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, 1, rsp.Imported)
+				require.Equal(t, 3, rsp.Failed)
+				require.NotEmpty(t, rsp.Cards[0].Error)
+			},
+		},
+		{
+			name: "Missing File",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().ImportContactTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Viewer",
+			file: &file,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleViewer, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().ImportContactTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "Internal Error",
+			file: &file,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					ImportContactTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(dbtx.ImportContactTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		currentTest := testCases[i]
+
+		t.Run(currentTest.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			database := mockdb.NewMockDatabase(ctrl)
+			currentTest.buildStubs(database)
+
+			server := newTestServer(t, database)
+			recorder := httptest.NewRecorder()
+
+			var body bytes.Buffer
+			writer := multipart.NewWriter(&body)
+			if currentTest.file != nil {
+				part, err := writer.CreateFormFile("file", "contacts.vcf")
+				require.NoError(t, err)
+				_, err = part.Write([]byte(*currentTest.file))
+				require.NoError(t, err)
+			}
+			require.NoError(t, writer.Close())
+
+			request, err := http.NewRequest(http.MethodPost, "/contacts/import", &body)
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", writer.FormDataContentType())
+
+			currentTest.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			currentTest.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestParseSkillCategory(t *testing.T) {
+	testCases := []struct {
+		category string
+		name     string
+		level    string
+		ok       bool
+	}{
+		{"Go (expert)", "Go", "expert", true},
+		{"C (ANSI) (beginner)", "C (ANSI)", "beginner", true},
+		{"Friends", "", "", false},
+		{"Go ()", "", "", false},
+		{" (expert)", "", "", false},
+	}
+
+	for _, tc := range testCases {
+		name, level, ok := parseSkillCategory(tc.category)
+		require.Equal(t, tc.ok, ok, tc.category)
+		if ok {
+			require.Equal(t, tc.name, name)
+			require.Equal(t, tc.level, level)
+			require.Equal(t, tc.category, skillCategory(name, level))
+		}
+	}
+}
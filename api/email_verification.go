@@ -0,0 +1,169 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/IsuruHaupe/web-api/auth"
+	token "github.com/IsuruHaupe/web-api/auth/token"
+	db "github.com/IsuruHaupe/web-api/db/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+// Behaviours available through the UNVERIFIED_LOGIN configuration for users who did not verify their email.
+const (
+	unverifiedLoginLimit  = "limit"
+	unverifiedLoginRefuse = "refuse"
+)
+
+var errEmailNotVerified = errors.New("email address has not been verified")
+
+// This function returns the role given to the access tokens of an user. Disabled users are refused, users who did
+// not verify their email are refused or limited to the viewer role depending on the configuration.
+func (server *Server) accessRole(user db.User) (string, error) {
+	if user.DisabledAt.Valid {
+		return "", errAccountDisabled
+	}
+	if user.EmailVerified {
+		return user.Role, nil
+	}
+	if server.config.UnverifiedLogin == unverifiedLoginRefuse {
+		return "", errEmailNotVerified
+	}
+	return token.RoleViewer, nil
+}
+
+// This function will build the signed verification link of the email of an user. The email is part of the
+// signature so that the link can not be used anymore once the email has changed.
+func (server *Server) emailVerificationLink(user db.User) string {
+	expires := strconv.FormatInt(time.Now().Add(server.config.EmailVerificationDuration).Unix(), 10)
+	values := url.Values{}
+	values.Set("username", user.Username)
+	values.Set("expires", expires)
+	values.Set("signature", auth.SignMessage(server.config.EmailVerificationKey, user.Username, user.Email, expires))
+	return server.config.EmailVerificationURL + "?" + values.Encode()
+}
+
+// This function will send the verification link to the email of an user.
+func (server *Server) sendVerificationEmail(user db.User) error {
+	content := fmt.Sprintf(
+		"Hello %s,\n\nPlease verify your email address by opening the following link :\n\n%s\n\nThe link expires in %s. If you did not create an account, you can ignore this email.\n",
+		user.Fullname,
+		server.emailVerificationLink(user),
+		server.config.EmailVerificationDuration,
+	)
+	return server.mailer.SendEmail(user.Email, "Verify your email address", content)
+}
+
+// Request holder when receiving a verify email request, the fields come from the signed link.
+type verifyEmailRequest struct {
+	Username  string `form:"username" binding:"required,alphanum"`
+	Expires   int64  `form:"expires" binding:"required"`
+	Signature string `form:"signature" binding:"required,hexadecimal"`
+}
+
+// verifyEmail godoc
+// @Summary Verify the email of an user
+// @Description This function is used to verify the email of an user with the signed link sent at signup.
+// @Tags user
+// @Produce json
+// @Param username query string true "username"
+// @Param expires query int true "expires"
+// @Param signature query string true "signature"
+// @Success 200 {object} api.userResponse
+// @Router /users/email/verify [get]
+func (server *Server) verifyEmail(ctx *gin.Context) {
+	var req verifyEmailRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if time.Now().Unix() > req.Expires {
+		err := errors.New("verification link has expired")
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	user, err := server.database.GetUser(ctx, req.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	expires := strconv.FormatInt(req.Expires, 10)
+	if !auth.VerifyMessage(server.config.EmailVerificationKey, req.Signature, user.Username, user.Email, expires) {
+		err := errors.New("invalid verification link")
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	if !user.EmailVerified {
+		user, err = server.database.VerifyUserEmail(ctx, db.VerifyUserEmailParams{
+			Username: user.Username,
+			Email:    user.Email,
+		})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, newUserResponse(user))
+}
+
+// Request holder when receiving a resend verification email request.
+type resendVerificationEmailRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// resendVerificationEmail godoc
+// @Summary Resend the verification email
+// @Description This function is used to send a new verification link to the email of an user who did not verify it yet.
+// @Description A link is sent at most once per EMAIL_VERIFICATION_RESEND_INTERVAL and the response does not tell whether it was sent.
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param user body api.resendVerificationEmailRequest true "Resend Verification Email"
+// @Success 200 {string} string "If an unverified account exists for this email, a verification link has been sent."
+// @Router /users/email/verify/resend [post]
+func (server *Server) resendVerificationEmail(ctx *gin.Context) {
+	var req resendVerificationEmailRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	const response = "If an unverified account exists for this email, a verification link has been sent."
+
+	// The update only matches unverified users to whom no link was sent recently, which rate limits the endpoint.
+	user, err := server.database.MarkEmailVerificationSent(ctx, db.MarkEmailVerificationSentParams{
+		Email:      req.Email,
+		SentBefore: time.Now().Add(-server.config.EmailVerificationResendInterval),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusOK, response)
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// A failure is only logged, answering with an error would tell that the email belongs to an unverified user.
+	err = server.sendVerificationEmail(user)
+	if err != nil {
+		ctx.Error(err)
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,396 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	token "github.com/IsuruHaupe/web-api/auth/token"
+	mockdb "github.com/IsuruHaupe/web-api/db/mock"
+	db "github.com/IsuruHaupe/web-api/db/sqlc"
+	mockmail "github.com/IsuruHaupe/web-api/mail/mock"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginUnverifiedUserAPI(t *testing.T) {
+	user, password := randomUser(t)
+	user.EmailVerified = false
+
+	testCases := []struct {
+		name            string
+		unverifiedLogin string
+		buildStubs      func(database *mockdb.MockDatabase)
+		checkResponse   func(server *Server, recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name:            "Limit",
+			unverifiedLogin: unverifiedLoginLimit,
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				database.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1)
+			},
+			checkResponse: func(server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response loginUserResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &response)
+				require.NoError(t, err)
+				require.False(t, response.User.EmailVerified)
+
+				// Unverified users are limited to the viewer role.
+				payload, err := server.tokenMaker.VerifyToken(response.AccessToken, token.TokenTypeAccess)
+				require.NoError(t, err)
+				require.Equal(t, token.RoleViewer, payload.Role)
+			},
+		},
+		{
+			name:            "Refuse",
+			unverifiedLogin: unverifiedLoginRefuse,
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				database.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		currentTest := testCases[i]
+
+		t.Run(currentTest.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			database := mockdb.NewMockDatabase(ctrl)
+			currentTest.buildStubs(database)
+
+			server := newTestServer(t, database)
+			server.config.UnverifiedLogin = currentTest.unverifiedLogin
+			recorder := httptest.NewRecorder()
+
+			// Marshal body data to JSON
+			data, err := json.Marshal(gin.H{
+				"username": user.Username,
+				"password": password,
+			})
+			require.NoError(t, err)
+
+			url := "/users/login"
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			currentTest.checkResponse(server, recorder)
+		})
+	}
+}
+
+func TestVerifyEmailAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	user.EmailVerified = false
+	verifiedUser := user
+	verifiedUser.EmailVerified = true
+
+	testCases := []struct {
+		name          string
+		buildQuery    func(server *Server) url.Values
+		buildStubs    func(database *mockdb.MockDatabase)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildQuery: func(server *Server) url.Values {
+				return verificationLinkQuery(t, server.emailVerificationLink(user))
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				arg := db.VerifyUserEmailParams{
+					Username: user.Username,
+					Email:    user.Email,
+				}
+				database.EXPECT().
+					VerifyUserEmail(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(verifiedUser, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchUser(t, recorder.Body, verifiedUser)
+			},
+		},
+		{
+			name: "Already Verified",
+			buildQuery: func(server *Server) url.Values {
+				return verificationLinkQuery(t, server.emailVerificationLink(user))
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(verifiedUser, nil)
+				database.EXPECT().
+					VerifyUserEmail(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchUser(t, recorder.Body, verifiedUser)
+			},
+		},
+		{
+			name: "Email Changed",
+			buildQuery: func(server *Server) url.Values {
+				return verificationLinkQuery(t, server.emailVerificationLink(user))
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				changedUser := user
+				changedUser.Email = "changed@example.com"
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(changedUser, nil)
+				database.EXPECT().
+					VerifyUserEmail(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Tampered Expiration",
+			buildQuery: func(server *Server) url.Values {
+				query := verificationLinkQuery(t, server.emailVerificationLink(user))
+				query.Set("expires", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+				return query
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				database.EXPECT().
+					VerifyUserEmail(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Expired Link",
+			buildQuery: func(server *Server) url.Values {
+				server.config.EmailVerificationDuration = -time.Minute
+				return verificationLinkQuery(t, server.emailVerificationLink(user))
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Missing Signature",
+			buildQuery: func(server *Server) url.Values {
+				query := verificationLinkQuery(t, server.emailVerificationLink(user))
+				query.Del("signature")
+				return query
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Not Found",
+			buildQuery: func(server *Server) url.Values {
+				return verificationLinkQuery(t, server.emailVerificationLink(user))
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(db.User{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		currentTest := testCases[i]
+
+		t.Run(currentTest.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			database := mockdb.NewMockDatabase(ctrl)
+			currentTest.buildStubs(database)
+
+			server := newTestServer(t, database)
+			recorder := httptest.NewRecorder()
+
+			url := "/users/email/verify?" + currentTest.buildQuery(server).Encode()
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			currentTest.checkResponse(recorder)
+		})
+	}
+}
+
+func TestResendVerificationEmailAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	user.EmailVerified = false
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(database *mockdb.MockDatabase, mailer *mockmail.MockMailer)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"email": user.Email,
+			},
+			buildStubs: func(database *mockdb.MockDatabase, mailer *mockmail.MockMailer) {
+				database.EXPECT().
+					MarkEmailVerificationSent(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.MarkEmailVerificationSentParams) (db.User, error) {
+						require.Equal(t, user.Email, arg.Email)
+						require.WithinDuration(t, time.Now().Add(-time.Minute), arg.SentBefore, time.Second)
+						return user, nil
+					})
+				mailer.EXPECT().
+					SendEmail(gomock.Eq(user.Email), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "Rate Limited Or Verified",
+			body: gin.H{
+				"email": user.Email,
+			},
+			buildStubs: func(database *mockdb.MockDatabase, mailer *mockmail.MockMailer) {
+				database.EXPECT().
+					MarkEmailVerificationSent(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, sql.ErrNoRows)
+				mailer.EXPECT().
+					SendEmail(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "Invalid Email",
+			body: gin.H{
+				"email": "invalid",
+			},
+			buildStubs: func(database *mockdb.MockDatabase, mailer *mockmail.MockMailer) {
+				database.EXPECT().
+					MarkEmailVerificationSent(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Mail Error",
+			body: gin.H{
+				"email": user.Email,
+			},
+			buildStubs: func(database *mockdb.MockDatabase, mailer *mockmail.MockMailer) {
+				database.EXPECT().
+					MarkEmailVerificationSent(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(user, nil)
+				mailer.EXPECT().
+					SendEmail(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				// The response is the same as when no link is sent.
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, `"If an unverified account exists for this email, a verification link has been sent."`, recorder.Body.String())
+			},
+		},
+	}
+
+	for i := range testCases {
+		currentTest := testCases[i]
+
+		t.Run(currentTest.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			database := mockdb.NewMockDatabase(ctrl)
+			mailer := mockmail.NewMockMailer(ctrl)
+			currentTest.buildStubs(database, mailer)
+
+			server := newTestServerWithMailer(t, database, mailer)
+			recorder := httptest.NewRecorder()
+
+			// Marshal body data to JSON
+			data, err := json.Marshal(currentTest.body)
+			require.NoError(t, err)
+
+			url := "/users/email/verify/resend"
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			currentTest.checkResponse(recorder)
+		})
+	}
+}
+
+// verificationLinkQuery returns the query parameters of a verification link.
+func verificationLinkQuery(t *testing.T, link string) url.Values {
+	parsed, err := url.Parse(link)
+	require.NoError(t, err)
+	require.Equal(t, "/users/email/verify", parsed.Path)
+	return parsed.Query()
+}
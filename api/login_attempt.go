@@ -0,0 +1,114 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/IsuruHaupe/web-api/auth"
+	"github.com/IsuruHaupe/web-api/lockout"
+	"github.com/gin-gonic/gin"
+)
+
+// Prefixes of the keys under which failed logins are tracked.
+const (
+	loginAttemptUserPrefix = "user:"
+	loginAttemptIPPrefix   = "ip:"
+)
+
+// Unknown usernames and wrong passwords get the same error so that usernames can not be guessed.
+var errInvalidCredentials = errors.New("invalid username or password")
+
+// This function compares the password with a dummy hash so that checking an unknown username takes as long
+// as checking a wrong password. The dummy hash is made by the server hasher to cost the same as real ones.
+func (server *Server) checkDummyPassword(password string) {
+	server.dummyPasswordOnce.Do(func() {
+		dummyPassword, err := auth.GenerateSecret(passwordResetTokenSize)
+		if err == nil {
+			server.dummyPasswordHash, _ = server.passwordHasher.Hash(dummyPassword)
+		}
+	})
+	_ = auth.CheckPassword(password, server.dummyPasswordHash)
+}
+
+// This function returns the lockout policy of the failed logins of an username.
+func (server *Server) userLoginPolicy() lockout.Policy {
+	return lockout.Policy{
+		FreeAttempts:     server.config.LoginFreeAttempts,
+		LockoutThreshold: server.config.LoginLockoutThreshold,
+		BaseDelay:        server.config.LoginBackoffBase,
+		LockoutDuration:  server.config.LoginLockoutDuration,
+	}
+}
+
+// This function returns the lockout policy of the failed logins of a client IP, it is looser than the one of
+// usernames since several users can share an IP.
+func (server *Server) ipLoginPolicy() lockout.Policy {
+	return lockout.Policy{
+		FreeAttempts:     server.config.LoginIPFreeAttempts,
+		LockoutThreshold: server.config.LoginIPLockoutThreshold,
+		BaseDelay:        server.config.LoginBackoffBase,
+		LockoutDuration:  server.config.LoginLockoutDuration,
+	}
+}
+
+// This function returns how long logins are still refused for the username or for the client IP.
+func (server *Server) loginLockRemaining(ctx *gin.Context, username string) (time.Duration, error) {
+	now := time.Now()
+
+	userAttempts, err := server.loginAttempts.Get(ctx, loginAttemptUserPrefix+username)
+	if err != nil {
+		return 0, err
+	}
+	ipAttempts, err := server.loginAttempts.Get(ctx, loginAttemptIPPrefix+ctx.ClientIP())
+	if err != nil {
+		return 0, err
+	}
+
+	remaining := server.userLoginPolicy().Remaining(userAttempts, now)
+	if ipRemaining := server.ipLoginPolicy().Remaining(ipAttempts, now); ipRemaining > remaining {
+		remaining = ipRemaining
+	}
+	return remaining, nil
+}
+
+// This function answers with a 429 and tells true when logins are still refused for the username or for the client IP.
+func (server *Server) refuseLockedLogin(ctx *gin.Context, username string) bool {
+	remaining, err := server.loginLockRemaining(ctx, username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return true
+	}
+	if remaining > 0 {
+		seconds := int(math.Ceil(remaining.Seconds()))
+		ctx.Header("Retry-After", strconv.Itoa(seconds))
+		err := fmt.Errorf("too many failed login attempts, retry in %d seconds", seconds)
+		ctx.JSON(http.StatusTooManyRequests, errorResponse(err))
+		return true
+	}
+	return false
+}
+
+// This function records a failed login for the username and for the client IP.
+func (server *Server) recordLoginFailure(ctx *gin.Context, username string) error {
+	_, err := server.loginAttempts.RecordFailure(ctx, loginAttemptUserPrefix+username, server.config.LoginAttemptWindow)
+	if err != nil {
+		return err
+	}
+	_, err = server.loginAttempts.RecordFailure(ctx, loginAttemptIPPrefix+ctx.ClientIP(), server.config.LoginAttemptWindow)
+	return err
+}
+
+// This function forgets the failed logins of an username. Failures of the client IP are kept, otherwise logging
+// in to an account owned by an attacker would let them try again from the same IP.
+func (server *Server) resetLoginFailures(ctx *gin.Context, username string) error {
+	return server.loginAttempts.Reset(ctx, loginAttemptUserPrefix+username)
+}
+
+// This function forgets the failed logins of a client IP, it is only done by admins.
+func (server *Server) resetIPLoginFailures(ctx *gin.Context, ip string) error {
+	return server.loginAttempts.Reset(ctx, loginAttemptIPPrefix+ip)
+}
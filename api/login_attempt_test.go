@@ -0,0 +1,204 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/IsuruHaupe/web-api/auth"
+	mockdb "github.com/IsuruHaupe/web-api/db/mock"
+	db "github.com/IsuruHaupe/web-api/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// loginRequest sends a login request to the server and returns the response.
+func loginRequest(t *testing.T, server *Server, username string, password string) *httptest.ResponseRecorder {
+	data, err := json.Marshal(gin.H{
+		"username": username,
+		"password": password,
+	})
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodPost, "/users/login", bytes.NewReader(data))
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	server.router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestLoginLockout(t *testing.T) {
+	user, password := randomUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	database := mockdb.NewMockDatabase(ctrl)
+	server := newTestServer(t, database)
+	server.config.LoginFreeAttempts = 2
+	server.config.LoginLockoutThreshold = 4
+	server.config.LoginIPFreeAttempts = 100
+	server.config.LoginIPLockoutThreshold = 100
+	server.config.LoginBackoffBase = time.Minute
+	server.config.LoginLockoutDuration = time.Hour
+	server.config.LoginAttemptWindow = time.Hour
+
+	// The free attempts are not delayed, whether the username exists or not.
+	database.EXPECT().
+		GetUser(gomock.Any(), gomock.Eq(user.Username)).
+		Times(2).
+		Return(user, nil)
+	for i := 0; i < 2; i++ {
+		recorder := loginRequest(t, server, user.Username, "incorrect")
+		require.Equal(t, http.StatusUnauthorized, recorder.Code)
+	}
+
+	// The third failure locks the username with the base delay.
+	database.EXPECT().
+		GetUser(gomock.Any(), gomock.Eq(user.Username)).
+		Times(1).
+		Return(user, nil)
+	recorder := loginRequest(t, server, user.Username, "incorrect")
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	// While locked, even the right password is refused without checking it.
+	recorder = loginRequest(t, server, user.Username, password)
+	require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	retryAfter, err := strconv.Atoi(recorder.Header().Get("Retry-After"))
+	require.NoError(t, err)
+	require.InDelta(t, time.Minute.Seconds(), retryAfter, 1)
+
+	// Other usernames are not locked.
+	database.EXPECT().
+		GetUser(gomock.Any(), gomock.Eq("unknown")).
+		Times(1).
+		Return(db.User{}, sql.ErrNoRows)
+	recorder = loginRequest(t, server, "unknown", password)
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
+
+func TestLoginLockoutIP(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	database := mockdb.NewMockDatabase(ctrl)
+	server := newTestServer(t, database)
+	server.config.LoginFreeAttempts = 100
+	server.config.LoginLockoutThreshold = 100
+	server.config.LoginIPFreeAttempts = 2
+	server.config.LoginIPLockoutThreshold = 3
+	server.config.LoginBackoffBase = time.Minute
+	server.config.LoginLockoutDuration = time.Hour
+	server.config.LoginAttemptWindow = time.Hour
+
+	// Guessing a different username every time still locks the client IP.
+	database.EXPECT().
+		GetUser(gomock.Any(), gomock.Any()).
+		Times(3).
+		Return(db.User{}, sql.ErrNoRows)
+	for i := 0; i < 3; i++ {
+		recorder := loginRequest(t, server, "unknown"+strconv.Itoa(i), "password")
+		require.Equal(t, http.StatusUnauthorized, recorder.Code)
+	}
+
+	recorder := loginRequest(t, server, "unknown", "password")
+	require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	retryAfter, err := strconv.Atoi(recorder.Header().Get("Retry-After"))
+	require.NoError(t, err)
+	require.InDelta(t, time.Hour.Seconds(), retryAfter, 1)
+}
+
+func TestLoginTwoFactorLockout(t *testing.T) {
+	user, _ := randomTwoFactorUser(t)
+	challengeToken, err := auth.GenerateSecret(twoFactorChallengeTokenSize)
+	require.NoError(t, err)
+	challenge := db.TwoFactorChallenge{
+		HashedToken: auth.HashSecret(challengeToken),
+		Username:    user.Username,
+		ExpiresAt:   time.Now().Add(time.Minute),
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	database := mockdb.NewMockDatabase(ctrl)
+	server := newTestServer(t, database)
+	server.config.LoginFreeAttempts = 2
+	server.config.LoginLockoutThreshold = 4
+	server.config.LoginIPFreeAttempts = 100
+	server.config.LoginIPLockoutThreshold = 100
+	server.config.LoginBackoffBase = time.Minute
+	server.config.LoginLockoutDuration = time.Hour
+	server.config.LoginAttemptWindow = time.Hour
+
+	for i := 0; i < 3; i++ {
+		_, err := server.loginAttempts.RecordFailure(context.Background(), loginAttemptUserPrefix+user.Username, time.Hour)
+		require.NoError(t, err)
+	}
+
+	// A locked username can't try codes with a challenge it got before, no attempt of the challenge is used.
+	database.EXPECT().
+		GetTwoFactorChallenge(gomock.Any(), gomock.Eq(challenge.HashedToken)).
+		Times(1).
+		Return(challenge, nil)
+	database.EXPECT().
+		UseTwoFactorChallengeAttempt(gomock.Any(), gomock.Any()).
+		Times(0)
+
+	data, err := json.Marshal(gin.H{"challenge_token": challengeToken, "code": currentTOTPCode(t, user)})
+	require.NoError(t, err)
+	request, err := http.NewRequest(http.MethodPost, "/users/login/2fa", bytes.NewReader(data))
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	retryAfter, err := strconv.Atoi(recorder.Header().Get("Retry-After"))
+	require.NoError(t, err)
+	require.InDelta(t, time.Minute.Seconds(), retryAfter, 1)
+}
+
+func TestLoginResetsFailures(t *testing.T) {
+	user, password := randomUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	database := mockdb.NewMockDatabase(ctrl)
+	database.EXPECT().
+		GetUser(gomock.Any(), gomock.Eq(user.Username)).
+		AnyTimes().
+		Return(user, nil)
+	database.EXPECT().
+		CreateSession(gomock.Any(), gomock.Any()).
+		Times(1)
+
+	server := newTestServer(t, database)
+	server.config.LoginFreeAttempts = 1
+	server.config.LoginLockoutThreshold = 10
+	server.config.LoginIPFreeAttempts = 100
+	server.config.LoginIPLockoutThreshold = 100
+	server.config.LoginBackoffBase = time.Minute
+	server.config.LoginLockoutDuration = time.Hour
+	server.config.LoginAttemptWindow = time.Hour
+
+	recorder := loginRequest(t, server, user.Username, "incorrect")
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	recorder = loginRequest(t, server, user.Username, password)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	// The successful login started the count again, the next failure is free.
+	recorder = loginRequest(t, server, user.Username, "incorrect")
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+	recorder = loginRequest(t, server, user.Username, "incorrect")
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
@@ -8,18 +8,35 @@ import (
 	"github.com/IsuruHaupe/web-api/auth"
 	"github.com/IsuruHaupe/web-api/config"
 	"github.com/IsuruHaupe/web-api/db/database"
+	"github.com/IsuruHaupe/web-api/lockout"
+	"github.com/IsuruHaupe/web-api/mail"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/require"
 )
 
 // newTestServer creates a new test server using authentification.
 func newTestServer(t *testing.T, database database.Database) *Server {
+	return newTestServerWithMailer(t, database, nil)
+}
+
+// newTestServerWithMailer creates a new test server sending emails with the given mailer.
+func newTestServerWithMailer(t *testing.T, database database.Database, mailer mail.Mailer) *Server {
 	config := config.Config{
-		TokenSymmetricKey:   auth.RandomString(32),
-		AccessTokenDuration: time.Minute,
+		TokenSymmetricKey:               auth.RandomString(32),
+		TokenIssuer:                     "web-api",
+		TokenAudience:                   "web-api",
+		AccessTokenDuration:             time.Minute,
+		PasswordResetDuration:           time.Minute,
+		PasswordResetResendInterval:     time.Minute,
+		EmailVerificationKey:            auth.RandomString(32),
+		EmailVerificationURL:            "http://localhost:8080/users/email/verify",
+		EmailVerificationDuration:       time.Minute,
+		EmailVerificationResendInterval: time.Minute,
+		TwoFactorChallengeDuration:      time.Minute,
+		TOTPIssuer:                      "web-api",
 	}
 
-	server, err := NewServer(config, database)
+	server, err := NewServer(config, database, mailer, lockout.NewMemoryStore())
 	require.NoError(t, err)
 
 	return server
@@ -1,23 +1,30 @@
 package api
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
 	"net/http"
 	"strings"
 
+	authHelper "github.com/IsuruHaupe/web-api/auth"
 	auth "github.com/IsuruHaupe/web-api/auth/token"
+	"github.com/IsuruHaupe/web-api/db/database"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 const (
 	authorizationHeaderKey  = "authorization"
 	authorizationTypeBearer = "bearer"
+	authorizationTypeAPIKey = "apikey"
 	authorizationPayloadKey = "authorization_payload"
+	authorizationScopesKey  = "authorization_scopes"
 )
 
-// This function will be used to authenticate users.
-func authMiddleware(tokenMaker auth.Maker) gin.HandlerFunc {
+// This function will be used to authenticate users, either with a bearer access token or with an API key.
+// Access tokens of OAuth2 clients are restricted to their scopes, like API keys.
+func authMiddleware(tokenMaker auth.Maker, database database.Database) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authorizationHeader := ctx.GetHeader(authorizationHeaderKey)
 
@@ -36,19 +43,28 @@ func authMiddleware(tokenMaker auth.Maker) gin.HandlerFunc {
 			return
 		}
 
-		// We check if the authorization type is valid.
+		// We check if the authorization type is valid and authenticate the user accordingly.
+		var payload *auth.Payload
+		var status int
+		var err error
 		authorizationType := strings.ToLower(fields[0])
-		if authorizationType != authorizationTypeBearer {
-			err := fmt.Errorf("unsupported authorization type %s", authorizationType)
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
-			return
+		switch authorizationType {
+		case authorizationTypeBearer:
+			payload, status, err = authenticateAccessToken(ctx, tokenMaker, database, fields[1])
+			if err == nil && payload.ClientID != "" {
+				ctx.Set(authorizationScopesKey, payload.Scopes)
+			}
+		case authorizationTypeAPIKey:
+			var scopes []string
+			payload, scopes, status, err = authenticateAPIKey(ctx, database, fields[1])
+			if err == nil {
+				ctx.Set(authorizationScopesKey, scopes)
+			}
+		default:
+			status, err = http.StatusUnauthorized, fmt.Errorf("unsupported authorization type %s", authorizationType)
 		}
-
-		// Parse and verify the access token.
-		accessToken := fields[1]
-		payload, err := tokenMaker.VerifyToken(accessToken)
 		if err != nil {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			ctx.AbortWithStatusJSON(status, errorResponse(err))
 			return
 		}
 
@@ -57,3 +73,132 @@ func authMiddleware(tokenMaker auth.Maker) gin.HandlerFunc {
 		ctx.Next()
 	}
 }
+
+// Parse and verify the access token, access tokens bound to a session are rejected once that session
+// has been blocked or deleted. Access tokens of OAuth2 clients are rejected once the client has been deleted
+// or its owner disabled.
+func authenticateAccessToken(ctx *gin.Context, tokenMaker auth.Maker, database database.Database, accessToken string) (*auth.Payload, int, error) {
+	payload, err := tokenMaker.VerifyToken(accessToken, auth.TokenTypeAccess)
+	if err != nil {
+		return nil, http.StatusUnauthorized, err
+	}
+
+	// Check that the session the access token was issued from is still active.
+	if payload.SessionID != uuid.Nil {
+		session, err := database.GetSession(ctx, payload.SessionID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil, http.StatusUnauthorized, errors.New("session has been revoked")
+			}
+			return nil, http.StatusInternalServerError, err
+		}
+
+		if session.IsBlocked {
+			return nil, http.StatusUnauthorized, errors.New("blocked session")
+		}
+
+		if session.Username != payload.Username {
+			return nil, http.StatusUnauthorized, errors.New("incorrect session user")
+		}
+	}
+
+	if payload.ClientID != "" {
+		clientID, err := uuid.Parse(payload.ClientID)
+		if err != nil {
+			return nil, http.StatusUnauthorized, auth.ErrInvalidToken
+		}
+
+		owner, err := database.GetActiveOAuthClientOwner(ctx, clientID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil, http.StatusUnauthorized, errors.New("OAuth2 client has been revoked")
+			}
+			return nil, http.StatusInternalServerError, err
+		}
+
+		if owner != payload.Username {
+			return nil, http.StatusUnauthorized, errors.New("incorrect OAuth2 client owner")
+		}
+	}
+
+	return payload, http.StatusOK, nil
+}
+
+// Find the API key from its hash and record that it has been used. The payload carries the current role
+// of the owner of the key, and the scopes of the key are returned along with it.
+func authenticateAPIKey(ctx *gin.Context, database database.Database, key string) (*auth.Payload, []string, int, error) {
+	apiKey, err := database.UseAPIKey(ctx, authHelper.HashAPIKey(key))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil, http.StatusUnauthorized, errors.New("invalid API key")
+		}
+		return nil, nil, http.StatusInternalServerError, err
+	}
+
+	payload := &auth.Payload{
+		ID:       apiKey.ID,
+		Username: apiKey.Username,
+		Role:     apiKey.Role,
+	}
+	return payload, apiKey.Scopes, http.StatusOK, nil
+}
+
+// This function will be used to restrict routes to some roles, it must be used after authMiddleware.
+func roleMiddleware(roles ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		payload := ctx.MustGet(authorizationPayloadKey).(*auth.Payload)
+
+		for _, role := range roles {
+			if payload.Role == role {
+				ctx.Next()
+				return
+			}
+		}
+
+		err := fmt.Errorf("role %s is not allowed to access this resource", payload.Role)
+		ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err))
+	}
+}
+
+// This function will be used to restrict routes to API keys and OAuth2 clients holding the given scope, it must
+// be used after authMiddleware. Requests authenticated with the access token of an user are not restricted by scopes.
+func scopeMiddleware(scope string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if hasScope(ctx, scope) {
+			ctx.Next()
+			return
+		}
+
+		err := fmt.Errorf("credentials are missing the %s scope", scope)
+		ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err))
+	}
+}
+
+// This function tells whether the credentials of the request hold the scope, it is used by handlers doing more than
+// what the scope of their route allows. The access token of an user holds every scope.
+func hasScope(ctx *gin.Context, scope string) bool {
+	scopes, ok := ctx.Get(authorizationScopesKey)
+	if !ok {
+		return true
+	}
+
+	for _, allowed := range scopes.([]string) {
+		if allowed == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// This function will be used to restrict routes managing the account to access tokens of users, it must be used
+// after authMiddleware. API keys and OAuth2 clients can not be used to manage sessions or other API keys.
+func tokenOnlyMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if _, ok := ctx.Get(authorizationScopesKey); ok {
+			err := errors.New("this route can not be used with an API key or an OAuth2 client")
+			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.Next()
+	}
+}
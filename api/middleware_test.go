@@ -1,14 +1,20 @@
 package api
 
 import (
+	"database/sql"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	authHelper "github.com/IsuruHaupe/web-api/auth"
 	auth "github.com/IsuruHaupe/web-api/auth/token"
+	mockdb "github.com/IsuruHaupe/web-api/db/mock"
+	db "github.com/IsuruHaupe/web-api/db/sqlc"
 	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
 
@@ -18,11 +24,16 @@ func addAuthorization(
 	tokenMaker auth.Maker,
 	authorizationType string,
 	username string,
+	role string,
 	duration time.Duration,
 ) {
-	token, payload, err := tokenMaker.CreateToken(username, duration)
+	payload, err := auth.NewPayload(username, auth.TokenTypeAccess, duration)
 	require.NoError(t, err)
-	require.NotEmpty(t, payload)
+	payload.Role = role
+
+	token, err := tokenMaker.CreateToken(payload)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
 
 	authorizationHeader := fmt.Sprintf("%s %s", authorizationType, token)
 	request.Header.Set(authorizationHeaderKey, authorizationHeader)
@@ -37,7 +48,7 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name: "OK",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user", time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user", auth.RoleEditor, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
@@ -54,7 +65,7 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name: "UnsupportedAuthorization",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, "unsupported", "user", time.Minute)
+				addAuthorization(t, request, tokenMaker, "unsupported", "user", auth.RoleEditor, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -63,7 +74,7 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name: "InvalidAuthorizationFormat",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, "", "user", time.Minute)
+				addAuthorization(t, request, tokenMaker, "", "user", auth.RoleEditor, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -72,7 +83,21 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name: "ExpiredToken",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user", -time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user", auth.RoleEditor, -time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "SessionToken",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				payload, err := auth.NewPayload("user", auth.TokenTypeSession, time.Minute)
+				require.NoError(t, err)
+
+				token, err := tokenMaker.CreateToken(payload)
+				require.NoError(t, err)
+				request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, token))
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -90,7 +115,7 @@ func TestAuthMiddleware(t *testing.T) {
 			authPath := "/auth"
 			server.router.GET(
 				authPath,
-				authMiddleware(server.tokenMaker),
+				authMiddleware(server.tokenMaker, server.database),
 				func(ctx *gin.Context) {
 					ctx.JSON(http.StatusOK, gin.H{})
 				},
@@ -106,3 +131,451 @@ func TestAuthMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestRoleMiddleware(t *testing.T) {
+	testCases := []struct {
+		name          string
+		role          string
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Admin",
+			role: auth.RoleAdmin,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "Editor",
+			role: auth.RoleEditor,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "Viewer",
+			role: auth.RoleViewer,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "No Role",
+			role: "",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		currentTest := testCases[i]
+
+		t.Run(currentTest.name, func(t *testing.T) {
+			server := newTestServer(t, nil)
+
+			// We had a custom route so that we can test the middleware part.
+			rolePath := "/role"
+			server.router.GET(
+				rolePath,
+				authMiddleware(server.tokenMaker, server.database),
+				roleMiddleware(auth.RoleAdmin, auth.RoleEditor),
+				func(ctx *gin.Context) {
+					ctx.JSON(http.StatusOK, gin.H{})
+				},
+			)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, rolePath, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "user", currentTest.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			currentTest.checkResponse(t, recorder)
+		})
+	}
+}
+
+func addSessionAuthorization(
+	t *testing.T,
+	request *http.Request,
+	tokenMaker auth.Maker,
+	username string,
+	sessionID uuid.UUID,
+	duration time.Duration,
+) {
+	payload, err := auth.NewPayload(username, auth.TokenTypeAccess, duration)
+	require.NoError(t, err)
+	payload.SessionID = sessionID
+
+	token, err := tokenMaker.CreateToken(payload)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+
+	authorizationHeader := fmt.Sprintf("%s %s", authorizationTypeBearer, token)
+	request.Header.Set(authorizationHeaderKey, authorizationHeader)
+}
+
+func TestAuthMiddlewareSession(t *testing.T) {
+	user, _ := randomUser(t)
+	session := randomSession(user.Username)
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(database *mockdb.MockDatabase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "BlockedSession",
+			buildStubs: func(database *mockdb.MockDatabase) {
+				blockedSession := session
+				blockedSession.IsBlocked = true
+				database.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(blockedSession, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "DeletedSession",
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(db.Session{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Internal Error",
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Session{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		currentTest := testCases[i]
+
+		t.Run(currentTest.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			database := mockdb.NewMockDatabase(ctrl)
+			currentTest.buildStubs(database)
+
+			server := newTestServer(t, database)
+
+			// We had a custom route so that we can test the middleware part.
+			authPath := "/auth"
+			server.router.GET(
+				authPath,
+				authMiddleware(server.tokenMaker, server.database),
+				func(ctx *gin.Context) {
+					ctx.JSON(http.StatusOK, gin.H{})
+				},
+			)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, authPath, nil)
+			require.NoError(t, err)
+
+			addSessionAuthorization(t, request, server.tokenMaker, user.Username, session.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			currentTest.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestAuthMiddlewareAPIKey(t *testing.T) {
+	user, _ := randomUser(t)
+	key, _, hashedKey, err := authHelper.GenerateAPIKey()
+	require.NoError(t, err)
+	apiKeyID := uuid.New()
+
+	testCases := []struct {
+		name          string
+		method        string
+		url           string
+		buildStubs    func(database *mockdb.MockDatabase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK",
+			method: http.MethodGet,
+			url:    "/skills?page_id=1&page_size=5",
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					UseAPIKey(gomock.Any(), gomock.Eq(hashedKey)).
+					Times(1).
+					Return(db.UseAPIKeyRow{ID: apiKeyID, Username: user.Username, Scopes: []string{scopeSkillsRead}, Role: auth.RoleEditor}, nil)
+				database.EXPECT().
+					ListSkills(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.Skill{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "Missing Scope",
+			method: http.MethodGet,
+			url:    "/contacts?page_id=1&page_size=5",
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					UseAPIKey(gomock.Any(), gomock.Eq(hashedKey)).
+					Times(1).
+					Return(db.UseAPIKeyRow{ID: apiKeyID, Username: user.Username, Scopes: []string{scopeSkillsRead}, Role: auth.RoleEditor}, nil)
+				database.EXPECT().
+					ListContacts(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "Viewer Write Scope",
+			method: http.MethodDelete,
+			url:    "/skills/1",
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					UseAPIKey(gomock.Any(), gomock.Eq(hashedKey)).
+					Times(1).
+					Return(db.UseAPIKeyRow{ID: apiKeyID, Username: user.Username, Scopes: []string{scopeSkillsWrite}, Role: auth.RoleViewer}, nil)
+				database.EXPECT().
+					DeleteSkill(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "Unknown Key",
+			method: http.MethodGet,
+			url:    "/skills?page_id=1&page_size=5",
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					UseAPIKey(gomock.Any(), gomock.Eq(hashedKey)).
+					Times(1).
+					Return(db.UseAPIKeyRow{}, sql.ErrNoRows)
+				database.EXPECT().
+					ListSkills(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:   "Internal Error",
+			method: http.MethodGet,
+			url:    "/skills?page_id=1&page_size=5",
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					UseAPIKey(gomock.Any(), gomock.Eq(hashedKey)).
+					Times(1).
+					Return(db.UseAPIKeyRow{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		currentTest := testCases[i]
+
+		t.Run(currentTest.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			database := mockdb.NewMockDatabase(ctrl)
+			currentTest.buildStubs(database)
+
+			server := newTestServer(t, database)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(currentTest.method, currentTest.url, nil)
+			require.NoError(t, err)
+
+			addAPIKeyAuthorization(request, key)
+			server.router.ServeHTTP(recorder, request)
+			currentTest.checkResponse(t, recorder)
+		})
+	}
+}
+
+func addClientAuthorization(t *testing.T, request *http.Request, tokenMaker auth.Maker, username string, clientID uuid.UUID, scopes []string) {
+	payload, err := auth.NewPayload(username, auth.TokenTypeAccess, time.Minute)
+	require.NoError(t, err)
+	payload.Role = auth.RoleEditor
+	payload.ClientID = clientID.String()
+	payload.Scopes = scopes
+
+	token, err := tokenMaker.CreateToken(payload)
+	require.NoError(t, err)
+
+	request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, token))
+}
+
+func TestAuthMiddlewareOAuthClient(t *testing.T) {
+	user, _ := randomUser(t)
+	clientID := uuid.New()
+
+	testCases := []struct {
+		name          string
+		method        string
+		url           string
+		buildStubs    func(database *mockdb.MockDatabase)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK",
+			method: http.MethodGet,
+			url:    "/skills?page_id=1&page_size=5",
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetActiveOAuthClientOwner(gomock.Any(), gomock.Eq(clientID)).
+					Times(1).
+					Return(user.Username, nil)
+				database.EXPECT().
+					ListSkills(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.Skill{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "Missing Scope",
+			method: http.MethodGet,
+			url:    "/contacts?page_id=1&page_size=5",
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetActiveOAuthClientOwner(gomock.Any(), gomock.Eq(clientID)).
+					Times(1).
+					Return(user.Username, nil)
+				database.EXPECT().
+					ListContacts(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "Account Route",
+			method: http.MethodGet,
+			url:    "/sessions",
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetActiveOAuthClientOwner(gomock.Any(), gomock.Eq(clientID)).
+					Times(1).
+					Return(user.Username, nil)
+				database.EXPECT().
+					ListSessions(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "Revoked Client",
+			method: http.MethodGet,
+			url:    "/skills?page_id=1&page_size=5",
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetActiveOAuthClientOwner(gomock.Any(), gomock.Eq(clientID)).
+					Times(1).
+					Return("", sql.ErrNoRows)
+				database.EXPECT().
+					ListSkills(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:   "Other Owner",
+			method: http.MethodGet,
+			url:    "/skills?page_id=1&page_size=5",
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetActiveOAuthClientOwner(gomock.Any(), gomock.Eq(clientID)).
+					Times(1).
+					Return("other", nil)
+				database.EXPECT().
+					ListSkills(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:   "Internal Error",
+			method: http.MethodGet,
+			url:    "/skills?page_id=1&page_size=5",
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetActiveOAuthClientOwner(gomock.Any(), gomock.Eq(clientID)).
+					Times(1).
+					Return("", sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		currentTest := testCases[i]
+
+		t.Run(currentTest.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			database := mockdb.NewMockDatabase(ctrl)
+			currentTest.buildStubs(database)
+
+			server := newTestServer(t, database)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(currentTest.method, currentTest.url, nil)
+			require.NoError(t, err)
+
+			addClientAuthorization(t, request, server.tokenMaker, user.Username, clientID, []string{scopeSkillsRead})
+			server.router.ServeHTTP(recorder, request)
+			currentTest.checkResponse(t, recorder)
+		})
+	}
+}
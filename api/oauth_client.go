@@ -0,0 +1,396 @@
+package api
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	authHelper "github.com/IsuruHaupe/web-api/auth"
+	auth "github.com/IsuruHaupe/web-api/auth/token"
+	db "github.com/IsuruHaupe/web-api/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// Number of random bytes of the OAuth2 client secrets.
+	oauthClientSecretSize = 32
+	// The only grant OAuth2 clients can use, they have no user to send through an authorization flow.
+	grantTypeClientCredentials = "client_credentials"
+)
+
+// Audit actions of the OAuth2 clients management.
+const (
+	auditActionCreateOAuthClient = "oauth_client.create"
+	auditActionDeleteOAuthClient = "oauth_client.delete"
+)
+
+// Request holder when receiving a create OAuth2 client request.
+type createOAuthClientRequest struct {
+	Name   string   `json:"name" binding:"required"`
+	Owner  string   `json:"owner" binding:"required,alphanum"`
+	Scopes []string `json:"scopes" binding:"required,min=1,dive,oneof=contacts:read contacts:write skills:read skills:write"`
+}
+
+// This is the expected returned response when listing OAuth2 clients, the secret is never exposed.
+type oauthClientResponse struct {
+	ClientID   uuid.UUID  `json:"client_id"`
+	Name       string     `json:"name"`
+	Owner      string     `json:"owner"`
+	Scopes     []string   `json:"scopes"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func newOAuthClientResponse(client db.OauthClient) oauthClientResponse {
+	rsp := oauthClientResponse{
+		ClientID:  client.ID,
+		Name:      client.Name,
+		Owner:     client.Owner,
+		Scopes:    client.Scopes,
+		CreatedAt: client.CreatedAt,
+	}
+	if client.LastUsedAt.Valid {
+		rsp.LastUsedAt = &client.LastUsedAt.Time
+	}
+	return rsp
+}
+
+// This is the expected returned response on succesful creation, the secret is only shown this time.
+type createOAuthClientResponse struct {
+	oauthClientResponse
+	ClientSecret string `json:"client_secret"`
+}
+
+// createOAuthClient godoc
+// @Security bearerAuth
+// @Summary Register an OAuth2 client
+// @Description This function is used by admins to register a service which gets access tokens with the client credentials grant on /oauth/token.
+// @Description The client acts on behalf of its owner, contacts and skills it creates belong to the owner. The secret is only returned once.
+// @Description Scopes are contacts:read, contacts:write, skills:read and skills:write, clients of viewers can only be given read scopes.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param client body api.createOAuthClientRequest true "Create OAuth2 client"
+// @Success 200 {object} api.createOAuthClientResponse
+// @Router /admin/oauth-clients [post]
+func (server *Server) createOAuthClient(ctx *gin.Context) {
+	var req createOAuthClientRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	owner, err := server.database.GetUser(ctx, req.Owner)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// A client can not be given more rights than its owner.
+	if err := checkScopesAllowed(owner.Role, req.Scopes); err != nil {
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	clientSecret, err := authHelper.GenerateSecret(oauthClientSecretSize)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	id, err := uuid.NewRandom()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	var client db.OauthClient
+	details := fmt.Sprintf("client %s", id)
+	_, err = server.database.AuditTx(ctx, newAuditLog(ctx, auditActionCreateOAuthClient, owner.Username, details), func(q db.Querier) error {
+		var err error
+		client, err = q.CreateOAuthClient(ctx, db.CreateOAuthClientParams{
+			ID:           id,
+			Name:         req.Name,
+			Owner:        owner.Username,
+			HashedSecret: authHelper.HashSecret(clientSecret),
+			Scopes:       req.Scopes,
+		})
+		return err
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := createOAuthClientResponse{
+		oauthClientResponse: newOAuthClientResponse(client),
+		ClientSecret:        clientSecret,
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// listOAuthClients godoc
+// @Security bearerAuth
+// @Summary List OAuth2 clients
+// @Description This function is used by admins to list the registered OAuth2 clients.
+// @Tags admin
+// @Produce json
+// @Success 200 {array} api.oauthClientResponse
+// @Router /admin/oauth-clients [get]
+func (server *Server) listOAuthClients(ctx *gin.Context) {
+	clients, err := server.database.ListOAuthClients(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := make([]oauthClientResponse, 0, len(clients))
+	for _, client := range clients {
+		response = append(response, newOAuthClientResponse(client))
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+// Request holder for deleting OAuth2 client request.
+type deleteOAuthClientRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// deleteOAuthClient godoc
+// @Security bearerAuth
+// @Summary Delete an OAuth2 client
+// @Description This function is used by admins to delete an OAuth2 client, its access tokens stop working right away.
+// @Tags admin
+// @Accept application/x-www-form-urlencoded
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {string} string "Successfully deleted OAuth2 client."
+// @Router /admin/oauth-clients/{id} [delete]
+func (server *Server) deleteOAuthClient(ctx *gin.Context) {
+	var req deleteOAuthClientRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	clientID := uuid.MustParse(req.ID)
+
+	// The owner of the client is the target of the audit record.
+	client, err := server.database.GetOAuthClient(ctx, clientID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	details := fmt.Sprintf("client %s", client.ID)
+	_, err = server.database.AuditTx(ctx, newAuditLog(ctx, auditActionDeleteOAuthClient, client.Owner, details), func(q db.Querier) error {
+		deleted, err := q.DeleteOAuthClient(ctx, client.ID)
+		if err != nil {
+			return err
+		}
+		if deleted == 0 {
+			return sql.ErrNoRows
+		}
+		return nil
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, "Successfully deleted OAuth2 client.")
+}
+
+// Request holder when receiving a token request (RFC 6749, section 4.4.2). The client credentials can also be
+// sent with HTTP basic authentication.
+type oauthTokenRequest struct {
+	GrantType    string `form:"grant_type" binding:"required"`
+	Scope        string `form:"scope"`
+	ClientID     string `form:"client_id"`
+	ClientSecret string `form:"client_secret"`
+}
+
+// This is the expected returned response on succesful token request (RFC 6749, section 5.1).
+type oauthTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+	Scope       string `json:"scope"`
+}
+
+// Error codes of the token endpoint (RFC 6749, section 5.2).
+const (
+	oauthErrorInvalidRequest       = "invalid_request"
+	oauthErrorInvalidClient        = "invalid_client"
+	oauthErrorUnauthorizedClient   = "unauthorized_client"
+	oauthErrorUnsupportedGrantType = "unsupported_grant_type"
+	oauthErrorInvalidScope         = "invalid_scope"
+)
+
+var errInvalidClient = errors.New("invalid client credentials")
+
+// Wrapper for returning errors of the token endpoint, which must follow the OAuth2 format.
+func oauthErrorResponse(code string, err error) gin.H {
+	return gin.H{"error": code, "error_description": err.Error()}
+}
+
+// oauthToken godoc
+// @Summary Get an access token for an OAuth2 client
+// @Description This function is used by services to get an access token with the client credentials grant (RFC 6749, section 4.4).
+// @Description The client authenticates with HTTP basic authentication or with the client_id and client_secret parameters.
+// @Description The token acts on behalf of the owner of the client and is restricted to the requested scopes, every scope of the client by default.
+// @Tags oauth
+// @Accept application/x-www-form-urlencoded
+// @Produce json
+// @Param grant_type formData string true "client_credentials"
+// @Param scope formData string false "space separated scopes"
+// @Param client_id formData string false "client_id"
+// @Param client_secret formData string false "client_secret"
+// @Success 200 {object} api.oauthTokenResponse
+// @Router /oauth/token [post]
+func (server *Server) oauthToken(ctx *gin.Context) {
+	// Tokens must not be cached (RFC 6749, section 5.1).
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Header("Pragma", "no-cache")
+
+	var req oauthTokenRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, oauthErrorResponse(oauthErrorInvalidRequest, err))
+		return
+	}
+	if req.GrantType != grantTypeClientCredentials {
+		err := fmt.Errorf("unsupported grant type %s", req.GrantType)
+		ctx.JSON(http.StatusBadRequest, oauthErrorResponse(oauthErrorUnsupportedGrantType, err))
+		return
+	}
+
+	client, status, err := server.authenticateOAuthClient(ctx, req)
+	if err != nil {
+		switch status {
+		case http.StatusUnauthorized:
+			ctx.Header("WWW-Authenticate", `Basic realm="oauth"`)
+			ctx.JSON(status, oauthErrorResponse(oauthErrorInvalidClient, err))
+		case http.StatusBadRequest:
+			ctx.JSON(status, oauthErrorResponse(oauthErrorInvalidRequest, err))
+		default:
+			ctx.JSON(status, errorResponse(err))
+		}
+		return
+	}
+
+	// A client can only narrow down its scopes.
+	scopes := client.Scopes
+	if req.Scope != "" {
+		scopes = strings.Fields(req.Scope)
+		for _, scope := range scopes {
+			if !containsScope(client.Scopes, scope) {
+				err := fmt.Errorf("client is not allowed the %s scope", scope)
+				ctx.JSON(http.StatusBadRequest, oauthErrorResponse(oauthErrorInvalidScope, err))
+				return
+			}
+		}
+	}
+
+	owner, err := server.database.GetUser(ctx, client.Owner)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	role, err := server.accessRole(owner)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, oauthErrorResponse(oauthErrorUnauthorizedClient, err))
+		return
+	}
+
+	payload, err := auth.NewPayload(owner.Username, auth.TokenTypeAccess, server.config.AccessTokenDuration)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	payload.Role = role
+	payload.ClientID = client.ID.String()
+	payload.Scopes = scopes
+
+	accessToken, err := server.tokenMaker.CreateToken(payload)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	err = server.database.UseOAuthClient(ctx, client.ID)
+	if err != nil {
+		ctx.Error(err)
+	}
+
+	ctx.JSON(http.StatusOK, oauthTokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(server.config.AccessTokenDuration.Seconds()),
+		Scope:       strings.Join(scopes, " "),
+	})
+}
+
+// This function will find the client from its credentials, unknown clients and wrong secrets get the same error.
+func (server *Server) authenticateOAuthClient(ctx *gin.Context, req oauthTokenRequest) (db.OauthClient, int, error) {
+	clientID, clientSecret := req.ClientID, req.ClientSecret
+	if username, password, ok := ctx.Request.BasicAuth(); ok {
+		if clientID != "" || clientSecret != "" {
+			return db.OauthClient{}, http.StatusBadRequest, errors.New("client credentials must be sent only once")
+		}
+
+		// Credentials are form encoded before being sent with basic authentication (RFC 6749, section 2.3.1).
+		var err error
+		clientID, err = url.QueryUnescape(username)
+		if err != nil {
+			return db.OauthClient{}, http.StatusUnauthorized, errInvalidClient
+		}
+		clientSecret, err = url.QueryUnescape(password)
+		if err != nil {
+			return db.OauthClient{}, http.StatusUnauthorized, errInvalidClient
+		}
+	}
+
+	id, err := uuid.Parse(clientID)
+	if err != nil || clientSecret == "" {
+		return db.OauthClient{}, http.StatusUnauthorized, errInvalidClient
+	}
+
+	client, err := server.database.GetOAuthClient(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.OauthClient{}, http.StatusUnauthorized, errInvalidClient
+		}
+		return db.OauthClient{}, http.StatusInternalServerError, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(authHelper.HashSecret(clientSecret)), []byte(client.HashedSecret)) != 1 {
+		return db.OauthClient{}, http.StatusUnauthorized, errInvalidClient
+	}
+	return client, http.StatusOK, nil
+}
+
+func containsScope(scopes []string, scope string) bool {
+	for _, allowed := range scopes {
+		if allowed == scope {
+			return true
+		}
+	}
+	return false
+}
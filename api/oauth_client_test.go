@@ -0,0 +1,599 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	authHelper "github.com/IsuruHaupe/web-api/auth"
+	auth "github.com/IsuruHaupe/web-api/auth/token"
+	mockdb "github.com/IsuruHaupe/web-api/db/mock"
+	db "github.com/IsuruHaupe/web-api/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// expectOAuthClientAuditTx expects an audited action on an OAuth2 client, the details hold the client ID.
+func expectOAuthClientAuditTx(t *testing.T, database *mockdb.MockDatabase, actor string, action string, owner string) *gomock.Call {
+	return database.EXPECT().
+		AuditTx(gomock.Any(), gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ interface{}, arg db.CreateAuditLogParams, run func(q db.Querier) error) (db.AuditLog, error) {
+			require.Equal(t, actor, arg.Actor)
+			require.Equal(t, action, arg.Action)
+			require.Equal(t, owner, arg.Target)
+			require.True(t, strings.HasPrefix(arg.Details, "client "))
+			if err := run(database); err != nil {
+				return db.AuditLog{}, err
+			}
+			return db.AuditLog{ID: 1, Actor: arg.Actor, Action: arg.Action, Target: arg.Target, Details: arg.Details}, nil
+		})
+}
+
+func TestCreateOAuthClientAPI(t *testing.T) {
+	admin, _ := randomUser(t)
+	admin.Role = auth.RoleAdmin
+	owner, _ := randomUser(t)
+	viewer, _ := randomUser(t)
+	viewer.Role = auth.RoleViewer
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(database *mockdb.MockDatabase)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"name":   "contacts-sync",
+				"owner":  owner.Username,
+				"scopes": []string{scopeContactsRead, scopeContactsWrite},
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(owner.Username)).
+					Times(1).
+					Return(owner, nil)
+				expectOAuthClientAuditTx(t, database, admin.Username, auditActionCreateOAuthClient, owner.Username)
+				database.EXPECT().
+					CreateOAuthClient(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CreateOAuthClientParams) (db.OauthClient, error) {
+						require.Equal(t, "contacts-sync", arg.Name)
+						require.Equal(t, owner.Username, arg.Owner)
+						require.Equal(t, []string{scopeContactsRead, scopeContactsWrite}, arg.Scopes)
+						return db.OauthClient{
+							ID:           arg.ID,
+							Name:         arg.Name,
+							Owner:        arg.Owner,
+							HashedSecret: arg.HashedSecret,
+							Scopes:       arg.Scopes,
+							CreatedAt:    time.Now(),
+						}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp createOAuthClientResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.NotEqual(t, uuid.Nil, rsp.ClientID)
+				require.NotEmpty(t, rsp.ClientSecret)
+				require.Equal(t, owner.Username, rsp.Owner)
+				require.NotContains(t, recorder.Body.String(), "hashed_secret")
+			},
+		},
+		{
+			name: "Unknown Owner",
+			body: gin.H{
+				"name":   "contacts-sync",
+				"owner":  "unknown",
+				"scopes": []string{scopeContactsRead},
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq("unknown")).
+					Times(1).
+					Return(db.User{}, sql.ErrNoRows)
+				database.EXPECT().
+					AuditTx(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "Viewer Write Scope",
+			body: gin.H{
+				"name":   "contacts-sync",
+				"owner":  viewer.Username,
+				"scopes": []string{scopeContactsWrite},
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(viewer.Username)).
+					Times(1).
+					Return(viewer, nil)
+				database.EXPECT().
+					AuditTx(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "Invalid Scope",
+			body: gin.H{
+				"name":   "contacts-sync",
+				"owner":  owner.Username,
+				"scopes": []string{"users:write"},
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Internal Error",
+			body: gin.H{
+				"name":   "contacts-sync",
+				"owner":  owner.Username,
+				"scopes": []string{scopeContactsRead},
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(owner.Username)).
+					Times(1).
+					Return(owner, nil)
+				expectOAuthClientAuditTx(t, database, admin.Username, auditActionCreateOAuthClient, owner.Username)
+				database.EXPECT().
+					CreateOAuthClient(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.OauthClient{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		currentTest := testCases[i]
+
+		t.Run(currentTest.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			database := mockdb.NewMockDatabase(ctrl)
+			currentTest.buildStubs(database)
+
+			server := newTestServer(t, database)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(currentTest.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/admin/oauth-clients", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, admin.Username, auth.RoleAdmin, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			currentTest.checkResponse(recorder)
+		})
+	}
+}
+
+func TestListOAuthClientsAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := db.OauthClient{
+		ID:           uuid.New(),
+		Name:         "contacts-sync",
+		Owner:        "owner",
+		HashedSecret: authHelper.HashSecret("secret"),
+		Scopes:       []string{scopeContactsRead},
+		LastUsedAt:   sql.NullTime{Time: time.Now(), Valid: true},
+		CreatedAt:    time.Now(),
+	}
+	database := mockdb.NewMockDatabase(ctrl)
+	database.EXPECT().
+		ListOAuthClients(gomock.Any()).
+		Times(1).
+		Return([]db.OauthClient{client}, nil)
+
+	server := newTestServer(t, database)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/admin/oauth-clients", nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "admin", auth.RoleAdmin, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	data, err := ioutil.ReadAll(recorder.Body)
+	require.NoError(t, err)
+	var clients []oauthClientResponse
+	require.NoError(t, json.Unmarshal(data, &clients))
+	require.Len(t, clients, 1)
+	require.Equal(t, client.ID, clients[0].ClientID)
+	require.NotNil(t, clients[0].LastUsedAt)
+	require.NotContains(t, string(data), client.HashedSecret)
+}
+
+func TestDeleteOAuthClientAPI(t *testing.T) {
+	admin, _ := randomUser(t)
+	client := db.OauthClient{
+		ID:     uuid.New(),
+		Name:   "contacts-sync",
+		Owner:  "owner",
+		Scopes: []string{scopeContactsRead},
+	}
+
+	testCases := []struct {
+		name          string
+		id            string
+		buildStubs    func(database *mockdb.MockDatabase)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			id:   client.ID.String(),
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetOAuthClient(gomock.Any(), gomock.Eq(client.ID)).
+					Times(1).
+					Return(client, nil)
+				expectOAuthClientAuditTx(t, database, admin.Username, auditActionDeleteOAuthClient, client.Owner)
+				database.EXPECT().
+					DeleteOAuthClient(gomock.Any(), gomock.Eq(client.ID)).
+					Times(1).
+					Return(int64(1), nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "Not Found",
+			id:   client.ID.String(),
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetOAuthClient(gomock.Any(), gomock.Eq(client.ID)).
+					Times(1).
+					Return(db.OauthClient{}, sql.ErrNoRows)
+				database.EXPECT().
+					AuditTx(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "Deleted Concurrently",
+			id:   client.ID.String(),
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetOAuthClient(gomock.Any(), gomock.Eq(client.ID)).
+					Times(1).
+					Return(client, nil)
+				expectOAuthClientAuditTx(t, database, admin.Username, auditActionDeleteOAuthClient, client.Owner)
+				database.EXPECT().
+					DeleteOAuthClient(gomock.Any(), gomock.Eq(client.ID)).
+					Times(1).
+					Return(int64(0), nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "Invalid ID",
+			id:   "invalid",
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetOAuthClient(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		currentTest := testCases[i]
+
+		t.Run(currentTest.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			database := mockdb.NewMockDatabase(ctrl)
+			currentTest.buildStubs(database)
+
+			server := newTestServer(t, database)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("/admin/oauth-clients/%s", currentTest.id), nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, admin.Username, auth.RoleAdmin, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			currentTest.checkResponse(recorder)
+		})
+	}
+}
+
+func TestOAuthTokenAPI(t *testing.T) {
+	owner, _ := randomUser(t)
+	clientSecret, err := authHelper.GenerateSecret(oauthClientSecretSize)
+	require.NoError(t, err)
+	client := db.OauthClient{
+		ID:           uuid.New(),
+		Name:         "contacts-sync",
+		Owner:        owner.Username,
+		HashedSecret: authHelper.HashSecret(clientSecret),
+		Scopes:       []string{scopeContactsRead, scopeContactsWrite},
+	}
+
+	testCases := []struct {
+		name          string
+		form          url.Values
+		basicAuth     bool
+		buildStubs    func(database *mockdb.MockDatabase)
+		checkResponse func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			form:      url.Values{"grant_type": {"client_credentials"}},
+			basicAuth: true,
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetOAuthClient(gomock.Any(), gomock.Eq(client.ID)).
+					Times(1).
+					Return(client, nil)
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(owner.Username)).
+					Times(1).
+					Return(owner, nil)
+				database.EXPECT().
+					UseOAuthClient(gomock.Any(), gomock.Eq(client.ID)).
+					Times(1)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "no-store", recorder.Header().Get("Cache-Control"))
+
+				var rsp oauthTokenResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, "Bearer", rsp.TokenType)
+				require.Equal(t, int64(60), rsp.ExpiresIn)
+				require.Equal(t, "contacts:read contacts:write", rsp.Scope)
+
+				payload, err := server.tokenMaker.VerifyToken(rsp.AccessToken, auth.TokenTypeAccess)
+				require.NoError(t, err)
+				require.Equal(t, owner.Username, payload.Username)
+				require.Equal(t, owner.Role, payload.Role)
+				require.Equal(t, client.ID.String(), payload.ClientID)
+				require.Equal(t, client.Scopes, payload.Scopes)
+			},
+		},
+		{
+			name: "Form Credentials With Narrower Scope",
+			form: url.Values{
+				"grant_type":    {"client_credentials"},
+				"client_id":     {client.ID.String()},
+				"client_secret": {clientSecret},
+				"scope":         {scopeContactsRead},
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetOAuthClient(gomock.Any(), gomock.Eq(client.ID)).
+					Times(1).
+					Return(client, nil)
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(owner.Username)).
+					Times(1).
+					Return(owner, nil)
+				database.EXPECT().
+					UseOAuthClient(gomock.Any(), gomock.Eq(client.ID)).
+					Times(1)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp oauthTokenResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				payload, err := server.tokenMaker.VerifyToken(rsp.AccessToken, auth.TokenTypeAccess)
+				require.NoError(t, err)
+				require.Equal(t, []string{scopeContactsRead}, payload.Scopes)
+			},
+		},
+		{
+			name: "Scope Not Allowed",
+			form: url.Values{
+				"grant_type": {"client_credentials"},
+				"scope":      {scopeSkillsRead},
+			},
+			basicAuth: true,
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetOAuthClient(gomock.Any(), gomock.Eq(client.ID)).
+					Times(1).
+					Return(client, nil)
+				database.EXPECT().
+					UseOAuthClient(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), oauthErrorInvalidScope)
+			},
+		},
+		{
+			name: "Wrong Secret",
+			form: url.Values{
+				"grant_type":    {"client_credentials"},
+				"client_id":     {client.ID.String()},
+				"client_secret": {"wrong"},
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetOAuthClient(gomock.Any(), gomock.Eq(client.ID)).
+					Times(1).
+					Return(client, nil)
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.Contains(t, recorder.Body.String(), oauthErrorInvalidClient)
+				require.NotEmpty(t, recorder.Header().Get("WWW-Authenticate"))
+			},
+		},
+		{
+			name:      "Unknown Client",
+			form:      url.Values{"grant_type": {"client_credentials"}},
+			basicAuth: true,
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetOAuthClient(gomock.Any(), gomock.Eq(client.ID)).
+					Times(1).
+					Return(db.OauthClient{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.Contains(t, recorder.Body.String(), oauthErrorInvalidClient)
+			},
+		},
+		{
+			name: "Missing Credentials",
+			form: url.Values{"grant_type": {"client_credentials"}},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetOAuthClient(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.Contains(t, recorder.Body.String(), oauthErrorInvalidClient)
+			},
+		},
+		{
+			name: "Credentials Sent Twice",
+			form: url.Values{
+				"grant_type": {"client_credentials"},
+				"client_id":  {client.ID.String()},
+			},
+			basicAuth: true,
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetOAuthClient(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), oauthErrorInvalidRequest)
+			},
+		},
+		{
+			name:      "Unsupported Grant Type",
+			form:      url.Values{"grant_type": {"password"}},
+			basicAuth: true,
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetOAuthClient(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), oauthErrorUnsupportedGrantType)
+			},
+		},
+		{
+			name:      "Disabled Owner",
+			form:      url.Values{"grant_type": {"client_credentials"}},
+			basicAuth: true,
+			buildStubs: func(database *mockdb.MockDatabase) {
+				disabledOwner := owner
+				disabledOwner.DisabledAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+				database.EXPECT().
+					GetOAuthClient(gomock.Any(), gomock.Eq(client.ID)).
+					Times(1).
+					Return(client, nil)
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(owner.Username)).
+					Times(1).
+					Return(disabledOwner, nil)
+				database.EXPECT().
+					UseOAuthClient(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), oauthErrorUnauthorizedClient)
+			},
+		},
+		{
+			name:      "Internal Error",
+			form:      url.Values{"grant_type": {"client_credentials"}},
+			basicAuth: true,
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetOAuthClient(gomock.Any(), gomock.Eq(client.ID)).
+					Times(1).
+					Return(db.OauthClient{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		currentTest := testCases[i]
+
+		t.Run(currentTest.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			database := mockdb.NewMockDatabase(ctrl)
+			currentTest.buildStubs(database)
+
+			server := newTestServer(t, database)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPost, "/oauth/token", strings.NewReader(currentTest.form.Encode()))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if currentTest.basicAuth {
+				request.SetBasicAuth(url.QueryEscape(client.ID.String()), url.QueryEscape(clientSecret))
+			}
+
+			server.router.ServeHTTP(recorder, request)
+			currentTest.checkResponse(t, server, recorder)
+		})
+	}
+}
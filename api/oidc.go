@@ -0,0 +1,286 @@
+package api
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/IsuruHaupe/web-api/auth"
+	"github.com/IsuruHaupe/web-api/db/database"
+	db "github.com/IsuruHaupe/web-api/db/sqlc"
+	"github.com/IsuruHaupe/web-api/oidc"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+const (
+	// Cookie binding the callback to the browser which started the login, it holds the state.
+	oidcStateCookie = "oidc_state"
+	// Lifetime of a login with the identity provider when none is configured.
+	defaultOIDCLoginDuration = 10 * time.Minute
+	// Number of usernames tried when provisioning an user whose preferred username is already taken.
+	oidcUsernameAttempts = 5
+)
+
+var (
+	errInvalidOIDCLogin = errors.New("invalid or expired login with the identity provider")
+	errMissingOIDCEmail = errors.New("the identity provider did not share an email address")
+	errOIDCEmailTaken   = errors.New("an account already uses the email of this identity, login with its password")
+)
+
+// oidcLogin godoc
+// @Summary Login with the identity provider
+// @Description This function is used to start a login with the OpenID Connect identity provider, the user is redirected to it.
+// @Description The identity provider sends the user back to /users/oidc/callback, which returns the same response as /users/login.
+// @Tags user
+// @Success 302
+// @Router /users/oidc/login [get]
+func (server *Server) oidcLogin(ctx *gin.Context) {
+	state, err := oidc.GenerateRandomValue()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	nonce, err := oidc.GenerateRandomValue()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	codeVerifier, err := oidc.GenerateRandomValue()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	duration := server.config.OIDCLoginDuration
+	if duration == 0 {
+		duration = defaultOIDCLoginDuration
+	}
+
+	// Logins which were never finished are cleaned up along the way.
+	err = server.database.DeleteExpiredOIDCLogins(ctx)
+	if err != nil {
+		ctx.Error(err)
+	}
+
+	// Only the hash of the state is stored, the code verifier never leaves the server.
+	_, err = server.database.CreateOIDCLogin(ctx, db.CreateOIDCLoginParams{
+		HashedState:  auth.HashSecret(state),
+		CodeVerifier: codeVerifier,
+		Nonce:        nonce,
+		ExpiresAt:    time.Now().Add(duration),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	server.setOIDCStateCookie(ctx, state, int(duration.Seconds()))
+	ctx.Redirect(http.StatusFound, server.oidcProvider.AuthCodeURL(state, nonce, codeVerifier))
+}
+
+func (server *Server) setOIDCStateCookie(ctx *gin.Context, value string, maxAge int) {
+	secure := strings.HasPrefix(server.config.OIDCRedirectURL, "https://")
+	ctx.SetSameSite(http.SameSiteLaxMode)
+	ctx.SetCookie(oidcStateCookie, value, maxAge, "/users/oidc", "", secure, true)
+}
+
+// Request holder when the identity provider sends the user back.
+type oidcCallbackRequest struct {
+	Code             string `form:"code"`
+	State            string `form:"state" binding:"required"`
+	Error            string `form:"error"`
+	ErrorDescription string `form:"error_description"`
+}
+
+// oidcCallback godoc
+// @Summary Finish a login with the identity provider
+// @Description This function is used by the identity provider to send the user back with an authorization code.
+// @Description Users signing in for the first time are created from the claims of the ID token, with the email verified if the identity provider says so.
+// @Description No account is created when the email is already used by an user who did not sign in with the identity provider.
+// @Description When two-factor authentication is enabled, a challenge token is returned instead and the login is finished on /users/login/2fa.
+// @Tags user
+// @Produce json
+// @Param code query string true "code"
+// @Param state query string true "state"
+// @Success 200 {object} api.loginUserResponse
+// @Success 200 {object} api.twoFactorChallengeResponse
+// @Router /users/oidc/callback [get]
+func (server *Server) oidcCallback(ctx *gin.Context) {
+	var req oidcCallbackRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// The state must be the one given to this browser, otherwise an attacker could log the user in its own account.
+	cookie, err := ctx.Cookie(oidcStateCookie)
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookie), []byte(req.State)) != 1 {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errInvalidOIDCLogin))
+		return
+	}
+	server.setOIDCStateCookie(ctx, "", -1)
+
+	// The login can only be finished once.
+	oidcLogin, err := server.database.ConsumeOIDCLogin(ctx, auth.HashSecret(req.State))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(errInvalidOIDCLogin))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if req.Error != "" {
+		err := fmt.Errorf("the identity provider refused the login : %s %s", req.Error, req.ErrorDescription)
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+	if req.Code == "" {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errors.New("missing authorization code")))
+		return
+	}
+
+	rawIDToken, err := server.oidcProvider.Exchange(ctx, req.Code, oidcLogin.CodeVerifier)
+	if err != nil {
+		ctx.JSON(http.StatusBadGateway, errorResponse(err))
+		return
+	}
+
+	claims, err := server.oidcProvider.VerifyIDToken(ctx, rawIDToken, oidcLogin.Nonce)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	user, err := server.database.GetUserByIdentity(ctx, db.GetUserByIdentityParams{
+		Issuer:  server.oidcProvider.Issuer,
+		Subject: claims.Subject,
+	})
+	if err == sql.ErrNoRows {
+		user, err = server.provisionOIDCUser(ctx, claims)
+		if err != nil {
+			switch {
+			case errors.Is(err, errMissingOIDCEmail):
+				ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			case errors.Is(err, errOIDCEmailTaken):
+				ctx.JSON(http.StatusForbidden, errorResponse(err))
+			default:
+				if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "unique_violation" {
+					ctx.JSON(http.StatusForbidden, errorResponse(err))
+					return
+				}
+				ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			}
+			return
+		}
+	} else if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	server.completeLogin(ctx, user)
+}
+
+// This function will create the user signing in with the identity provider for the first time. Existing accounts
+// are never linked through their email, which the identity provider could let anybody choose.
+func (server *Server) provisionOIDCUser(ctx *gin.Context, claims *oidc.Claims) (db.User, error) {
+	if claims.Email == "" {
+		return db.User{}, errMissingOIDCEmail
+	}
+
+	_, err := server.database.GetUserByEmail(ctx, claims.Email)
+	if err == nil {
+		return db.User{}, errOIDCEmailTaken
+	}
+	if err != sql.ErrNoRows {
+		return db.User{}, err
+	}
+
+	username, err := server.availableUsername(ctx, claims)
+	if err != nil {
+		return db.User{}, err
+	}
+
+	// The user has no password until it asks for a password reset.
+	unusablePassword, err := auth.GenerateSecret(passwordResetTokenSize)
+	if err != nil {
+		return db.User{}, err
+	}
+	hashedPassword, err := server.passwordHasher.Hash(unusablePassword)
+	if err != nil {
+		return db.User{}, err
+	}
+
+	fullname := claims.Name
+	if fullname == "" {
+		fullname = username
+	}
+
+	user, err := server.database.ProvisionOIDCUserTx(ctx, database.ProvisionOIDCUserTxParams{
+		User: db.CreateUserParams{
+			Username:       username,
+			HashedPassword: hashedPassword,
+			Fullname:       fullname,
+			Email:          claims.Email,
+		},
+		EmailVerified: claims.EmailVerified,
+		Issuer:        server.oidcProvider.Issuer,
+		Subject:       claims.Subject,
+	})
+	if err != nil {
+		return db.User{}, err
+	}
+
+	// The account is created even if the email can not be sent, a new link can be requested later on.
+	if !user.EmailVerified {
+		err = server.sendVerificationEmail(user)
+		if err != nil {
+			ctx.Error(err)
+		}
+	}
+
+	return user, nil
+}
+
+// This function will find a free username for a new user, based on its preferred username or its email.
+// Usernames are alphanumeric so other characters are dropped, digits are added when the username is taken.
+func (server *Server) availableUsername(ctx *gin.Context, claims *oidc.Claims) (string, error) {
+	base := alphanumeric(claims.PreferredUsername)
+	if base == "" {
+		base = alphanumeric(strings.SplitN(claims.Email, "@", 2)[0])
+	}
+	if base == "" {
+		base = "user"
+	}
+
+	username := base
+	for i := 0; i < oidcUsernameAttempts; i++ {
+		_, err := server.database.GetUser(ctx, username)
+		if err == sql.ErrNoRows {
+			return username, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		username = fmt.Sprintf("%s%04d", base, rand.Intn(10000))
+	}
+	return "", fmt.Errorf("no username available for %s", base)
+}
+
+func alphanumeric(value string) string {
+	var sb strings.Builder
+	for _, r := range value {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,541 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/IsuruHaupe/web-api/auth"
+	dbtx "github.com/IsuruHaupe/web-api/db/database"
+	mockdb "github.com/IsuruHaupe/web-api/db/mock"
+	db "github.com/IsuruHaupe/web-api/db/sqlc"
+	mockmail "github.com/IsuruHaupe/web-api/mail/mock"
+	"github.com/IsuruHaupe/web-api/oidc"
+	"github.com/IsuruHaupe/web-api/oidc/oidctest"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+const testOIDCRedirectURL = "http://localhost:8080/users/oidc/callback"
+
+// newTestOIDCServer creates a new test server signing in users with a local identity provider.
+func newTestOIDCServer(t *testing.T, database dbtx.Database, mailer *mockmail.MockMailer, idp *oidctest.IdentityProvider) *Server {
+	server := newTestServerWithMailer(t, database, mailer)
+	server.config.OIDCLoginDuration = time.Minute
+
+	provider, err := oidc.NewProvider(idp.Issuer(), idp.ClientID, idp.ClientSecret, idp.AuthURL(), idp.TokenURL(), idp.JWKSURL(), testOIDCRedirectURL, nil)
+	require.NoError(t, err)
+	server.oidcProvider = provider
+	server.setUpRouter()
+
+	return server
+}
+
+func newTestIdentityProvider(t *testing.T) *oidctest.IdentityProvider {
+	idp, err := oidctest.New("web-api", "secret")
+	require.NoError(t, err)
+	t.Cleanup(idp.Close)
+	return idp
+}
+
+func TestOIDCDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServer(t, mockdb.NewMockDatabase(ctrl))
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/users/oidc/login", nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestOIDCLoginAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	idp := newTestIdentityProvider(t)
+	database := mockdb.NewMockDatabase(ctrl)
+	var oidcLogin db.CreateOIDCLoginParams
+	database.EXPECT().
+		DeleteExpiredOIDCLogins(gomock.Any()).
+		Times(1)
+	database.EXPECT().
+		CreateOIDCLogin(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ interface{}, arg db.CreateOIDCLoginParams) (db.OidcLogin, error) {
+			require.WithinDuration(t, time.Now().Add(time.Minute), arg.ExpiresAt, time.Second)
+			oidcLogin = arg
+			return db.OidcLogin{HashedState: arg.HashedState}, nil
+		})
+
+	server := newTestOIDCServer(t, database, nil, idp)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/users/oidc/login", nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusFound, recorder.Code)
+
+	location, err := url.Parse(recorder.Header().Get("Location"))
+	require.NoError(t, err)
+	require.Equal(t, idp.AuthURL(), location.Scheme+"://"+location.Host+location.Path)
+
+	// Only hashes and derived values of the secrets are sent to the identity provider.
+	query := location.Query()
+	state := query.Get("state")
+	require.Equal(t, auth.HashSecret(state), oidcLogin.HashedState)
+	require.Equal(t, oidcLogin.Nonce, query.Get("nonce"))
+	require.Equal(t, oidc.CodeChallenge(oidcLogin.CodeVerifier), query.Get("code_challenge"))
+	require.NotContains(t, location.String(), oidcLogin.CodeVerifier)
+
+	cookies := recorder.Result().Cookies()
+	require.Len(t, cookies, 1)
+	require.Equal(t, oidcStateCookie, cookies[0].Name)
+	require.Equal(t, state, cookies[0].Value)
+	require.True(t, cookies[0].HttpOnly)
+}
+
+func TestOIDCCallbackAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	subject := auth.RandomString(16)
+	claims := map[string]interface{}{
+		"email":              user.Email,
+		"email_verified":     true,
+		"name":               user.Fullname,
+		"preferred_username": user.Username,
+	}
+
+	testCases := []struct {
+		name          string
+		claims        map[string]interface{}
+		query         func(query url.Values)
+		cookie        func(state string) string
+		buildStubs    func(database *mockdb.MockDatabase, mailer *mockmail.MockMailer, oidcLogin db.OidcLogin)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK",
+			claims: claims,
+			buildStubs: func(database *mockdb.MockDatabase, mailer *mockmail.MockMailer, oidcLogin db.OidcLogin) {
+				database.EXPECT().
+					ConsumeOIDCLogin(gomock.Any(), gomock.Eq(oidcLogin.HashedState)).
+					Times(1).
+					Return(oidcLogin, nil)
+				database.EXPECT().
+					GetUserByIdentity(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.GetUserByIdentityParams) (db.User, error) {
+						require.Equal(t, subject, arg.Subject)
+						return user, nil
+					})
+				database.EXPECT().
+					ProvisionOIDCUserTx(gomock.Any(), gomock.Any()).
+					Times(0)
+				database.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "access_token")
+			},
+		},
+		{
+			name:   "New User",
+			claims: claims,
+			buildStubs: func(database *mockdb.MockDatabase, mailer *mockmail.MockMailer, oidcLogin db.OidcLogin) {
+				database.EXPECT().
+					ConsumeOIDCLogin(gomock.Any(), gomock.Eq(oidcLogin.HashedState)).
+					Times(1).
+					Return(oidcLogin, nil)
+				database.EXPECT().
+					GetUserByIdentity(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, sql.ErrNoRows)
+				database.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).
+					Times(1).
+					Return(db.User{}, sql.ErrNoRows)
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(db.User{}, sql.ErrNoRows)
+				database.EXPECT().
+					ProvisionOIDCUserTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg dbtx.ProvisionOIDCUserTxParams) (db.User, error) {
+						require.Equal(t, user.Username, arg.User.Username)
+						require.Equal(t, user.Fullname, arg.User.Fullname)
+						require.Equal(t, user.Email, arg.User.Email)
+						require.NotEmpty(t, arg.User.HashedPassword)
+						require.True(t, arg.EmailVerified)
+						require.Equal(t, subject, arg.Subject)
+						return user, nil
+					})
+				mailer.EXPECT().
+					SendEmail(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+				database.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "access_token")
+			},
+		},
+		{
+			name: "New User Unverified Email",
+			claims: map[string]interface{}{
+				"email":          user.Email,
+				"email_verified": false,
+			},
+			buildStubs: func(database *mockdb.MockDatabase, mailer *mockmail.MockMailer, oidcLogin db.OidcLogin) {
+				unverifiedUser := user
+				unverifiedUser.EmailVerified = false
+
+				database.EXPECT().
+					ConsumeOIDCLogin(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(oidcLogin, nil)
+				database.EXPECT().
+					GetUserByIdentity(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, sql.ErrNoRows)
+				database.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, sql.ErrNoRows)
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, sql.ErrNoRows)
+				database.EXPECT().
+					ProvisionOIDCUserTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg dbtx.ProvisionOIDCUserTxParams) (db.User, error) {
+						require.False(t, arg.EmailVerified)
+						require.Equal(t, arg.User.Username, arg.User.Fullname)
+						return unverifiedUser, nil
+					})
+				mailer.EXPECT().
+					SendEmail(gomock.Eq(user.Email), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+				database.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "New User Username Taken",
+			claims: claims,
+			buildStubs: func(database *mockdb.MockDatabase, mailer *mockmail.MockMailer, oidcLogin db.OidcLogin) {
+				database.EXPECT().
+					ConsumeOIDCLogin(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(oidcLogin, nil)
+				database.EXPECT().
+					GetUserByIdentity(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, sql.ErrNoRows)
+				database.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, sql.ErrNoRows)
+				gomock.InOrder(
+					database.EXPECT().
+						GetUser(gomock.Any(), gomock.Eq(user.Username)).
+						Times(1).
+						Return(db.User{Username: user.Username}, nil),
+					database.EXPECT().
+						GetUser(gomock.Any(), gomock.Any()).
+						Times(1).
+						Return(db.User{}, sql.ErrNoRows),
+				)
+				database.EXPECT().
+					ProvisionOIDCUserTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg dbtx.ProvisionOIDCUserTxParams) (db.User, error) {
+						require.Regexp(t, fmt.Sprintf("^%s[0-9]{4}$", user.Username), arg.User.Username)
+						return user, nil
+					})
+				database.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "Email Taken",
+			claims: claims,
+			buildStubs: func(database *mockdb.MockDatabase, mailer *mockmail.MockMailer, oidcLogin db.OidcLogin) {
+				database.EXPECT().
+					ConsumeOIDCLogin(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(oidcLogin, nil)
+				database.EXPECT().
+					GetUserByIdentity(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, sql.ErrNoRows)
+				database.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).
+					Times(1).
+					Return(user, nil)
+				database.EXPECT().
+					ProvisionOIDCUserTx(gomock.Any(), gomock.Any()).
+					Times(0)
+				database.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errOIDCEmailTaken.Error())
+			},
+		},
+		{
+			name:   "Missing Email",
+			claims: map[string]interface{}{},
+			buildStubs: func(database *mockdb.MockDatabase, mailer *mockmail.MockMailer, oidcLogin db.OidcLogin) {
+				database.EXPECT().
+					ConsumeOIDCLogin(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(oidcLogin, nil)
+				database.EXPECT().
+					GetUserByIdentity(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, sql.ErrNoRows)
+				database.EXPECT().
+					ProvisionOIDCUserTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errMissingOIDCEmail.Error())
+			},
+		},
+		{
+			name:   "Two Factor",
+			claims: claims,
+			buildStubs: func(database *mockdb.MockDatabase, mailer *mockmail.MockMailer, oidcLogin db.OidcLogin) {
+				twoFactorUser := user
+				twoFactorUser.TotpEnabled = true
+
+				database.EXPECT().
+					ConsumeOIDCLogin(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(oidcLogin, nil)
+				database.EXPECT().
+					GetUserByIdentity(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(twoFactorUser, nil)
+				database.EXPECT().
+					CreateTwoFactorChallenge(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TwoFactorChallenge{Username: user.Username}, nil)
+				database.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "challenge_token")
+				require.NotContains(t, recorder.Body.String(), "access_token")
+			},
+		},
+		{
+			name:   "Disabled",
+			claims: claims,
+			buildStubs: func(database *mockdb.MockDatabase, mailer *mockmail.MockMailer, oidcLogin db.OidcLogin) {
+				disabledUser := user
+				disabledUser.DisabledAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+				database.EXPECT().
+					ConsumeOIDCLogin(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(oidcLogin, nil)
+				database.EXPECT().
+					GetUserByIdentity(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(disabledUser, nil)
+				database.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errAccountDisabled.Error())
+			},
+		},
+		{
+			name:   "Missing Cookie",
+			claims: claims,
+			cookie: func(state string) string {
+				return ""
+			},
+			buildStubs: func(database *mockdb.MockDatabase, mailer *mockmail.MockMailer, oidcLogin db.OidcLogin) {
+				database.EXPECT().
+					ConsumeOIDCLogin(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:   "Cookie Of Another Login",
+			claims: claims,
+			cookie: func(state string) string {
+				return state + "x"
+			},
+			buildStubs: func(database *mockdb.MockDatabase, mailer *mockmail.MockMailer, oidcLogin db.OidcLogin) {
+				database.EXPECT().
+					ConsumeOIDCLogin(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:   "Expired Login",
+			claims: claims,
+			buildStubs: func(database *mockdb.MockDatabase, mailer *mockmail.MockMailer, oidcLogin db.OidcLogin) {
+				database.EXPECT().
+					ConsumeOIDCLogin(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.OidcLogin{}, sql.ErrNoRows)
+				database.EXPECT().
+					GetUserByIdentity(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errInvalidOIDCLogin.Error())
+			},
+		},
+		{
+			name:   "Identity Provider Error",
+			claims: claims,
+			query: func(query url.Values) {
+				query.Del("code")
+				query.Set("error", "access_denied")
+			},
+			buildStubs: func(database *mockdb.MockDatabase, mailer *mockmail.MockMailer, oidcLogin db.OidcLogin) {
+				database.EXPECT().
+					ConsumeOIDCLogin(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(oidcLogin, nil)
+				database.EXPECT().
+					GetUserByIdentity(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "access_denied")
+			},
+		},
+		{
+			name:   "Wrong Code Verifier",
+			claims: claims,
+			buildStubs: func(database *mockdb.MockDatabase, mailer *mockmail.MockMailer, oidcLogin db.OidcLogin) {
+				oidcLogin.CodeVerifier = auth.RandomString(43)
+				database.EXPECT().
+					ConsumeOIDCLogin(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(oidcLogin, nil)
+				database.EXPECT().
+					GetUserByIdentity(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadGateway, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "invalid_grant")
+			},
+		},
+		{
+			name:   "Wrong Nonce",
+			claims: claims,
+			buildStubs: func(database *mockdb.MockDatabase, mailer *mockmail.MockMailer, oidcLogin db.OidcLogin) {
+				oidcLogin.Nonce = auth.RandomString(43)
+				database.EXPECT().
+					ConsumeOIDCLogin(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(oidcLogin, nil)
+				database.EXPECT().
+					GetUserByIdentity(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.Contains(t, recorder.Body.String(), oidc.ErrInvalidIDToken.Error())
+			},
+		},
+	}
+
+	for i := range testCases {
+		currentTest := testCases[i]
+
+		t.Run(currentTest.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			idp := newTestIdentityProvider(t)
+			idp.SetUser(subject, currentTest.claims)
+
+			database := mockdb.NewMockDatabase(ctrl)
+			mailer := mockmail.NewMockMailer(ctrl)
+			server := newTestOIDCServer(t, database, mailer, idp)
+
+			// The browser goes through the identity provider, which sends it back with a code.
+			state, err := oidc.GenerateRandomValue()
+			require.NoError(t, err)
+			nonce, err := oidc.GenerateRandomValue()
+			require.NoError(t, err)
+			codeVerifier, err := oidc.GenerateRandomValue()
+			require.NoError(t, err)
+			callback, err := idp.Authorize(server.oidcProvider.AuthCodeURL(state, nonce, codeVerifier))
+			require.NoError(t, err)
+
+			currentTest.buildStubs(database, mailer, db.OidcLogin{
+				HashedState:  auth.HashSecret(state),
+				CodeVerifier: codeVerifier,
+				Nonce:        nonce,
+				ExpiresAt:    time.Now().Add(time.Minute),
+			})
+
+			query := callback.Query()
+			if currentTest.query != nil {
+				currentTest.query(query)
+			}
+			cookie := state
+			if currentTest.cookie != nil {
+				cookie = currentTest.cookie(state)
+			}
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, "/users/oidc/callback?"+query.Encode(), nil)
+			require.NoError(t, err)
+			if cookie != "" {
+				request.AddCookie(&http.Cookie{Name: oidcStateCookie, Value: cookie})
+			}
+
+			server.router.ServeHTTP(recorder, request)
+			currentTest.checkResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/IsuruHaupe/web-api/auth"
+	"github.com/IsuruHaupe/web-api/db/database"
+	db "github.com/IsuruHaupe/web-api/db/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+// Number of random bytes of the password reset tokens.
+const passwordResetTokenSize = 32
+
+var errInvalidPasswordReset = errors.New("invalid or expired password reset token")
+
+// Request holder when receiving a forgot password request.
+type forgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// forgotPassword godoc
+// @Summary Request a password reset
+// @Description This function is used to send a password reset token to the email of an user.
+// @Description A token is sent at most once per PASSWORD_RESET_RESEND_INTERVAL, it replaces the unused tokens sent before.
+// @Description The response is the same whether an account exists for the email or not.
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param user body api.forgotPasswordRequest true "Forgot Password"
+// @Success 200 {string} string "If an account exists for this email, a password reset token has been sent."
+// @Router /users/password/forgot [post]
+func (server *Server) forgotPassword(ctx *gin.Context) {
+	var req forgotPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	const response = "If an account exists for this email, a password reset token has been sent."
+
+	// We do not tell whether the email belongs to an user. The update only matches users to whom no token was sent
+	// recently, which rate limits the endpoint.
+	user, err := server.database.MarkPasswordResetSent(ctx, db.MarkPasswordResetSentParams{
+		Email:      req.Email,
+		SentBefore: time.Now().Add(-server.config.PasswordResetResendInterval),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusOK, response)
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	resetToken, err := auth.GenerateSecret(passwordResetTokenSize)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// Only the latest token can be used, the ones sent before are forgotten.
+	err = server.database.DeleteUnusedPasswordResets(ctx, user.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// Only the hash of the token is stored, the token itself is only sent by email.
+	passwordReset, err := server.database.CreatePasswordReset(ctx, db.CreatePasswordResetParams{
+		HashedToken: auth.HashSecret(resetToken),
+		Username:    user.Username,
+		ExpiresAt:   time.Now().Add(server.config.PasswordResetDuration),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// A failure is only logged, answering with an error would tell that the email belongs to an user.
+	err = server.sendPasswordResetEmail(user, resetToken, passwordReset)
+	if err != nil {
+		ctx.Error(err)
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// This function will send the password reset token to the email of an user.
+func (server *Server) sendPasswordResetEmail(user db.User, resetToken string, passwordReset db.PasswordReset) error {
+	content := fmt.Sprintf(
+		"Hello %s,\n\nA password reset has been requested for your account. Use the following token to choose a new password :\n\n%s\n\nThe token can be used once and expires at %s. If you did not request a password reset, you can ignore this email.\n",
+		user.Fullname,
+		resetToken,
+		passwordReset.ExpiresAt.Format(time.RFC1123),
+	)
+	return server.mailer.SendEmail(user.Email, "Reset your password", content)
+}
+
+// Request holder when receiving a reset password request.
+type resetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+// resetPassword godoc
+// @Summary Reset the password of an user
+// @Description This function is used to choose a new password with the token received by email.
+// @Description The token can only be used once and every session of the user is revoked.
+// @Description The new password must follow the password policy.
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param user body api.resetPasswordRequest true "Reset Password"
+// @Success 200 {object} api.userResponse
+// @Router /users/password/reset [post]
+func (server *Server) resetPassword(ctx *gin.Context) {
+	var req resetPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	hashedToken := auth.HashSecret(req.Token)
+
+	// The user is needed to check that the new password does not contain its username or email.
+	passwordReset, err := server.database.GetPasswordReset(ctx, hashedToken)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(errInvalidPasswordReset))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	user, err := server.database.GetUser(ctx, passwordReset.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	err = server.passwordPolicy.Check(req.NewPassword, user.Username, user.Email)
+	if err != nil {
+		if errors.Is(err, auth.ErrPasswordPolicy) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	hashedPassword, err := server.passwordHasher.Hash(req.NewPassword)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// The token is only consumed here, a concurrent request using it fails.
+	user, err = server.database.ResetPasswordTx(ctx, database.ResetPasswordTxParams{
+		HashedToken:    hashedToken,
+		HashedPassword: hashedPassword,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(errInvalidPasswordReset))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUserResponse(user))
+}
@@ -0,0 +1,390 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/IsuruHaupe/web-api/auth"
+	dbtx "github.com/IsuruHaupe/web-api/db/database"
+	mockdb "github.com/IsuruHaupe/web-api/db/mock"
+	db "github.com/IsuruHaupe/web-api/db/sqlc"
+	mockmail "github.com/IsuruHaupe/web-api/mail/mock"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForgotPasswordAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(database *mockdb.MockDatabase, mailer *mockmail.MockMailer)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"email": user.Email,
+			},
+			buildStubs: func(database *mockdb.MockDatabase, mailer *mockmail.MockMailer) {
+				var hashedToken string
+				database.EXPECT().
+					MarkPasswordResetSent(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.MarkPasswordResetSentParams) (db.User, error) {
+						require.Equal(t, user.Email, arg.Email)
+						require.WithinDuration(t, time.Now().Add(-time.Minute), arg.SentBefore, time.Second)
+						return user, nil
+					})
+				database.EXPECT().
+					DeleteUnusedPasswordResets(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(nil)
+				database.EXPECT().
+					CreatePasswordReset(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CreatePasswordResetParams) (db.PasswordReset, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.WithinDuration(t, time.Now().Add(time.Minute), arg.ExpiresAt, time.Second)
+						hashedToken = arg.HashedToken
+
+						return db.PasswordReset{
+							HashedToken: arg.HashedToken,
+							Username:    arg.Username,
+							ExpiresAt:   arg.ExpiresAt,
+						}, nil
+					})
+				mailer.EXPECT().
+					SendEmail(gomock.Eq(user.Email), gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(to string, subject string, content string) error {
+						// The email holds the token whose hash has been stored.
+						require.NotContains(t, content, hashedToken)
+						found := false
+						for _, word := range bytes.Fields([]byte(content)) {
+							if auth.HashSecret(string(word)) == hashedToken {
+								found = true
+							}
+						}
+						require.True(t, found)
+						return nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "Unknown Email Or Rate Limited",
+			body: gin.H{
+				"email": "unknown@example.com",
+			},
+			buildStubs: func(database *mockdb.MockDatabase, mailer *mockmail.MockMailer) {
+				database.EXPECT().
+					MarkPasswordResetSent(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, sql.ErrNoRows)
+				database.EXPECT().
+					CreatePasswordReset(gomock.Any(), gomock.Any()).
+					Times(0)
+				mailer.EXPECT().
+					SendEmail(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "Invalid Email",
+			body: gin.H{
+				"email": "invalid",
+			},
+			buildStubs: func(database *mockdb.MockDatabase, mailer *mockmail.MockMailer) {
+				database.EXPECT().
+					MarkPasswordResetSent(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Internal Error",
+			body: gin.H{
+				"email": user.Email,
+			},
+			buildStubs: func(database *mockdb.MockDatabase, mailer *mockmail.MockMailer) {
+				database.EXPECT().
+					MarkPasswordResetSent(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(user, nil)
+				database.EXPECT().
+					DeleteUnusedPasswordResets(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sql.ErrConnDone)
+				database.EXPECT().
+					CreatePasswordReset(gomock.Any(), gomock.Any()).
+					Times(0)
+				mailer.EXPECT().
+					SendEmail(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name: "Mail Error",
+			body: gin.H{
+				"email": user.Email,
+			},
+			buildStubs: func(database *mockdb.MockDatabase, mailer *mockmail.MockMailer) {
+				database.EXPECT().
+					MarkPasswordResetSent(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(user, nil)
+				database.EXPECT().
+					DeleteUnusedPasswordResets(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+				database.EXPECT().
+					CreatePasswordReset(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.PasswordReset{Username: user.Username, ExpiresAt: time.Now()}, nil)
+				mailer.EXPECT().
+					SendEmail(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				// The response is the same as for an unknown email.
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		currentTest := testCases[i]
+
+		t.Run(currentTest.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			database := mockdb.NewMockDatabase(ctrl)
+			mailer := mockmail.NewMockMailer(ctrl)
+			currentTest.buildStubs(database, mailer)
+
+			server := newTestServerWithMailer(t, database, mailer)
+			recorder := httptest.NewRecorder()
+
+			// Marshal body data to JSON
+			data, err := json.Marshal(currentTest.body)
+			require.NoError(t, err)
+
+			url := "/users/password/forgot"
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			currentTest.checkResponse(recorder)
+		})
+	}
+}
+
+func TestResetPasswordAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	resetToken, err := auth.GenerateSecret(passwordResetTokenSize)
+	require.NoError(t, err)
+	newPassword := "new-password"
+	passwordReset := db.PasswordReset{
+		HashedToken: auth.HashSecret(resetToken),
+		Username:    user.Username,
+		ExpiresAt:   time.Now().Add(time.Minute),
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(database *mockdb.MockDatabase)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"token":        resetToken,
+				"new_password": newPassword,
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetPasswordReset(gomock.Any(), gomock.Eq(auth.HashSecret(resetToken))).
+					Times(1).
+					Return(passwordReset, nil)
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				database.EXPECT().
+					ResetPasswordTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg dbtx.ResetPasswordTxParams) (db.User, error) {
+						require.Equal(t, auth.HashSecret(resetToken), arg.HashedToken)
+						require.NoError(t, auth.CheckPassword(newPassword, arg.HashedPassword))
+						return user, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchUser(t, recorder.Body, user)
+			},
+		},
+		{
+			name: "Invalid Token",
+			body: gin.H{
+				"token":        "invalid",
+				"new_password": newPassword,
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetPasswordReset(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.PasswordReset{}, sql.ErrNoRows)
+				database.EXPECT().
+					ResetPasswordTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Token Used Concurrently",
+			body: gin.H{
+				"token":        resetToken,
+				"new_password": newPassword,
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetPasswordReset(gomock.Any(), gomock.Eq(auth.HashSecret(resetToken))).
+					Times(1).
+					Return(passwordReset, nil)
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				database.EXPECT().
+					ResetPasswordTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Too Short Password",
+			body: gin.H{
+				"token":        resetToken,
+				"new_password": "short",
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetPasswordReset(gomock.Any(), gomock.Eq(auth.HashSecret(resetToken))).
+					Times(1).
+					Return(passwordReset, nil)
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				database.EXPECT().
+					ResetPasswordTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), auth.ErrPasswordPolicy.Error())
+			},
+		},
+		{
+			name: "Password Contains Username",
+			body: gin.H{
+				"token":        resetToken,
+				"new_password": user.Username + "-2022",
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetPasswordReset(gomock.Any(), gomock.Eq(auth.HashSecret(resetToken))).
+					Times(1).
+					Return(passwordReset, nil)
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				database.EXPECT().
+					ResetPasswordTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), auth.ErrPasswordPolicy.Error())
+			},
+		},
+		{
+			name: "Internal Error",
+			body: gin.H{
+				"token":        resetToken,
+				"new_password": newPassword,
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetPasswordReset(gomock.Any(), gomock.Eq(auth.HashSecret(resetToken))).
+					Times(1).
+					Return(passwordReset, nil)
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				database.EXPECT().
+					ResetPasswordTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		currentTest := testCases[i]
+
+		t.Run(currentTest.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			database := mockdb.NewMockDatabase(ctrl)
+			currentTest.buildStubs(database)
+
+			server := newTestServer(t, database)
+			recorder := httptest.NewRecorder()
+
+			// Marshal body data to JSON
+			data, err := json.Marshal(currentTest.body)
+			require.NoError(t, err)
+
+			url := "/users/password/reset"
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			currentTest.checkResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/IsuruHaupe/web-api/auth"
+	token "github.com/IsuruHaupe/web-api/auth/token"
+	db "github.com/IsuruHaupe/web-api/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+// getProfile godoc
+// @Security bearerAuth
+// @Summary Get the profile of the logged in user
+// @Tags user
+// @Description This function is used to get the account of the logged in user.
+// @Produce json
+// @Success 200 {object} api.userResponse
+// @Router /users/me [get]
+func (server *Server) getProfile(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	user, err := server.database.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUserResponse(user))
+}
+
+// Request holder when receiving an update profile request, empty fields are left unchanged.
+type updateProfileRequest struct {
+	FullName string `json:"full_name,omitempty"`
+	Email    string `json:"email,omitempty" binding:"omitempty,email"`
+}
+
+// updateProfile godoc
+// @Security bearerAuth
+// @Summary Update the profile of the logged in user
+// @Tags user
+// @Description This function is used to change the full name or the email of the logged in user.
+// @Description A new email must not be used by another account, it has to be verified again with the link sent to it.
+// @Accept json
+// @Produce json
+// @Param user body api.updateProfileRequest true "Update Profile"
+// @Success 200 {object} api.userResponse
+// @Router /users/me [patch]
+func (server *Server) updateProfile(ctx *gin.Context) {
+	var req updateProfileRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	user, err := server.database.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	args := db.UpdateUserProfileParams{
+		Username: user.Username,
+		Fullname: user.Fullname,
+		Email:    user.Email,
+	}
+	if req.FullName != "" {
+		args.Fullname = req.FullName
+	}
+	if req.Email != "" {
+		args.Email = req.Email
+	}
+
+	updatedUser, err := server.database.UpdateUserProfile(ctx, args)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "unique_violation":
+				ctx.JSON(http.StatusForbidden, errorResponse(err))
+				return
+			}
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// The email is updated even if the link can not be sent, a new one can be requested later on.
+	if updatedUser.Email != user.Email {
+		err = server.sendVerificationEmail(updatedUser)
+		if err != nil {
+			ctx.Error(err)
+		}
+	}
+
+	ctx.JSON(http.StatusOK, newUserResponse(updatedUser))
+}
+
+// Request holder when receiving a delete account request.
+type deleteAccountRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+// deleteAccount godoc
+// @Security bearerAuth
+// @Summary Delete the account of the logged in user
+// @Tags user
+// @Description This function is used to delete the account of the logged in user, the password is asked again.
+// @Description The contacts, skills, sessions and API keys of the user are deleted along with it.
+// @Accept json
+// @Produce json
+// @Param user body api.deleteAccountRequest true "Delete Account"
+// @Success 200 {string} string "Successfully deleted account."
+// @Router /users/me [delete]
+func (server *Server) deleteAccount(ctx *gin.Context) {
+	var req deleteAccountRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	user, err := server.database.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	err = auth.CheckPassword(req.Password, user.HashedPassword)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	// Foreign keys cascade the deletion to everything the user owns.
+	err = server.database.DeleteUser(ctx, user.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, "Successfully deleted account.")
+}
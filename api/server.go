@@ -1,12 +1,22 @@
 package api
 
 import (
+	"context"
 	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
 
+	authHelper "github.com/IsuruHaupe/web-api/auth"
 	auth "github.com/IsuruHaupe/web-api/auth/token"
 	"github.com/IsuruHaupe/web-api/config"
 	"github.com/IsuruHaupe/web-api/db/database"
 	"github.com/IsuruHaupe/web-api/docs"
+	"github.com/IsuruHaupe/web-api/lockout"
+	"github.com/IsuruHaupe/web-api/mail"
+	"github.com/IsuruHaupe/web-api/oidc"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"     // swagger embed files
 	ginSwagger "github.com/swaggo/gin-swagger" // gin-swagger middleware
@@ -14,27 +24,163 @@ import (
 
 // This struct is used to regroup the database connection, the gin router and the configuration.
 type Server struct {
-	config     config.Config
-	database   database.Database
-	tokenMaker auth.Maker
+	config        config.Config
+	database      database.Database
+	tokenMaker    auth.Maker
+	mailer        mail.Mailer
+	loginAttempts lockout.Store
+	// Hasher of the new passwords, older hashes are upgraded to it on login.
+	passwordHasher authHelper.PasswordHasher
+	passwordPolicy authHelper.PasswordPolicy
+	// Identity provider users can sign in with, nil when OpenID Connect is not configured.
+	oidcProvider *oidc.Provider
+	// Hash compared on logins of unknown usernames, see checkDummyPassword.
+	dummyPasswordOnce sync.Once
+	dummyPasswordHash string
+	// CSV imports running in the background.
+	importJobs sync.WaitGroup
 	router     *gin.Engine
+	httpServer *http.Server
 }
 
 // This function will create a new server and setup all routes.
-func NewServer(config config.Config, database database.Database) (*Server, error) {
-	tokenMaker, err := auth.NewPasetoMaker(config.TokenSymmetricKey)
+func NewServer(config config.Config, database database.Database, mailer mail.Mailer, loginAttempts lockout.Store) (*Server, error) {
+	tokenMaker, err := newTokenMaker(config)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create token maker : %w", err)
 	}
+	switch config.UnverifiedLogin {
+	case "", unverifiedLoginLimit, unverifiedLoginRefuse:
+	default:
+		return nil, fmt.Errorf("unsupported unverified login behaviour %s", config.UnverifiedLogin)
+	}
+	passwordHasher, err := newPasswordHasher(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create password hasher : %w", err)
+	}
+	passwordPolicy, err := newPasswordPolicy(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create password policy : %w", err)
+	}
+	oidcProvider, err := newOIDCProvider(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create OpenID Connect provider : %w", err)
+	}
 	server := &Server{
-		config:     config,
-		database:   database,
-		tokenMaker: tokenMaker,
+		config:         config,
+		database:       database,
+		tokenMaker:     tokenMaker,
+		mailer:         mailer,
+		loginAttempts:  loginAttempts,
+		passwordHasher: passwordHasher,
+		passwordPolicy: passwordPolicy,
+		oidcProvider:   oidcProvider,
 	}
 	server.setUpRouter()
+	server.httpServer = &http.Server{Handler: server.router}
 	return server, nil
 }
 
+// This function will create the password hasher selected in the configuration, argon2id is used by default.
+// Parameters left empty in the configuration take their default value.
+func newPasswordHasher(config config.Config) (authHelper.PasswordHasher, error) {
+	if config.PasswordHashAlgorithm == "" {
+		return authHelper.DefaultPasswordHasher, nil
+	}
+
+	defaults := authHelper.DefaultPasswordHasher
+	if config.Argon2Memory == 0 {
+		config.Argon2Memory = defaults.Argon2Memory
+	}
+	if config.Argon2Iterations == 0 {
+		config.Argon2Iterations = defaults.Argon2Iterations
+	}
+	if config.Argon2Parallelism == 0 {
+		config.Argon2Parallelism = defaults.Argon2Parallelism
+	}
+	if config.BcryptCost == 0 {
+		config.BcryptCost = defaults.BcryptCost
+	}
+
+	return authHelper.NewPasswordHasher(
+		config.PasswordHashAlgorithm,
+		config.Argon2Memory,
+		config.Argon2Iterations,
+		config.Argon2Parallelism,
+		config.BcryptCost,
+	)
+}
+
+// This function will create the policy new passwords must follow. Breached passwords are only refused when a
+// directory of breached password hashes is configured.
+func newPasswordPolicy(config config.Config) (authHelper.PasswordPolicy, error) {
+	var breached authHelper.BreachedPasswords
+	if config.BreachedPasswordsDir != "" {
+		directory, err := authHelper.NewBreachedPasswordDirectory(config.BreachedPasswordsDir)
+		if err != nil {
+			return authHelper.PasswordPolicy{}, err
+		}
+		breached = directory
+	}
+
+	return authHelper.NewPasswordPolicy(config.PasswordMinLength, config.PasswordMinCharacterClasses, breached)
+}
+
+// This function will create the identity provider users can sign in with, OpenID Connect is only enabled when
+// an issuer is configured.
+func newOIDCProvider(config config.Config) (*oidc.Provider, error) {
+	if config.OIDCIssuer == "" {
+		return nil, nil
+	}
+
+	return oidc.NewProvider(
+		config.OIDCIssuer,
+		config.OIDCClientID,
+		config.OIDCClientSecret,
+		config.OIDCAuthURL,
+		config.OIDCTokenURL,
+		config.OIDCJWKSURL,
+		config.OIDCRedirectURL,
+		strings.Fields(config.OIDCScopes),
+	)
+}
+
+// Token makers available through the TOKEN_MAKER configuration.
+const (
+	tokenMakerPaseto       = "paseto"
+	tokenMakerPasetoPublic = "paseto-public"
+	tokenMakerJWT          = "jwt"
+)
+
+// This function will create the token maker selected in the configuration, paseto v2.local is used by default.
+// When a keyring file is configured, keys are read from it instead of the configuration so that they can be rotated.
+func newTokenMaker(config config.Config) (auth.Maker, error) {
+	switch config.TokenMaker {
+	case "", tokenMakerPaseto:
+		if config.TokenKeyringFile != "" {
+			return auth.NewPasetoKeyringMaker(config.TokenKeyringFile, auth.PurposeLocal, config.TokenIssuer, config.TokenAudience)
+		}
+		return auth.NewPasetoMaker(config.TokenSymmetricKey, config.TokenIssuer, config.TokenAudience)
+	case tokenMakerPasetoPublic:
+		if config.TokenKeyringFile != "" {
+			return auth.NewPasetoKeyringMaker(config.TokenKeyringFile, auth.PurposePublic, config.TokenIssuer, config.TokenAudience)
+		}
+		return auth.NewPasetoPublicMaker(config.TokenPrivateKey, config.TokenIssuer, config.TokenAudience)
+	case tokenMakerJWT:
+		key := config.TokenSymmetricKey
+		if config.TokenJWTAlgorithm != auth.AlgorithmHS256 {
+			data, err := ioutil.ReadFile(config.TokenPrivateKeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("cannot read private key : %w", err)
+			}
+			key = string(data)
+		}
+		return auth.NewJWTMaker(config.TokenJWTAlgorithm, key, config.TokenIssuer, config.TokenAudience)
+	default:
+		return nil, fmt.Errorf("unsupported token maker %s", config.TokenMaker)
+	}
+}
+
 // This function will setup all routes.
 func (server *Server) setUpRouter() {
 	// Swagger 2.0 Meta Information.
@@ -47,38 +193,121 @@ func (server *Server) setUpRouter() {
 
 	router := gin.Default()
 
-	// Group routes that need authentification/authorization together.
-	authRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker))
+	// Group routes that need authentification/authorization together. Routes modifying contacts and skills
+	// are restricted to editors and admins, API keys must also hold the scope of the route.
+	authenticate := authMiddleware(server.tokenMaker, server.database)
+	writers := roleMiddleware(auth.RoleAdmin, auth.RoleEditor)
+	accountRoutes := router.Group("/").Use(authenticate, tokenOnlyMiddleware())
+	adminRoutes := router.Group("/admin").Use(authenticate, tokenOnlyMiddleware(), roleMiddleware(auth.RoleAdmin))
+	contactReadRoutes := router.Group("/").Use(authenticate, scopeMiddleware(scopeContactsRead))
+	contactWriteRoutes := router.Group("/").Use(authenticate, writers, scopeMiddleware(scopeContactsWrite))
+	skillReadRoutes := router.Group("/").Use(authenticate, scopeMiddleware(scopeSkillsRead))
+	skillWriteRoutes := router.Group("/").Use(authenticate, writers, scopeMiddleware(scopeSkillsWrite))
 	// Add routes to the gin server.
 	// Contacts routes.
-	authRoutes.POST("/contacts", server.createContact)
-	authRoutes.GET("/contacts/:id", server.getContact)
-	authRoutes.GET("/contact-skills/:id", server.getContactSkills)
-	authRoutes.GET("/contacts", server.listContacts)
-	authRoutes.GET("/contacts-with-skill", server.getContactWithSkill)
-	authRoutes.GET("/contacts-with-skill-and-level", server.getContactWithSkillAndLevel)
-	authRoutes.DELETE("/contacts/:id", server.deleteContact)
-	authRoutes.PATCH("/contacts", server.updateContact)
+	contactWriteRoutes.POST("/contacts", server.createContact)
+	contactReadRoutes.GET("/contacts/search", server.searchContacts)
+	contactReadRoutes.GET("/contacts/export.vcf", server.exportContactsVCard)
+	contactReadRoutes.GET("/contacts/export", server.exportContacts)
+	contactWriteRoutes.POST("/contacts/import", server.importContactsVCard)
+	contactWriteRoutes.POST("/contacts/import/csv", server.importContactsCSV)
+	contactReadRoutes.GET("/contacts/import/jobs/:id", server.getImportJob)
+	contactReadRoutes.GET("/contacts/duplicates", server.listDuplicateContacts)
+	contactWriteRoutes.POST("/contacts/merge", server.mergeContacts)
+	contactReadRoutes.GET("/contacts/:id", server.getContact)
+	contactReadRoutes.GET("/contact-skills/:id", server.getContactSkills)
+	contactReadRoutes.GET("/contacts", server.listContacts)
+	contactReadRoutes.GET("/contacts-with-skill", server.getContactWithSkill)
+	contactReadRoutes.GET("/contacts-with-skill-and-level", server.getContactWithSkillAndLevel)
+	contactWriteRoutes.DELETE("/contacts/:id", server.deleteContact)
+	contactWriteRoutes.PATCH("/contacts", server.updateContact)
 	// Skills routes.
-	authRoutes.POST("/skills", server.createSkill)
-	authRoutes.GET("/skills/:id", server.getSkill)
-	authRoutes.GET("/skills", server.listSkills)
-	authRoutes.DELETE("/skills/:id", server.deleteSkill)
-	authRoutes.PATCH("/skills", server.updateSkill)
+	skillWriteRoutes.POST("/skills", server.createSkill)
+	skillReadRoutes.GET("/skills/:id", server.getSkill)
+	skillReadRoutes.GET("/skills", server.listSkills)
+	skillWriteRoutes.DELETE("/skills/:id", server.deleteSkill)
+	skillWriteRoutes.PATCH("/skills", server.updateSkill)
 	// Binding skills and contacts route.
-	authRoutes.POST("/add-skill", server.createSkillToContact)
+	contactWriteRoutes.POST("/add-skill", server.createSkillToContact)
+	// Sessions routes.
+	accountRoutes.POST("/users/logout", server.logoutUser)
+	accountRoutes.GET("/users/me", server.getProfile)
+	accountRoutes.PATCH("/users/me", server.updateProfile)
+	accountRoutes.DELETE("/users/me", server.deleteAccount)
+	accountRoutes.PATCH("/users/password", server.changePassword)
+	accountRoutes.GET("/sessions", server.listSessions)
+	accountRoutes.DELETE("/sessions/:id", server.deleteSession)
+	// Two-factor authentication routes.
+	accountRoutes.POST("/users/2fa/enroll", server.enrollTOTP)
+	accountRoutes.POST("/users/2fa/enable", server.enableTOTP)
+	accountRoutes.POST("/users/2fa/disable", server.disableTOTP)
+	// API keys routes.
+	accountRoutes.POST("/api-keys", server.createAPIKey)
+	accountRoutes.GET("/api-keys", server.listAPIKeys)
+	accountRoutes.DELETE("/api-keys/:id", server.deleteAPIKey)
+	// Administration routes.
+	adminRoutes.GET("/users", server.listUsers)
+	adminRoutes.GET("/users/:username", server.getUser)
+	adminRoutes.POST("/users/:username/unlock", server.unlockUser)
+	adminRoutes.POST("/users/:username/disable", server.disableUser)
+	adminRoutes.POST("/users/:username/enable", server.enableUser)
+	adminRoutes.POST("/users/:username/sessions/revoke", server.revokeUserSessions)
+	adminRoutes.POST("/users/:username/password/reset", server.resetUserPassword)
+	adminRoutes.GET("/audit-logs", server.listAuditLogs)
+	adminRoutes.POST("/oauth-clients", server.createOAuthClient)
+	adminRoutes.GET("/oauth-clients", server.listOAuthClients)
+	adminRoutes.DELETE("/oauth-clients/:id", server.deleteOAuthClient)
 	// Authentification routes.
 	router.POST("/users", server.createUser)
 	router.POST("/users/login", server.loginUser)
+	router.POST("/users/login/2fa", server.loginTwoFactor)
+	router.POST("/users/password/forgot", server.forgotPassword)
+	router.POST("/users/password/reset", server.resetPassword)
+	if server.oidcProvider != nil {
+		router.GET("/users/oidc/login", server.oidcLogin)
+		router.GET("/users/oidc/callback", server.oidcCallback)
+	}
+	router.GET("/users/email/verify", server.verifyEmail)
+	router.POST("/users/email/verify/resend", server.resendVerificationEmail)
 	router.POST("/tokens/renew_access", server.renewAccessToken)
+	router.POST("/oauth/token", server.oauthToken)
+	router.GET("/tokens/public_key", server.getTokenPublicKey)
 	// Documentation routes, available at : http://localhost:8080/swagger/index.html.
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	server.router = router
 }
 
-// Start the server on the specified address.
+// Start the server on the specified address, nil is returned once it is shut down.
 func (server *Server) Start(address string) error {
-	return server.router.Run(address)
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	err = server.httpServer.Serve(listener)
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
+
+// Shutdown stops the server : new requests are refused, then the ongoing requests and the CSV imports running
+// in the background are waited for. The imports still running when ctx is done are left as they are.
+func (server *Server) Shutdown(ctx context.Context) error {
+	if err := server.httpServer.Shutdown(ctx); err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		server.importJobs.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 // Wrapper for returning error.
@@ -0,0 +1,157 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	auth "github.com/IsuruHaupe/web-api/auth/token"
+	db "github.com/IsuruHaupe/web-api/db/sqlc"
+	"github.com/IsuruHaupe/web-api/useragent"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// This is the expected returned response when listing sessions, the session token is never exposed.
+// The user agent and the client IP are the ones of the last renewal, the flags tell whether they differ
+// from the ones of the login.
+type sessionResponse struct {
+	ID               uuid.UUID        `json:"id"`
+	Device           string           `json:"device"`
+	DeviceDetails    useragent.Device `json:"device_details"`
+	UserAgent        string           `json:"user_agent"`
+	ClientIp         string           `json:"client_ip"`
+	IsBlocked        bool             `json:"is_blocked"`
+	IsCurrent        bool             `json:"is_current"`
+	IpChanged        bool             `json:"ip_changed"`
+	UserAgentChanged bool             `json:"user_agent_changed"`
+	ExpiresAt        time.Time        `json:"expires_at"`
+	CreatedAt        time.Time        `json:"created_at"`
+	LastRefreshedAt  time.Time        `json:"last_refreshed_at"`
+}
+
+func newSessionResponse(session db.ListSessionsRow, currentSessionID uuid.UUID) sessionResponse {
+	device := useragent.Parse(session.UserAgent)
+	return sessionResponse{
+		ID:               session.ID,
+		Device:           device.String(),
+		DeviceDetails:    device,
+		UserAgent:        session.UserAgent,
+		ClientIp:         session.ClientIp,
+		IsBlocked:        session.IsBlocked,
+		IsCurrent:        session.ID == currentSessionID,
+		IpChanged:        session.IpChanged,
+		UserAgentChanged: session.UserAgentChanged,
+		ExpiresAt:        session.ExpiresAt,
+		// Each renewal creates a new session, the family was created when the user logged in. The access tokens
+		// used since the last renewal are not tracked.
+		CreatedAt:       session.FamilyCreatedAt,
+		LastRefreshedAt: session.CreatedAt,
+	}
+}
+
+// logoutUser godoc
+// @Security bearerAuth
+// @Summary Logout an user
+// @Description This function is used to block the session the access token was issued from.
+// @Tags user
+// @Produce json
+// @Success 200 {string} string "Successfully logged out."
+// @Router /users/logout [post]
+func (server *Server) logoutUser(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Payload)
+	if authPayload.SessionID == uuid.Nil {
+		err := errors.New("access token is not bound to a session")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	_, err := server.database.BlockSession(ctx, authPayload.SessionID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, "Successfully logged out.")
+}
+
+// listSessions godoc
+// @Security bearerAuth
+// @Summary List sessions
+// @Tags session
+// @Description This function is used to list the active sessions of an user, along with the device they are used from.
+// @Description The session the access token was issued from is flagged as the current one. The ip_changed and user_agent_changed
+// @Description flags are set when the session was last renewed from another IP address or user agent than the ones it was created with.
+// @Produce json
+// @Success 200 {array} api.sessionResponse
+// @Router /sessions [get]
+func (server *Server) listSessions(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Payload)
+	sessions, err := server.database.ListSessions(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := make([]sessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		response = append(response, newSessionResponse(session, authPayload.SessionID))
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+// Request holder for deleting session request.
+type deleteSessionRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// deleteSession godoc
+// @Security bearerAuth
+// @Summary Delete a session
+// @Tags session
+// @Description This function is used to revoke a session of an user, e.g : on another device.
+// @Accept application/x-www-form-urlencoded
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {string} string "Successfully deleted session."
+// @Router /sessions/{id} [delete]
+func (server *Server) deleteSession(ctx *gin.Context) {
+	var req deleteSessionRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	sessionID := uuid.MustParse(req.ID)
+
+	// Get the session to check ownership before deletion.
+	session, err := server.database.GetSession(ctx, sessionID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// Check for owernership.
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*auth.Payload)
+	if session.Username != authPayload.Username {
+		err := errors.New("session doesn't belong to the user")
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	err = server.database.DeleteSession(ctx, sessionID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, "Successfully deleted session.")
+}
@@ -36,7 +36,7 @@ func TestGetSkillAPI(t *testing.T) {
 			name:    "Pass",
 			skillID: skill.ID,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				database.EXPECT().
@@ -53,7 +53,7 @@ func TestGetSkillAPI(t *testing.T) {
 			name:    "Unauthorized User",
 			skillID: skill.ID,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "unauthorized_user", time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "unauthorized_user", auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				database.EXPECT().
@@ -83,7 +83,7 @@ func TestGetSkillAPI(t *testing.T) {
 			name:    "Not Found",
 			skillID: skill.ID,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				database.EXPECT().
@@ -99,7 +99,7 @@ func TestGetSkillAPI(t *testing.T) {
 			name:    "Internal Error",
 			skillID: skill.ID,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				database.EXPECT().
@@ -115,7 +115,7 @@ func TestGetSkillAPI(t *testing.T) {
 			name:    "Bad Request",
 			skillID: 0,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				database.EXPECT().
@@ -173,7 +173,7 @@ func TestCreateSkillAPI(t *testing.T) {
 				"skill_level": skill.SkillLevel,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				arg := db.CreateSkillParams{
@@ -221,7 +221,7 @@ func TestCreateSkillAPI(t *testing.T) {
 				"skill_level": skill.SkillLevel,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockDatabase) {
 				store.EXPECT().
@@ -240,7 +240,7 @@ func TestCreateSkillAPI(t *testing.T) {
 				"skill_level": skill.SkillLevel,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockDatabase) {
 				store.EXPECT().
@@ -309,7 +309,7 @@ func TestListSkillsAPI(t *testing.T) {
 				pageSize: n,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				arg := db.ListSkillsParams{
@@ -352,7 +352,7 @@ func TestListSkillsAPI(t *testing.T) {
 				pageSize: n,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				database.EXPECT().
@@ -371,7 +371,7 @@ func TestListSkillsAPI(t *testing.T) {
 				pageSize: n,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				database.EXPECT().
@@ -389,7 +389,7 @@ func TestListSkillsAPI(t *testing.T) {
 				pageSize: 100000,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				database.EXPECT().
@@ -447,7 +447,7 @@ func TestDeleteSkillAPI(t *testing.T) {
 			name:    "Pass",
 			skillID: skill.ID,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				database.EXPECT().
@@ -463,11 +463,29 @@ func TestDeleteSkillAPI(t *testing.T) {
 				require.Equal(t, http.StatusOK, recorder.Code)
 			},
 		},
+		{
+			name:    "Viewer",
+			skillID: skill.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleViewer, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetSkill(gomock.Any(), gomock.Any()).
+					Times(0)
+				database.EXPECT().
+					DeleteSkill(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
 		{
 			name:    "Unauthorized User",
 			skillID: skill.ID,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "unauthorized_user", time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "unauthorized_user", auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				database.EXPECT().
@@ -505,7 +523,7 @@ func TestDeleteSkillAPI(t *testing.T) {
 			name:    "Internal Error",
 			skillID: skill.ID,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				database.EXPECT().
@@ -525,7 +543,7 @@ func TestDeleteSkillAPI(t *testing.T) {
 			name:    "Bad Request",
 			skillID: 0,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				database.EXPECT().
@@ -584,7 +602,7 @@ func TestUpdateSkillAPI(t *testing.T) {
 				"skill_level": "Expert",
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 
@@ -613,7 +631,7 @@ func TestUpdateSkillAPI(t *testing.T) {
 				"firstname": "Isuru",
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				database.EXPECT().
@@ -638,7 +656,7 @@ func TestUpdateSkillAPI(t *testing.T) {
 				"firstname": "Isuru",
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				database.EXPECT().
@@ -664,7 +682,7 @@ func TestUpdateSkillAPI(t *testing.T) {
 				"firstname": "Isuru",
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker auth.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, auth.RoleEditor, time.Minute)
 			},
 			buildStubs: func(database *mockdb.MockDatabase) {
 				database.EXPECT().
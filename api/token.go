@@ -1,12 +1,19 @@
 package api
 
 import (
+	"crypto/ed25519"
 	"database/sql"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
 
+	auth "github.com/IsuruHaupe/web-api/auth/token"
+	"github.com/IsuruHaupe/web-api/db/database"
+	db "github.com/IsuruHaupe/web-api/db/sqlc"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type renewAccessTokenRequest struct {
@@ -14,14 +21,45 @@ type renewAccessTokenRequest struct {
 }
 
 type renewAccessTokenResponse struct {
-	AccessToken          string    `json:"access_token"`
-	AccessTokenExpiresAt time.Time `json:"access_token_expires_at"`
+	SessionID             uuid.UUID `json:"session_id"`
+	AccessToken           string    `json:"access_token"`
+	AccessTokenExpiresAt  time.Time `json:"access_token_expires_at"`
+	SessionToken          string    `json:"session_token"`
+	SessionTokenExpiresAt time.Time `json:"session_token_expires_at"`
+	Warnings              []string  `json:"warnings,omitempty"`
+}
+
+// Differences between the device renewing a session and the device the session was created on.
+type deviceChange struct {
+	IPChanged        bool
+	UserAgentChanged bool
+}
+
+var errDeviceChanged = errors.New("the session can not be renewed from another device than the one it was created on")
+
+func (change deviceChange) changed() bool {
+	return change.IPChanged || change.UserAgentChanged
+}
+
+func (change deviceChange) warnings() []string {
+	var warnings []string
+	if change.IPChanged {
+		warnings = append(warnings, "the session was created from another IP address")
+	}
+	if change.UserAgentChanged {
+		warnings = append(warnings, "the session was created with another user agent")
+	}
+	return warnings
 }
 
 // renewAccessToken godoc
 // @Security bearerAuth
 // @Summary Renew access token
 // @Description This function is used to renew an access token for an user provinding the sessionToken.
+// @Description The session token is rotated : the returned session token replaces the one provided, which can not be used anymore.
+// @Description Presenting an already rotated session token revokes every session of its family.
+// @Description Warnings are returned when the IP address or the user agent differ from the ones the session was created with,
+// @Description such renewals are refused when SESSION_BLOCK_DEVICE_CHANGE is set.
 // @Tags token
 // @Accept json
 // @Produce json
@@ -35,7 +73,7 @@ func (server *Server) renewAccessToken(ctx *gin.Context) {
 		return
 	}
 
-	sessionPayload, err := server.tokenMaker.VerifyToken(req.SessionToken)
+	sessionPayload, err := server.tokenMaker.VerifyToken(req.SessionToken, auth.TokenTypeSession)
 	if err != nil {
 		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
 		return
@@ -51,12 +89,6 @@ func (server *Server) renewAccessToken(ctx *gin.Context) {
 		return
 	}
 
-	if session.IsBlocked {
-		err := fmt.Errorf("blocked session")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
-		return
-	}
-
 	if session.Username != sessionPayload.Username {
 		err := fmt.Errorf("incorrect session user")
 		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
@@ -69,25 +101,203 @@ func (server *Server) renewAccessToken(ctx *gin.Context) {
 		return
 	}
 
+	// A rotated session token must never be presented again, if it is the token has leaked
+	// and we revoke the whole family.
+	if session.IsRotated {
+		server.revokeSessionFamily(ctx, session)
+		return
+	}
+
+	if session.IsBlocked {
+		err := fmt.Errorf("blocked session")
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
 	if time.Now().After(session.ExpiresAt) {
 		err := fmt.Errorf("expired session")
 		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
 		return
 	}
 
-	accessToken, accessPayload, err := server.tokenMaker.CreateToken(
-		sessionPayload.Username,
-		server.config.AccessTokenDuration,
-	)
+	change, err := server.detectDeviceChange(ctx, session)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if change.changed() && server.config.SessionBlockDeviceChange {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errDeviceChanged))
+		return
+	}
+
+	// The role is read again so that role changes and email verification apply from the next renewal.
+	user, err := server.database.GetUser(ctx, session.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	role, err := server.accessRole(user)
+	if err != nil {
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	// The new session token keeps the expiration of the family so that rotation never extends a session.
+	newSessionPayload, err := auth.NewPayload(session.Username, auth.TokenTypeSession, time.Until(session.ExpiresAt))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	newSessionToken, err := server.tokenMaker.CreateToken(newSessionPayload)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	result, err := server.database.RotateSessionTx(ctx, database.RotateSessionTxParams{
+		ParentID: session.ID,
+		Session: db.CreateSessionParams{
+			ID:               newSessionPayload.ID,
+			Username:         session.Username,
+			SessionToken:     newSessionToken,
+			UserAgent:        ctx.Request.UserAgent(),
+			ClientIp:         ctx.ClientIP(),
+			IsBlocked:        false,
+			ExpiresAt:        newSessionPayload.ExpiredAt,
+			FamilyID:         session.FamilyID,
+			ParentID:         uuid.NullUUID{UUID: session.ID, Valid: true},
+			IpChanged:        change.IPChanged,
+			UserAgentChanged: change.UserAgentChanged,
+		},
+	})
+	if err != nil {
+		// Someone else rotated the session in the meantime, the token was used twice.
+		if err == sql.ErrNoRows {
+			server.revokeSessionFamily(ctx, session)
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	accessPayload, err := auth.NewPayload(session.Username, auth.TokenTypeAccess, server.config.AccessTokenDuration)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	accessPayload.SessionID = result.Session.ID
+	accessPayload.Role = role
+
+	accessToken, err := server.tokenMaker.CreateToken(accessPayload)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
 	rsp := renewAccessTokenResponse{
-		AccessToken:          accessToken,
-		AccessTokenExpiresAt: accessPayload.ExpiredAt,
+		SessionID:             result.Session.ID,
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  accessPayload.ExpiredAt,
+		SessionToken:          newSessionToken,
+		SessionTokenExpiresAt: newSessionPayload.ExpiredAt,
+		Warnings:              change.warnings(),
 	}
 	ctx.JSON(http.StatusOK, rsp)
 
 }
+
+// revokeSessionFamily blocks every session sharing the family of the given session and rejects the request.
+func (server *Server) revokeSessionFamily(ctx *gin.Context, session db.Session) {
+	err := server.database.BlockSessionFamily(ctx, session.FamilyID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	err = fmt.Errorf("session token reuse detected, all related sessions have been revoked")
+	ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+}
+
+type tokenPublicKey struct {
+	KeyID     string `json:"key_id,omitempty"`
+	PublicKey string `json:"public_key"`
+}
+
+type tokenPublicKeyResponse struct {
+	Version   string           `json:"version"`
+	Purpose   string           `json:"purpose"`
+	PublicKey string           `json:"public_key"`
+	Keys      []tokenPublicKey `json:"keys"`
+}
+
+// This function will compare the device renewing a session with the one the session family was created on. The values of
+// each renewal are kept on the new session, so the first session of the family holds the ones of the login.
+func (server *Server) detectDeviceChange(ctx *gin.Context, session db.Session) (deviceChange, error) {
+	origin := session
+	if session.FamilyID != session.ID {
+		first, err := server.database.GetSession(ctx, session.FamilyID)
+		if err != nil && err != sql.ErrNoRows {
+			return deviceChange{}, err
+		}
+		// The first session may have been deleted, the values of the last renewal are used instead.
+		if err == nil {
+			origin = first
+		}
+	}
+
+	return deviceChange{
+		IPChanged:        ctx.ClientIP() != origin.ClientIp,
+		UserAgentChanged: ctx.Request.UserAgent() != origin.UserAgent,
+	}, nil
+}
+
+// getTokenPublicKey godoc
+// @Summary Get the token public keys
+// @Description This function is used to publish the hex encoded Ed25519 public keys so that other services can verify tokens offline.
+// @Description public_key is the current signing key, keys lists every key still accepted along with the key ID found in the token footer.
+// @Tags token
+// @Produce json
+// @Success 200 {object} api.tokenPublicKeyResponse
+// @Router /tokens/public_key [get]
+func (server *Server) getTokenPublicKey(ctx *gin.Context) {
+	provider, ok := server.tokenMaker.(auth.PublicKeyProvider)
+	if !ok {
+		err := errors.New("tokens are not signed with a public key")
+		ctx.JSON(http.StatusNotFound, errorResponse(err))
+		return
+	}
+
+	publicKeys := provider.PublicKeys()
+	if len(publicKeys) == 0 {
+		err := errors.New("tokens are not signed with a public key")
+		ctx.JSON(http.StatusNotFound, errorResponse(err))
+		return
+	}
+
+	rsp := tokenPublicKeyResponse{
+		Version: "v2",
+		Purpose: "public",
+		Keys:    make([]tokenPublicKey, 0, len(publicKeys)),
+	}
+	for _, publicKey := range publicKeys {
+		key, ok := publicKey.Key.(ed25519.PublicKey)
+		if !ok {
+			err := errors.New("unsupported public key type")
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		rsp.Keys = append(rsp.Keys, tokenPublicKey{
+			KeyID:     publicKey.ID,
+			PublicKey: hex.EncodeToString(key),
+		})
+	}
+	rsp.PublicKey = rsp.Keys[0].PublicKey
+
+	ctx.JSON(http.StatusOK, rsp)
+}
@@ -0,0 +1,556 @@
+package api
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	authHelper "github.com/IsuruHaupe/web-api/auth"
+	auth "github.com/IsuruHaupe/web-api/auth/token"
+	"github.com/IsuruHaupe/web-api/config"
+	dbtx "github.com/IsuruHaupe/web-api/db/database"
+	mockdb "github.com/IsuruHaupe/web-api/db/mock"
+	db "github.com/IsuruHaupe/web-api/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenewAccessTokenAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		body          func(sessionToken string) gin.H
+		buildStubs    func(database *mockdb.MockDatabase, session db.Session)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder, session db.Session)
+	}{
+		{
+			name: "OK",
+			body: func(sessionToken string) gin.H {
+				return gin.H{"session_token": sessionToken}
+			},
+			buildStubs: func(database *mockdb.MockDatabase, session db.Session) {
+				database.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(session.Username)).
+					Times(1).
+					Return(user, nil)
+				database.EXPECT().
+					RotateSessionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg dbtx.RotateSessionTxParams) (dbtx.RotateSessionTxResult, error) {
+						require.Equal(t, session.ID, arg.ParentID)
+						require.Equal(t, session.FamilyID, arg.Session.FamilyID)
+						require.Equal(t, session.ID, arg.Session.ParentID.UUID)
+						require.WithinDuration(t, session.ExpiresAt, arg.Session.ExpiresAt, time.Second)
+
+						parent := session
+						parent.IsRotated = true
+						return dbtx.RotateSessionTxResult{
+							ParentSession: parent,
+							Session: db.Session{
+								ID:           arg.Session.ID,
+								Username:     arg.Session.Username,
+								SessionToken: arg.Session.SessionToken,
+								ExpiresAt:    arg.Session.ExpiresAt,
+								FamilyID:     arg.Session.FamilyID,
+								ParentID:     arg.Session.ParentID,
+							},
+						}, nil
+					})
+				database.EXPECT().
+					BlockSessionFamily(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, session db.Session) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				data, err := ioutil.ReadAll(recorder.Body)
+				require.NoError(t, err)
+
+				var response renewAccessTokenResponse
+				err = json.Unmarshal(data, &response)
+				require.NoError(t, err)
+				require.NotEmpty(t, response.AccessToken)
+				require.NotEmpty(t, response.SessionToken)
+				require.NotEqual(t, session.SessionToken, response.SessionToken)
+				require.NotEqual(t, session.ID, response.SessionID)
+			},
+		},
+		{
+			name: "Reused Session Token",
+			body: func(sessionToken string) gin.H {
+				return gin.H{"session_token": sessionToken}
+			},
+			buildStubs: func(database *mockdb.MockDatabase, session db.Session) {
+				session.IsRotated = true
+				database.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+				database.EXPECT().
+					BlockSessionFamily(gomock.Any(), gomock.Eq(session.FamilyID)).
+					Times(1).
+					Return(nil)
+				database.EXPECT().
+					RotateSessionTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, session db.Session) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Concurrent Rotation",
+			body: func(sessionToken string) gin.H {
+				return gin.H{"session_token": sessionToken}
+			},
+			buildStubs: func(database *mockdb.MockDatabase, session db.Session) {
+				database.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(session.Username)).
+					Times(1).
+					Return(user, nil)
+				database.EXPECT().
+					RotateSessionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(dbtx.RotateSessionTxResult{}, sql.ErrNoRows)
+				database.EXPECT().
+					BlockSessionFamily(gomock.Any(), gomock.Eq(session.FamilyID)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, session db.Session) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Blocked Session",
+			body: func(sessionToken string) gin.H {
+				return gin.H{"session_token": sessionToken}
+			},
+			buildStubs: func(database *mockdb.MockDatabase, session db.Session) {
+				session.IsBlocked = true
+				database.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+				database.EXPECT().
+					RotateSessionTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, session db.Session) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Mismatched Session Token",
+			body: func(sessionToken string) gin.H {
+				return gin.H{"session_token": sessionToken}
+			},
+			buildStubs: func(database *mockdb.MockDatabase, session db.Session) {
+				session.SessionToken = "another-token"
+				database.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+				database.EXPECT().
+					RotateSessionTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, session db.Session) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Expired Session",
+			body: func(sessionToken string) gin.H {
+				return gin.H{"session_token": sessionToken}
+			},
+			buildStubs: func(database *mockdb.MockDatabase, session db.Session) {
+				session.ExpiresAt = time.Now().Add(-time.Minute)
+				database.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+				database.EXPECT().
+					RotateSessionTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, session db.Session) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Not Found",
+			body: func(sessionToken string) gin.H {
+				return gin.H{"session_token": sessionToken}
+			},
+			buildStubs: func(database *mockdb.MockDatabase, session db.Session) {
+				database.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(db.Session{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, session db.Session) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "Invalid Token",
+			body: func(sessionToken string) gin.H {
+				return gin.H{"session_token": "invalid-token"}
+			},
+			buildStubs: func(database *mockdb.MockDatabase, session db.Session) {
+				database.EXPECT().
+					GetSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, session db.Session) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Bad Request",
+			body: func(sessionToken string) gin.H {
+				return gin.H{}
+			},
+			buildStubs: func(database *mockdb.MockDatabase, session db.Session) {
+				database.EXPECT().
+					GetSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, session db.Session) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		currentTest := testCases[i]
+
+		t.Run(currentTest.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			database := mockdb.NewMockDatabase(ctrl)
+			server := newTestServer(t, database)
+
+			// Create the session the session token belongs to.
+			payload, err := auth.NewPayload(user.Username, auth.TokenTypeSession, time.Hour)
+			require.NoError(t, err)
+			sessionToken, err := server.tokenMaker.CreateToken(payload)
+			require.NoError(t, err)
+
+			session := db.Session{
+				ID:           payload.ID,
+				Username:     user.Username,
+				SessionToken: sessionToken,
+				ExpiresAt:    payload.ExpiredAt,
+				FamilyID:     payload.ID,
+			}
+			currentTest.buildStubs(database, session)
+
+			recorder := httptest.NewRecorder()
+
+			// Marshal body data to JSON
+			data, err := json.Marshal(currentTest.body(sessionToken))
+			require.NoError(t, err)
+
+			url := "/tokens/renew_access"
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			currentTest.checkResponse(t, recorder, session)
+		})
+	}
+}
+
+func TestRenewAccessTokenDeviceChange(t *testing.T) {
+	user, _ := randomUser(t)
+	firefox := "Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:98.0) Gecko/20100101 Firefox/98.0"
+	familyID := uuid.New()
+
+	testCases := []struct {
+		name          string
+		userAgent     string
+		remoteAddr    string
+		block         bool
+		checkRotation func(t *testing.T, arg dbtx.RotateSessionTxParams)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "Same Device",
+			userAgent:  firefox,
+			remoteAddr: "10.0.0.1:4321",
+			block:      true,
+			checkRotation: func(t *testing.T, arg dbtx.RotateSessionTxParams) {
+				require.False(t, arg.Session.IpChanged)
+				require.False(t, arg.Session.UserAgentChanged)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response renewAccessTokenResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Empty(t, response.Warnings)
+			},
+		},
+		{
+			name:       "Other IP",
+			userAgent:  firefox,
+			remoteAddr: "10.0.0.2:4321",
+			checkRotation: func(t *testing.T, arg dbtx.RotateSessionTxParams) {
+				require.True(t, arg.Session.IpChanged)
+				require.False(t, arg.Session.UserAgentChanged)
+				require.Equal(t, "10.0.0.2", arg.Session.ClientIp)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response renewAccessTokenResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Len(t, response.Warnings, 1)
+			},
+		},
+		{
+			name:       "Other User Agent",
+			userAgent:  "curl/7.81.0",
+			remoteAddr: "10.0.0.1:4321",
+			checkRotation: func(t *testing.T, arg dbtx.RotateSessionTxParams) {
+				require.False(t, arg.Session.IpChanged)
+				require.True(t, arg.Session.UserAgentChanged)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response renewAccessTokenResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Len(t, response.Warnings, 1)
+			},
+		},
+		{
+			name:       "Blocked",
+			userAgent:  "curl/7.81.0",
+			remoteAddr: "10.0.0.2:4321",
+			block:      true,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		currentTest := testCases[i]
+
+		t.Run(currentTest.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			database := mockdb.NewMockDatabase(ctrl)
+			server := newTestServer(t, database)
+			server.config.SessionBlockDeviceChange = currentTest.block
+
+			// The session being renewed was already rotated from another network, the first session holds the login.
+			payload, err := auth.NewPayload(user.Username, auth.TokenTypeSession, time.Hour)
+			require.NoError(t, err)
+			sessionToken, err := server.tokenMaker.CreateToken(payload)
+			require.NoError(t, err)
+
+			session := db.Session{
+				ID:           payload.ID,
+				Username:     user.Username,
+				SessionToken: sessionToken,
+				UserAgent:    firefox,
+				ClientIp:     "10.0.0.3",
+				ExpiresAt:    payload.ExpiredAt,
+				FamilyID:     familyID,
+				ParentID:     uuid.NullUUID{UUID: familyID, Valid: true},
+			}
+			first := db.Session{
+				ID:        familyID,
+				Username:  user.Username,
+				UserAgent: firefox,
+				ClientIp:  "10.0.0.1",
+				IsRotated: true,
+				FamilyID:  familyID,
+			}
+
+			database.EXPECT().
+				GetSession(gomock.Any(), gomock.Eq(session.ID)).
+				Times(1).
+				Return(session, nil)
+			database.EXPECT().
+				GetSession(gomock.Any(), gomock.Eq(familyID)).
+				Times(1).
+				Return(first, nil)
+			if currentTest.checkRotation == nil {
+				database.EXPECT().
+					RotateSessionTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			} else {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				database.EXPECT().
+					RotateSessionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg dbtx.RotateSessionTxParams) (dbtx.RotateSessionTxResult, error) {
+						currentTest.checkRotation(t, arg)
+						return dbtx.RotateSessionTxResult{
+							Session: db.Session{
+								ID:        arg.Session.ID,
+								Username:  arg.Session.Username,
+								ExpiresAt: arg.Session.ExpiresAt,
+								FamilyID:  arg.Session.FamilyID,
+							},
+						}, nil
+					})
+			}
+
+			data, err := json.Marshal(gin.H{"session_token": sessionToken})
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPost, "/tokens/renew_access", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set("User-Agent", currentTest.userAgent)
+			request.RemoteAddr = currentTest.remoteAddr
+
+			server.router.ServeHTTP(recorder, request)
+			currentTest.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetTokenPublicKeyAPI(t *testing.T) {
+	seed := make([]byte, ed25519.SeedSize)
+	_, err := rand.Read(seed)
+	require.NoError(t, err)
+
+	keyringPath := filepath.Join(t.TempDir(), "keyring.json")
+	key, err := auth.NewKey(time.Now())
+	require.NoError(t, err)
+	keyring := &auth.Keyring{Keys: []auth.Key{key}}
+	_, err = keyring.Rotate(0, time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, keyring.Save(keyringPath))
+
+	testCases := []struct {
+		name          string
+		config        config.Config
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			config: config.Config{
+				TokenMaker:          tokenMakerPasetoPublic,
+				TokenPrivateKey:     hex.EncodeToString(seed),
+				TokenIssuer:         "web-api",
+				TokenAudience:       "web-api",
+				AccessTokenDuration: time.Minute,
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				data, err := ioutil.ReadAll(recorder.Body)
+				require.NoError(t, err)
+
+				var response tokenPublicKeyResponse
+				err = json.Unmarshal(data, &response)
+				require.NoError(t, err)
+
+				publicKey := ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey)
+				require.Equal(t, "public", response.Purpose)
+				require.Equal(t, hex.EncodeToString(publicKey), response.PublicKey)
+			},
+		},
+		{
+			name: "Keyring",
+			config: config.Config{
+				TokenMaker:          tokenMakerPasetoPublic,
+				TokenKeyringFile:    keyringPath,
+				TokenIssuer:         "web-api",
+				TokenAudience:       "web-api",
+				AccessTokenDuration: time.Minute,
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				data, err := ioutil.ReadAll(recorder.Body)
+				require.NoError(t, err)
+
+				var response tokenPublicKeyResponse
+				err = json.Unmarshal(data, &response)
+				require.NoError(t, err)
+
+				require.Len(t, response.Keys, 2)
+				require.Equal(t, keyring.Keys[1].ID, response.Keys[0].KeyID)
+				require.Equal(t, response.Keys[0].PublicKey, response.PublicKey)
+			},
+		},
+		{
+			name: "Symmetric Maker",
+			config: config.Config{
+				TokenMaker:          tokenMakerPaseto,
+				TokenSymmetricKey:   authHelper.RandomString(32),
+				TokenIssuer:         "web-api",
+				TokenAudience:       "web-api",
+				AccessTokenDuration: time.Minute,
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "JWT Maker",
+			config: config.Config{
+				TokenMaker:          tokenMakerJWT,
+				TokenSymmetricKey:   authHelper.RandomString(32),
+				TokenJWTAlgorithm:   auth.AlgorithmHS256,
+				TokenIssuer:         "web-api",
+				TokenAudience:       "web-api",
+				AccessTokenDuration: time.Minute,
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		currentTest := testCases[i]
+
+		t.Run(currentTest.name, func(t *testing.T) {
+			server, err := NewServer(currentTest.config, nil, nil, nil)
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			url := "/tokens/public_key"
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			currentTest.checkResponse(t, recorder)
+		})
+	}
+}
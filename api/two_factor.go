@@ -0,0 +1,379 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/IsuruHaupe/web-api/auth"
+	token "github.com/IsuruHaupe/web-api/auth/token"
+	"github.com/IsuruHaupe/web-api/db/database"
+	db "github.com/IsuruHaupe/web-api/db/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// Number of random bytes of the two-factor challenge tokens.
+	twoFactorChallengeTokenSize = 32
+	// Number of codes that can be tried with a challenge before it has to be requested again by logging in.
+	maxTwoFactorAttempts = 5
+	// Number of recovery codes given when enabling two-factor authentication.
+	recoveryCodeCount = 10
+)
+
+var (
+	errInvalidChallenge     = errors.New("invalid or expired two-factor challenge token")
+	errInvalidTwoFactor     = errors.New("invalid two-factor code")
+	errTwoFactorEnabled     = errors.New("two-factor authentication is already enabled")
+	errTwoFactorNotEnrolled = errors.New("two-factor authentication has not been enrolled")
+)
+
+// This is the expected returned response on login when two-factor authentication is enabled.
+type twoFactorChallengeResponse struct {
+	TwoFactorRequired       bool      `json:"two_factor_required"`
+	ChallengeToken          string    `json:"challenge_token"`
+	ChallengeTokenExpiresAt time.Time `json:"challenge_token_expires_at"`
+}
+
+// This function will create the short-lived challenge an user has to answer with a second factor to finish its login.
+// Only the hash of the token is stored.
+func (server *Server) createTwoFactorChallenge(ctx *gin.Context, user db.User) (twoFactorChallengeResponse, error) {
+	challengeToken, err := auth.GenerateSecret(twoFactorChallengeTokenSize)
+	if err != nil {
+		return twoFactorChallengeResponse{}, err
+	}
+
+	challenge, err := server.database.CreateTwoFactorChallenge(ctx, db.CreateTwoFactorChallengeParams{
+		HashedToken: auth.HashSecret(challengeToken),
+		Username:    user.Username,
+		ExpiresAt:   time.Now().Add(server.config.TwoFactorChallengeDuration),
+	})
+	if err != nil {
+		return twoFactorChallengeResponse{}, err
+	}
+
+	return twoFactorChallengeResponse{
+		TwoFactorRequired:       true,
+		ChallengeToken:          challengeToken,
+		ChallengeTokenExpiresAt: challenge.ExpiresAt,
+	}, nil
+}
+
+// This function will check a TOTP or recovery code of an user. TOTP codes and recovery codes can only be used once.
+func (server *Server) checkSecondFactor(ctx *gin.Context, user db.User, code string) (bool, error) {
+	if step, ok := auth.ValidateTOTP(user.TotpSecret.String, code, time.Now()); ok {
+		_, err := server.database.UseTOTPStep(ctx, db.UseTOTPStepParams{
+			Username:     user.Username,
+			TotpLastStep: step,
+		})
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return false, nil
+			}
+			return false, err
+		}
+		return true, nil
+	}
+
+	_, err := server.database.UseRecoveryCode(ctx, db.UseRecoveryCodeParams{
+		HashedCode: auth.HashSecret(auth.NormalizeRecoveryCode(code)),
+		Username:   user.Username,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Request holder when receiving a two-factor login request.
+type loginTwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
+
+// loginTwoFactor godoc
+// @Summary Finish the login of an user with two-factor authentication
+// @Description This function is used to finish a login with the challenge token returned by /users/login and a TOTP code or a recovery code.
+// @Description A challenge token can be used for a few attempts only, codes are refused while the user or the client IP is locked out.
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param user body api.loginTwoFactorRequest true "Login Two-Factor"
+// @Success 200 {object} api.loginUserResponse
+// @Router /users/login/2fa [post]
+func (server *Server) loginTwoFactor(ctx *gin.Context) {
+	var req loginTwoFactorRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	hashedToken := auth.HashSecret(req.ChallengeToken)
+	challenge, err := server.database.GetTwoFactorChallenge(ctx, hashedToken)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(errInvalidChallenge))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// A locked username can't keep trying codes with the challenges it already holds.
+	if server.refuseLockedLogin(ctx, challenge.Username) {
+		return
+	}
+
+	// An attempt is used before the code is checked, so that concurrent requests can't try more codes than allowed.
+	challenge, err = server.database.UseTwoFactorChallengeAttempt(ctx, db.UseTwoFactorChallengeAttemptParams{
+		HashedToken: hashedToken,
+		MaxAttempts: maxTwoFactorAttempts,
+	})
+	if err != nil {
+		// Unknown, expired and used up challenges are not told apart.
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(errInvalidChallenge))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	user, err := server.database.GetUser(ctx, challenge.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// Two-factor authentication may have been disabled since the challenge was created.
+	if !user.TotpEnabled {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errInvalidChallenge))
+		return
+	}
+
+	ok, err := server.checkSecondFactor(ctx, user, req.Code)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if !ok {
+		if err := server.recordLoginFailure(ctx, user.Username); err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errInvalidTwoFactor))
+		return
+	}
+
+	// The challenge is consumed, a concurrent request using it fails here.
+	deleted, err := server.database.DeleteTwoFactorChallenge(ctx, hashedToken)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if deleted == 0 {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errInvalidChallenge))
+		return
+	}
+
+	role, err := server.accessRole(user)
+	if err != nil {
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	response, err := server.createLoginSession(ctx, user, role)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// This is the expected returned response when enrolling two-factor authentication.
+type enrollTOTPResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// enrollTOTP godoc
+// @Security bearerAuth
+// @Summary Enroll two-factor authentication
+// @Description This function is used to generate a new TOTP secret for the logged in user.
+// @Description The provisioning URI is meant to be shown as a QR code to an authenticator application, two-factor authentication is turned on once a code is confirmed on /users/2fa/enable.
+// @Tags two-factor
+// @Produce json
+// @Success 200 {object} api.enrollTOTPResponse
+// @Router /users/2fa/enroll [post]
+func (server *Server) enrollTOTP(ctx *gin.Context) {
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	user, err := server.database.SetUserTOTPSecret(ctx, db.SetUserTOTPSecretParams{
+		Username:   authPayload.Username,
+		TotpSecret: sql.NullString{String: secret, Valid: true},
+	})
+	if err != nil {
+		// The secret is only replaced while two-factor authentication is off.
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusForbidden, errorResponse(errTwoFactorEnabled))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := enrollTOTPResponse{
+		Secret:          secret,
+		ProvisioningURI: auth.TOTPProvisioningURI(server.config.TOTPIssuer, user.Username, secret),
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// Request holder when receiving an enable two-factor request.
+type enableTOTPRequest struct {
+	Code string `json:"code" binding:"required,numeric,len=6"`
+}
+
+// This is the expected returned response when enabling two-factor authentication, recovery codes are only shown this time.
+type enableTOTPResponse struct {
+	RecoveryCodes []string     `json:"recovery_codes"`
+	User          userResponse `json:"user"`
+}
+
+// enableTOTP godoc
+// @Security bearerAuth
+// @Summary Enable two-factor authentication
+// @Description This function is used to turn on two-factor authentication by confirming a code of the enrolled secret.
+// @Description One-time recovery codes are returned, they can be used instead of a code if the authenticator is lost.
+// @Tags two-factor
+// @Accept json
+// @Produce json
+// @Param code body api.enableTOTPRequest true "Enable Two-Factor"
+// @Success 200 {object} api.enableTOTPResponse
+// @Router /users/2fa/enable [post]
+func (server *Server) enableTOTP(ctx *gin.Context) {
+	var req enableTOTPRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	user, err := server.database.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if user.TotpEnabled {
+		ctx.JSON(http.StatusForbidden, errorResponse(errTwoFactorEnabled))
+		return
+	}
+	if !user.TotpSecret.Valid {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errTwoFactorNotEnrolled))
+		return
+	}
+
+	step, ok := auth.ValidateTOTP(user.TotpSecret.String, req.Code, time.Now())
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errInvalidTwoFactor))
+		return
+	}
+
+	recoveryCodes, err := auth.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	hashedRecoveryCodes := make([]string, 0, len(recoveryCodes))
+	for _, code := range recoveryCodes {
+		hashedRecoveryCodes = append(hashedRecoveryCodes, auth.HashSecret(code))
+	}
+
+	user, err = server.database.EnableTOTPTx(ctx, database.EnableTOTPTxParams{
+		Username:            user.Username,
+		TOTPStep:            step,
+		HashedRecoveryCodes: hashedRecoveryCodes,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(errInvalidTwoFactor))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := enableTOTPResponse{
+		RecoveryCodes: recoveryCodes,
+		User:          newUserResponse(user),
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// Request holder when receiving a disable two-factor request.
+type disableTOTPRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+// disableTOTP godoc
+// @Security bearerAuth
+// @Summary Disable two-factor authentication
+// @Description This function is used to turn off two-factor authentication, the password of the user is asked again.
+// @Description The secret and the recovery codes are deleted.
+// @Tags two-factor
+// @Accept json
+// @Produce json
+// @Param password body api.disableTOTPRequest true "Disable Two-Factor"
+// @Success 200 {object} api.userResponse
+// @Router /users/2fa/disable [post]
+func (server *Server) disableTOTP(ctx *gin.Context) {
+	var req disableTOTPRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	user, err := server.database.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	err = auth.CheckPassword(req.Password, user.HashedPassword)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	user, err = server.database.DisableTOTPTx(ctx, user.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUserResponse(user))
+}
@@ -0,0 +1,722 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/IsuruHaupe/web-api/auth"
+	token "github.com/IsuruHaupe/web-api/auth/token"
+	dbtx "github.com/IsuruHaupe/web-api/db/database"
+	mockdb "github.com/IsuruHaupe/web-api/db/mock"
+	db "github.com/IsuruHaupe/web-api/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// randomTwoFactorUser returns a random user with two-factor authentication enabled.
+func randomTwoFactorUser(t *testing.T) (user db.User, password string) {
+	user, password = randomUser(t)
+	secret, err := auth.GenerateTOTPSecret()
+	require.NoError(t, err)
+	user.TotpSecret = sql.NullString{String: secret, Valid: true}
+	user.TotpEnabled = true
+	return
+}
+
+// currentTOTPCode returns the TOTP code of the secret of the user at the current time.
+func currentTOTPCode(t *testing.T, user db.User) string {
+	code, err := auth.TOTPCode(user.TotpSecret.String, auth.TOTPStep(time.Now()))
+	require.NoError(t, err)
+	return code
+}
+
+// invalidTOTPCode returns a code that is not accepted for the secret of the user at the current time.
+func invalidTOTPCode(t *testing.T, user db.User) string {
+	for i := 0; ; i++ {
+		code := fmt.Sprintf("%06d", i)
+		if _, ok := auth.ValidateTOTP(user.TotpSecret.String, code, time.Now()); !ok {
+			return code
+		}
+	}
+}
+
+func TestLoginUserTwoFactorAPI(t *testing.T) {
+	user, password := randomTwoFactorUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	database := mockdb.NewMockDatabase(ctrl)
+	database.EXPECT().
+		GetUser(gomock.Any(), gomock.Eq(user.Username)).
+		Times(1).
+		Return(user, nil)
+	database.EXPECT().
+		CreateTwoFactorChallenge(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ interface{}, arg db.CreateTwoFactorChallengeParams) (db.TwoFactorChallenge, error) {
+			require.Equal(t, user.Username, arg.Username)
+			require.WithinDuration(t, time.Now().Add(time.Minute), arg.ExpiresAt, time.Second)
+			return db.TwoFactorChallenge{
+				HashedToken: arg.HashedToken,
+				Username:    arg.Username,
+				ExpiresAt:   arg.ExpiresAt,
+			}, nil
+		})
+	// No session is created before the second factor is checked.
+	database.EXPECT().
+		CreateSession(gomock.Any(), gomock.Any()).
+		Times(0)
+
+	server := newTestServer(t, database)
+	recorder := httptest.NewRecorder()
+
+	data, err := json.Marshal(gin.H{
+		"username": user.Username,
+		"password": password,
+	})
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodPost, "/users/login", bytes.NewReader(data))
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response twoFactorChallengeResponse
+	err = json.Unmarshal(recorder.Body.Bytes(), &response)
+	require.NoError(t, err)
+	require.True(t, response.TwoFactorRequired)
+	require.NotEmpty(t, response.ChallengeToken)
+	require.NotContains(t, recorder.Body.String(), "access_token")
+}
+
+func TestLoginTwoFactorAPI(t *testing.T) {
+	user, _ := randomTwoFactorUser(t)
+	challengeToken, err := auth.GenerateSecret(twoFactorChallengeTokenSize)
+	require.NoError(t, err)
+	hashedToken := auth.HashSecret(challengeToken)
+	challenge := db.TwoFactorChallenge{
+		HashedToken: hashedToken,
+		Username:    user.Username,
+		ExpiresAt:   time.Now().Add(time.Minute),
+	}
+	attemptArg := db.UseTwoFactorChallengeAttemptParams{
+		HashedToken: hashedToken,
+		MaxAttempts: maxTwoFactorAttempts,
+	}
+	recoveryCode := "abcde-fghij"
+
+	testCases := []struct {
+		name          string
+		body          func() gin.H
+		buildStubs    func(database *mockdb.MockDatabase)
+		checkResponse func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: func() gin.H {
+				return gin.H{"challenge_token": challengeToken, "code": currentTOTPCode(t, user)}
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetTwoFactorChallenge(gomock.Any(), gomock.Eq(hashedToken)).
+					Times(1).
+					Return(challenge, nil)
+				database.EXPECT().
+					UseTwoFactorChallengeAttempt(gomock.Any(), gomock.Eq(attemptArg)).
+					Times(1).
+					Return(challenge, nil)
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				database.EXPECT().
+					UseTOTPStep(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.UseTOTPStepParams) (db.User, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.InDelta(t, auth.TOTPStep(time.Now()), arg.TotpLastStep, 1)
+						return user, nil
+					})
+				database.EXPECT().
+					DeleteTwoFactorChallenge(gomock.Any(), gomock.Eq(hashedToken)).
+					Times(1).
+					Return(int64(1), nil)
+				database.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response loginUserResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &response)
+				require.NoError(t, err)
+				require.NotEmpty(t, response.AccessToken)
+				require.NotEmpty(t, response.SessionToken)
+				require.True(t, response.User.TwoFactorEnabled)
+			},
+		},
+		{
+			name: "Recovery Code",
+			body: func() gin.H {
+				return gin.H{"challenge_token": challengeToken, "code": " ABCDE-FGHIJ "}
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetTwoFactorChallenge(gomock.Any(), gomock.Eq(hashedToken)).
+					Times(1).
+					Return(challenge, nil)
+				database.EXPECT().
+					UseTwoFactorChallengeAttempt(gomock.Any(), gomock.Eq(attemptArg)).
+					Times(1).
+					Return(challenge, nil)
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				arg := db.UseRecoveryCodeParams{
+					HashedCode: auth.HashSecret(recoveryCode),
+					Username:   user.Username,
+				}
+				database.EXPECT().
+					UseRecoveryCode(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.RecoveryCode{HashedCode: arg.HashedCode, Username: user.Username}, nil)
+				database.EXPECT().
+					DeleteTwoFactorChallenge(gomock.Any(), gomock.Eq(hashedToken)).
+					Times(1).
+					Return(int64(1), nil)
+				database.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "Invalid Code",
+			body: func() gin.H {
+				return gin.H{"challenge_token": challengeToken, "code": "invalid"}
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetTwoFactorChallenge(gomock.Any(), gomock.Eq(hashedToken)).
+					Times(1).
+					Return(challenge, nil)
+				database.EXPECT().
+					UseTwoFactorChallengeAttempt(gomock.Any(), gomock.Eq(attemptArg)).
+					Times(1).
+					Return(challenge, nil)
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				database.EXPECT().
+					UseRecoveryCode(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.RecoveryCode{}, sql.ErrNoRows)
+				database.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+				attempts, err := server.loginAttempts.Get(context.Background(), loginAttemptUserPrefix+user.Username)
+				require.NoError(t, err)
+				require.Equal(t, 1, attempts.Failures)
+			},
+		},
+		{
+			name: "Replayed Code",
+			body: func() gin.H {
+				return gin.H{"challenge_token": challengeToken, "code": currentTOTPCode(t, user)}
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetTwoFactorChallenge(gomock.Any(), gomock.Eq(hashedToken)).
+					Times(1).
+					Return(challenge, nil)
+				database.EXPECT().
+					UseTwoFactorChallengeAttempt(gomock.Any(), gomock.Eq(attemptArg)).
+					Times(1).
+					Return(challenge, nil)
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				database.EXPECT().
+					UseTOTPStep(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, sql.ErrNoRows)
+				database.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Challenge Used Up Or Expired",
+			body: func() gin.H {
+				return gin.H{"challenge_token": challengeToken, "code": currentTOTPCode(t, user)}
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetTwoFactorChallenge(gomock.Any(), gomock.Eq(hashedToken)).
+					Times(1).
+					Return(challenge, nil)
+				database.EXPECT().
+					UseTwoFactorChallengeAttempt(gomock.Any(), gomock.Eq(attemptArg)).
+					Times(1).
+					Return(db.TwoFactorChallenge{}, sql.ErrNoRows)
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Unknown Challenge",
+			body: func() gin.H {
+				return gin.H{"challenge_token": "unknown", "code": currentTOTPCode(t, user)}
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetTwoFactorChallenge(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TwoFactorChallenge{}, sql.ErrNoRows)
+				database.EXPECT().
+					UseTwoFactorChallengeAttempt(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Challenge Already Used",
+			body: func() gin.H {
+				return gin.H{"challenge_token": challengeToken, "code": currentTOTPCode(t, user)}
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetTwoFactorChallenge(gomock.Any(), gomock.Eq(hashedToken)).
+					Times(1).
+					Return(challenge, nil)
+				database.EXPECT().
+					UseTwoFactorChallengeAttempt(gomock.Any(), gomock.Eq(attemptArg)).
+					Times(1).
+					Return(challenge, nil)
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				database.EXPECT().
+					UseTOTPStep(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(user, nil)
+				database.EXPECT().
+					DeleteTwoFactorChallenge(gomock.Any(), gomock.Eq(hashedToken)).
+					Times(1).
+					Return(int64(0), nil)
+				database.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Missing Code",
+			body: func() gin.H {
+				return gin.H{"challenge_token": challengeToken}
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetTwoFactorChallenge(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		currentTest := testCases[i]
+
+		t.Run(currentTest.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			database := mockdb.NewMockDatabase(ctrl)
+			currentTest.buildStubs(database)
+
+			server := newTestServer(t, database)
+			server.config.LoginAttemptWindow = time.Minute
+			recorder := httptest.NewRecorder()
+
+			// Marshal body data to JSON
+			data, err := json.Marshal(currentTest.body())
+			require.NoError(t, err)
+
+			url := "/users/login/2fa"
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			currentTest.checkResponse(t, server, recorder)
+		})
+	}
+}
+
+func TestEnrollTOTPAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(database *mockdb.MockDatabase)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, token.RoleEditor, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					SetUserTOTPSecret(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.SetUserTOTPSecretParams) (db.User, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.True(t, arg.TotpSecret.Valid)
+
+						enrolledUser := user
+						enrolledUser.TotpSecret = arg.TotpSecret
+						return enrolledUser, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response enrollTOTPResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &response)
+				require.NoError(t, err)
+				require.NotEmpty(t, response.Secret)
+				require.Equal(t, auth.TOTPProvisioningURI("web-api", user.Username, response.Secret), response.ProvisioningURI)
+			},
+		},
+		{
+			name: "Already Enabled",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, token.RoleEditor, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					SetUserTOTPSecret(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "No Authorization",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					SetUserTOTPSecret(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		currentTest := testCases[i]
+
+		t.Run(currentTest.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			database := mockdb.NewMockDatabase(ctrl)
+			currentTest.buildStubs(database)
+
+			server := newTestServer(t, database)
+			recorder := httptest.NewRecorder()
+
+			url := "/users/2fa/enroll"
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			currentTest.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			currentTest.checkResponse(recorder)
+		})
+	}
+}
+
+func TestEnableTOTPAPI(t *testing.T) {
+	enrolledUser, _ := randomTwoFactorUser(t)
+	enrolledUser.TotpEnabled = false
+	enabledUser := enrolledUser
+	enabledUser.TotpEnabled = true
+	user, _ := randomUser(t)
+	user.Username = enrolledUser.Username
+
+	testCases := []struct {
+		name          string
+		body          func() gin.H
+		buildStubs    func(database *mockdb.MockDatabase)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: func() gin.H {
+				return gin.H{"code": currentTOTPCode(t, enrolledUser)}
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(enrolledUser.Username)).
+					Times(1).
+					Return(enrolledUser, nil)
+				database.EXPECT().
+					EnableTOTPTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg dbtx.EnableTOTPTxParams) (db.User, error) {
+						require.Equal(t, enrolledUser.Username, arg.Username)
+						require.InDelta(t, auth.TOTPStep(time.Now()), arg.TOTPStep, 1)
+						require.Len(t, arg.HashedRecoveryCodes, recoveryCodeCount)
+						return enabledUser, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response enableTOTPResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &response)
+				require.NoError(t, err)
+				require.Len(t, response.RecoveryCodes, recoveryCodeCount)
+				require.True(t, response.User.TwoFactorEnabled)
+			},
+		},
+		{
+			name: "Invalid Code",
+			body: func() gin.H {
+				return gin.H{"code": invalidTOTPCode(t, enrolledUser)}
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(enrolledUser.Username)).
+					Times(1).
+					Return(enrolledUser, nil)
+				database.EXPECT().
+					EnableTOTPTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Not Enrolled",
+			body: func() gin.H {
+				return gin.H{"code": "123456"}
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				database.EXPECT().
+					EnableTOTPTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Already Enabled",
+			body: func() gin.H {
+				return gin.H{"code": currentTOTPCode(t, enabledUser)}
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(enabledUser.Username)).
+					Times(1).
+					Return(enabledUser, nil)
+				database.EXPECT().
+					EnableTOTPTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "Invalid Code Format",
+			body: func() gin.H {
+				return gin.H{"code": "abcdef"}
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		currentTest := testCases[i]
+
+		t.Run(currentTest.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			database := mockdb.NewMockDatabase(ctrl)
+			currentTest.buildStubs(database)
+
+			server := newTestServer(t, database)
+			recorder := httptest.NewRecorder()
+
+			// Marshal body data to JSON
+			data, err := json.Marshal(currentTest.body())
+			require.NoError(t, err)
+
+			url := "/users/2fa/enable"
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, enrolledUser.Username, token.RoleEditor, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			currentTest.checkResponse(recorder)
+		})
+	}
+}
+
+func TestDisableTOTPAPI(t *testing.T) {
+	user, password := randomTwoFactorUser(t)
+	disabledUser := user
+	disabledUser.TotpEnabled = false
+	disabledUser.TotpSecret = sql.NullString{}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(database *mockdb.MockDatabase)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"password": password,
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				database.EXPECT().
+					DisableTOTPTx(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(disabledUser, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response userResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &response)
+				require.NoError(t, err)
+				require.False(t, response.TwoFactorEnabled)
+			},
+		},
+		{
+			name: "Incorrect Password",
+			body: gin.H{
+				"password": "incorrect",
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				database.EXPECT().
+					DisableTOTPTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Internal Error",
+			body: gin.H{
+				"password": password,
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				database.EXPECT().
+					DisableTOTPTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		currentTest := testCases[i]
+
+		t.Run(currentTest.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			database := mockdb.NewMockDatabase(ctrl)
+			currentTest.buildStubs(database)
+
+			server := newTestServer(t, database)
+			recorder := httptest.NewRecorder()
+
+			// Marshal body data to JSON
+			data, err := json.Marshal(currentTest.body)
+			require.NoError(t, err)
+
+			url := "/users/2fa/disable"
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, token.RoleEditor, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			currentTest.checkResponse(recorder)
+		})
+	}
+}
@@ -2,10 +2,12 @@ package api
 
 import (
 	"database/sql"
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/IsuruHaupe/web-api/auth"
+	token "github.com/IsuruHaupe/web-api/auth/token"
 	db "github.com/IsuruHaupe/web-api/db/sqlc"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -15,7 +17,7 @@ import (
 // Request holder when receiving a create user request.
 type createUserRequest struct {
 	Username string `json:"username" binding:"required,alphanum"`
-	Password string `json:"password" binding:"required,min=6"`
+	Password string `json:"password" binding:"required"`
 	FullName string `json:"full_name" binding:"required"`
 	Email    string `json:"email" binding:"required,email"`
 }
@@ -25,6 +27,9 @@ type userResponse struct {
 	Username            string    `json:"username"`
 	Fullname            string    `json:"fullname"`
 	Email               string    `json:"email"`
+	Role                string    `json:"role"`
+	EmailVerified       bool      `json:"email_verified"`
+	TwoFactorEnabled    bool      `json:"two_factor_enabled"`
 	PasswordLastChanged time.Time `json:"password_last_changed"`
 	CreatedAt           time.Time `json:"created_at"`
 }
@@ -34,6 +39,9 @@ func newUserResponse(user db.User) userResponse {
 		Username:            user.Username,
 		Fullname:            user.Fullname,
 		Email:               user.Email,
+		Role:                user.Role,
+		EmailVerified:       user.EmailVerified,
+		TwoFactorEnabled:    user.TotpEnabled,
 		PasswordLastChanged: user.PasswordLastChanged,
 		CreatedAt:           user.CreateAt,
 	}
@@ -42,6 +50,8 @@ func newUserResponse(user db.User) userResponse {
 // createUser godoc
 // @Summary Create a new user
 // @Description This function is used to create a new user account.
+// @Description A verification link is sent to the email, unverified users are refused or limited to the viewer role at login depending on the configuration.
+// @Description The password must follow the password policy and must not contain the username or the email.
 // @Tags user
 // @Accept json
 // @Produce json
@@ -56,8 +66,18 @@ func (server *Server) createUser(ctx *gin.Context) {
 		return
 	}
 
+	err := server.passwordPolicy.Check(req.Password, req.Username, req.Email)
+	if err != nil {
+		if errors.Is(err, auth.ErrPasswordPolicy) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
 	// Compute hashed password.
-	hashedPassword, err := auth.HashPassword(req.Password)
+	hashedPassword, err := server.passwordHasher.Hash(req.Password)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
@@ -84,13 +104,19 @@ func (server *Server) createUser(ctx *gin.Context) {
 		return
 	}
 
+	// The account is created even if the email can not be sent, a new link can be requested later on.
+	err = server.sendVerificationEmail(user)
+	if err != nil {
+		ctx.Error(err)
+	}
+
 	response := newUserResponse(user)
 	ctx.JSON(http.StatusOK, response)
 }
 
 type loginUserRequest struct {
 	Username string `json:"username" binding:"required,alphanum"`
-	Password string `json:"password" binding:"required,min=6"`
+	Password string `json:"password" binding:"required"`
 }
 
 type loginUserResponse struct {
@@ -106,11 +132,15 @@ type loginUserResponse struct {
 // @Security bearerAuth
 // @Summary Login an user
 // @Description This function is used to authenticate a user providing the username and password.
+// @Description Unknown usernames and wrong passwords get the same response, logins are refused for a while after too many failures.
+// @Description Passwords hashed with an older algorithm or weaker parameters are rehashed on a successful login.
+// @Description When two-factor authentication is enabled, a challenge token is returned instead and the login is finished on /users/login/2fa.
 // @Tags user
 // @Accept json
 // @Produce json
 // @Param user body api.loginUserRequest true "Login User"
 // @Success 200 {object} api.loginUserResponse
+// @Success 200 {object} api.twoFactorChallengeResponse
 // @Router /users/login [post]
 func (server *Server) loginUser(ctx *gin.Context) {
 	var req loginUserRequest
@@ -119,35 +149,100 @@ func (server *Server) loginUser(ctx *gin.Context) {
 		return
 	}
 
+	// Logins are refused for a while after too many failures for the username or the client IP.
+	if server.refuseLockedLogin(ctx, req.Username) {
+		return
+	}
+
 	// Check if user is correct.
 	user, err := server.database.GetUser(ctx, req.Username)
+	if err != nil && err != sql.ErrNoRows {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if err == sql.ErrNoRows {
+		server.checkDummyPassword(req.Password)
+	} else {
+		err = auth.CheckPassword(req.Password, user.HashedPassword)
+	}
 	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+		if err := server.recordLoginFailure(ctx, req.Username); err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errInvalidCredentials))
 		return
 	}
 
-	err = auth.CheckPassword(req.Password, user.HashedPassword)
+	err = server.resetLoginFailures(ctx, user.Username)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.Username, server.config.AccessTokenDuration)
+	// Hashes made with an older algorithm or weaker parameters are upgraded while the password is known.
+	if server.passwordHasher.NeedsRehash(user.HashedPassword) {
+		server.rehashPassword(ctx, user, req.Password)
+	}
+
+	server.completeLogin(ctx, user)
+}
+
+// This function will finish the login of an user who proved its identity, either with its password or with an
+// identity provider. With two-factor authentication, a challenge token is returned instead of the session and
+// the login is finished on /users/login/2fa.
+func (server *Server) completeLogin(ctx *gin.Context, user db.User) {
+	role, err := server.accessRole(user)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	if user.TotpEnabled {
+		response, err := server.createTwoFactorChallenge(ctx, user)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusOK, response)
 		return
 	}
 
-	sessionToken, sessionPayload, err := server.tokenMaker.CreateToken(user.Username, server.config.SessionDuration)
+	response, err := server.createLoginSession(ctx, user, role)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
+	ctx.JSON(http.StatusOK, response)
+}
+
+// This function will create a new session for an user who proved its identity, along with the first access token.
+func (server *Server) createLoginSession(ctx *gin.Context, user db.User, role string) (loginUserResponse, error) {
+	sessionPayload, err := token.NewPayload(user.Username, token.TokenTypeSession, server.config.SessionDuration)
+	if err != nil {
+		return loginUserResponse{}, err
+	}
+
+	sessionToken, err := server.tokenMaker.CreateToken(sessionPayload)
+	if err != nil {
+		return loginUserResponse{}, err
+	}
+
+	// The access token is bound to the session so that it is revoked along with it.
+	accessPayload, err := token.NewPayload(user.Username, token.TokenTypeAccess, server.config.AccessTokenDuration)
+	if err != nil {
+		return loginUserResponse{}, err
+	}
+	accessPayload.SessionID = sessionPayload.ID
+	accessPayload.Role = role
+
+	accessToken, err := server.tokenMaker.CreateToken(accessPayload)
+	if err != nil {
+		return loginUserResponse{}, err
+	}
+
 	session, err := server.database.CreateSession(ctx, db.CreateSessionParams{
 		ID:           sessionPayload.ID,
 		Username:     user.Username,
@@ -156,21 +251,107 @@ func (server *Server) loginUser(ctx *gin.Context) {
 		ClientIp:     ctx.ClientIP(),
 		IsBlocked:    false,
 		ExpiresAt:    sessionPayload.ExpiredAt,
+		FamilyID:     sessionPayload.ID,
 	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
+		return loginUserResponse{}, err
 	}
 
-	response := loginUserResponse{
+	return loginUserResponse{
 		SessionID:             session.ID,
 		AccessToken:           accessToken,
 		AccessTokenExpiresAt:  accessPayload.ExpiredAt,
 		SessionToken:          sessionToken,
 		SessionTokenExpiresAt: sessionPayload.ExpiredAt,
 		User:                  newUserResponse(user),
+	}, nil
+}
+
+// This function replaces the hash of the password of an user with one made by the server hasher. A failure does not
+// prevent the login, the hash will be upgraded on a later one. The update is skipped if the password changed meanwhile.
+func (server *Server) rehashPassword(ctx *gin.Context, user db.User, password string) {
+	hashedPassword, err := server.passwordHasher.Hash(password)
+	if err != nil {
+		ctx.Error(err)
+		return
 	}
 
-	ctx.JSON(http.StatusOK, response)
+	err = server.database.UpdateUserPasswordHash(ctx, db.UpdateUserPasswordHashParams{
+		Username:          user.Username,
+		HashedPassword:    hashedPassword,
+		OldHashedPassword: user.HashedPassword,
+	})
+	if err != nil {
+		ctx.Error(err)
+	}
+}
+
+// Request holder when receiving a change password request.
+type changePasswordRequest struct {
+	OldPassword string `json:"old_password" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+// changePassword godoc
+// @Security bearerAuth
+// @Summary Change the password of an user
+// @Description This function is used to change the password of the logged in user.
+// @Description Every session of the user is revoked along with the access tokens issued from them, the user has to login again.
+// @Description The new password must follow the password policy.
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param user body api.changePasswordRequest true "Change Password"
+// @Success 200 {object} api.userResponse
+// @Router /users/password [patch]
+func (server *Server) changePassword(ctx *gin.Context) {
+	var req changePasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	user, err := server.database.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	err = auth.CheckPassword(req.OldPassword, user.HashedPassword)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	err = server.passwordPolicy.Check(req.NewPassword, user.Username, user.Email)
+	if err != nil {
+		if errors.Is(err, auth.ErrPasswordPolicy) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	hashedPassword, err := server.passwordHasher.Hash(req.NewPassword)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	user, err = server.database.ChangePasswordTx(ctx, db.UpdateUserPasswordParams{
+		Username:       user.Username,
+		HashedPassword: hashedPassword,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
 
+	ctx.JSON(http.StatusOK, newUserResponse(user))
 }
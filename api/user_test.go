@@ -10,15 +10,19 @@ import (
 	"net/http/httptest"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/IsuruHaupe/web-api/auth"
+	token "github.com/IsuruHaupe/web-api/auth/token"
 	mockdb "github.com/IsuruHaupe/web-api/db/mock"
 	db "github.com/IsuruHaupe/web-api/db/sqlc"
+	mockmail "github.com/IsuruHaupe/web-api/mail/mock"
 	"github.com/Pallinder/go-randomdata"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/lib/pq"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
 )
 
 // Create a custom validator for testing user
@@ -55,7 +59,7 @@ func TestCreateUserAPI(t *testing.T) {
 	testCases := []struct {
 		name          string
 		body          gin.H
-		buildStubs    func(database *mockdb.MockDatabase)
+		buildStubs    func(database *mockdb.MockDatabase, mailer *mockmail.MockMailer)
 		checkResponse func(recoder *httptest.ResponseRecorder)
 	}{
 		{
@@ -66,7 +70,7 @@ func TestCreateUserAPI(t *testing.T) {
 				"full_name": user.Fullname,
 				"email":     user.Email,
 			},
-			buildStubs: func(database *mockdb.MockDatabase) {
+			buildStubs: func(database *mockdb.MockDatabase, mailer *mockmail.MockMailer) {
 				arg := db.CreateUserParams{
 					Username: user.Username,
 					Fullname: user.Fullname,
@@ -76,8 +80,36 @@ func TestCreateUserAPI(t *testing.T) {
 					CreateUser(gomock.Any(), EqCreateUserParams(arg, password)).
 					Times(1).
 					Return(user, nil)
+				mailer.EXPECT().
+					SendEmail(gomock.Eq(user.Email), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchUser(t, recorder.Body, user)
+			},
+		},
+		{
+			name: "Mail Error",
+			body: gin.H{
+				"username":  user.Username,
+				"password":  password,
+				"full_name": user.Fullname,
+				"email":     user.Email,
+			},
+			buildStubs: func(database *mockdb.MockDatabase, mailer *mockmail.MockMailer) {
+				database.EXPECT().
+					CreateUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(user, nil)
+				mailer.EXPECT().
+					SendEmail(gomock.Eq(user.Email), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sql.ErrConnDone)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				// The user can ask for a new verification link.
 				require.Equal(t, http.StatusOK, recorder.Code)
 				requireBodyMatchUser(t, recorder.Body, user)
 			},
@@ -90,7 +122,7 @@ func TestCreateUserAPI(t *testing.T) {
 				"full_name": user.Fullname,
 				"email":     user.Email,
 			},
-			buildStubs: func(database *mockdb.MockDatabase) {
+			buildStubs: func(database *mockdb.MockDatabase, mailer *mockmail.MockMailer) {
 				database.EXPECT().
 					CreateUser(gomock.Any(), gomock.Any()).
 					Times(1).
@@ -108,7 +140,7 @@ func TestCreateUserAPI(t *testing.T) {
 				"full_name": user.Fullname,
 				"email":     user.Email,
 			},
-			buildStubs: func(database *mockdb.MockDatabase) {
+			buildStubs: func(database *mockdb.MockDatabase, mailer *mockmail.MockMailer) {
 				database.EXPECT().
 					CreateUser(gomock.Any(), gomock.Any()).
 					Times(1).
@@ -126,7 +158,7 @@ func TestCreateUserAPI(t *testing.T) {
 				"full_name": user.Fullname,
 				"email":     user.Email,
 			},
-			buildStubs: func(database *mockdb.MockDatabase) {
+			buildStubs: func(database *mockdb.MockDatabase, mailer *mockmail.MockMailer) {
 				database.EXPECT().
 					CreateUser(gomock.Any(), gomock.Any()).
 					Times(0)
@@ -143,7 +175,7 @@ func TestCreateUserAPI(t *testing.T) {
 				"full_name": user.Fullname,
 				"email":     "invalid-email",
 			},
-			buildStubs: func(database *mockdb.MockDatabase) {
+			buildStubs: func(database *mockdb.MockDatabase, mailer *mockmail.MockMailer) {
 				database.EXPECT().
 					CreateUser(gomock.Any(), gomock.Any()).
 					Times(0)
@@ -160,13 +192,32 @@ func TestCreateUserAPI(t *testing.T) {
 				"full_name": user.Fullname,
 				"email":     user.Email,
 			},
-			buildStubs: func(database *mockdb.MockDatabase) {
+			buildStubs: func(database *mockdb.MockDatabase, mailer *mockmail.MockMailer) {
 				database.EXPECT().
 					CreateUser(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), auth.ErrPasswordPolicy.Error())
+			},
+		},
+		{
+			name: "Password Contains Email",
+			body: gin.H{
+				"username":  user.Username,
+				"password":  user.Email + "!",
+				"full_name": user.Fullname,
+				"email":     user.Email,
+			},
+			buildStubs: func(database *mockdb.MockDatabase, mailer *mockmail.MockMailer) {
+				database.EXPECT().
+					CreateUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), auth.ErrPasswordPolicy.Error())
 			},
 		},
 	}
@@ -179,9 +230,10 @@ func TestCreateUserAPI(t *testing.T) {
 			defer ctrl.Finish()
 
 			database := mockdb.NewMockDatabase(ctrl)
-			currentTest.buildStubs(database)
+			mailer := mockmail.NewMockMailer(ctrl)
+			currentTest.buildStubs(database, mailer)
 
-			server := newTestServer(t, database)
+			server := newTestServerWithMailer(t, database, mailer)
 			recorder := httptest.NewRecorder()
 
 			// Marshal body data to JSON
@@ -201,6 +253,13 @@ func TestCreateUserAPI(t *testing.T) {
 func TestLoginUserAPI(t *testing.T) {
 	user, password := randomUser(t)
 
+	// An user whose password was hashed before the switch to argon2id.
+	bcryptHasher, err := auth.NewPasswordHasher(auth.PasswordAlgorithmBcrypt, 0, 0, 0, bcrypt.DefaultCost)
+	require.NoError(t, err)
+	bcryptUser := user
+	bcryptUser.HashedPassword, err = bcryptHasher.Hash(password)
+	require.NoError(t, err)
+
 	testCases := []struct {
 		name          string
 		body          gin.H
@@ -218,6 +277,60 @@ func TestLoginUserAPI(t *testing.T) {
 					GetUser(gomock.Any(), gomock.Eq(user.Username)).
 					Times(1).
 					Return(user, nil)
+				database.EXPECT().
+					UpdateUserPasswordHash(gomock.Any(), gomock.Any()).
+					Times(0)
+				database.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "Disabled",
+			body: gin.H{
+				"username": user.Username,
+				"password": password,
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				disabledUser := user
+				disabledUser.DisabledAt = sql.NullTime{Time: time.Now(), Valid: true}
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(disabledUser, nil)
+				database.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errAccountDisabled.Error())
+			},
+		},
+		{
+			name: "Outdated Hash",
+			body: gin.H{
+				"username": user.Username,
+				"password": password,
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(bcryptUser, nil)
+				database.EXPECT().
+					UpdateUserPasswordHash(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.UpdateUserPasswordHashParams) error {
+						require.Equal(t, user.Username, arg.Username)
+						require.Equal(t, bcryptUser.HashedPassword, arg.OldHashedPassword)
+						require.NoError(t, auth.CheckPassword(password, arg.HashedPassword))
+						require.False(t, auth.DefaultPasswordHasher.NeedsRehash(arg.HashedPassword))
+						return nil
+					})
 				database.EXPECT().
 					CreateSession(gomock.Any(), gomock.Any()).
 					Times(1)
@@ -226,6 +339,49 @@ func TestLoginUserAPI(t *testing.T) {
 				require.Equal(t, http.StatusOK, recorder.Code)
 			},
 		},
+		{
+			name: "Outdated Hash Update Error",
+			body: gin.H{
+				"username": user.Username,
+				"password": password,
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(bcryptUser, nil)
+				database.EXPECT().
+					UpdateUserPasswordHash(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sql.ErrConnDone)
+				database.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				// The hash will be upgraded on a later login.
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "Outdated Hash Incorrect Password",
+			body: gin.H{
+				"username": user.Username,
+				"password": "incorrect",
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(bcryptUser, nil)
+				database.EXPECT().
+					UpdateUserPasswordHash(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
 		{
 			name: "Not Found",
 			body: gin.H{
@@ -239,7 +395,9 @@ func TestLoginUserAPI(t *testing.T) {
 					Return(db.User{}, sql.ErrNoRows)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusNotFound, recorder.Code)
+				// Unknown usernames and wrong passwords get the same response.
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errInvalidCredentials.Error())
 			},
 		},
 		{
@@ -256,6 +414,7 @@ func TestLoginUserAPI(t *testing.T) {
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.Contains(t, recorder.Body.String(), errInvalidCredentials.Error())
 			},
 		},
 		{
@@ -320,6 +479,187 @@ func TestLoginUserAPI(t *testing.T) {
 	}
 }
 
+func TestChangePasswordAPI(t *testing.T) {
+	user, password := randomUser(t)
+	newPassword := "new-password"
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(database *mockdb.MockDatabase)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"old_password": password,
+				"new_password": newPassword,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, token.RoleEditor, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				database.EXPECT().
+					ChangePasswordTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.UpdateUserPasswordParams) (db.User, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.NoError(t, auth.CheckPassword(newPassword, arg.HashedPassword))
+
+						updatedUser := user
+						updatedUser.HashedPassword = arg.HashedPassword
+						updatedUser.PasswordLastChanged = time.Now()
+						return updatedUser, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchUser(t, recorder.Body, user)
+			},
+		},
+		{
+			name: "Incorrect Password",
+			body: gin.H{
+				"old_password": "incorrect",
+				"new_password": newPassword,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, token.RoleEditor, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				database.EXPECT().
+					ChangePasswordTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Too Short Password",
+			body: gin.H{
+				"old_password": password,
+				"new_password": "short",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, token.RoleEditor, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				database.EXPECT().
+					ChangePasswordTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), auth.ErrPasswordPolicy.Error())
+			},
+		},
+		{
+			name: "Password Contains Username",
+			body: gin.H{
+				"old_password": password,
+				"new_password": "new-" + user.Username,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, token.RoleEditor, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				database.EXPECT().
+					ChangePasswordTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), auth.ErrPasswordPolicy.Error())
+			},
+		},
+		{
+			name: "No Authorization",
+			body: gin.H{
+				"old_password": password,
+				"new_password": newPassword,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					ChangePasswordTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Internal Error",
+			body: gin.H{
+				"old_password": password,
+				"new_password": newPassword,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, token.RoleEditor, time.Minute)
+			},
+			buildStubs: func(database *mockdb.MockDatabase) {
+				database.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				database.EXPECT().
+					ChangePasswordTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		currentTest := testCases[i]
+
+		t.Run(currentTest.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			database := mockdb.NewMockDatabase(ctrl)
+			currentTest.buildStubs(database)
+
+			server := newTestServer(t, database)
+			recorder := httptest.NewRecorder()
+
+			// Marshal body data to JSON
+			data, err := json.Marshal(currentTest.body)
+			require.NoError(t, err)
+
+			url := "/users/password"
+			request, err := http.NewRequest(http.MethodPatch, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			currentTest.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			currentTest.checkResponse(recorder)
+		})
+	}
+}
+
 func randomUser(t *testing.T) (user db.User, password string) {
 	password = "password"
 	hashedPassword, err := auth.HashPassword(password)
@@ -330,6 +670,8 @@ func randomUser(t *testing.T) (user db.User, password string) {
 		HashedPassword: hashedPassword,
 		Fullname:       randomdata.FullName(randomdata.Female),
 		Email:          randomdata.Email(),
+		Role:           token.RoleEditor,
+		EmailVerified:  true,
 	}
 	return
 }
@@ -345,5 +687,7 @@ func requireBodyMatchUser(t *testing.T, body *bytes.Buffer, user db.User) {
 	require.Equal(t, user.Username, gotUser.Username)
 	require.Equal(t, user.Fullname, gotUser.Fullname)
 	require.Equal(t, user.Email, gotUser.Email)
+	require.Equal(t, user.Role, gotUser.Role)
+	require.Equal(t, user.EmailVerified, gotUser.EmailVerified)
 	require.Empty(t, gotUser.HashedPassword)
 }
@@ -0,0 +1,26 @@
+package auth
+
+const (
+	// APIKeyPrefix starts every API key so that leaked keys are easy to recognize.
+	APIKeyPrefix = "wak_"
+	apiKeySize   = 32
+	// Number of characters of the key, prefix included, kept in clear to identify it.
+	apiKeyDisplaySize = len(APIKeyPrefix) + 8
+)
+
+// This function is used to generate a new random API key, it returns the key which is only shown once,
+// the beginning of the key used to identify it and the hash of the key which is stored.
+func GenerateAPIKey() (key string, prefix string, hashedKey string, err error) {
+	secret, err := GenerateSecret(apiKeySize)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	key = APIKeyPrefix + secret
+	return key, key[:apiKeyDisplaySize], HashAPIKey(key), nil
+}
+
+// This function is used to compute the hash of an API key.
+func HashAPIKey(key string) string {
+	return HashSecret(key)
+}
@@ -0,0 +1,23 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKey(t *testing.T) {
+	key1, prefix1, hashedKey1, err := GenerateAPIKey()
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(key1, APIKeyPrefix))
+	require.True(t, strings.HasPrefix(key1, prefix1))
+	require.NotEqual(t, key1, prefix1)
+	require.Equal(t, HashAPIKey(key1), hashedKey1)
+	require.NotContains(t, hashedKey1, key1)
+
+	key2, _, hashedKey2, err := GenerateAPIKey()
+	require.NoError(t, err)
+	require.NotEqual(t, key1, key2)
+	require.NotEqual(t, hashedKey1, hashedKey2)
+}
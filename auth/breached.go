@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Number of hexadecimal characters of the SHA-1 prefixes naming the files of a breached password directory.
+const breachedPrefixSize = 5
+
+// BreachedPasswords tells whether a password is known to have been leaked.
+type BreachedPasswords interface {
+	Contains(password string) (bool, error)
+}
+
+// BreachedPasswordDirectory is a breached password list kept on disk with the k-anonymity layout of the
+// Pwned Passwords range API, so that it can be checked offline. The uppercase SHA-1 of every password is split
+// after its first 5 characters, the prefix names a "<PREFIX>.txt" file whose lines are "<SUFFIX>:<COUNT>".
+// This is the layout written by the Pwned Passwords downloader.
+type BreachedPasswordDirectory struct {
+	dir string
+}
+
+// NewBreachedPasswordDirectory creates a new BreachedPasswordDirectory reading the files of the directory.
+func NewBreachedPasswordDirectory(dir string) (*BreachedPasswordDirectory, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open breached password directory : %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("breached password list %s is not a directory", dir)
+	}
+	return &BreachedPasswordDirectory{dir: dir}, nil
+}
+
+// Contains reads the file of the prefix of the password hash and looks for its suffix.
+// A missing file means that no breached password has this prefix.
+func (directory *BreachedPasswordDirectory) Contains(password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:breachedPrefixSize], hash[breachedPrefixSize:]
+
+	file, err := os.Open(filepath.Join(directory.dir, prefix+".txt"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("cannot read breached password list : %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		fields := strings.SplitN(line, ":", 2)
+		if !strings.EqualFold(fields[0], suffix) {
+			continue
+		}
+		// Padding lines added by the range API have a count of 0.
+		if len(fields) == 2 {
+			occurrences, err := strconv.Atoi(fields[1])
+			if err == nil && occurrences == 0 {
+				return false, nil
+			}
+		}
+		return true, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return false, fmt.Errorf("cannot read breached password list : %w", err)
+	}
+
+	return false, nil
+}
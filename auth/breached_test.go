@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// The SHA-1 of "password" is 5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8.
+func newTestBreachedPasswordDirectory(t *testing.T) *BreachedPasswordDirectory {
+	dir := t.TempDir()
+	content := "0018A45C4D1DEF81644B54AB7F969B88D65:1\n" +
+		"1E4C9B93F3F0682250B6CF8331B7EE68FD8:9545824\n" +
+		"1E4C9B93F3F0682250B6CF8331B7EE68FD9:0\n"
+	err := ioutil.WriteFile(filepath.Join(dir, "5BAA6.txt"), []byte(content), 0600)
+	require.NoError(t, err)
+
+	directory, err := NewBreachedPasswordDirectory(dir)
+	require.NoError(t, err)
+	return directory
+}
+
+func TestBreachedPasswordDirectory(t *testing.T) {
+	directory := newTestBreachedPasswordDirectory(t)
+
+	breached, err := directory.Contains("password")
+	require.NoError(t, err)
+	require.True(t, breached)
+
+	// Same prefix file, unknown suffix.
+	breached, err = directory.Contains("Password")
+	require.NoError(t, err)
+	require.False(t, breached)
+
+	// No file for the prefix.
+	breached, err = directory.Contains(RandomString(16))
+	require.NoError(t, err)
+	require.False(t, breached)
+}
+
+func TestNewBreachedPasswordDirectoryInvalid(t *testing.T) {
+	_, err := NewBreachedPasswordDirectory(filepath.Join(t.TempDir(), "missing"))
+	require.Error(t, err)
+
+	file := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, ioutil.WriteFile(file, nil, 0600))
+	_, err = NewBreachedPasswordDirectory(file)
+	require.Error(t, err)
+}
@@ -1,21 +1,198 @@
 package auth
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"strings"
 
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
-// This function is used to compute the hash of the password.
-func HashPassword(password string) (string, error) {
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+// Password hashing algorithms available through the PASSWORD_HASH_ALGORITHM configuration.
+const (
+	PasswordAlgorithmArgon2id = "argon2id"
+	PasswordAlgorithmBcrypt   = "bcrypt"
+)
+
+const (
+	argon2SaltSize = 16
+	argon2KeySize  = 32
+	// Prefix of the argon2id hashes, they are stored in the PHC string format :
+	// $argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<hash>
+	argon2Prefix = "$argon2id$"
+)
+
+var (
+	// ErrMismatchedPassword is returned when a password does not match its hash, whatever the algorithm.
+	ErrMismatchedPassword = errors.New("hashed password is not the hash of the given password")
+	// ErrUnknownPasswordHash is returned when a hash has not been created by a supported algorithm.
+	ErrUnknownPasswordHash = errors.New("unknown password hash format")
+)
+
+// PasswordHasher holds the algorithm and the parameters used to hash new passwords. Every hash records its
+// algorithm and parameters so that hashes made with older settings can still be checked and then upgraded.
+type PasswordHasher struct {
+	Algorithm         string
+	Argon2Memory      uint32
+	Argon2Iterations  uint32
+	Argon2Parallelism uint8
+	BcryptCost        int
+}
+
+// DefaultPasswordHasher uses argon2id with the parameters recommended by RFC 9106 for memory constrained environments.
+var DefaultPasswordHasher = PasswordHasher{
+	Algorithm:         PasswordAlgorithmArgon2id,
+	Argon2Memory:      64 * 1024,
+	Argon2Iterations:  3,
+	Argon2Parallelism: 4,
+	BcryptCost:        bcrypt.DefaultCost,
+}
+
+// NewPasswordHasher creates a new PasswordHasher after checking its parameters.
+func NewPasswordHasher(algorithm string, argon2Memory uint32, argon2Iterations uint32, argon2Parallelism uint8, bcryptCost int) (PasswordHasher, error) {
+	hasher := PasswordHasher{
+		Algorithm:         algorithm,
+		Argon2Memory:      argon2Memory,
+		Argon2Iterations:  argon2Iterations,
+		Argon2Parallelism: argon2Parallelism,
+		BcryptCost:        bcryptCost,
+	}
+
+	switch algorithm {
+	case PasswordAlgorithmArgon2id:
+		if argon2Iterations < 1 || argon2Parallelism < 1 || argon2Memory < 8*uint32(argon2Parallelism) {
+			return PasswordHasher{}, fmt.Errorf("invalid argon2id parameters m=%d,t=%d,p=%d", argon2Memory, argon2Iterations, argon2Parallelism)
+		}
+	case PasswordAlgorithmBcrypt:
+		if bcryptCost < bcrypt.MinCost || bcryptCost > bcrypt.MaxCost {
+			return PasswordHasher{}, fmt.Errorf("invalid bcrypt cost %d", bcryptCost)
+		}
+	default:
+		return PasswordHasher{}, fmt.Errorf("unsupported password hash algorithm %s", algorithm)
+	}
+
+	return hasher, nil
+}
+
+// Hash computes the hash of the password with the algorithm and the parameters of the hasher.
+func (hasher PasswordHasher) Hash(password string) (string, error) {
+	switch hasher.Algorithm {
+	case PasswordAlgorithmArgon2id:
+		salt := make([]byte, argon2SaltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return "", fmt.Errorf("failed to hashed password : %w", err)
+		}
+		hash := argon2.IDKey([]byte(password), salt, hasher.Argon2Iterations, hasher.Argon2Memory, hasher.Argon2Parallelism, argon2KeySize)
+		return fmt.Sprintf(
+			"%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+			argon2Prefix,
+			argon2.Version,
+			hasher.Argon2Memory,
+			hasher.Argon2Iterations,
+			hasher.Argon2Parallelism,
+			base64.RawStdEncoding.EncodeToString(salt),
+			base64.RawStdEncoding.EncodeToString(hash),
+		), nil
+	case PasswordAlgorithmBcrypt:
+		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), hasher.BcryptCost)
+		if err != nil {
+			return "", fmt.Errorf("failed to hashed password : %w", err)
+		}
+		return string(hashedPassword), nil
+	default:
+		return "", fmt.Errorf("unsupported password hash algorithm %s", hasher.Algorithm)
+	}
+}
+
+// NeedsRehash tells whether a hash was made with another algorithm or other parameters than the ones of the hasher.
+func (hasher PasswordHasher) NeedsRehash(hashedPassword string) bool {
+	if strings.HasPrefix(hashedPassword, argon2Prefix) {
+		params, _, _, err := decodeArgon2Hash(hashedPassword)
+		if err != nil {
+			return true
+		}
+		return hasher.Algorithm != PasswordAlgorithmArgon2id ||
+			params.version != argon2.Version ||
+			params.memory != hasher.Argon2Memory ||
+			params.iterations != hasher.Argon2Iterations ||
+			params.parallelism != hasher.Argon2Parallelism
+	}
+
+	cost, err := bcrypt.Cost([]byte(hashedPassword))
 	if err != nil {
-		return "", fmt.Errorf("failed to hashed password : %w", err)
+		return true
 	}
-	return string(hashedPassword), nil
+	return hasher.Algorithm != PasswordAlgorithmBcrypt || cost != hasher.BcryptCost
+}
+
+// This function is used to compute the hash of the password with the default hasher.
+func HashPassword(password string) (string, error) {
+	return DefaultPasswordHasher.Hash(password)
 }
 
-// Check if the password is correct.
+// Check if the password is correct, the algorithm is read from the hash.
 func CheckPassword(password string, hashedPassword string) error {
-	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+	if strings.HasPrefix(hashedPassword, argon2Prefix) {
+		params, salt, hash, err := decodeArgon2Hash(hashedPassword)
+		if err != nil {
+			return err
+		}
+		computed := argon2.IDKey([]byte(password), salt, params.iterations, params.memory, params.parallelism, uint32(len(hash)))
+		if subtle.ConstantTimeCompare(hash, computed) != 1 {
+			return ErrMismatchedPassword
+		}
+		return nil
+	}
+
+	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+	if err == bcrypt.ErrMismatchedHashAndPassword {
+		return ErrMismatchedPassword
+	}
+	if err != nil {
+		return fmt.Errorf("%w : %v", ErrUnknownPasswordHash, err)
+	}
+	return nil
+}
+
+// Parameters of an argon2id hash.
+type argon2Params struct {
+	version     int
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+}
+
+// This function is used to read the parameters, the salt and the hash of an argon2id hash.
+func decodeArgon2Hash(hashedPassword string) (argon2Params, []byte, []byte, error) {
+	var params argon2Params
+
+	// The leading $ gives an empty first part.
+	parts := strings.Split(hashedPassword, "$")
+	if len(parts) != 6 {
+		return params, nil, nil, ErrUnknownPasswordHash
+	}
+
+	_, err := fmt.Sscanf(parts[2], "v=%d", &params.version)
+	if err != nil {
+		return params, nil, nil, ErrUnknownPasswordHash
+	}
+	_, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.iterations, &params.parallelism)
+	if err != nil {
+		return params, nil, nil, ErrUnknownPasswordHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, ErrUnknownPasswordHash
+	}
+	hash, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(hash) == 0 {
+		return params, nil, nil, ErrUnknownPasswordHash
+	}
+
+	return params, salt, hash, nil
 }
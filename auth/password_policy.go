@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Minimum length used when none is configured.
+const DefaultPasswordMinLength = 8
+
+// Personal information shorter than this is not looked for in passwords, it would refuse too many of them.
+const minPersonalInfoLength = 3
+
+// ErrPasswordPolicy is wrapped by every error returned when a password does not meet the policy.
+var ErrPasswordPolicy = errors.New("password does not meet the policy")
+
+// PasswordPolicy holds the rules new passwords must follow.
+type PasswordPolicy struct {
+	MinLength int
+	// Number of character classes among lowercase letters, uppercase letters, digits and symbols a password must use.
+	MinCharacterClasses int
+	// Breached passwords are refused when a list is given.
+	Breached BreachedPasswords
+}
+
+// NewPasswordPolicy creates a new PasswordPolicy after checking its parameters, breached can be nil.
+func NewPasswordPolicy(minLength int, minCharacterClasses int, breached BreachedPasswords) (PasswordPolicy, error) {
+	if minLength == 0 {
+		minLength = DefaultPasswordMinLength
+	}
+	if minLength < 1 {
+		return PasswordPolicy{}, fmt.Errorf("invalid password minimum length %d", minLength)
+	}
+	if minCharacterClasses < 0 || minCharacterClasses > 4 {
+		return PasswordPolicy{}, fmt.Errorf("invalid password minimum character classes %d, must be between 0 and 4", minCharacterClasses)
+	}
+
+	return PasswordPolicy{
+		MinLength:           minLength,
+		MinCharacterClasses: minCharacterClasses,
+		Breached:            breached,
+	}, nil
+}
+
+// Check verifies that the password follows the policy and does not contain any of the personal information of
+// the user, such as its username or email. Errors wrapping ErrPasswordPolicy tell why the password is refused,
+// other errors come from the breached password list.
+func (policy PasswordPolicy) Check(password string, personalInfo ...string) error {
+	if utf8.RuneCountInString(password) < policy.MinLength {
+		return fmt.Errorf("%w : it must be at least %d characters long", ErrPasswordPolicy, policy.MinLength)
+	}
+
+	if classes := characterClasses(password); classes < policy.MinCharacterClasses {
+		return fmt.Errorf(
+			"%w : it must use at least %d of lowercase letters, uppercase letters, digits and symbols",
+			ErrPasswordPolicy,
+			policy.MinCharacterClasses,
+		)
+	}
+
+	lowerPassword := strings.ToLower(password)
+	for _, info := range personalInfo {
+		for _, part := range personalInfoParts(info) {
+			if strings.Contains(lowerPassword, part) {
+				return fmt.Errorf("%w : it must not contain your username or email", ErrPasswordPolicy)
+			}
+		}
+	}
+
+	if policy.Breached != nil {
+		breached, err := policy.Breached.Contains(password)
+		if err != nil {
+			return err
+		}
+		if breached {
+			return fmt.Errorf("%w : it appears in a list of breached passwords", ErrPasswordPolicy)
+		}
+	}
+
+	return nil
+}
+
+// This function counts the character classes used by the password.
+func characterClasses(password string) int {
+	var lower, upper, digit, symbol int
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			symbol = 1
+		}
+	}
+	return lower + upper + digit + symbol
+}
+
+// This function returns the lowercased parts of a personal information that must not appear in passwords,
+// for an email both the whole email and its local part are returned.
+func personalInfoParts(info string) []string {
+	info = strings.ToLower(strings.TrimSpace(info))
+	parts := []string{info}
+	if at := strings.LastIndex(info, "@"); at > 0 {
+		parts = append(parts, info[:at])
+	}
+
+	result := parts[:0]
+	for _, part := range parts {
+		if utf8.RuneCountInString(part) >= minPersonalInfoLength {
+			result = append(result, part)
+		}
+	}
+	return result
+}
@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordPolicy(t *testing.T) {
+	policy, err := NewPasswordPolicy(10, 3, newTestBreachedPasswordDirectory(t))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		password string
+		valid    bool
+	}{
+		{name: "OK", password: "Correct-horse-7", valid: true},
+		{name: "OK Unicode", password: "ÉtéÀParis42", valid: true},
+		{name: "Too Short", password: "Short-1", valid: false},
+		{name: "Too Few Classes", password: "correcthorsebattery", valid: false},
+		{name: "Contains Username", password: "Xx-JohnDoe-42", valid: false},
+		{name: "Contains Email Local Part", password: "Jane.Smith#2022", valid: false},
+	}
+
+	for i := range testCases {
+		currentTest := testCases[i]
+
+		t.Run(currentTest.name, func(t *testing.T) {
+			err := policy.Check(currentTest.password, "johndoe", "jane.smith@example.com")
+			if currentTest.valid {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, ErrPasswordPolicy)
+			}
+		})
+	}
+}
+
+func TestPasswordPolicyBreached(t *testing.T) {
+	policy, err := NewPasswordPolicy(0, 0, newTestBreachedPasswordDirectory(t))
+	require.NoError(t, err)
+	require.Equal(t, DefaultPasswordMinLength, policy.MinLength)
+
+	err = policy.Check("password")
+	require.ErrorIs(t, err, ErrPasswordPolicy)
+	require.Contains(t, err.Error(), "breached")
+
+	// Without a list, only the other rules apply.
+	policy.Breached = nil
+	require.NoError(t, policy.Check("password"))
+}
+
+func TestPasswordPolicyShortPersonalInfo(t *testing.T) {
+	policy, err := NewPasswordPolicy(0, 0, nil)
+	require.NoError(t, err)
+
+	// Too short personal information is not looked for.
+	require.NoError(t, policy.Check("bob-builder-42", "bo", "b@example.com"))
+}
+
+func TestNewPasswordPolicyInvalid(t *testing.T) {
+	_, err := NewPasswordPolicy(-1, 0, nil)
+	require.Error(t, err)
+
+	_, err = NewPasswordPolicy(8, 5, nil)
+	require.Error(t, err)
+}
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -13,16 +14,96 @@ func TestPassword(t *testing.T) {
 	hashedPassword1, err := HashPassword(password)
 	require.NoError(t, err)
 	require.NotEmpty(t, hashedPassword1)
+	require.True(t, strings.HasPrefix(hashedPassword1, "$argon2id$v=19$m=65536,t=3,p=4$"))
 
 	err = CheckPassword(password, hashedPassword1)
 	require.NoError(t, err)
 
 	wrongPassword := "wrong"
 	err = CheckPassword(wrongPassword, hashedPassword1)
-	require.EqualError(t, err, bcrypt.ErrMismatchedHashAndPassword.Error())
+	require.EqualError(t, err, ErrMismatchedPassword.Error())
 
 	hashedPassword2, err := HashPassword(password)
 	require.NoError(t, err)
 	require.NotEmpty(t, hashedPassword2)
 	require.NotEqual(t, hashedPassword1, hashedPassword2)
 }
+
+func TestBcryptPassword(t *testing.T) {
+	password := "password"
+	hasher, err := NewPasswordHasher(PasswordAlgorithmBcrypt, 0, 0, 0, bcrypt.MinCost)
+	require.NoError(t, err)
+
+	hashedPassword, err := hasher.Hash(password)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(hashedPassword, "$2a$"))
+
+	err = CheckPassword(password, hashedPassword)
+	require.NoError(t, err)
+
+	err = CheckPassword("wrong", hashedPassword)
+	require.EqualError(t, err, ErrMismatchedPassword.Error())
+}
+
+func TestCheckInvalidPasswordHash(t *testing.T) {
+	for _, hashedPassword := range []string{
+		"",
+		"plain",
+		"$argon2id$v=19$m=65536,t=3,p=4$c2FsdA",
+		"$argon2id$v=19$m=65536$c2FsdA$aGFzaA",
+		"$argon2id$v=19$m=65536,t=3,p=4$c2FsdA$",
+	} {
+		err := CheckPassword("password", hashedPassword)
+		require.ErrorIs(t, err, ErrUnknownPasswordHash, hashedPassword)
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	bcryptHasher, err := NewPasswordHasher(PasswordAlgorithmBcrypt, 0, 0, 0, bcrypt.MinCost)
+	require.NoError(t, err)
+	argon2Hasher, err := NewPasswordHasher(PasswordAlgorithmArgon2id, 1024, 1, 1, 0)
+	require.NoError(t, err)
+
+	bcryptHash, err := bcryptHasher.Hash("password")
+	require.NoError(t, err)
+	argon2Hash, err := argon2Hasher.Hash("password")
+	require.NoError(t, err)
+
+	require.False(t, bcryptHasher.NeedsRehash(bcryptHash))
+	require.False(t, argon2Hasher.NeedsRehash(argon2Hash))
+
+	// Another algorithm.
+	require.True(t, argon2Hasher.NeedsRehash(bcryptHash))
+	require.True(t, bcryptHasher.NeedsRehash(argon2Hash))
+
+	// Other parameters.
+	strongerBcryptHasher := bcryptHasher
+	strongerBcryptHasher.BcryptCost++
+	require.True(t, strongerBcryptHasher.NeedsRehash(bcryptHash))
+	for _, strongerArgon2Hasher := range []PasswordHasher{
+		{Algorithm: PasswordAlgorithmArgon2id, Argon2Memory: 2048, Argon2Iterations: 1, Argon2Parallelism: 1},
+		{Algorithm: PasswordAlgorithmArgon2id, Argon2Memory: 1024, Argon2Iterations: 2, Argon2Parallelism: 1},
+		{Algorithm: PasswordAlgorithmArgon2id, Argon2Memory: 1024, Argon2Iterations: 1, Argon2Parallelism: 2},
+	} {
+		require.True(t, strongerArgon2Hasher.NeedsRehash(argon2Hash))
+	}
+
+	require.True(t, argon2Hasher.NeedsRehash("plain"))
+}
+
+func TestNewPasswordHasher(t *testing.T) {
+	_, err := NewPasswordHasher(PasswordAlgorithmArgon2id, 64*1024, 3, 4, 0)
+	require.NoError(t, err)
+
+	_, err = NewPasswordHasher(PasswordAlgorithmArgon2id, 64*1024, 0, 4, 0)
+	require.Error(t, err)
+
+	_, err = NewPasswordHasher(PasswordAlgorithmArgon2id, 16, 3, 4, 0)
+	require.Error(t, err)
+
+	_, err = NewPasswordHasher(PasswordAlgorithmBcrypt, 0, 0, 0, 50)
+	require.Error(t, err)
+
+	_, err = NewPasswordHasher("md5", 0, 0, 0, 0)
+	require.Error(t, err)
+}
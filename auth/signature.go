@@ -0,0 +1,22 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// This function is used to sign a message made of several parts with HMAC-SHA256, e.g : email verification links.
+func SignMessage(key string, parts ...string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	// Parts are separated by a byte that can not appear in them so that they can not be shifted.
+	mac.Write([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// This function is used to check the signature of a message in constant time.
+func VerifyMessage(key string, signature string, parts ...string) bool {
+	expected := SignMessage(key, parts...)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
@@ -0,0 +1,19 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignMessage(t *testing.T) {
+	key := RandomString(32)
+	signature := SignMessage(key, "john", "john@example.com")
+	require.NotEmpty(t, signature)
+	require.True(t, VerifyMessage(key, signature, "john", "john@example.com"))
+
+	require.False(t, VerifyMessage(key, signature, "john", "jane@example.com"))
+	require.False(t, VerifyMessage(key, signature, "johnjohn@example.com"))
+	require.False(t, VerifyMessage(RandomString(32), signature, "john", "john@example.com"))
+	require.False(t, VerifyMessage(key, "", "john", "john@example.com"))
+}
@@ -0,0 +1,326 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Signing algorithms supported by the JWT maker.
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmES256 = "ES256"
+)
+
+const (
+	minHMACKeySize = 32
+	minRSAKeySize  = 2048
+	es256KeySize   = 32
+)
+
+// jwtHeader is the JOSE header of the token.
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ,omitempty"`
+}
+
+// jwtClaims are the registered claims the payload is mapped to.
+type jwtClaims struct {
+	ID        string      `json:"jti"`
+	TokenType string      `json:"token_type"`
+	Subject   string      `json:"sub"`
+	SessionID string      `json:"sid,omitempty"`
+	Role      string      `json:"role,omitempty"`
+	ClientID  string      `json:"client_id,omitempty"`
+	Scope     string      `json:"scope,omitempty"`
+	Issuer    string      `json:"iss"`
+	Audience  jwtAudience `json:"aud"`
+	IssuedAt  int64       `json:"iat"`
+	NotBefore int64       `json:"nbf"`
+	ExpiresAt int64       `json:"exp"`
+}
+
+// jwtAudience accepts both the single string and the array forms of the aud claim.
+type jwtAudience []string
+
+func (audience *jwtAudience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*audience = jwtAudience{single}
+		return nil
+	}
+
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return err
+	}
+	*audience = multiple
+	return nil
+}
+
+func (audience jwtAudience) contains(value string) bool {
+	for _, item := range audience {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// JSON web token maker. The algorithm is fixed when the maker is created and tokens using any other
+// algorithm, including none, are rejected whatever their header says.
+type JWTMaker struct {
+	algorithm  string
+	claims     tokenClaims
+	hmacKey    []byte
+	privateKey crypto.Signer
+	publicKey  crypto.PublicKey
+}
+
+// Create a new JWTMaker. For HS256 the key is the shared secret, for RS256 and ES256 it is the PEM
+// encoded private key. Every token is issued by issuer for audience and both claims are checked on verification.
+func NewJWTMaker(algorithm string, key string, issuer string, audience string) (Maker, error) {
+	claims, err := newTokenClaims(issuer, audience)
+	if err != nil {
+		return nil, err
+	}
+
+	maker := &JWTMaker{
+		algorithm: algorithm,
+		claims:    claims,
+	}
+
+	switch algorithm {
+	case AlgorithmHS256:
+		if len(key) < minHMACKeySize {
+			return nil, fmt.Errorf("invalid size for the key, must be atleast %d characters", minHMACKeySize)
+		}
+		maker.hmacKey = []byte(key)
+	case AlgorithmRS256:
+		privateKey, err := parsePrivateKey(key)
+		if err != nil {
+			return nil, err
+		}
+		rsaKey, ok := privateKey.(*rsa.PrivateKey)
+		if !ok {
+			return nil, errors.New("RS256 requires an RSA private key")
+		}
+		if rsaKey.N.BitLen() < minRSAKeySize {
+			return nil, fmt.Errorf("invalid size for the RSA key, must be atleast %d bits", minRSAKeySize)
+		}
+		maker.privateKey = rsaKey
+		maker.publicKey = &rsaKey.PublicKey
+	case AlgorithmES256:
+		privateKey, err := parsePrivateKey(key)
+		if err != nil {
+			return nil, err
+		}
+		ecKey, ok := privateKey.(*ecdsa.PrivateKey)
+		if !ok || ecKey.Curve != elliptic.P256() {
+			return nil, errors.New("ES256 requires an ECDSA P-256 private key")
+		}
+		maker.privateKey = ecKey
+		maker.publicKey = &ecKey.PublicKey
+	default:
+		return nil, fmt.Errorf("unsupported jwt algorithm %s", algorithm)
+	}
+
+	return maker, nil
+}
+
+// CreateToken creates a new signed token from the given payload.
+func (maker *JWTMaker) CreateToken(payload *Payload) (string, error) {
+	header, err := json.Marshal(jwtHeader{Algorithm: maker.algorithm, Type: "JWT"})
+	if err != nil {
+		return "", err
+	}
+
+	payload = maker.claims.stamp(payload)
+	claims := jwtClaims{
+		ID:        payload.ID.String(),
+		TokenType: payload.TokenType,
+		Subject:   payload.Username,
+		Role:      payload.Role,
+		ClientID:  payload.ClientID,
+		Scope:     strings.Join(payload.Scopes, " "),
+		Issuer:    payload.Issuer,
+		Audience:  jwtAudience{payload.Audience},
+		IssuedAt:  payload.IssuedAt.Unix(),
+		NotBefore: payload.NotBefore.Unix(),
+		ExpiresAt: payload.ExpiredAt.Unix(),
+	}
+	if payload.SessionID != uuid.Nil {
+		claims.SessionID = payload.SessionID.String()
+	}
+	body, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := encodeSegment(header) + "." + encodeSegment(body)
+	signature, err := maker.sign([]byte(signingInput))
+	if err != nil {
+		return "", err
+	}
+
+	return signingInput + "." + encodeSegment(signature), nil
+}
+
+// VerifyToken checks if the token is valid and of the expected type.
+func (maker *JWTMaker) VerifyToken(token string, tokenType string) (*Payload, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, ErrInvalidToken
+	}
+	if header.Algorithm != maker.algorithm {
+		return nil, ErrInvalidToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	if !maker.verify([]byte(parts[0]+"."+parts[1]), signature) {
+		return nil, ErrInvalidToken
+	}
+
+	var claims jwtClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+	// The token may be meant for several audiences, ours only has to be one of them.
+	if !claims.Audience.contains(maker.claims.audience) {
+		return nil, ErrInvalidToken
+	}
+
+	payload := &Payload{
+		TokenType: claims.TokenType,
+		Username:  claims.Subject,
+		Role:      claims.Role,
+		ClientID:  claims.ClientID,
+		Issuer:    claims.Issuer,
+		Audience:  maker.claims.audience,
+		IssuedAt:  time.Unix(claims.IssuedAt, 0),
+		NotBefore: time.Unix(claims.NotBefore, 0),
+		ExpiredAt: time.Unix(claims.ExpiresAt, 0),
+	}
+	payload.ID, err = uuid.Parse(claims.ID)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	if claims.Scope != "" {
+		payload.Scopes = strings.Fields(claims.Scope)
+	}
+	if claims.SessionID != "" {
+		payload.SessionID, err = uuid.Parse(claims.SessionID)
+		if err != nil {
+			return nil, ErrInvalidToken
+		}
+	}
+
+	err = maker.claims.verify(payload, tokenType)
+	if err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
+
+func (maker *JWTMaker) sign(signingInput []byte) ([]byte, error) {
+	if maker.algorithm == AlgorithmHS256 {
+		mac := hmac.New(sha256.New, maker.hmacKey)
+		mac.Write(signingInput)
+		return mac.Sum(nil), nil
+	}
+
+	digest := sha256.Sum256(signingInput)
+	if maker.algorithm == AlgorithmES256 {
+		// JWS uses the fixed size r || s encoding instead of ASN.1.
+		r, s, err := ecdsa.Sign(rand.Reader, maker.privateKey.(*ecdsa.PrivateKey), digest[:])
+		if err != nil {
+			return nil, err
+		}
+		signature := make([]byte, 2*es256KeySize)
+		r.FillBytes(signature[:es256KeySize])
+		s.FillBytes(signature[es256KeySize:])
+		return signature, nil
+	}
+	return maker.privateKey.Sign(rand.Reader, digest[:], crypto.SHA256)
+}
+
+func (maker *JWTMaker) verify(signingInput []byte, signature []byte) bool {
+	switch maker.algorithm {
+	case AlgorithmHS256:
+		mac := hmac.New(sha256.New, maker.hmacKey)
+		mac.Write(signingInput)
+		return hmac.Equal(signature, mac.Sum(nil))
+	case AlgorithmRS256:
+		digest := sha256.Sum256(signingInput)
+		return rsa.VerifyPKCS1v15(maker.publicKey.(*rsa.PublicKey), crypto.SHA256, digest[:], signature) == nil
+	case AlgorithmES256:
+		if len(signature) != 2*es256KeySize {
+			return false
+		}
+		digest := sha256.Sum256(signingInput)
+		r := new(big.Int).SetBytes(signature[:es256KeySize])
+		s := new(big.Int).SetBytes(signature[es256KeySize:])
+		return ecdsa.Verify(maker.publicKey.(*ecdsa.PublicKey), digest[:], r, s)
+	}
+	return false
+}
+
+// parsePrivateKey reads a PEM encoded PKCS #8, PKCS #1 or SEC 1 private key.
+func parsePrivateKey(key string) (crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(key))
+	if block == nil {
+		return nil, errors.New("invalid private key, must be PEM encoded")
+	}
+
+	if privateKey, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		signer, ok := privateKey.(crypto.Signer)
+		if !ok {
+			return nil, errors.New("unsupported private key type")
+		}
+		return signer, nil
+	}
+	if privateKey, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return privateKey, nil
+	}
+	if privateKey, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return privateKey, nil
+	}
+	return nil, errors.New("invalid private key, must be PKCS #8, PKCS #1 or SEC 1")
+}
+
+func encodeSegment(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeSegment(segment string, value interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, value)
+}
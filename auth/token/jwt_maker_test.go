@@ -0,0 +1,258 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IsuruHaupe/web-api/auth"
+	"github.com/Pallinder/go-randomdata"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testIssuer   = "web-api"
+	testAudience = "web-api-clients"
+)
+
+func randomJWTKey(t *testing.T, algorithm string) string {
+	switch algorithm {
+	case AlgorithmRS256:
+		privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		der := x509.MarshalPKCS1PrivateKey(privateKey)
+		return string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: der}))
+	case AlgorithmES256:
+		privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+		require.NoError(t, err)
+		return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	default:
+		return auth.RandomString(32)
+	}
+}
+
+func newTestJWTMaker(t *testing.T, algorithm string) Maker {
+	maker, err := NewJWTMaker(algorithm, randomJWTKey(t, algorithm), testIssuer, testAudience)
+	require.NoError(t, err)
+	return maker
+}
+
+func TestJWTMaker(t *testing.T) {
+	for _, algorithm := range []string{AlgorithmHS256, AlgorithmRS256, AlgorithmES256} {
+		t.Run(algorithm, func(t *testing.T) {
+			maker := newTestJWTMaker(t, algorithm)
+
+			username := randomdata.FirstName(randomdata.Female)
+			duration := time.Minute
+			issuedAt := time.Now()
+			expiredAt := issuedAt.Add(duration)
+
+			payload, err := NewPayload(username, TokenTypeAccess, duration)
+			require.NoError(t, err)
+			payload.SessionID = uuid.New()
+			payload.Role = RoleViewer
+
+			token, err := maker.CreateToken(payload)
+			require.NoError(t, err)
+			require.NotEmpty(t, token)
+
+			verifiedPayload, err := maker.VerifyToken(token, TokenTypeAccess)
+			require.NoError(t, err)
+			require.NotEmpty(t, verifiedPayload)
+
+			require.Equal(t, payload.ID, verifiedPayload.ID)
+			require.Equal(t, username, verifiedPayload.Username)
+			require.Equal(t, payload.SessionID, verifiedPayload.SessionID)
+			require.Equal(t, payload.Role, verifiedPayload.Role)
+			require.Equal(t, TokenTypeAccess, verifiedPayload.TokenType)
+			require.Equal(t, testIssuer, verifiedPayload.Issuer)
+			require.Equal(t, testAudience, verifiedPayload.Audience)
+			require.WithinDuration(t, issuedAt, verifiedPayload.IssuedAt, time.Second)
+			require.WithinDuration(t, issuedAt, verifiedPayload.NotBefore, time.Second)
+			require.WithinDuration(t, expiredAt, verifiedPayload.ExpiredAt, time.Second)
+		})
+	}
+}
+
+func TestJWTClientToken(t *testing.T) {
+	maker := newTestJWTMaker(t, AlgorithmHS256)
+
+	payload, err := NewPayload(randomdata.FirstName(randomdata.Female), TokenTypeAccess, time.Minute)
+	require.NoError(t, err)
+	payload.ClientID = uuid.New().String()
+	payload.Scopes = []string{"contacts:read", "skills:read"}
+
+	token, err := maker.CreateToken(payload)
+	require.NoError(t, err)
+
+	verifiedPayload, err := maker.VerifyToken(token, TokenTypeAccess)
+	require.NoError(t, err)
+	require.Equal(t, payload.ClientID, verifiedPayload.ClientID)
+	require.Equal(t, payload.Scopes, verifiedPayload.Scopes)
+}
+
+func TestJWTTokenTypeNotBefore(t *testing.T) {
+	maker := newTestJWTMaker(t, AlgorithmHS256)
+
+	sessionPayload, err := NewPayload(randomdata.FirstName(randomdata.Female), TokenTypeSession, time.Minute)
+	require.NoError(t, err)
+	sessionToken, err := maker.CreateToken(sessionPayload)
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(sessionToken, TokenTypeAccess)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+
+	payload, err = maker.VerifyToken(sessionToken, TokenTypeSession)
+	require.NoError(t, err)
+	require.Equal(t, TokenTypeSession, payload.TokenType)
+
+	futurePayload, err := NewPayload(randomdata.FirstName(randomdata.Female), TokenTypeAccess, 2*time.Minute)
+	require.NoError(t, err)
+	futurePayload.NotBefore = futurePayload.NotBefore.Add(time.Minute)
+	futureToken, err := maker.CreateToken(futurePayload)
+	require.NoError(t, err)
+
+	payload, err = maker.VerifyToken(futureToken, TokenTypeAccess)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestExpiredJWTToken(t *testing.T) {
+	maker := newTestJWTMaker(t, AlgorithmHS256)
+
+	payload, err := NewPayload(randomdata.FirstName(randomdata.Female), TokenTypeAccess, -time.Minute)
+	require.NoError(t, err)
+
+	token, err := maker.CreateToken(payload)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+
+	payload, err = maker.VerifyToken(token, TokenTypeAccess)
+	require.EqualError(t, err, ErrExpiredToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestInvalidJWTTokenAlgNone(t *testing.T) {
+	maker := newTestJWTMaker(t, AlgorithmHS256)
+
+	payload, err := NewPayload(randomdata.FirstName(randomdata.Female), TokenTypeAccess, time.Minute)
+	require.NoError(t, err)
+
+	token, err := maker.CreateToken(payload)
+	require.NoError(t, err)
+
+	// Replace the header with alg none and drop the signature.
+	parts := strings.Split(token, ".")
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+	unsignedToken := header + "." + parts[1] + "."
+
+	payload, err = maker.VerifyToken(unsignedToken, TokenTypeAccess)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestInvalidJWTToken(t *testing.T) {
+	maker := newTestJWTMaker(t, AlgorithmRS256)
+
+	payload, err := NewPayload(randomdata.FirstName(randomdata.Female), TokenTypeAccess, time.Minute)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name  string
+		maker Maker
+	}{
+		{
+			name:  "OtherKey",
+			maker: newTestJWTMaker(t, AlgorithmRS256),
+		},
+		{
+			name:  "OtherAlgorithm",
+			maker: newTestJWTMaker(t, AlgorithmHS256),
+		},
+		{
+			name:  "OtherKeyType",
+			maker: newTestJWTMaker(t, AlgorithmES256),
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			token, err := tc.maker.CreateToken(payload)
+			require.NoError(t, err)
+
+			verifiedPayload, err := maker.VerifyToken(token, TokenTypeAccess)
+			require.EqualError(t, err, ErrInvalidToken.Error())
+			require.Nil(t, verifiedPayload)
+		})
+	}
+}
+
+func TestJWTIssuerAudience(t *testing.T) {
+	key := randomJWTKey(t, AlgorithmHS256)
+
+	maker, err := NewJWTMaker(AlgorithmHS256, key, testIssuer, testAudience)
+	require.NoError(t, err)
+
+	payload, err := NewPayload(randomdata.FirstName(randomdata.Female), TokenTypeAccess, time.Minute)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		issuer   string
+		audience string
+	}{
+		{
+			name:     "OtherIssuer",
+			issuer:   "other-issuer",
+			audience: testAudience,
+		},
+		{
+			name:     "OtherAudience",
+			issuer:   testIssuer,
+			audience: "other-audience",
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			otherMaker, err := NewJWTMaker(AlgorithmHS256, key, tc.issuer, tc.audience)
+			require.NoError(t, err)
+
+			token, err := otherMaker.CreateToken(payload)
+			require.NoError(t, err)
+
+			verifiedPayload, err := maker.VerifyToken(token, TokenTypeAccess)
+			require.EqualError(t, err, ErrInvalidToken.Error())
+			require.Nil(t, verifiedPayload)
+		})
+	}
+}
+
+func TestNewJWTMaker(t *testing.T) {
+	_, err := NewJWTMaker("none", "", testIssuer, testAudience)
+	require.Error(t, err)
+
+	_, err = NewJWTMaker(AlgorithmHS256, auth.RandomString(16), testIssuer, testAudience)
+	require.Error(t, err)
+
+	_, err = NewJWTMaker(AlgorithmRS256, randomJWTKey(t, AlgorithmES256), testIssuer, testAudience)
+	require.Error(t, err)
+
+	_, err = NewJWTMaker(AlgorithmHS256, randomJWTKey(t, AlgorithmHS256), "", "")
+	require.Error(t, err)
+}
@@ -0,0 +1,161 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// This defines the errors returned when looking up keys in a keyring.
+var (
+	ErrNoSigningKey = errors.New("keyring has no active signing key")
+	ErrUnknownKey   = errors.New("unknown or expired key")
+)
+
+// KeySize is the size of the keyring secrets, used both as paseto v2.local keys and Ed25519 seeds.
+const KeySize = chacha20poly1305.KeySize
+
+// Key is a secret identified by the key ID written in the token footer.
+type Key struct {
+	ID        string     `json:"id"`
+	Secret    string     `json:"secret"`
+	CreatedAt time.Time  `json:"created_at"`
+	NotBefore time.Time  `json:"not_before"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// Keyring holds every key able to verify tokens, the newest active key is used to create them.
+type Keyring struct {
+	Keys []Key `json:"keys"`
+}
+
+// NewKey generates a new random key which can be used to sign tokens from notBefore.
+func NewKey(notBefore time.Time) (Key, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return Key{}, err
+	}
+	secret := make([]byte, KeySize)
+	if _, err := rand.Read(secret); err != nil {
+		return Key{}, err
+	}
+
+	return Key{
+		ID:        hex.EncodeToString(id),
+		Secret:    hex.EncodeToString(secret),
+		CreatedAt: time.Now(),
+		NotBefore: notBefore,
+	}, nil
+}
+
+// LoadKeyring reads a keyring from a JSON file.
+func LoadKeyring(path string) (*Keyring, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read keyring : %w", err)
+	}
+
+	keyring := &Keyring{}
+	if err := json.Unmarshal(data, keyring); err != nil {
+		return nil, fmt.Errorf("cannot parse keyring : %w", err)
+	}
+	for _, key := range keyring.Keys {
+		if _, err := key.secret(); err != nil {
+			return nil, fmt.Errorf("invalid key %s : %w", key.ID, err)
+		}
+	}
+	return keyring, nil
+}
+
+// Save writes the keyring to a JSON file only readable by its owner.
+// The file is replaced atomically so that running servers never read a partial keyring.
+func (keyring *Keyring) Save(path string) error {
+	data, err := json.MarshalIndent(keyring, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmpPath := path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// Rotate adds a new key which becomes the signing key after activationDelay, this leaves time to every
+// server to load it before it is used. Previous keys stay valid for verification until retention has
+// passed and keys already expired are removed.
+func (keyring *Keyring) Rotate(activationDelay time.Duration, retention time.Duration) (Key, error) {
+	now := time.Now()
+	key, err := NewKey(now.Add(activationDelay))
+	if err != nil {
+		return Key{}, err
+	}
+
+	expiresAt := key.NotBefore.Add(retention)
+	keys := make([]Key, 0, len(keyring.Keys)+1)
+	for _, previous := range keyring.Keys {
+		if previous.expired(now) {
+			continue
+		}
+		if previous.ExpiresAt == nil || previous.ExpiresAt.After(expiresAt) {
+			previous.ExpiresAt = &expiresAt
+		}
+		keys = append(keys, previous)
+	}
+	keyring.Keys = append(keys, key)
+
+	return key, nil
+}
+
+// SigningKey returns the newest key which is active at the given time.
+func (keyring *Keyring) SigningKey(now time.Time) (Key, error) {
+	var signingKey *Key
+	for i := range keyring.Keys {
+		key := &keyring.Keys[i]
+		if key.NotBefore.After(now) || key.expired(now) {
+			continue
+		}
+		if signingKey == nil || key.NotBefore.After(signingKey.NotBefore) {
+			signingKey = key
+		}
+	}
+
+	if signingKey == nil {
+		return Key{}, ErrNoSigningKey
+	}
+	return *signingKey, nil
+}
+
+// VerificationKey returns the key with the given ID if it has not expired yet.
+// Keys which are not active yet are returned as well, other servers may already sign with them.
+func (keyring *Keyring) VerificationKey(id string, now time.Time) (Key, error) {
+	for _, key := range keyring.Keys {
+		if key.ID == id && !key.expired(now) {
+			return key, nil
+		}
+	}
+	return Key{}, ErrUnknownKey
+}
+
+func (key Key) expired(now time.Time) bool {
+	return key.ExpiresAt != nil && now.After(*key.ExpiresAt)
+}
+
+func (key Key) secret() ([]byte, error) {
+	secret, err := hex.DecodeString(key.Secret)
+	if err != nil {
+		return nil, fmt.Errorf("secret must be hex encoded : %w", err)
+	}
+	if len(secret) != KeySize {
+		return nil, fmt.Errorf("secret must be exactly %d bytes", KeySize)
+	}
+	return secret, nil
+}
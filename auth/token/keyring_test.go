@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyringRotate(t *testing.T) {
+	key1, err := NewKey(time.Now())
+	require.NoError(t, err)
+	keyring := &Keyring{Keys: []Key{key1}}
+
+	key2, err := keyring.Rotate(time.Minute, time.Hour)
+	require.NoError(t, err)
+	require.NotEqual(t, key1.ID, key2.ID)
+	require.Len(t, keyring.Keys, 2)
+
+	// The new key is not used for signing before its activation.
+	signingKey, err := keyring.SigningKey(time.Now())
+	require.NoError(t, err)
+	require.Equal(t, key1.ID, signingKey.ID)
+
+	signingKey, err = keyring.SigningKey(time.Now().Add(2 * time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, key2.ID, signingKey.ID)
+
+	// The previous key verifies tokens until the retention has passed.
+	_, err = keyring.VerificationKey(key1.ID, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	_, err = keyring.VerificationKey(key1.ID, time.Now().Add(2*time.Hour))
+	require.EqualError(t, err, ErrUnknownKey.Error())
+
+	// The new key can already verify tokens.
+	_, err = keyring.VerificationKey(key2.ID, time.Now())
+	require.NoError(t, err)
+}
+
+func TestKeyringRotateRemovesExpiredKeys(t *testing.T) {
+	key1, err := NewKey(time.Now())
+	require.NoError(t, err)
+	keyring := &Keyring{Keys: []Key{key1}}
+
+	_, err = keyring.Rotate(0, -time.Minute)
+	require.NoError(t, err)
+	require.Len(t, keyring.Keys, 2)
+
+	key3, err := keyring.Rotate(0, time.Hour)
+	require.NoError(t, err)
+	require.Len(t, keyring.Keys, 2)
+	require.NotEqual(t, key1.ID, keyring.Keys[0].ID)
+	require.Equal(t, key3.ID, keyring.Keys[1].ID)
+}
+
+func TestKeyringSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keyring.json")
+
+	key, err := NewKey(time.Now())
+	require.NoError(t, err)
+	keyring := &Keyring{Keys: []Key{key}}
+	require.NoError(t, keyring.Save(path))
+
+	loadedKeyring, err := LoadKeyring(path)
+	require.NoError(t, err)
+	require.Len(t, loadedKeyring.Keys, 1)
+	require.Equal(t, key.ID, loadedKeyring.Keys[0].ID)
+	require.Equal(t, key.Secret, loadedKeyring.Keys[0].Secret)
+
+	_, err = LoadKeyring(filepath.Join(t.TempDir(), "missing.json"))
+	require.Error(t, err)
+}
+
+func TestKeyringNoSigningKey(t *testing.T) {
+	key, err := NewKey(time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	keyring := &Keyring{Keys: []Key{key}}
+
+	_, err = keyring.SigningKey(time.Now())
+	require.EqualError(t, err, ErrNoSigningKey.Error())
+}
@@ -1,11 +1,24 @@
 package auth
 
-import "time"
+import "crypto"
 
 // This is a general interface for token based authentification/authorization.
 type Maker interface {
-	// CreateToken creates a new token for a specific user and duration.
-	CreateToken(username string, duration time.Duration) (string, *Payload, error)
-	// VerifyToken checks if the token is valid or not.
-	VerifyToken(token string) (*Payload, error)
+	// CreateToken creates a new signed token from the given payload.
+	CreateToken(payload *Payload) (string, error)
+	// VerifyToken checks if the token is valid and of the expected type.
+	VerifyToken(token string, tokenType string) (*Payload, error)
+}
+
+// PublicKeyProvider is implemented by the makers using asymmetric keys, so that the
+// public keys can be shared with the services verifying tokens offline.
+type PublicKeyProvider interface {
+	// PublicKeys returns the keys used to verify tokens, the current signing key comes first.
+	PublicKeys() []PublicKey
+}
+
+// PublicKey is a key used to verify tokens, ID is the key ID found in the token footer if any.
+type PublicKey struct {
+	ID  string
+	Key crypto.PublicKey
 }
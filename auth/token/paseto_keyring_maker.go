@@ -0,0 +1,229 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/o1egl/paseto"
+)
+
+// Paseto purposes supported by the keyring maker.
+const (
+	PurposeLocal  = "local"
+	PurposePublic = "public"
+)
+
+// The keyring file is checked for changes at most once per interval. Tokens using an unknown key force a
+// reload, at most once per unknown key interval so that forged tokens can not make us read the file on each request.
+const (
+	keyringRefreshInterval    = 10 * time.Second
+	keyringUnknownKeyInterval = time.Second
+)
+
+// keyFooter is the paseto footer used to find the key a token was created with.
+type keyFooter struct {
+	KeyID string `json:"kid"`
+}
+
+// Paseto token maker backed by a keyring file : tokens are created with the current key and tokens created
+// with older keys are accepted until those keys expire. The keyring file is reloaded when it changes so that
+// keys can be rotated without restarting the server.
+type PasetoKeyringMaker struct {
+	paseto  *paseto.V2
+	purpose string
+	path    string
+	claims  tokenClaims
+
+	mutex     sync.RWMutex
+	keyring   *Keyring
+	modTime   time.Time
+	checkedAt time.Time
+	forcedAt  time.Time
+}
+
+// Create a new PasetoKeyringMaker using v2.local or v2.public tokens, every token is issued by issuer for audience.
+func NewPasetoKeyringMaker(path string, purpose string, issuer string, audience string) (Maker, error) {
+	if purpose != PurposeLocal && purpose != PurposePublic {
+		return nil, fmt.Errorf("unsupported paseto purpose %s", purpose)
+	}
+	claims, err := newTokenClaims(issuer, audience)
+	if err != nil {
+		return nil, err
+	}
+
+	maker := &PasetoKeyringMaker{
+		paseto:  paseto.NewV2(),
+		purpose: purpose,
+		path:    path,
+		claims:  claims,
+	}
+	if err := maker.refresh(true); err != nil {
+		return nil, err
+	}
+	return maker, nil
+}
+
+// CreateToken creates a new signed token from the given payload.
+func (maker *PasetoKeyringMaker) CreateToken(payload *Payload) (string, error) {
+	if err := maker.refresh(false); err != nil {
+		return "", err
+	}
+
+	maker.mutex.RLock()
+	key, err := maker.keyring.SigningKey(time.Now())
+	maker.mutex.RUnlock()
+	if err != nil {
+		return "", err
+	}
+
+	secret, err := key.secret()
+	if err != nil {
+		return "", err
+	}
+
+	footer := keyFooter{KeyID: key.ID}
+	payload = maker.claims.stamp(payload)
+	if maker.purpose == PurposePublic {
+		return maker.paseto.Sign(ed25519.NewKeyFromSeed(secret), payload, footer)
+	}
+	return maker.paseto.Encrypt(secret, payload, footer)
+}
+
+// VerifyToken checks if the token is valid and of the expected type.
+func (maker *PasetoKeyringMaker) VerifyToken(token string, tokenType string) (*Payload, error) {
+	var footer keyFooter
+	if err := paseto.ParseFooter(token, &footer); err != nil || footer.KeyID == "" {
+		return nil, ErrInvalidToken
+	}
+
+	// A failed reload keeps the previous keyring so that tokens can still be verified.
+	_ = maker.refresh(false)
+	key, err := maker.verificationKey(footer.KeyID)
+	if err == ErrUnknownKey {
+		// The key may have been added by a rotation since the last reload.
+		if maker.allowUnknownKeyRefresh() {
+			_ = maker.refresh(true)
+			key, err = maker.verificationKey(footer.KeyID)
+		}
+	}
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	secret, err := key.secret()
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	payload := &Payload{}
+	if maker.purpose == PurposePublic {
+		publicKey := ed25519.NewKeyFromSeed(secret).Public()
+		err = maker.paseto.Verify(token, publicKey, payload, nil)
+	} else {
+		err = maker.paseto.Decrypt(token, secret, payload, nil)
+	}
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	err = maker.claims.verify(payload, tokenType)
+	if err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
+
+// PublicKeys returns the public keys of every key which has not expired, the signing key comes first.
+// It returns nothing for v2.local keyrings since their keys must stay secret.
+func (maker *PasetoKeyringMaker) PublicKeys() []PublicKey {
+	if maker.purpose != PurposePublic {
+		return nil
+	}
+	_ = maker.refresh(false)
+
+	maker.mutex.RLock()
+	defer maker.mutex.RUnlock()
+
+	now := time.Now()
+	signingKey, _ := maker.keyring.SigningKey(now)
+	publicKeys := []PublicKey{}
+	for _, key := range maker.keyring.Keys {
+		if key.expired(now) {
+			continue
+		}
+		secret, err := key.secret()
+		if err != nil {
+			continue
+		}
+
+		publicKey := PublicKey{ID: key.ID, Key: ed25519.NewKeyFromSeed(secret).Public()}
+		if key.ID == signingKey.ID {
+			publicKeys = append([]PublicKey{publicKey}, publicKeys...)
+		} else {
+			publicKeys = append(publicKeys, publicKey)
+		}
+	}
+	return publicKeys
+}
+
+func (maker *PasetoKeyringMaker) verificationKey(id string) (Key, error) {
+	maker.mutex.RLock()
+	defer maker.mutex.RUnlock()
+	return maker.keyring.VerificationKey(id, time.Now())
+}
+
+// allowUnknownKeyRefresh reports whether a token using an unknown key may force a reload of the keyring.
+func (maker *PasetoKeyringMaker) allowUnknownKeyRefresh() bool {
+	maker.mutex.Lock()
+	defer maker.mutex.Unlock()
+
+	if time.Since(maker.forcedAt) < keyringUnknownKeyInterval {
+		return false
+	}
+	maker.forcedAt = time.Now()
+	return true
+}
+
+// refresh reloads the keyring file if it changed since the last load, or unconditionally when forced. It runs on
+// every token, so the write lock is only taken when the file is due to be checked.
+func (maker *PasetoKeyringMaker) refresh(force bool) error {
+	if !force && !maker.refreshDue() {
+		return nil
+	}
+
+	maker.mutex.Lock()
+	defer maker.mutex.Unlock()
+
+	// Another token may have checked the file while the lock was awaited.
+	if !force && time.Since(maker.checkedAt) < keyringRefreshInterval {
+		return nil
+	}
+	maker.checkedAt = time.Now()
+
+	info, err := os.Stat(maker.path)
+	if err != nil {
+		return fmt.Errorf("cannot read keyring : %w", err)
+	}
+	if !force && info.ModTime().Equal(maker.modTime) {
+		return nil
+	}
+
+	keyring, err := LoadKeyring(maker.path)
+	if err != nil {
+		return err
+	}
+	maker.keyring = keyring
+	maker.modTime = info.ModTime()
+	return nil
+}
+
+// refreshDue reports whether the keyring file was not checked for keyringRefreshInterval.
+func (maker *PasetoKeyringMaker) refreshDue() bool {
+	maker.mutex.RLock()
+	defer maker.mutex.RUnlock()
+	return time.Since(maker.checkedAt) >= keyringRefreshInterval
+}
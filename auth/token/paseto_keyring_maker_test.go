@@ -0,0 +1,170 @@
+package auth
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Pallinder/go-randomdata"
+	"github.com/o1egl/paseto"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestKeyring(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "keyring.json")
+
+	key, err := NewKey(time.Now())
+	require.NoError(t, err)
+	keyring := &Keyring{Keys: []Key{key}}
+	require.NoError(t, keyring.Save(path))
+
+	return path
+}
+
+func rotateTestKeyring(t *testing.T, path string, retention time.Duration) Key {
+	keyring, err := LoadKeyring(path)
+	require.NoError(t, err)
+
+	key, err := keyring.Rotate(0, retention)
+	require.NoError(t, err)
+	require.NoError(t, keyring.Save(path))
+
+	return key
+}
+
+func createTestToken(t *testing.T, maker Maker, duration time.Duration) string {
+	payload, err := NewPayload(randomdata.FirstName(randomdata.Female), TokenTypeAccess, duration)
+	require.NoError(t, err)
+
+	token, err := maker.CreateToken(payload)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+
+	return token
+}
+
+func TestPasetoKeyringMaker(t *testing.T) {
+	for _, purpose := range []string{PurposeLocal, PurposePublic} {
+		t.Run(purpose, func(t *testing.T) {
+			path := newTestKeyring(t)
+			maker, err := NewPasetoKeyringMaker(path, purpose, testIssuer, testAudience)
+			require.NoError(t, err)
+
+			oldToken := createTestToken(t, maker, time.Minute)
+			require.Contains(t, oldToken, "v2."+purpose+".")
+
+			// Tokens created with the previous key stay valid after a rotation.
+			newKey := rotateTestKeyring(t, path, time.Hour)
+			require.NoError(t, maker.(*PasetoKeyringMaker).refresh(true))
+
+			newToken := createTestToken(t, maker, time.Minute)
+			var footer keyFooter
+			require.NoError(t, paseto.ParseFooter(newToken, &footer))
+			require.Equal(t, newKey.ID, footer.KeyID)
+
+			for _, token := range []string{oldToken, newToken} {
+				payload, err := maker.VerifyToken(token, TokenTypeAccess)
+				require.NoError(t, err)
+				require.NotEmpty(t, payload)
+			}
+		})
+	}
+}
+
+func TestPasetoKeyringMakerConcurrentRefresh(t *testing.T) {
+	path := newTestKeyring(t)
+	maker, err := NewPasetoKeyringMaker(path, PurposePublic, testIssuer, testAudience)
+	require.NoError(t, err)
+
+	// The tokens are created and verified while a reload of the rotated keyring is due.
+	newKey := rotateTestKeyring(t, path, time.Hour)
+	maker.(*PasetoKeyringMaker).checkedAt = time.Time{}
+	maker.(*PasetoKeyringMaker).modTime = time.Time{}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < cap(errs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			payload, err := NewPayload(randomdata.FirstName(randomdata.Female), TokenTypeAccess, time.Minute)
+			if err == nil {
+				var token string
+				if token, err = maker.CreateToken(payload); err == nil {
+					_, err = maker.VerifyToken(token, TokenTypeAccess)
+				}
+			}
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	var footer keyFooter
+	require.NoError(t, paseto.ParseFooter(createTestToken(t, maker, time.Minute), &footer))
+	require.Equal(t, newKey.ID, footer.KeyID)
+}
+
+func TestPasetoKeyringMakerExpiredKey(t *testing.T) {
+	path := newTestKeyring(t)
+	maker, err := NewPasetoKeyringMaker(path, PurposeLocal, testIssuer, testAudience)
+	require.NoError(t, err)
+
+	token := createTestToken(t, maker, time.Minute)
+
+	rotateTestKeyring(t, path, -time.Minute)
+	require.NoError(t, maker.(*PasetoKeyringMaker).refresh(true))
+
+	payload, err := maker.VerifyToken(token, TokenTypeAccess)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestPasetoKeyringMakerReloadsUnknownKey(t *testing.T) {
+	path := newTestKeyring(t)
+	maker, err := NewPasetoKeyringMaker(path, PurposeLocal, testIssuer, testAudience)
+	require.NoError(t, err)
+
+	// Another server rotated the keyring and already signs with the new key.
+	rotateTestKeyring(t, path, time.Hour)
+	otherMaker, err := NewPasetoKeyringMaker(path, PurposeLocal, testIssuer, testAudience)
+	require.NoError(t, err)
+	token := createTestToken(t, otherMaker, time.Minute)
+
+	payload, err := maker.VerifyToken(token, TokenTypeAccess)
+	require.NoError(t, err)
+	require.NotEmpty(t, payload)
+}
+
+func TestPasetoKeyringMakerExpiredToken(t *testing.T) {
+	maker, err := NewPasetoKeyringMaker(newTestKeyring(t), PurposeLocal, testIssuer, testAudience)
+	require.NoError(t, err)
+
+	token := createTestToken(t, maker, -time.Minute)
+
+	payload, err := maker.VerifyToken(token, TokenTypeAccess)
+	require.EqualError(t, err, ErrExpiredToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestPasetoKeyringMakerPublicKeys(t *testing.T) {
+	path := newTestKeyring(t)
+	maker, err := NewPasetoKeyringMaker(path, PurposePublic, testIssuer, testAudience)
+	require.NoError(t, err)
+	require.Len(t, maker.(PublicKeyProvider).PublicKeys(), 1)
+
+	newKey := rotateTestKeyring(t, path, time.Hour)
+	require.NoError(t, maker.(*PasetoKeyringMaker).refresh(true))
+
+	publicKeys := maker.(PublicKeyProvider).PublicKeys()
+	require.Len(t, publicKeys, 2)
+	require.Equal(t, newKey.ID, publicKeys[0].ID)
+
+	localMaker, err := NewPasetoKeyringMaker(newTestKeyring(t), PurposeLocal, testIssuer, testAudience)
+	require.NoError(t, err)
+	require.Empty(t, localMaker.(PublicKeyProvider).PublicKeys())
+}
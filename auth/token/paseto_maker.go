@@ -2,7 +2,6 @@ package auth
 
 import (
 	"fmt"
-	"time"
 
 	"github.com/o1egl/paseto"
 	"golang.org/x/crypto/chacha20poly1305"
@@ -12,40 +11,41 @@ import (
 type PasetoMaker struct {
 	paseto       *paseto.V2
 	symmetricKey []byte
+	claims       tokenClaims
 }
 
-// Create a new PasetoMaker.
-func NewPasetoMaker(symmetricKey string) (Maker, error) {
+// Create a new PasetoMaker, every token is issued by issuer for audience.
+func NewPasetoMaker(symmetricKey string, issuer string, audience string) (Maker, error) {
 	if len(symmetricKey) != chacha20poly1305.KeySize {
 		return nil, fmt.Errorf("invalid size for the key, must be atleast %d characters", chacha20poly1305.KeySize)
 	}
+	claims, err := newTokenClaims(issuer, audience)
+	if err != nil {
+		return nil, err
+	}
 
 	maker := &PasetoMaker{
 		paseto:       paseto.NewV2(),
 		symmetricKey: []byte(symmetricKey),
+		claims:       claims,
 	}
 	return maker, nil
 }
 
-// CreateToken creates a new token for a specific user and duration.
-func (maker *PasetoMaker) CreateToken(username string, duration time.Duration) (string, *Payload, error) {
-	payload, err := NewPayload(username, duration)
-	if err != nil {
-		return "", payload, err
-	}
-	token, err := maker.paseto.Encrypt(maker.symmetricKey, payload, nil)
-	return token, payload, err
+// CreateToken creates a new signed token from the given payload.
+func (maker *PasetoMaker) CreateToken(payload *Payload) (string, error) {
+	return maker.paseto.Encrypt(maker.symmetricKey, maker.claims.stamp(payload), nil)
 }
 
-// VerifyToken checks if the token is valid or not.
-func (maker *PasetoMaker) VerifyToken(token string) (*Payload, error) {
+// VerifyToken checks if the token is valid and of the expected type.
+func (maker *PasetoMaker) VerifyToken(token string, tokenType string) (*Payload, error) {
 	payload := &Payload{}
 
 	err := maker.paseto.Decrypt(token, maker.symmetricKey, payload, nil)
 	if err != nil {
 		return nil, ErrInvalidToken
 	}
-	err = payload.Valid()
+	err = maker.claims.verify(payload, tokenType)
 	if err != nil {
 		return nil, err
 	}
@@ -6,11 +6,12 @@ import (
 
 	"github.com/IsuruHaupe/web-api/auth"
 	"github.com/Pallinder/go-randomdata"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
 
 func TestPasetoMaker(t *testing.T) {
-	maker, err := NewPasetoMaker(auth.RandomString(32))
+	maker, err := NewPasetoMaker(auth.RandomString(32), testIssuer, testAudience)
 	require.NoError(t, err)
 
 	username := randomdata.FirstName(randomdata.Female)
@@ -18,31 +19,111 @@ func TestPasetoMaker(t *testing.T) {
 	issuedAt := time.Now()
 	expiredAt := issuedAt.Add(duration)
 
-	token, payload, err := maker.CreateToken(username, duration)
+	payload, err := NewPayload(username, TokenTypeAccess, duration)
+	require.NoError(t, err)
+	payload.SessionID = uuid.New()
+	payload.Role = RoleViewer
+
+	token, err := maker.CreateToken(payload)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
-	require.NotEmpty(t, payload)
 
-	payload, err = maker.VerifyToken(token)
+	verifiedPayload, err := maker.VerifyToken(token, TokenTypeAccess)
 	require.NoError(t, err)
-	require.NotEmpty(t, payload)
+	require.NotEmpty(t, verifiedPayload)
 
-	require.NotZero(t, payload.ID)
-	require.Equal(t, username, payload.Username)
-	require.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second)
-	require.WithinDuration(t, expiredAt, payload.ExpiredAt, time.Second)
+	require.NotZero(t, verifiedPayload.ID)
+	require.Equal(t, username, verifiedPayload.Username)
+	require.Equal(t, payload.SessionID, verifiedPayload.SessionID)
+	require.Equal(t, payload.Role, verifiedPayload.Role)
+	require.Equal(t, TokenTypeAccess, verifiedPayload.TokenType)
+	require.Equal(t, testIssuer, verifiedPayload.Issuer)
+	require.Equal(t, testAudience, verifiedPayload.Audience)
+	require.Empty(t, payload.Issuer)
+	require.WithinDuration(t, issuedAt, verifiedPayload.IssuedAt, time.Second)
+	require.WithinDuration(t, expiredAt, verifiedPayload.ExpiredAt, time.Second)
 }
 
 func TestExpiredPasetoToken(t *testing.T) {
-	maker, err := NewPasetoMaker(auth.RandomString(32))
+	maker, err := NewPasetoMaker(auth.RandomString(32), testIssuer, testAudience)
+	require.NoError(t, err)
+
+	payload, err := NewPayload(randomdata.FirstName(randomdata.Female), TokenTypeAccess, -time.Minute)
 	require.NoError(t, err)
 
-	token, payload, err := maker.CreateToken(randomdata.FirstName(randomdata.Female), -time.Minute)
+	token, err := maker.CreateToken(payload)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
-	require.NotEmpty(t, payload)
 
-	payload, err = maker.VerifyToken(token)
+	payload, err = maker.VerifyToken(token, TokenTypeAccess)
 	require.Error(t, err, ErrExpiredToken.Error())
 	require.Nil(t, payload)
 }
+
+func TestPasetoMakerClaims(t *testing.T) {
+	symmetricKey := auth.RandomString(32)
+	maker, err := NewPasetoMaker(symmetricKey, testIssuer, testAudience)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name      string
+		issuer    string
+		audience  string
+		tokenType string
+		notBefore time.Duration
+	}{
+		{
+			name:      "OtherIssuer",
+			issuer:    "other-issuer",
+			audience:  testAudience,
+			tokenType: TokenTypeAccess,
+		},
+		{
+			name:      "OtherAudience",
+			issuer:    testIssuer,
+			audience:  "other-audience",
+			tokenType: TokenTypeAccess,
+		},
+		{
+			name:      "SessionToken",
+			issuer:    testIssuer,
+			audience:  testAudience,
+			tokenType: TokenTypeSession,
+		},
+		{
+			name:      "NotValidYet",
+			issuer:    testIssuer,
+			audience:  testAudience,
+			tokenType: TokenTypeAccess,
+			notBefore: time.Minute,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			otherMaker, err := NewPasetoMaker(symmetricKey, tc.issuer, tc.audience)
+			require.NoError(t, err)
+
+			payload, err := NewPayload(randomdata.FirstName(randomdata.Female), tc.tokenType, 2*time.Minute)
+			require.NoError(t, err)
+			payload.NotBefore = payload.NotBefore.Add(tc.notBefore)
+
+			token, err := otherMaker.CreateToken(payload)
+			require.NoError(t, err)
+
+			verifiedPayload, err := maker.VerifyToken(token, TokenTypeAccess)
+			require.EqualError(t, err, ErrInvalidToken.Error())
+			require.Nil(t, verifiedPayload)
+		})
+	}
+}
+
+func TestNewPasetoMaker(t *testing.T) {
+	_, err := NewPasetoMaker(auth.RandomString(16), testIssuer, testAudience)
+	require.Error(t, err)
+
+	_, err = NewPasetoMaker(auth.RandomString(32), "", "")
+	require.Error(t, err)
+}
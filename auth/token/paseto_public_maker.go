@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/o1egl/paseto"
+)
+
+// Asymmetric token maker using paseto v2.public : tokens are signed with an Ed25519 private key
+// and can be verified by anyone holding the public key.
+type PasetoPublicMaker struct {
+	paseto     *paseto.V2
+	privateKey ed25519.PrivateKey
+	publicKey  ed25519.PublicKey
+	claims     tokenClaims
+}
+
+// Create a new PasetoPublicMaker from an hex encoded Ed25519 seed, every token is issued by issuer for audience.
+func NewPasetoPublicMaker(privateKeySeed string, issuer string, audience string) (Maker, error) {
+	seed, err := hex.DecodeString(privateKeySeed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key, must be hex encoded : %w", err)
+	}
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("invalid size for the private key, must be exactly %d bytes", ed25519.SeedSize)
+	}
+
+	claims, err := newTokenClaims(issuer, audience)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey := ed25519.NewKeyFromSeed(seed)
+	maker := &PasetoPublicMaker{
+		paseto:     paseto.NewV2(),
+		privateKey: privateKey,
+		publicKey:  privateKey.Public().(ed25519.PublicKey),
+		claims:     claims,
+	}
+	return maker, nil
+}
+
+// CreateToken creates a new signed token from the given payload.
+func (maker *PasetoPublicMaker) CreateToken(payload *Payload) (string, error) {
+	return maker.paseto.Sign(maker.privateKey, maker.claims.stamp(payload), nil)
+}
+
+// VerifyToken checks if the token is valid and of the expected type.
+func (maker *PasetoPublicMaker) VerifyToken(token string, tokenType string) (*Payload, error) {
+	payload := &Payload{}
+
+	err := maker.paseto.Verify(token, maker.publicKey, payload, nil)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	err = maker.claims.verify(payload, tokenType)
+	if err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
+
+// PublicKeys returns the key other services need to verify tokens.
+func (maker *PasetoPublicMaker) PublicKeys() []PublicKey {
+	return []PublicKey{{Key: maker.publicKey}}
+}
@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/IsuruHaupe/web-api/auth"
+	"github.com/Pallinder/go-randomdata"
+	"github.com/stretchr/testify/require"
+)
+
+func randomPrivateKeySeed(t *testing.T) string {
+	seed := make([]byte, ed25519.SeedSize)
+	_, err := rand.Read(seed)
+	require.NoError(t, err)
+	return hex.EncodeToString(seed)
+}
+
+func TestPasetoPublicMaker(t *testing.T) {
+	maker, err := NewPasetoPublicMaker(randomPrivateKeySeed(t), testIssuer, testAudience)
+	require.NoError(t, err)
+
+	username := randomdata.FirstName(randomdata.Female)
+	duration := time.Minute
+	issuedAt := time.Now()
+	expiredAt := issuedAt.Add(duration)
+
+	payload, err := NewPayload(username, TokenTypeAccess, duration)
+	require.NoError(t, err)
+
+	token, err := maker.CreateToken(payload)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+	require.Contains(t, token, "v2.public.")
+
+	verifiedPayload, err := maker.VerifyToken(token, TokenTypeAccess)
+	require.NoError(t, err)
+	require.NotEmpty(t, verifiedPayload)
+
+	require.Equal(t, payload.ID, verifiedPayload.ID)
+	require.Equal(t, username, verifiedPayload.Username)
+	require.WithinDuration(t, issuedAt, verifiedPayload.IssuedAt, time.Second)
+	require.WithinDuration(t, expiredAt, verifiedPayload.ExpiredAt, time.Second)
+
+	publicKeys := maker.(PublicKeyProvider).PublicKeys()
+	require.Len(t, publicKeys, 1)
+	publicKey, ok := publicKeys[0].Key.(ed25519.PublicKey)
+	require.True(t, ok)
+	require.Len(t, publicKey, ed25519.PublicKeySize)
+}
+
+func TestExpiredPasetoPublicToken(t *testing.T) {
+	maker, err := NewPasetoPublicMaker(randomPrivateKeySeed(t), testIssuer, testAudience)
+	require.NoError(t, err)
+
+	payload, err := NewPayload(randomdata.FirstName(randomdata.Female), TokenTypeAccess, -time.Minute)
+	require.NoError(t, err)
+
+	token, err := maker.CreateToken(payload)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+
+	payload, err = maker.VerifyToken(token, TokenTypeAccess)
+	require.EqualError(t, err, ErrExpiredToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestInvalidPasetoPublicToken(t *testing.T) {
+	maker, err := NewPasetoPublicMaker(randomPrivateKeySeed(t), testIssuer, testAudience)
+	require.NoError(t, err)
+
+	otherMaker, err := NewPasetoPublicMaker(randomPrivateKeySeed(t), testIssuer, testAudience)
+	require.NoError(t, err)
+
+	localMaker, err := NewPasetoMaker(auth.RandomString(32), testIssuer, testAudience)
+	require.NoError(t, err)
+
+	payload, err := NewPayload(randomdata.FirstName(randomdata.Female), TokenTypeAccess, time.Minute)
+	require.NoError(t, err)
+
+	// Tokens signed with another key or encrypted with v2.local must be rejected.
+	for _, signer := range []Maker{otherMaker, localMaker} {
+		token, err := signer.CreateToken(payload)
+		require.NoError(t, err)
+
+		verifiedPayload, err := maker.VerifyToken(token, TokenTypeAccess)
+		require.EqualError(t, err, ErrInvalidToken.Error())
+		require.Nil(t, verifiedPayload)
+	}
+}
+
+func TestInvalidPasetoPublicKey(t *testing.T) {
+	_, err := NewPasetoPublicMaker("not-hex", testIssuer, testAudience)
+	require.Error(t, err)
+
+	_, err = NewPasetoPublicMaker(hex.EncodeToString([]byte("too-short")), testIssuer, testAudience)
+	require.Error(t, err)
+}
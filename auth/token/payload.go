@@ -13,34 +13,88 @@ var (
 	ErrInvalidToken = errors.New("invalid token")
 )
 
+// Types of tokens, a token of one type is never accepted where another type is expected.
+const (
+	// Short-lived token sent with each request.
+	TokenTypeAccess = "access"
+	// Long-lived token only used to renew access tokens.
+	TokenTypeSession = "session"
+)
+
 // Payload contains the payload data of the token.
 type Payload struct {
 	ID        uuid.UUID `json:"id"`
+	TokenType string    `json:"token_type"`
 	Username  string    `json:"username"`
+	SessionID uuid.UUID `json:"session_id"`
+	Role      string    `json:"role"`
+	// ClientID and Scopes are only set on the tokens of OAuth2 clients, which act on behalf of their owner.
+	ClientID string   `json:"client_id,omitempty"`
+	Scopes   []string `json:"scopes,omitempty"`
+	// Issuer and Audience are set by the maker which creates the token.
+	Issuer    string    `json:"issuer"`
+	Audience  string    `json:"audience"`
 	IssuedAt  time.Time `json:"issued_at"`
+	NotBefore time.Time `json:"not_before"`
 	ExpiredAt time.Time `json:"expired_at"`
 }
 
-// NewPayload creates a new token for a specific user and duration.
-func NewPayload(username string, duration time.Duration) (*Payload, error) {
+// NewPayload creates a new token of the given type for a specific user and duration.
+func NewPayload(username string, tokenType string, duration time.Duration) (*Payload, error) {
 	tokenID, err := uuid.NewRandom()
 	if err != nil {
 		return nil, err
 	}
 
+	now := time.Now()
 	payload := &Payload{
 		ID:        tokenID,
+		TokenType: tokenType,
 		Username:  username,
-		IssuedAt:  time.Now(),
-		ExpiredAt: time.Now().Add(duration),
+		IssuedAt:  now,
+		NotBefore: now,
+		ExpiredAt: now.Add(duration),
 	}
 	return payload, nil
 }
 
-/// Valid checks is the token payload is valid.
+// Valid checks is the token payload is valid at the current time.
 func (payload *Payload) Valid() error {
-	if time.Now().After(payload.ExpiredAt) {
+	now := time.Now()
+	if now.Before(payload.NotBefore) {
+		return ErrInvalidToken
+	}
+	if now.After(payload.ExpiredAt) {
 		return ErrExpiredToken
 	}
 	return nil
 }
+
+// tokenClaims holds the claims a maker writes in every token and expects back on verification.
+type tokenClaims struct {
+	issuer   string
+	audience string
+}
+
+func newTokenClaims(issuer string, audience string) (tokenClaims, error) {
+	if issuer == "" || audience == "" {
+		return tokenClaims{}, errors.New("issuer and audience are required")
+	}
+	return tokenClaims{issuer: issuer, audience: audience}, nil
+}
+
+// stamp returns a copy of the payload issued by the maker, the payload of the caller is left untouched.
+func (claims tokenClaims) stamp(payload *Payload) *Payload {
+	stamped := *payload
+	stamped.Issuer = claims.issuer
+	stamped.Audience = claims.audience
+	return &stamped
+}
+
+// verify checks that the payload was issued by the maker, for its audience and with the expected type.
+func (claims tokenClaims) verify(payload *Payload, tokenType string) error {
+	if payload.Issuer != claims.issuer || payload.Audience != claims.audience || payload.TokenType != tokenType {
+		return ErrInvalidToken
+	}
+	return payload.Valid()
+}
@@ -0,0 +1,11 @@
+package auth
+
+// Roles a user can be given, they are stored on the user and embedded in the access tokens.
+const (
+	// RoleAdmin can do everything an editor can and manage the other users.
+	RoleAdmin = "admin"
+	// RoleEditor can read and modify contacts and skills, it is the default role.
+	RoleEditor = "editor"
+	// RoleViewer can only read contacts and skills.
+	RoleViewer = "viewer"
+)
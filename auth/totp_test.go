@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTOTPCode(t *testing.T) {
+	// Test vectors from RFC 6238, truncated to 6 digits.
+	secret := totpEncoding.EncodeToString([]byte("12345678901234567890"))
+	testCases := []struct {
+		time int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, testCase := range testCases {
+		code, err := TOTPCode(secret, TOTPStep(time.Unix(testCase.time, 0)))
+		require.NoError(t, err)
+		require.Equal(t, testCase.code, code)
+	}
+
+	_, err := TOTPCode("not base32!", 1)
+	require.Error(t, err)
+}
+
+func TestValidateTOTP(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	require.NoError(t, err)
+	require.Len(t, secret, 32)
+
+	now := time.Now()
+	step := TOTPStep(now)
+
+	for _, offset := range []int64{-1, 0, 1} {
+		code, err := TOTPCode(secret, step+offset)
+		require.NoError(t, err)
+
+		validStep, ok := ValidateTOTP(secret, code, now)
+		require.True(t, ok)
+		require.Equal(t, step+offset, validStep)
+	}
+
+	// Codes outside of the allowed clock drift are refused.
+	code, err := TOTPCode(secret, step+2)
+	require.NoError(t, err)
+	_, ok := ValidateTOTP(secret, code, now)
+	require.False(t, ok)
+
+	_, ok = ValidateTOTP(secret, "12345", now)
+	require.False(t, ok)
+}
+
+func TestTOTPProvisioningURI(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	require.NoError(t, err)
+
+	uri, err := url.Parse(TOTPProvisioningURI("web-api", "john", secret))
+	require.NoError(t, err)
+	require.Equal(t, "otpauth", uri.Scheme)
+	require.Equal(t, "totp", uri.Host)
+	require.Equal(t, "/web-api:john", uri.Path)
+	require.Equal(t, secret, uri.Query().Get("secret"))
+	require.Equal(t, "web-api", uri.Query().Get("issuer"))
+	require.Equal(t, "6", uri.Query().Get("digits"))
+	require.Equal(t, "30", uri.Query().Get("period"))
+}
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+	codes, err := GenerateRecoveryCodes(10)
+	require.NoError(t, err)
+	require.Len(t, codes, 10)
+
+	seen := make(map[string]bool)
+	for _, code := range codes {
+		require.Regexp(t, "^[a-z2-7]{5}-[a-z2-7]{5}$", code)
+		require.False(t, seen[code])
+		seen[code] = true
+		require.Equal(t, code, NormalizeRecoveryCode(" "+code+" "))
+	}
+}
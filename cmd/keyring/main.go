@@ -0,0 +1,122 @@
+// Command keyring manages the keyring used to create and verify tokens when TOKEN_KEYRING_FILE is set.
+//
+//	go run ./cmd/keyring init     creates a keyring with a single active key.
+//	go run ./cmd/keyring rotate   adds a new signing key and schedules the expiration of the previous ones.
+//	go run ./cmd/keyring list     lists the keys without their secrets.
+//
+// Running servers reload the keyring file when it changes, so keys are rotated without downtime : the new key
+// is only used for signing once TOKEN_KEY_ACTIVATION_DELAY has passed, and previous keys keep verifying tokens
+// during TOKEN_KEY_RETENTION, which should be at least SESSION_DURATION.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	auth "github.com/IsuruHaupe/web-api/auth/token"
+	"github.com/IsuruHaupe/web-api/config"
+)
+
+func main() {
+	// Load configuration using viper
+	config, err := config.LoadConfig(".")
+	if err != nil {
+		log.Fatal("Error when loading configuration : ", err)
+	}
+
+	flags := flag.NewFlagSet("keyring", flag.ExitOnError)
+	path := flags.String("file", config.TokenKeyringFile, "path of the keyring file")
+	activationDelay := flags.Duration("activation-delay", config.TokenKeyActivationDelay, "delay before a new key is used to sign tokens")
+	retention := flags.Duration("retention", config.TokenKeyRetention, "time during which previous keys still verify tokens")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage : keyring [flags] init|rotate|list")
+		flags.PrintDefaults()
+	}
+
+	if len(os.Args) < 2 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	command := os.Args[1]
+	if err := flags.Parse(os.Args[2:]); err != nil {
+		log.Fatal(err)
+	}
+	if *path == "" {
+		log.Fatal("no keyring file, set TOKEN_KEYRING_FILE or use -file")
+	}
+
+	switch command {
+	case "init":
+		err = initKeyring(*path)
+	case "rotate":
+		err = rotateKeyring(*path, *activationDelay, *retention)
+	case "list":
+		err = listKeyring(*path)
+	default:
+		flags.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// initKeyring creates a new keyring file whose key can be used right away.
+func initKeyring(path string) error {
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("keyring %s already exists, use rotate to add a key", path)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	key, err := auth.NewKey(time.Now())
+	if err != nil {
+		return err
+	}
+
+	keyring := &auth.Keyring{Keys: []auth.Key{key}}
+	if err := keyring.Save(path); err != nil {
+		return err
+	}
+	fmt.Printf("created keyring %s with key %s\n", path, key.ID)
+	return nil
+}
+
+// rotateKeyring adds a new key to the keyring file.
+func rotateKeyring(path string, activationDelay time.Duration, retention time.Duration) error {
+	keyring, err := auth.LoadKeyring(path)
+	if err != nil {
+		return err
+	}
+
+	key, err := keyring.Rotate(activationDelay, retention)
+	if err != nil {
+		return err
+	}
+	if err := keyring.Save(path); err != nil {
+		return err
+	}
+	fmt.Printf("added key %s, used for signing from %s\n", key.ID, key.NotBefore.Format(time.RFC3339))
+	return nil
+}
+
+// listKeyring prints the keys of the keyring file without their secrets.
+func listKeyring(path string) error {
+	keyring, err := auth.LoadKeyring(path)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keyring.Keys {
+		expiresAt := "never"
+		if key.ExpiresAt != nil {
+			expiresAt = key.ExpiresAt.Format(time.RFC3339)
+		}
+		fmt.Printf("%s\tnot before %s\texpires %s\n", key.ID, key.NotBefore.Format(time.RFC3339), expiresAt)
+	}
+	return nil
+}
@@ -8,12 +8,65 @@ import (
 
 // We store all configuration of the application here.
 type Config struct {
-	DBDriver            string        `mapstructure:"DB_DRIVER"`
-	DBSource            string        `mapstructure:"DB_SOURCE"`
-	ServerAddress       string        `mapstructure:"SERVER_ADDRESS"`
-	TokenSymmetricKey   string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
-	AccessTokenDuration time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
-	SessionDuration     time.Duration `mapstructure:"SESSION_DURATION"`
+	DBDriver                        string        `mapstructure:"DB_DRIVER"`
+	DBSource                        string        `mapstructure:"DB_SOURCE"`
+	ServerAddress                   string        `mapstructure:"SERVER_ADDRESS"`
+	ShutdownTimeout                 time.Duration `mapstructure:"SHUTDOWN_TIMEOUT"`
+	TokenMaker                      string        `mapstructure:"TOKEN_MAKER"`
+	TokenSymmetricKey               string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
+	TokenPrivateKey                 string        `mapstructure:"TOKEN_PRIVATE_KEY"`
+	TokenPrivateKeyFile             string        `mapstructure:"TOKEN_PRIVATE_KEY_FILE"`
+	TokenJWTAlgorithm               string        `mapstructure:"TOKEN_JWT_ALGORITHM"`
+	TokenIssuer                     string        `mapstructure:"TOKEN_ISSUER"`
+	TokenAudience                   string        `mapstructure:"TOKEN_AUDIENCE"`
+	TokenKeyringFile                string        `mapstructure:"TOKEN_KEYRING_FILE"`
+	TokenKeyActivationDelay         time.Duration `mapstructure:"TOKEN_KEY_ACTIVATION_DELAY"`
+	TokenKeyRetention               time.Duration `mapstructure:"TOKEN_KEY_RETENTION"`
+	AccessTokenDuration             time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
+	SessionDuration                 time.Duration `mapstructure:"SESSION_DURATION"`
+	SessionBlockDeviceChange        bool          `mapstructure:"SESSION_BLOCK_DEVICE_CHANGE"`
+	CSVImportBackgroundRows         int           `mapstructure:"CSV_IMPORT_BACKGROUND_ROWS"`
+	PasswordResetDuration           time.Duration `mapstructure:"PASSWORD_RESET_DURATION"`
+	PasswordResetResendInterval     time.Duration `mapstructure:"PASSWORD_RESET_RESEND_INTERVAL"`
+	PasswordHashAlgorithm           string        `mapstructure:"PASSWORD_HASH_ALGORITHM"`
+	Argon2Memory                    uint32        `mapstructure:"ARGON2_MEMORY"`
+	Argon2Iterations                uint32        `mapstructure:"ARGON2_ITERATIONS"`
+	Argon2Parallelism               uint8         `mapstructure:"ARGON2_PARALLELISM"`
+	BcryptCost                      int           `mapstructure:"BCRYPT_COST"`
+	PasswordMinLength               int           `mapstructure:"PASSWORD_MIN_LENGTH"`
+	PasswordMinCharacterClasses     int           `mapstructure:"PASSWORD_MIN_CHARACTER_CLASSES"`
+	BreachedPasswordsDir            string        `mapstructure:"BREACHED_PASSWORDS_DIR"`
+	EmailVerificationKey            string        `mapstructure:"EMAIL_VERIFICATION_KEY"`
+	EmailVerificationURL            string        `mapstructure:"EMAIL_VERIFICATION_URL"`
+	EmailVerificationDuration       time.Duration `mapstructure:"EMAIL_VERIFICATION_DURATION"`
+	EmailVerificationResendInterval time.Duration `mapstructure:"EMAIL_VERIFICATION_RESEND_INTERVAL"`
+	TwoFactorChallengeDuration      time.Duration `mapstructure:"TWO_FACTOR_CHALLENGE_DURATION"`
+	TOTPIssuer                      string        `mapstructure:"TOTP_ISSUER"`
+	LoginAttemptStore               string        `mapstructure:"LOGIN_ATTEMPT_STORE"`
+	LoginFreeAttempts               int           `mapstructure:"LOGIN_FREE_ATTEMPTS"`
+	LoginLockoutThreshold           int           `mapstructure:"LOGIN_LOCKOUT_THRESHOLD"`
+	LoginIPFreeAttempts             int           `mapstructure:"LOGIN_IP_FREE_ATTEMPTS"`
+	LoginIPLockoutThreshold         int           `mapstructure:"LOGIN_IP_LOCKOUT_THRESHOLD"`
+	LoginBackoffBase                time.Duration `mapstructure:"LOGIN_BACKOFF_BASE"`
+	LoginLockoutDuration            time.Duration `mapstructure:"LOGIN_LOCKOUT_DURATION"`
+	LoginAttemptWindow              time.Duration `mapstructure:"LOGIN_ATTEMPT_WINDOW"`
+	UnverifiedLogin                 string        `mapstructure:"UNVERIFIED_LOGIN"`
+	OIDCIssuer                      string        `mapstructure:"OIDC_ISSUER"`
+	OIDCClientID                    string        `mapstructure:"OIDC_CLIENT_ID"`
+	OIDCClientSecret                string        `mapstructure:"OIDC_CLIENT_SECRET"`
+	OIDCAuthURL                     string        `mapstructure:"OIDC_AUTH_URL"`
+	OIDCTokenURL                    string        `mapstructure:"OIDC_TOKEN_URL"`
+	OIDCJWKSURL                     string        `mapstructure:"OIDC_JWKS_URL"`
+	OIDCRedirectURL                 string        `mapstructure:"OIDC_REDIRECT_URL"`
+	OIDCScopes                      string        `mapstructure:"OIDC_SCOPES"`
+	OIDCLoginDuration               time.Duration `mapstructure:"OIDC_LOGIN_DURATION"`
+	Mailer                          string        `mapstructure:"MAILER"`
+	MailSender                      string        `mapstructure:"MAIL_SENDER"`
+	MailLogFile                     string        `mapstructure:"MAIL_LOG_FILE"`
+	SMTPHost                        string        `mapstructure:"SMTP_HOST"`
+	SMTPPort                        int           `mapstructure:"SMTP_PORT"`
+	SMTPUsername                    string        `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword                    string        `mapstructure:"SMTP_PASSWORD"`
 }
 
 // Read config file or environment var and parse them.
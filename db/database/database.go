@@ -1,9 +1,106 @@
 package database
 
-import db "github.com/IsuruHaupe/web-api/db/sqlc"
+import (
+	"context"
+
+	db "github.com/IsuruHaupe/web-api/db/sqlc"
+	"github.com/google/uuid"
+)
 
 // We use embedding to ask for struct implementing this interface to implement every function in db.Querier.
 // This is useful for switching between database implementation.
 type Database interface {
 	db.Querier
+	// RotateSessionTx replaces a session by a new one of the same family in a single transaction.
+	RotateSessionTx(ctx context.Context, arg RotateSessionTxParams) (RotateSessionTxResult, error)
+	// ChangePasswordTx updates the password of an user and blocks all of its sessions in a single transaction.
+	ChangePasswordTx(ctx context.Context, arg db.UpdateUserPasswordParams) (db.User, error)
+	// ResetPasswordTx consumes a password reset token and changes the password of its user in a single transaction.
+	ResetPasswordTx(ctx context.Context, arg ResetPasswordTxParams) (db.User, error)
+	// EnableTOTPTx turns on two-factor authentication for an user and replaces its recovery codes in a single transaction.
+	EnableTOTPTx(ctx context.Context, arg EnableTOTPTxParams) (db.User, error)
+	// DisableTOTPTx turns off two-factor authentication for an user and deletes its recovery codes in a single transaction.
+	DisableTOTPTx(ctx context.Context, username string) (db.User, error)
+	// AuditTx runs an admin action and writes its audit record in a single transaction, the action gets the
+	// queries of the transaction. Nothing is recorded if the action fails.
+	AuditTx(ctx context.Context, arg db.CreateAuditLogParams, action func(q db.Querier) error) (db.AuditLog, error)
+	// ProvisionOIDCUserTx creates an user signing in with an identity provider and links it to its identity in a single transaction.
+	ProvisionOIDCUserTx(ctx context.Context, arg ProvisionOIDCUserTxParams) (db.User, error)
+	// MergeContactsTx updates the surviving contact, moves the skills of the merged contact to it and deletes the merged
+	// contact in a single transaction.
+	MergeContactsTx(ctx context.Context, arg MergeContactsTxParams) (MergeContactsTxResult, error)
+	// ImportContactTx creates a contact, the skills it has which don't exist yet and binds them to the contact in a
+	// single transaction.
+	ImportContactTx(ctx context.Context, arg ImportContactTxParams) (ImportContactTxResult, error)
+	// ExportContacts reads the contacts of an owner matching the filters with their skills aggregated, in a single
+	// query. Each row is handed to fn as soon as it is read, the export stops at the first error fn returns.
+	ExportContacts(ctx context.Context, arg ExportContactsParams, fn func(db.ListContactsWithSkillsRow) error) error
+}
+
+// RotateSessionTxParams contains the input parameters of the session rotation transaction.
+type RotateSessionTxParams struct {
+	ParentID uuid.UUID
+	Session  db.CreateSessionParams
+}
+
+// RotateSessionTxResult is the result of the session rotation transaction.
+type RotateSessionTxResult struct {
+	ParentSession db.Session
+	Session       db.Session
+}
+
+// ResetPasswordTxParams contains the input parameters of the password reset transaction.
+type ResetPasswordTxParams struct {
+	HashedToken    string
+	HashedPassword string
+}
+
+// EnableTOTPTxParams contains the input parameters of the two-factor enabling transaction.
+type EnableTOTPTxParams struct {
+	Username            string
+	TOTPStep            int64
+	HashedRecoveryCodes []string
+}
+
+// ProvisionOIDCUserTxParams contains the input parameters of the OpenID Connect provisioning transaction.
+type ProvisionOIDCUserTxParams struct {
+	User          db.CreateUserParams
+	EmailVerified bool
+	Issuer        string
+	Subject       string
+}
+
+// MergeContactsTxParams contains the input parameters of the contact merging transaction.
+type MergeContactsTxParams struct {
+	Survivor db.UpdateContactParams
+	MergedID int64
+	Owner    string
+}
+
+// MergeContactsTxResult is the result of the contact merging transaction.
+type MergeContactsTxResult struct {
+	Contact     db.Contact
+	MovedSkills int64
+}
+
+// ImportContactTxParams contains the input parameters of the contact import transaction.
+type ImportContactTxParams struct {
+	Contact db.CreateContactParams
+	Skills  []db.GetOrCreateSkillParams
+}
+
+// ImportContactTxResult is the result of the contact import transaction.
+type ImportContactTxResult struct {
+	Contact db.Contact
+	Skills  []db.Skill
+}
+
+// ExportContactsParams contains the filters of the contact export, the empty ones are not applied. Search matches the
+// contacts whose full name or email holds it, SkillName and SkillLevel the contacts having such a skill. All of them
+// are compared case insensitively.
+type ExportContactsParams struct {
+	Owner      string
+	Search     string
+	SkillName  string
+	SkillLevel string
 }
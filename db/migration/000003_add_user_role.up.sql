@@ -0,0 +1,2 @@
+ALTER TABLE "users" ADD COLUMN "role" varchar NOT NULL DEFAULT 'editor';
+ALTER TABLE "users" ADD CONSTRAINT "users_role_check" CHECK ("role" IN ('admin', 'editor', 'viewer'));
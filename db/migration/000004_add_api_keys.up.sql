@@ -0,0 +1,14 @@
+CREATE TABLE "api_keys" (
+  "id" uuid PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "name" varchar NOT NULL,
+  "prefix" varchar NOT NULL,
+  "hashed_key" varchar UNIQUE NOT NULL,
+  "scopes" varchar[] NOT NULL,
+  "last_used_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "api_keys" ADD FOREIGN KEY ("username") REFERENCES "users" ("username") ON DELETE CASCADE;
+
+CREATE INDEX ON "api_keys" ("username");
@@ -0,0 +1,5 @@
+ALTER TABLE "users" ADD COLUMN "email_verified" boolean NOT NULL DEFAULT false;
+ALTER TABLE "users" ADD COLUMN "email_verification_sent_at" timestamptz;
+
+-- Accounts created before email verification was introduced are considered verified.
+UPDATE "users" SET "email_verified" = true;
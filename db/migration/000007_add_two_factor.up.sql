@@ -0,0 +1,24 @@
+ALTER TABLE "users" ADD COLUMN "totp_secret" varchar;
+ALTER TABLE "users" ADD COLUMN "totp_enabled" boolean NOT NULL DEFAULT false;
+ALTER TABLE "users" ADD COLUMN "totp_last_step" bigint NOT NULL DEFAULT 0;
+
+CREATE TABLE "recovery_codes" (
+  "hashed_code" varchar PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "used_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "recovery_codes" ADD FOREIGN KEY ("username") REFERENCES "users" ("username") ON DELETE CASCADE;
+
+CREATE INDEX ON "recovery_codes" ("username");
+
+CREATE TABLE "two_factor_challenges" (
+  "hashed_token" varchar PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "attempts" int NOT NULL DEFAULT 0,
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "two_factor_challenges" ADD FOREIGN KEY ("username") REFERENCES "users" ("username") ON DELETE CASCADE;
@@ -0,0 +1,5 @@
+CREATE TABLE "login_attempts" (
+  "key" varchar PRIMARY KEY,
+  "failures" int NOT NULL,
+  "last_failure_at" timestamptz NOT NULL
+);
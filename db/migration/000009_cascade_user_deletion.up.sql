@@ -0,0 +1,2 @@
+ALTER TABLE "sessions" DROP CONSTRAINT "sessions_username_fkey";
+ALTER TABLE "sessions" ADD FOREIGN KEY ("username") REFERENCES "users" ("username") ON DELETE CASCADE;
@@ -0,0 +1,12 @@
+ALTER TABLE "users" ADD COLUMN "disabled_at" timestamptz;
+
+CREATE TABLE "audit_logs" (
+  "id" bigserial PRIMARY KEY,
+  "actor" varchar NOT NULL,
+  "action" varchar NOT NULL,
+  "target" varchar NOT NULL,
+  "details" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "audit_logs" ("target");
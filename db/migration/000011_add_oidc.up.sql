@@ -0,0 +1,19 @@
+CREATE TABLE "oidc_logins" (
+  "hashed_state" varchar PRIMARY KEY,
+  "code_verifier" varchar NOT NULL,
+  "nonce" varchar NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "user_identities" (
+  "issuer" varchar NOT NULL,
+  "subject" varchar NOT NULL,
+  "username" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("issuer", "subject")
+);
+
+CREATE INDEX ON "user_identities" ("username");
+
+ALTER TABLE "user_identities" ADD FOREIGN KEY ("username") REFERENCES "users" ("username") ON DELETE CASCADE;
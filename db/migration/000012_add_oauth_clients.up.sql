@@ -0,0 +1,13 @@
+CREATE TABLE "oauth_clients" (
+  "id" uuid PRIMARY KEY,
+  "name" varchar NOT NULL,
+  "owner" varchar NOT NULL,
+  "hashed_secret" varchar NOT NULL,
+  "scopes" varchar[] NOT NULL,
+  "last_used_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "oauth_clients" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username") ON DELETE CASCADE;
+
+CREATE INDEX ON "oauth_clients" ("owner");
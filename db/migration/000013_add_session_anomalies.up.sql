@@ -0,0 +1,2 @@
+ALTER TABLE "sessions" ADD COLUMN "ip_changed" boolean NOT NULL DEFAULT false;
+ALTER TABLE "sessions" ADD COLUMN "user_agent_changed" boolean NOT NULL DEFAULT false;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS "contacts_search_text_idx";
+DROP INDEX IF EXISTS "contacts_search_vector_idx";
+DROP INDEX IF EXISTS "contacts_owner_idx";
+DROP FUNCTION IF EXISTS contact_search_text;
+DROP FUNCTION IF EXISTS contact_search_vector;
+-- pg_trgm is left installed, it may have been installed before this migration and be used by other objects.
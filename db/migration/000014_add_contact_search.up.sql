@@ -0,0 +1,27 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- The document searched for each contact, weighted so that matches on the name rank first.
+-- The simple configuration is used since names and emails must not be stemmed.
+CREATE FUNCTION contact_search_vector(
+  firstname varchar, lastname varchar, fullname varchar, email varchar, phone_number varchar, home_address varchar
+) RETURNS tsvector LANGUAGE sql IMMUTABLE PARALLEL SAFE AS $$
+  SELECT setweight(to_tsvector('simple'::regconfig, firstname || ' ' || lastname || ' ' || fullname), 'A') ||
+    setweight(to_tsvector('simple'::regconfig, email), 'B') ||
+    setweight(to_tsvector('simple'::regconfig, phone_number), 'C') ||
+    setweight(to_tsvector('simple'::regconfig, home_address), 'D')
+$$;
+
+-- The text compared with the search for fuzzy matches.
+CREATE FUNCTION contact_search_text(
+  firstname varchar, lastname varchar, fullname varchar, email varchar, phone_number varchar, home_address varchar
+) RETURNS text LANGUAGE sql IMMUTABLE PARALLEL SAFE AS $$
+  SELECT lower(firstname || ' ' || lastname || ' ' || fullname || ' ' || email || ' ' || phone_number || ' ' || home_address)
+$$;
+
+CREATE INDEX ON "contacts" ("owner");
+
+CREATE INDEX "contacts_search_vector_idx" ON "contacts"
+  USING GIN (contact_search_vector("firstname", "lastname", "fullname", "email", "phone_number", "home_address"));
+
+CREATE INDEX "contacts_search_text_idx" ON "contacts"
+  USING GIN (contact_search_text("firstname", "lastname", "fullname", "email", "phone_number", "home_address") gin_trgm_ops);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "contacts_fullname_trgm_idx";
+DROP INDEX IF EXISTS "contacts_normalized_phone_idx";
+DROP INDEX IF EXISTS "contacts_normalized_email_idx";
+DROP FUNCTION IF EXISTS normalize_phone;
+DROP FUNCTION IF EXISTS normalize_email;
@@ -0,0 +1,15 @@
+-- Emails are compared case insensitively and without surrounding spaces.
+CREATE FUNCTION normalize_email(email varchar) RETURNS text LANGUAGE sql IMMUTABLE PARALLEL SAFE AS $$
+  SELECT lower(trim(email))
+$$;
+
+-- Phone numbers are compared on their digits only, so that "+33 6 12 34 56 78" and "+33612345678" are equal.
+CREATE FUNCTION normalize_phone(phone_number varchar) RETURNS text LANGUAGE sql IMMUTABLE PARALLEL SAFE AS $$
+  SELECT regexp_replace(phone_number, '[^0-9]', '', 'g')
+$$;
+
+CREATE INDEX "contacts_normalized_email_idx" ON "contacts" ("owner", normalize_email("email"));
+
+CREATE INDEX "contacts_normalized_phone_idx" ON "contacts" ("owner", normalize_phone("phone_number"));
+
+CREATE INDEX "contacts_fullname_trgm_idx" ON "contacts" USING GIN (lower("fullname") gin_trgm_ops);
@@ -0,0 +1,26 @@
+CREATE TABLE "import_jobs" (
+  "id" uuid PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "dry_run" boolean NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'running',
+  "total_rows" int NOT NULL,
+  "processed_rows" int NOT NULL DEFAULT 0,
+  "imported_rows" int NOT NULL DEFAULT 0,
+  "failed_rows" int NOT NULL DEFAULT 0,
+  "error" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "finished_at" timestamptz
+);
+
+ALTER TABLE "import_jobs" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username") ON DELETE CASCADE;
+
+CREATE INDEX ON "import_jobs" ("owner");
+
+CREATE TABLE "import_job_errors" (
+  "job_id" uuid NOT NULL,
+  "line" int NOT NULL,
+  "message" varchar NOT NULL,
+  PRIMARY KEY ("job_id", "line")
+);
+
+ALTER TABLE "import_job_errors" ADD FOREIGN KEY ("job_id") REFERENCES "import_jobs" ("id") ON DELETE CASCADE;
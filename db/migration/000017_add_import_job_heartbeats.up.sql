@@ -0,0 +1,2 @@
+-- Running import jobs are kept alive by the instance importing them, the ones left without heartbeat are abandoned.
+ALTER TABLE "import_jobs" ADD COLUMN "heartbeat_at" timestamptz NOT NULL DEFAULT (now());
@@ -8,6 +8,7 @@ import (
 	context "context"
 	reflect "reflect"
 
+	database "github.com/IsuruHaupe/web-api/db/database"
 	db "github.com/IsuruHaupe/web-api/db/sqlc"
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
@@ -36,6 +37,124 @@ func (m *MockDatabase) EXPECT() *MockDatabaseMockRecorder {
 	return m.recorder
 }
 
+// AuditTx mocks base method.
+func (m *MockDatabase) AuditTx(arg0 context.Context, arg1 db.CreateAuditLogParams, arg2 func(db.Querier) error) (db.AuditLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuditTx", arg0, arg1, arg2)
+	ret0, _ := ret[0].(db.AuditLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuditTx indicates an expected call of AuditTx.
+func (mr *MockDatabaseMockRecorder) AuditTx(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuditTx", reflect.TypeOf((*MockDatabase)(nil).AuditTx), arg0, arg1, arg2)
+}
+
+// BlockSession mocks base method.
+func (m *MockDatabase) BlockSession(arg0 context.Context, arg1 uuid.UUID) (db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockSession", arg0, arg1)
+	ret0, _ := ret[0].(db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlockSession indicates an expected call of BlockSession.
+func (mr *MockDatabaseMockRecorder) BlockSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockSession", reflect.TypeOf((*MockDatabase)(nil).BlockSession), arg0, arg1)
+}
+
+// BlockSessionFamily mocks base method.
+func (m *MockDatabase) BlockSessionFamily(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockSessionFamily", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BlockSessionFamily indicates an expected call of BlockSessionFamily.
+func (mr *MockDatabaseMockRecorder) BlockSessionFamily(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockSessionFamily", reflect.TypeOf((*MockDatabase)(nil).BlockSessionFamily), arg0, arg1)
+}
+
+// BlockUserSessions mocks base method.
+func (m *MockDatabase) BlockUserSessions(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockUserSessions", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BlockUserSessions indicates an expected call of BlockUserSessions.
+func (mr *MockDatabaseMockRecorder) BlockUserSessions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockUserSessions", reflect.TypeOf((*MockDatabase)(nil).BlockUserSessions), arg0, arg1)
+}
+
+// ChangePasswordTx mocks base method.
+func (m *MockDatabase) ChangePasswordTx(arg0 context.Context, arg1 db.UpdateUserPasswordParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePasswordTx", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangePasswordTx indicates an expected call of ChangePasswordTx.
+func (mr *MockDatabaseMockRecorder) ChangePasswordTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePasswordTx", reflect.TypeOf((*MockDatabase)(nil).ChangePasswordTx), arg0, arg1)
+}
+
+// ConsumeOIDCLogin mocks base method.
+func (m *MockDatabase) ConsumeOIDCLogin(arg0 context.Context, arg1 string) (db.OidcLogin, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeOIDCLogin", arg0, arg1)
+	ret0, _ := ret[0].(db.OidcLogin)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeOIDCLogin indicates an expected call of ConsumeOIDCLogin.
+func (mr *MockDatabaseMockRecorder) ConsumeOIDCLogin(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeOIDCLogin", reflect.TypeOf((*MockDatabase)(nil).ConsumeOIDCLogin), arg0, arg1)
+}
+
+// CreateAPIKey mocks base method.
+func (m *MockDatabase) CreateAPIKey(arg0 context.Context, arg1 db.CreateAPIKeyParams) (db.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", arg0, arg1)
+	ret0, _ := ret[0].(db.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockDatabaseMockRecorder) CreateAPIKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockDatabase)(nil).CreateAPIKey), arg0, arg1)
+}
+
+// CreateAuditLog mocks base method.
+func (m *MockDatabase) CreateAuditLog(arg0 context.Context, arg1 db.CreateAuditLogParams) (db.AuditLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuditLog", arg0, arg1)
+	ret0, _ := ret[0].(db.AuditLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAuditLog indicates an expected call of CreateAuditLog.
+func (mr *MockDatabaseMockRecorder) CreateAuditLog(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuditLog", reflect.TypeOf((*MockDatabase)(nil).CreateAuditLog), arg0, arg1)
+}
+
 // CreateContact mocks base method.
 func (m *MockDatabase) CreateContact(arg0 context.Context, arg1 db.CreateContactParams) (db.Contact, error) {
 	m.ctrl.T.Helper()
@@ -48,436 +167,1461 @@ func (m *MockDatabase) CreateContact(arg0 context.Context, arg1 db.CreateContact
 // CreateContact indicates an expected call of CreateContact.
 func (mr *MockDatabaseMockRecorder) CreateContact(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateContact", reflect.TypeOf((*MockDatabase)(nil).CreateContact), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateContact", reflect.TypeOf((*MockDatabase)(nil).CreateContact), arg0, arg1)
+}
+
+// CreateContactHasSkill mocks base method.
+func (m *MockDatabase) CreateContactHasSkill(arg0 context.Context, arg1 db.CreateContactHasSkillParams) (db.ContactHasSkill, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateContactHasSkill", arg0, arg1)
+	ret0, _ := ret[0].(db.ContactHasSkill)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateContactHasSkill indicates an expected call of CreateContactHasSkill.
+func (mr *MockDatabaseMockRecorder) CreateContactHasSkill(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateContactHasSkill", reflect.TypeOf((*MockDatabase)(nil).CreateContactHasSkill), arg0, arg1)
+}
+
+// CreateImportJob mocks base method.
+func (m *MockDatabase) CreateImportJob(arg0 context.Context, arg1 db.CreateImportJobParams) (db.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateImportJob", arg0, arg1)
+	ret0, _ := ret[0].(db.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateImportJob indicates an expected call of CreateImportJob.
+func (mr *MockDatabaseMockRecorder) CreateImportJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateImportJob", reflect.TypeOf((*MockDatabase)(nil).CreateImportJob), arg0, arg1)
+}
+
+// CreateImportJobError mocks base method.
+func (m *MockDatabase) CreateImportJobError(arg0 context.Context, arg1 db.CreateImportJobErrorParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateImportJobError", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateImportJobError indicates an expected call of CreateImportJobError.
+func (mr *MockDatabaseMockRecorder) CreateImportJobError(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateImportJobError", reflect.TypeOf((*MockDatabase)(nil).CreateImportJobError), arg0, arg1)
+}
+
+// CreateOAuthClient mocks base method.
+func (m *MockDatabase) CreateOAuthClient(arg0 context.Context, arg1 db.CreateOAuthClientParams) (db.OauthClient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOAuthClient", arg0, arg1)
+	ret0, _ := ret[0].(db.OauthClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOAuthClient indicates an expected call of CreateOAuthClient.
+func (mr *MockDatabaseMockRecorder) CreateOAuthClient(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOAuthClient", reflect.TypeOf((*MockDatabase)(nil).CreateOAuthClient), arg0, arg1)
+}
+
+// CreateOIDCLogin mocks base method.
+func (m *MockDatabase) CreateOIDCLogin(arg0 context.Context, arg1 db.CreateOIDCLoginParams) (db.OidcLogin, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOIDCLogin", arg0, arg1)
+	ret0, _ := ret[0].(db.OidcLogin)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOIDCLogin indicates an expected call of CreateOIDCLogin.
+func (mr *MockDatabaseMockRecorder) CreateOIDCLogin(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOIDCLogin", reflect.TypeOf((*MockDatabase)(nil).CreateOIDCLogin), arg0, arg1)
+}
+
+// CreatePasswordReset mocks base method.
+func (m *MockDatabase) CreatePasswordReset(arg0 context.Context, arg1 db.CreatePasswordResetParams) (db.PasswordReset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePasswordReset", arg0, arg1)
+	ret0, _ := ret[0].(db.PasswordReset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePasswordReset indicates an expected call of CreatePasswordReset.
+func (mr *MockDatabaseMockRecorder) CreatePasswordReset(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePasswordReset", reflect.TypeOf((*MockDatabase)(nil).CreatePasswordReset), arg0, arg1)
+}
+
+// CreateRecoveryCode mocks base method.
+func (m *MockDatabase) CreateRecoveryCode(arg0 context.Context, arg1 db.CreateRecoveryCodeParams) (db.RecoveryCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRecoveryCode", arg0, arg1)
+	ret0, _ := ret[0].(db.RecoveryCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRecoveryCode indicates an expected call of CreateRecoveryCode.
+func (mr *MockDatabaseMockRecorder) CreateRecoveryCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRecoveryCode", reflect.TypeOf((*MockDatabase)(nil).CreateRecoveryCode), arg0, arg1)
+}
+
+// CreateSession mocks base method.
+func (m *MockDatabase) CreateSession(arg0 context.Context, arg1 db.CreateSessionParams) (db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSession", arg0, arg1)
+	ret0, _ := ret[0].(db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSession indicates an expected call of CreateSession.
+func (mr *MockDatabaseMockRecorder) CreateSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockDatabase)(nil).CreateSession), arg0, arg1)
+}
+
+// CreateSkill mocks base method.
+func (m *MockDatabase) CreateSkill(arg0 context.Context, arg1 db.CreateSkillParams) (db.Skill, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSkill", arg0, arg1)
+	ret0, _ := ret[0].(db.Skill)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSkill indicates an expected call of CreateSkill.
+func (mr *MockDatabaseMockRecorder) CreateSkill(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSkill", reflect.TypeOf((*MockDatabase)(nil).CreateSkill), arg0, arg1)
+}
+
+// CreateTwoFactorChallenge mocks base method.
+func (m *MockDatabase) CreateTwoFactorChallenge(arg0 context.Context, arg1 db.CreateTwoFactorChallengeParams) (db.TwoFactorChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTwoFactorChallenge", arg0, arg1)
+	ret0, _ := ret[0].(db.TwoFactorChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTwoFactorChallenge indicates an expected call of CreateTwoFactorChallenge.
+func (mr *MockDatabaseMockRecorder) CreateTwoFactorChallenge(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTwoFactorChallenge", reflect.TypeOf((*MockDatabase)(nil).CreateTwoFactorChallenge), arg0, arg1)
+}
+
+// CreateUser mocks base method.
+func (m *MockDatabase) CreateUser(arg0 context.Context, arg1 db.CreateUserParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockDatabaseMockRecorder) CreateUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockDatabase)(nil).CreateUser), arg0, arg1)
+}
+
+// CreateUserIdentity mocks base method.
+func (m *MockDatabase) CreateUserIdentity(arg0 context.Context, arg1 db.CreateUserIdentityParams) (db.UserIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserIdentity", arg0, arg1)
+	ret0, _ := ret[0].(db.UserIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserIdentity indicates an expected call of CreateUserIdentity.
+func (mr *MockDatabaseMockRecorder) CreateUserIdentity(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserIdentity", reflect.TypeOf((*MockDatabase)(nil).CreateUserIdentity), arg0, arg1)
+}
+
+// DeleteAPIKey mocks base method.
+func (m *MockDatabase) DeleteAPIKey(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAPIKey", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAPIKey indicates an expected call of DeleteAPIKey.
+func (mr *MockDatabaseMockRecorder) DeleteAPIKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAPIKey", reflect.TypeOf((*MockDatabase)(nil).DeleteAPIKey), arg0, arg1)
+}
+
+// DeleteContact mocks base method.
+func (m *MockDatabase) DeleteContact(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteContact", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteContact indicates an expected call of DeleteContact.
+func (mr *MockDatabaseMockRecorder) DeleteContact(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteContact", reflect.TypeOf((*MockDatabase)(nil).DeleteContact), arg0, arg1)
+}
+
+// DeleteExpiredOIDCLogins mocks base method.
+func (m *MockDatabase) DeleteExpiredOIDCLogins(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredOIDCLogins", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpiredOIDCLogins indicates an expected call of DeleteExpiredOIDCLogins.
+func (mr *MockDatabaseMockRecorder) DeleteExpiredOIDCLogins(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredOIDCLogins", reflect.TypeOf((*MockDatabase)(nil).DeleteExpiredOIDCLogins), arg0)
+}
+
+// DeleteLoginAttempt mocks base method.
+func (m *MockDatabase) DeleteLoginAttempt(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLoginAttempt", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteLoginAttempt indicates an expected call of DeleteLoginAttempt.
+func (mr *MockDatabaseMockRecorder) DeleteLoginAttempt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLoginAttempt", reflect.TypeOf((*MockDatabase)(nil).DeleteLoginAttempt), arg0, arg1)
+}
+
+// DeleteOAuthClient mocks base method.
+func (m *MockDatabase) DeleteOAuthClient(arg0 context.Context, arg1 uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOAuthClient", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteOAuthClient indicates an expected call of DeleteOAuthClient.
+func (mr *MockDatabaseMockRecorder) DeleteOAuthClient(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOAuthClient", reflect.TypeOf((*MockDatabase)(nil).DeleteOAuthClient), arg0, arg1)
+}
+
+// DeleteOwnedContact mocks base method.
+func (m *MockDatabase) DeleteOwnedContact(arg0 context.Context, arg1 db.DeleteOwnedContactParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOwnedContact", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteOwnedContact indicates an expected call of DeleteOwnedContact.
+func (mr *MockDatabaseMockRecorder) DeleteOwnedContact(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOwnedContact", reflect.TypeOf((*MockDatabase)(nil).DeleteOwnedContact), arg0, arg1)
+}
+
+// DeleteRecoveryCodes mocks base method.
+func (m *MockDatabase) DeleteRecoveryCodes(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRecoveryCodes", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRecoveryCodes indicates an expected call of DeleteRecoveryCodes.
+func (mr *MockDatabaseMockRecorder) DeleteRecoveryCodes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRecoveryCodes", reflect.TypeOf((*MockDatabase)(nil).DeleteRecoveryCodes), arg0, arg1)
+}
+
+// DeleteSession mocks base method.
+func (m *MockDatabase) DeleteSession(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSession", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSession indicates an expected call of DeleteSession.
+func (mr *MockDatabaseMockRecorder) DeleteSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSession", reflect.TypeOf((*MockDatabase)(nil).DeleteSession), arg0, arg1)
+}
+
+// DeleteSkill mocks base method.
+func (m *MockDatabase) DeleteSkill(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSkill", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSkill indicates an expected call of DeleteSkill.
+func (mr *MockDatabaseMockRecorder) DeleteSkill(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSkill", reflect.TypeOf((*MockDatabase)(nil).DeleteSkill), arg0, arg1)
+}
+
+// DeleteTwoFactorChallenge mocks base method.
+func (m *MockDatabase) DeleteTwoFactorChallenge(arg0 context.Context, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTwoFactorChallenge", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTwoFactorChallenge indicates an expected call of DeleteTwoFactorChallenge.
+func (mr *MockDatabaseMockRecorder) DeleteTwoFactorChallenge(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTwoFactorChallenge", reflect.TypeOf((*MockDatabase)(nil).DeleteTwoFactorChallenge), arg0, arg1)
+}
+
+// DeleteUnusedPasswordResets mocks base method.
+func (m *MockDatabase) DeleteUnusedPasswordResets(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUnusedPasswordResets", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUnusedPasswordResets indicates an expected call of DeleteUnusedPasswordResets.
+func (mr *MockDatabaseMockRecorder) DeleteUnusedPasswordResets(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUnusedPasswordResets", reflect.TypeOf((*MockDatabase)(nil).DeleteUnusedPasswordResets), arg0, arg1)
+}
+
+// DeleteUser mocks base method.
+func (m *MockDatabase) DeleteUser(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUser", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUser indicates an expected call of DeleteUser.
+func (mr *MockDatabaseMockRecorder) DeleteUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockDatabase)(nil).DeleteUser), arg0, arg1)
+}
+
+// DisableTOTPTx mocks base method.
+func (m *MockDatabase) DisableTOTPTx(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableTOTPTx", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisableTOTPTx indicates an expected call of DisableTOTPTx.
+func (mr *MockDatabaseMockRecorder) DisableTOTPTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableTOTPTx", reflect.TypeOf((*MockDatabase)(nil).DisableTOTPTx), arg0, arg1)
+}
+
+// DisableUser mocks base method.
+func (m *MockDatabase) DisableUser(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisableUser indicates an expected call of DisableUser.
+func (mr *MockDatabaseMockRecorder) DisableUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableUser", reflect.TypeOf((*MockDatabase)(nil).DisableUser), arg0, arg1)
+}
+
+// DisableUserTOTP mocks base method.
+func (m *MockDatabase) DisableUserTOTP(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableUserTOTP", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisableUserTOTP indicates an expected call of DisableUserTOTP.
+func (mr *MockDatabaseMockRecorder) DisableUserTOTP(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableUserTOTP", reflect.TypeOf((*MockDatabase)(nil).DisableUserTOTP), arg0, arg1)
+}
+
+// EnableTOTPTx mocks base method.
+func (m *MockDatabase) EnableTOTPTx(arg0 context.Context, arg1 database.EnableTOTPTxParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableTOTPTx", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnableTOTPTx indicates an expected call of EnableTOTPTx.
+func (mr *MockDatabaseMockRecorder) EnableTOTPTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableTOTPTx", reflect.TypeOf((*MockDatabase)(nil).EnableTOTPTx), arg0, arg1)
+}
+
+// EnableUser mocks base method.
+func (m *MockDatabase) EnableUser(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnableUser indicates an expected call of EnableUser.
+func (mr *MockDatabaseMockRecorder) EnableUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableUser", reflect.TypeOf((*MockDatabase)(nil).EnableUser), arg0, arg1)
+}
+
+// EnableUserTOTP mocks base method.
+func (m *MockDatabase) EnableUserTOTP(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableUserTOTP", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnableUserTOTP indicates an expected call of EnableUserTOTP.
+func (mr *MockDatabaseMockRecorder) EnableUserTOTP(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableUserTOTP", reflect.TypeOf((*MockDatabase)(nil).EnableUserTOTP), arg0, arg1)
+}
+
+// ExportContacts mocks base method.
+func (m *MockDatabase) ExportContacts(arg0 context.Context, arg1 database.ExportContactsParams, arg2 func(db.ListContactsWithSkillsRow) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportContacts", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportContacts indicates an expected call of ExportContacts.
+func (mr *MockDatabaseMockRecorder) ExportContacts(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportContacts", reflect.TypeOf((*MockDatabase)(nil).ExportContacts), arg0, arg1, arg2)
+}
+
+// FailInterruptedImportJobs mocks base method.
+func (m *MockDatabase) FailInterruptedImportJobs(arg0 context.Context, arg1 db.FailInterruptedImportJobsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailInterruptedImportJobs", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FailInterruptedImportJobs indicates an expected call of FailInterruptedImportJobs.
+func (mr *MockDatabaseMockRecorder) FailInterruptedImportJobs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailInterruptedImportJobs", reflect.TypeOf((*MockDatabase)(nil).FailInterruptedImportJobs), arg0, arg1)
+}
+
+// FinishImportJob mocks base method.
+func (m *MockDatabase) FinishImportJob(arg0 context.Context, arg1 db.FinishImportJobParams) (db.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishImportJob", arg0, arg1)
+	ret0, _ := ret[0].(db.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinishImportJob indicates an expected call of FinishImportJob.
+func (mr *MockDatabaseMockRecorder) FinishImportJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishImportJob", reflect.TypeOf((*MockDatabase)(nil).FinishImportJob), arg0, arg1)
+}
+
+// GetAPIKey mocks base method.
+func (m *MockDatabase) GetAPIKey(arg0 context.Context, arg1 uuid.UUID) (db.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKey", arg0, arg1)
+	ret0, _ := ret[0].(db.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKey indicates an expected call of GetAPIKey.
+func (mr *MockDatabaseMockRecorder) GetAPIKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKey", reflect.TypeOf((*MockDatabase)(nil).GetAPIKey), arg0, arg1)
+}
+
+// GetActiveOAuthClientOwner mocks base method.
+func (m *MockDatabase) GetActiveOAuthClientOwner(arg0 context.Context, arg1 uuid.UUID) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveOAuthClientOwner", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveOAuthClientOwner indicates an expected call of GetActiveOAuthClientOwner.
+func (mr *MockDatabaseMockRecorder) GetActiveOAuthClientOwner(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveOAuthClientOwner", reflect.TypeOf((*MockDatabase)(nil).GetActiveOAuthClientOwner), arg0, arg1)
+}
+
+// GetContact mocks base method.
+func (m *MockDatabase) GetContact(arg0 context.Context, arg1 int64) (db.Contact, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContact", arg0, arg1)
+	ret0, _ := ret[0].(db.Contact)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetContact indicates an expected call of GetContact.
+func (mr *MockDatabaseMockRecorder) GetContact(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContact", reflect.TypeOf((*MockDatabase)(nil).GetContact), arg0, arg1)
+}
+
+// GetContactSkills mocks base method.
+func (m *MockDatabase) GetContactSkills(arg0 context.Context, arg1 int32) ([]db.Skill, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContactSkills", arg0, arg1)
+	ret0, _ := ret[0].([]db.Skill)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetContactSkills indicates an expected call of GetContactSkills.
+func (mr *MockDatabaseMockRecorder) GetContactSkills(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContactSkills", reflect.TypeOf((*MockDatabase)(nil).GetContactSkills), arg0, arg1)
+}
+
+// GetContactsWithSkill mocks base method.
+func (m *MockDatabase) GetContactsWithSkill(arg0 context.Context, arg1 string) ([]db.Contact, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContactsWithSkill", arg0, arg1)
+	ret0, _ := ret[0].([]db.Contact)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetContactsWithSkill indicates an expected call of GetContactsWithSkill.
+func (mr *MockDatabaseMockRecorder) GetContactsWithSkill(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContactsWithSkill", reflect.TypeOf((*MockDatabase)(nil).GetContactsWithSkill), arg0, arg1)
+}
+
+// GetContactsWithSkillAndLevel mocks base method.
+func (m *MockDatabase) GetContactsWithSkillAndLevel(arg0 context.Context, arg1 db.GetContactsWithSkillAndLevelParams) ([]db.Contact, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContactsWithSkillAndLevel", arg0, arg1)
+	ret0, _ := ret[0].([]db.Contact)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetContactsWithSkillAndLevel indicates an expected call of GetContactsWithSkillAndLevel.
+func (mr *MockDatabaseMockRecorder) GetContactsWithSkillAndLevel(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContactsWithSkillAndLevel", reflect.TypeOf((*MockDatabase)(nil).GetContactsWithSkillAndLevel), arg0, arg1)
+}
+
+// GetEmail mocks base method.
+func (m *MockDatabase) GetEmail(arg0 context.Context, arg1 int64) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEmail", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEmail indicates an expected call of GetEmail.
+func (mr *MockDatabaseMockRecorder) GetEmail(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEmail", reflect.TypeOf((*MockDatabase)(nil).GetEmail), arg0, arg1)
+}
+
+// GetFirstname mocks base method.
+func (m *MockDatabase) GetFirstname(arg0 context.Context, arg1 int64) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFirstname", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFirstname indicates an expected call of GetFirstname.
+func (mr *MockDatabaseMockRecorder) GetFirstname(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFirstname", reflect.TypeOf((*MockDatabase)(nil).GetFirstname), arg0, arg1)
+}
+
+// GetFullname mocks base method.
+func (m *MockDatabase) GetFullname(arg0 context.Context, arg1 int64) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFullname", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFullname indicates an expected call of GetFullname.
+func (mr *MockDatabaseMockRecorder) GetFullname(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFullname", reflect.TypeOf((*MockDatabase)(nil).GetFullname), arg0, arg1)
+}
+
+// GetHomeAddress mocks base method.
+func (m *MockDatabase) GetHomeAddress(arg0 context.Context, arg1 int64) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHomeAddress", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHomeAddress indicates an expected call of GetHomeAddress.
+func (mr *MockDatabaseMockRecorder) GetHomeAddress(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHomeAddress", reflect.TypeOf((*MockDatabase)(nil).GetHomeAddress), arg0, arg1)
+}
+
+// GetIfExistsContactID mocks base method.
+func (m *MockDatabase) GetIfExistsContactID(arg0 context.Context, arg1 int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIfExistsContactID", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIfExistsContactID indicates an expected call of GetIfExistsContactID.
+func (mr *MockDatabaseMockRecorder) GetIfExistsContactID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIfExistsContactID", reflect.TypeOf((*MockDatabase)(nil).GetIfExistsContactID), arg0, arg1)
+}
+
+// GetIfExistsSkillID mocks base method.
+func (m *MockDatabase) GetIfExistsSkillID(arg0 context.Context, arg1 int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIfExistsSkillID", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIfExistsSkillID indicates an expected call of GetIfExistsSkillID.
+func (mr *MockDatabaseMockRecorder) GetIfExistsSkillID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIfExistsSkillID", reflect.TypeOf((*MockDatabase)(nil).GetIfExistsSkillID), arg0, arg1)
+}
+
+// GetImportJob mocks base method.
+func (m *MockDatabase) GetImportJob(arg0 context.Context, arg1 uuid.UUID) (db.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImportJob", arg0, arg1)
+	ret0, _ := ret[0].(db.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImportJob indicates an expected call of GetImportJob.
+func (mr *MockDatabaseMockRecorder) GetImportJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImportJob", reflect.TypeOf((*MockDatabase)(nil).GetImportJob), arg0, arg1)
+}
+
+// GetLastname mocks base method.
+func (m *MockDatabase) GetLastname(arg0 context.Context, arg1 int64) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastname", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastname indicates an expected call of GetLastname.
+func (mr *MockDatabaseMockRecorder) GetLastname(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastname", reflect.TypeOf((*MockDatabase)(nil).GetLastname), arg0, arg1)
+}
+
+// GetLoginAttempt mocks base method.
+func (m *MockDatabase) GetLoginAttempt(arg0 context.Context, arg1 string) (db.LoginAttempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoginAttempt", arg0, arg1)
+	ret0, _ := ret[0].(db.LoginAttempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoginAttempt indicates an expected call of GetLoginAttempt.
+func (mr *MockDatabaseMockRecorder) GetLoginAttempt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoginAttempt", reflect.TypeOf((*MockDatabase)(nil).GetLoginAttempt), arg0, arg1)
+}
+
+// GetOAuthClient mocks base method.
+func (m *MockDatabase) GetOAuthClient(arg0 context.Context, arg1 uuid.UUID) (db.OauthClient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOAuthClient", arg0, arg1)
+	ret0, _ := ret[0].(db.OauthClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOAuthClient indicates an expected call of GetOAuthClient.
+func (mr *MockDatabaseMockRecorder) GetOAuthClient(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuthClient", reflect.TypeOf((*MockDatabase)(nil).GetOAuthClient), arg0, arg1)
+}
+
+// GetOrCreateSkill mocks base method.
+func (m *MockDatabase) GetOrCreateSkill(arg0 context.Context, arg1 db.GetOrCreateSkillParams) (db.Skill, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrCreateSkill", arg0, arg1)
+	ret0, _ := ret[0].(db.Skill)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrCreateSkill indicates an expected call of GetOrCreateSkill.
+func (mr *MockDatabaseMockRecorder) GetOrCreateSkill(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrCreateSkill", reflect.TypeOf((*MockDatabase)(nil).GetOrCreateSkill), arg0, arg1)
+}
+
+// GetPasswordReset mocks base method.
+func (m *MockDatabase) GetPasswordReset(arg0 context.Context, arg1 string) (db.PasswordReset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPasswordReset", arg0, arg1)
+	ret0, _ := ret[0].(db.PasswordReset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPasswordReset indicates an expected call of GetPasswordReset.
+func (mr *MockDatabaseMockRecorder) GetPasswordReset(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPasswordReset", reflect.TypeOf((*MockDatabase)(nil).GetPasswordReset), arg0, arg1)
+}
+
+// GetPhoneNumber mocks base method.
+func (m *MockDatabase) GetPhoneNumber(arg0 context.Context, arg1 int64) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPhoneNumber", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPhoneNumber indicates an expected call of GetPhoneNumber.
+func (mr *MockDatabaseMockRecorder) GetPhoneNumber(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPhoneNumber", reflect.TypeOf((*MockDatabase)(nil).GetPhoneNumber), arg0, arg1)
+}
+
+// GetSession mocks base method.
+func (m *MockDatabase) GetSession(arg0 context.Context, arg1 uuid.UUID) (db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSession", arg0, arg1)
+	ret0, _ := ret[0].(db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSession indicates an expected call of GetSession.
+func (mr *MockDatabaseMockRecorder) GetSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockDatabase)(nil).GetSession), arg0, arg1)
+}
+
+// GetSkill mocks base method.
+func (m *MockDatabase) GetSkill(arg0 context.Context, arg1 int64) (db.Skill, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSkill", arg0, arg1)
+	ret0, _ := ret[0].(db.Skill)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSkill indicates an expected call of GetSkill.
+func (mr *MockDatabaseMockRecorder) GetSkill(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSkill", reflect.TypeOf((*MockDatabase)(nil).GetSkill), arg0, arg1)
+}
+
+// GetSkillLevel mocks base method.
+func (m *MockDatabase) GetSkillLevel(arg0 context.Context, arg1 int64) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSkillLevel", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSkillLevel indicates an expected call of GetSkillLevel.
+func (mr *MockDatabaseMockRecorder) GetSkillLevel(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSkillLevel", reflect.TypeOf((*MockDatabase)(nil).GetSkillLevel), arg0, arg1)
+}
+
+// GetSkillName mocks base method.
+func (m *MockDatabase) GetSkillName(arg0 context.Context, arg1 int64) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSkillName", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSkillName indicates an expected call of GetSkillName.
+func (mr *MockDatabaseMockRecorder) GetSkillName(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSkillName", reflect.TypeOf((*MockDatabase)(nil).GetSkillName), arg0, arg1)
+}
+
+// GetTwoFactorChallenge mocks base method.
+func (m *MockDatabase) GetTwoFactorChallenge(arg0 context.Context, arg1 string) (db.TwoFactorChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTwoFactorChallenge", arg0, arg1)
+	ret0, _ := ret[0].(db.TwoFactorChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTwoFactorChallenge indicates an expected call of GetTwoFactorChallenge.
+func (mr *MockDatabaseMockRecorder) GetTwoFactorChallenge(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTwoFactorChallenge", reflect.TypeOf((*MockDatabase)(nil).GetTwoFactorChallenge), arg0, arg1)
+}
+
+// GetUser mocks base method.
+func (m *MockDatabase) GetUser(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockDatabaseMockRecorder) GetUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockDatabase)(nil).GetUser), arg0, arg1)
+}
+
+// GetUserByEmail mocks base method.
+func (m *MockDatabase) GetUserByEmail(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByEmail", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByEmail indicates an expected call of GetUserByEmail.
+func (mr *MockDatabaseMockRecorder) GetUserByEmail(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockDatabase)(nil).GetUserByEmail), arg0, arg1)
+}
+
+// GetUserByIdentity mocks base method.
+func (m *MockDatabase) GetUserByIdentity(arg0 context.Context, arg1 db.GetUserByIdentityParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByIdentity", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByIdentity indicates an expected call of GetUserByIdentity.
+func (mr *MockDatabaseMockRecorder) GetUserByIdentity(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByIdentity", reflect.TypeOf((*MockDatabase)(nil).GetUserByIdentity), arg0, arg1)
+}
+
+// GetUserCounts mocks base method.
+func (m *MockDatabase) GetUserCounts(arg0 context.Context, arg1 string) (db.GetUserCountsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserCounts", arg0, arg1)
+	ret0, _ := ret[0].(db.GetUserCountsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserCounts indicates an expected call of GetUserCounts.
+func (mr *MockDatabaseMockRecorder) GetUserCounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserCounts", reflect.TypeOf((*MockDatabase)(nil).GetUserCounts), arg0, arg1)
+}
+
+// ImportContactTx mocks base method.
+func (m *MockDatabase) ImportContactTx(arg0 context.Context, arg1 database.ImportContactTxParams) (database.ImportContactTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportContactTx", arg0, arg1)
+	ret0, _ := ret[0].(database.ImportContactTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportContactTx indicates an expected call of ImportContactTx.
+func (mr *MockDatabaseMockRecorder) ImportContactTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportContactTx", reflect.TypeOf((*MockDatabase)(nil).ImportContactTx), arg0, arg1)
+}
+
+// InvalidatePasswordResets mocks base method.
+func (m *MockDatabase) InvalidatePasswordResets(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InvalidatePasswordResets", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InvalidatePasswordResets indicates an expected call of InvalidatePasswordResets.
+func (mr *MockDatabaseMockRecorder) InvalidatePasswordResets(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidatePasswordResets", reflect.TypeOf((*MockDatabase)(nil).InvalidatePasswordResets), arg0, arg1)
+}
+
+// KeepImportJobAlive mocks base method.
+func (m *MockDatabase) KeepImportJobAlive(arg0 context.Context, arg1 uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "KeepImportJobAlive", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// KeepImportJobAlive indicates an expected call of KeepImportJobAlive.
+func (mr *MockDatabaseMockRecorder) KeepImportJobAlive(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "KeepImportJobAlive", reflect.TypeOf((*MockDatabase)(nil).KeepImportJobAlive), arg0, arg1)
+}
+
+// ListAPIKeys mocks base method.
+func (m *MockDatabase) ListAPIKeys(arg0 context.Context, arg1 string) ([]db.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAPIKeys", arg0, arg1)
+	ret0, _ := ret[0].([]db.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAPIKeys indicates an expected call of ListAPIKeys.
+func (mr *MockDatabaseMockRecorder) ListAPIKeys(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAPIKeys", reflect.TypeOf((*MockDatabase)(nil).ListAPIKeys), arg0, arg1)
+}
+
+// ListAuditLogs mocks base method.
+func (m *MockDatabase) ListAuditLogs(arg0 context.Context, arg1 db.ListAuditLogsParams) ([]db.AuditLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuditLogs", arg0, arg1)
+	ret0, _ := ret[0].([]db.AuditLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuditLogs indicates an expected call of ListAuditLogs.
+func (mr *MockDatabaseMockRecorder) ListAuditLogs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditLogs", reflect.TypeOf((*MockDatabase)(nil).ListAuditLogs), arg0, arg1)
+}
+
+// ListContacts mocks base method.
+func (m *MockDatabase) ListContacts(arg0 context.Context, arg1 db.ListContactsParams) ([]db.Contact, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListContacts", arg0, arg1)
+	ret0, _ := ret[0].([]db.Contact)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListContacts indicates an expected call of ListContacts.
+func (mr *MockDatabaseMockRecorder) ListContacts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListContacts", reflect.TypeOf((*MockDatabase)(nil).ListContacts), arg0, arg1)
+}
+
+// ListContactsWithSkills mocks base method.
+func (m *MockDatabase) ListContactsWithSkills(arg0 context.Context, arg1 string) ([]db.ListContactsWithSkillsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListContactsWithSkills", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListContactsWithSkillsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListContactsWithSkills indicates an expected call of ListContactsWithSkills.
+func (mr *MockDatabaseMockRecorder) ListContactsWithSkills(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListContactsWithSkills", reflect.TypeOf((*MockDatabase)(nil).ListContactsWithSkills), arg0, arg1)
+}
+
+// ListDuplicateContacts mocks base method.
+func (m *MockDatabase) ListDuplicateContacts(arg0 context.Context, arg1 db.ListDuplicateContactsParams) ([]db.ListDuplicateContactsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDuplicateContacts", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListDuplicateContactsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDuplicateContacts indicates an expected call of ListDuplicateContacts.
+func (mr *MockDatabaseMockRecorder) ListDuplicateContacts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDuplicateContacts", reflect.TypeOf((*MockDatabase)(nil).ListDuplicateContacts), arg0, arg1)
 }
 
-// CreateContactHasSkill mocks base method.
-func (m *MockDatabase) CreateContactHasSkill(arg0 context.Context, arg1 db.CreateContactHasSkillParams) (db.ContactHasSkill, error) {
+// ListImportJobErrors mocks base method.
+func (m *MockDatabase) ListImportJobErrors(arg0 context.Context, arg1 db.ListImportJobErrorsParams) ([]db.ListImportJobErrorsRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateContactHasSkill", arg0, arg1)
-	ret0, _ := ret[0].(db.ContactHasSkill)
+	ret := m.ctrl.Call(m, "ListImportJobErrors", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListImportJobErrorsRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateContactHasSkill indicates an expected call of CreateContactHasSkill.
-func (mr *MockDatabaseMockRecorder) CreateContactHasSkill(arg0, arg1 interface{}) *gomock.Call {
+// ListImportJobErrors indicates an expected call of ListImportJobErrors.
+func (mr *MockDatabaseMockRecorder) ListImportJobErrors(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateContactHasSkill", reflect.TypeOf((*MockDatabase)(nil).CreateContactHasSkill), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListImportJobErrors", reflect.TypeOf((*MockDatabase)(nil).ListImportJobErrors), arg0, arg1)
 }
 
-// CreateSession mocks base method.
-func (m *MockDatabase) CreateSession(arg0 context.Context, arg1 db.CreateSessionParams) (db.Session, error) {
+// ListOAuthClients mocks base method.
+func (m *MockDatabase) ListOAuthClients(arg0 context.Context) ([]db.OauthClient, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateSession", arg0, arg1)
-	ret0, _ := ret[0].(db.Session)
+	ret := m.ctrl.Call(m, "ListOAuthClients", arg0)
+	ret0, _ := ret[0].([]db.OauthClient)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateSession indicates an expected call of CreateSession.
-func (mr *MockDatabaseMockRecorder) CreateSession(arg0, arg1 interface{}) *gomock.Call {
+// ListOAuthClients indicates an expected call of ListOAuthClients.
+func (mr *MockDatabaseMockRecorder) ListOAuthClients(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockDatabase)(nil).CreateSession), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOAuthClients", reflect.TypeOf((*MockDatabase)(nil).ListOAuthClients), arg0)
 }
 
-// CreateSkill mocks base method.
-func (m *MockDatabase) CreateSkill(arg0 context.Context, arg1 db.CreateSkillParams) (db.Skill, error) {
+// ListSessions mocks base method.
+func (m *MockDatabase) ListSessions(arg0 context.Context, arg1 string) ([]db.ListSessionsRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateSkill", arg0, arg1)
-	ret0, _ := ret[0].(db.Skill)
+	ret := m.ctrl.Call(m, "ListSessions", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListSessionsRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateSkill indicates an expected call of CreateSkill.
-func (mr *MockDatabaseMockRecorder) CreateSkill(arg0, arg1 interface{}) *gomock.Call {
+// ListSessions indicates an expected call of ListSessions.
+func (mr *MockDatabaseMockRecorder) ListSessions(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSkill", reflect.TypeOf((*MockDatabase)(nil).CreateSkill), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSessions", reflect.TypeOf((*MockDatabase)(nil).ListSessions), arg0, arg1)
 }
 
-// CreateUser mocks base method.
-func (m *MockDatabase) CreateUser(arg0 context.Context, arg1 db.CreateUserParams) (db.User, error) {
+// ListSkills mocks base method.
+func (m *MockDatabase) ListSkills(arg0 context.Context, arg1 db.ListSkillsParams) ([]db.Skill, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateUser", arg0, arg1)
-	ret0, _ := ret[0].(db.User)
+	ret := m.ctrl.Call(m, "ListSkills", arg0, arg1)
+	ret0, _ := ret[0].([]db.Skill)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateUser indicates an expected call of CreateUser.
-func (mr *MockDatabaseMockRecorder) CreateUser(arg0, arg1 interface{}) *gomock.Call {
+// ListSkills indicates an expected call of ListSkills.
+func (mr *MockDatabaseMockRecorder) ListSkills(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockDatabase)(nil).CreateUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSkills", reflect.TypeOf((*MockDatabase)(nil).ListSkills), arg0, arg1)
 }
 
-// DeleteContact mocks base method.
-func (m *MockDatabase) DeleteContact(arg0 context.Context, arg1 int64) error {
+// ListUsers mocks base method.
+func (m *MockDatabase) ListUsers(arg0 context.Context, arg1 db.ListUsersParams) ([]db.ListUsersRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteContact", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "ListUsers", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListUsersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeleteContact indicates an expected call of DeleteContact.
-func (mr *MockDatabaseMockRecorder) DeleteContact(arg0, arg1 interface{}) *gomock.Call {
+// ListUsers indicates an expected call of ListUsers.
+func (mr *MockDatabaseMockRecorder) ListUsers(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteContact", reflect.TypeOf((*MockDatabase)(nil).DeleteContact), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockDatabase)(nil).ListUsers), arg0, arg1)
 }
 
-// DeleteSession mocks base method.
-func (m *MockDatabase) DeleteSession(arg0 context.Context, arg1 uuid.UUID) error {
+// MarkEmailVerificationSent mocks base method.
+func (m *MockDatabase) MarkEmailVerificationSent(arg0 context.Context, arg1 db.MarkEmailVerificationSentParams) (db.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteSession", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "MarkEmailVerificationSent", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeleteSession indicates an expected call of DeleteSession.
-func (mr *MockDatabaseMockRecorder) DeleteSession(arg0, arg1 interface{}) *gomock.Call {
+// MarkEmailVerificationSent indicates an expected call of MarkEmailVerificationSent.
+func (mr *MockDatabaseMockRecorder) MarkEmailVerificationSent(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSession", reflect.TypeOf((*MockDatabase)(nil).DeleteSession), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkEmailVerificationSent", reflect.TypeOf((*MockDatabase)(nil).MarkEmailVerificationSent), arg0, arg1)
 }
 
-// DeleteSkill mocks base method.
-func (m *MockDatabase) DeleteSkill(arg0 context.Context, arg1 int64) error {
+// MarkPasswordResetSent mocks base method.
+func (m *MockDatabase) MarkPasswordResetSent(arg0 context.Context, arg1 db.MarkPasswordResetSentParams) (db.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteSkill", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "MarkPasswordResetSent", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeleteSkill indicates an expected call of DeleteSkill.
-func (mr *MockDatabaseMockRecorder) DeleteSkill(arg0, arg1 interface{}) *gomock.Call {
+// MarkPasswordResetSent indicates an expected call of MarkPasswordResetSent.
+func (mr *MockDatabaseMockRecorder) MarkPasswordResetSent(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSkill", reflect.TypeOf((*MockDatabase)(nil).DeleteSkill), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkPasswordResetSent", reflect.TypeOf((*MockDatabase)(nil).MarkPasswordResetSent), arg0, arg1)
 }
 
-// DeleteUser mocks base method.
-func (m *MockDatabase) DeleteUser(arg0 context.Context, arg1 string) error {
+// MergeContactsTx mocks base method.
+func (m *MockDatabase) MergeContactsTx(arg0 context.Context, arg1 database.MergeContactsTxParams) (database.MergeContactsTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteUser", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "MergeContactsTx", arg0, arg1)
+	ret0, _ := ret[0].(database.MergeContactsTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeleteUser indicates an expected call of DeleteUser.
-func (mr *MockDatabaseMockRecorder) DeleteUser(arg0, arg1 interface{}) *gomock.Call {
+// MergeContactsTx indicates an expected call of MergeContactsTx.
+func (mr *MockDatabaseMockRecorder) MergeContactsTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockDatabase)(nil).DeleteUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeContactsTx", reflect.TypeOf((*MockDatabase)(nil).MergeContactsTx), arg0, arg1)
 }
 
-// GetContact mocks base method.
-func (m *MockDatabase) GetContact(arg0 context.Context, arg1 int64) (db.Contact, error) {
+// MoveContactSkills mocks base method.
+func (m *MockDatabase) MoveContactSkills(arg0 context.Context, arg1 db.MoveContactSkillsParams) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetContact", arg0, arg1)
-	ret0, _ := ret[0].(db.Contact)
+	ret := m.ctrl.Call(m, "MoveContactSkills", arg0, arg1)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetContact indicates an expected call of GetContact.
-func (mr *MockDatabaseMockRecorder) GetContact(arg0, arg1 interface{}) *gomock.Call {
+// MoveContactSkills indicates an expected call of MoveContactSkills.
+func (mr *MockDatabaseMockRecorder) MoveContactSkills(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContact", reflect.TypeOf((*MockDatabase)(nil).GetContact), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveContactSkills", reflect.TypeOf((*MockDatabase)(nil).MoveContactSkills), arg0, arg1)
 }
 
-// GetContactSkills mocks base method.
-func (m *MockDatabase) GetContactSkills(arg0 context.Context, arg1 int32) ([]db.Skill, error) {
+// ProvisionOIDCUserTx mocks base method.
+func (m *MockDatabase) ProvisionOIDCUserTx(arg0 context.Context, arg1 database.ProvisionOIDCUserTxParams) (db.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetContactSkills", arg0, arg1)
-	ret0, _ := ret[0].([]db.Skill)
+	ret := m.ctrl.Call(m, "ProvisionOIDCUserTx", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetContactSkills indicates an expected call of GetContactSkills.
-func (mr *MockDatabaseMockRecorder) GetContactSkills(arg0, arg1 interface{}) *gomock.Call {
+// ProvisionOIDCUserTx indicates an expected call of ProvisionOIDCUserTx.
+func (mr *MockDatabaseMockRecorder) ProvisionOIDCUserTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContactSkills", reflect.TypeOf((*MockDatabase)(nil).GetContactSkills), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProvisionOIDCUserTx", reflect.TypeOf((*MockDatabase)(nil).ProvisionOIDCUserTx), arg0, arg1)
 }
 
-// GetContactsWithSkill mocks base method.
-func (m *MockDatabase) GetContactsWithSkill(arg0 context.Context, arg1 string) ([]db.Contact, error) {
+// RecordLoginFailure mocks base method.
+func (m *MockDatabase) RecordLoginFailure(arg0 context.Context, arg1 db.RecordLoginFailureParams) (db.LoginAttempt, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetContactsWithSkill", arg0, arg1)
-	ret0, _ := ret[0].([]db.Contact)
+	ret := m.ctrl.Call(m, "RecordLoginFailure", arg0, arg1)
+	ret0, _ := ret[0].(db.LoginAttempt)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetContactsWithSkill indicates an expected call of GetContactsWithSkill.
-func (mr *MockDatabaseMockRecorder) GetContactsWithSkill(arg0, arg1 interface{}) *gomock.Call {
+// RecordLoginFailure indicates an expected call of RecordLoginFailure.
+func (mr *MockDatabaseMockRecorder) RecordLoginFailure(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContactsWithSkill", reflect.TypeOf((*MockDatabase)(nil).GetContactsWithSkill), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordLoginFailure", reflect.TypeOf((*MockDatabase)(nil).RecordLoginFailure), arg0, arg1)
 }
 
-// GetContactsWithSkillAndLevel mocks base method.
-func (m *MockDatabase) GetContactsWithSkillAndLevel(arg0 context.Context, arg1 db.GetContactsWithSkillAndLevelParams) ([]db.Contact, error) {
+// ResetPasswordTx mocks base method.
+func (m *MockDatabase) ResetPasswordTx(arg0 context.Context, arg1 database.ResetPasswordTxParams) (db.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetContactsWithSkillAndLevel", arg0, arg1)
-	ret0, _ := ret[0].([]db.Contact)
+	ret := m.ctrl.Call(m, "ResetPasswordTx", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetContactsWithSkillAndLevel indicates an expected call of GetContactsWithSkillAndLevel.
-func (mr *MockDatabaseMockRecorder) GetContactsWithSkillAndLevel(arg0, arg1 interface{}) *gomock.Call {
+// ResetPasswordTx indicates an expected call of ResetPasswordTx.
+func (mr *MockDatabaseMockRecorder) ResetPasswordTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContactsWithSkillAndLevel", reflect.TypeOf((*MockDatabase)(nil).GetContactsWithSkillAndLevel), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPasswordTx", reflect.TypeOf((*MockDatabase)(nil).ResetPasswordTx), arg0, arg1)
 }
 
-// GetEmail mocks base method.
-func (m *MockDatabase) GetEmail(arg0 context.Context, arg1 int64) (string, error) {
+// RotateSession mocks base method.
+func (m *MockDatabase) RotateSession(arg0 context.Context, arg1 uuid.UUID) (db.Session, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEmail", arg0, arg1)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "RotateSession", arg0, arg1)
+	ret0, _ := ret[0].(db.Session)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetEmail indicates an expected call of GetEmail.
-func (mr *MockDatabaseMockRecorder) GetEmail(arg0, arg1 interface{}) *gomock.Call {
+// RotateSession indicates an expected call of RotateSession.
+func (mr *MockDatabaseMockRecorder) RotateSession(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEmail", reflect.TypeOf((*MockDatabase)(nil).GetEmail), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateSession", reflect.TypeOf((*MockDatabase)(nil).RotateSession), arg0, arg1)
 }
 
-// GetFirstname mocks base method.
-func (m *MockDatabase) GetFirstname(arg0 context.Context, arg1 int64) (string, error) {
+// RotateSessionTx mocks base method.
+func (m *MockDatabase) RotateSessionTx(arg0 context.Context, arg1 database.RotateSessionTxParams) (database.RotateSessionTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFirstname", arg0, arg1)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "RotateSessionTx", arg0, arg1)
+	ret0, _ := ret[0].(database.RotateSessionTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetFirstname indicates an expected call of GetFirstname.
-func (mr *MockDatabaseMockRecorder) GetFirstname(arg0, arg1 interface{}) *gomock.Call {
+// RotateSessionTx indicates an expected call of RotateSessionTx.
+func (mr *MockDatabaseMockRecorder) RotateSessionTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFirstname", reflect.TypeOf((*MockDatabase)(nil).GetFirstname), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateSessionTx", reflect.TypeOf((*MockDatabase)(nil).RotateSessionTx), arg0, arg1)
 }
 
-// GetFullname mocks base method.
-func (m *MockDatabase) GetFullname(arg0 context.Context, arg1 int64) (string, error) {
+// SearchContacts mocks base method.
+func (m *MockDatabase) SearchContacts(arg0 context.Context, arg1 db.SearchContactsParams) ([]db.SearchContactsRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFullname", arg0, arg1)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "SearchContacts", arg0, arg1)
+	ret0, _ := ret[0].([]db.SearchContactsRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetFullname indicates an expected call of GetFullname.
-func (mr *MockDatabaseMockRecorder) GetFullname(arg0, arg1 interface{}) *gomock.Call {
+// SearchContacts indicates an expected call of SearchContacts.
+func (mr *MockDatabaseMockRecorder) SearchContacts(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFullname", reflect.TypeOf((*MockDatabase)(nil).GetFullname), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchContacts", reflect.TypeOf((*MockDatabase)(nil).SearchContacts), arg0, arg1)
 }
 
-// GetHomeAddress mocks base method.
-func (m *MockDatabase) GetHomeAddress(arg0 context.Context, arg1 int64) (string, error) {
+// SetUserTOTPSecret mocks base method.
+func (m *MockDatabase) SetUserTOTPSecret(arg0 context.Context, arg1 db.SetUserTOTPSecretParams) (db.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetHomeAddress", arg0, arg1)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "SetUserTOTPSecret", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetHomeAddress indicates an expected call of GetHomeAddress.
-func (mr *MockDatabaseMockRecorder) GetHomeAddress(arg0, arg1 interface{}) *gomock.Call {
+// SetUserTOTPSecret indicates an expected call of SetUserTOTPSecret.
+func (mr *MockDatabaseMockRecorder) SetUserTOTPSecret(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHomeAddress", reflect.TypeOf((*MockDatabase)(nil).GetHomeAddress), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserTOTPSecret", reflect.TypeOf((*MockDatabase)(nil).SetUserTOTPSecret), arg0, arg1)
 }
 
-// GetIfExistsContactID mocks base method.
-func (m *MockDatabase) GetIfExistsContactID(arg0 context.Context, arg1 int64) (bool, error) {
+// UpdateContact mocks base method.
+func (m *MockDatabase) UpdateContact(arg0 context.Context, arg1 db.UpdateContactParams) (db.Contact, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetIfExistsContactID", arg0, arg1)
-	ret0, _ := ret[0].(bool)
+	ret := m.ctrl.Call(m, "UpdateContact", arg0, arg1)
+	ret0, _ := ret[0].(db.Contact)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetIfExistsContactID indicates an expected call of GetIfExistsContactID.
-func (mr *MockDatabaseMockRecorder) GetIfExistsContactID(arg0, arg1 interface{}) *gomock.Call {
+// UpdateContact indicates an expected call of UpdateContact.
+func (mr *MockDatabaseMockRecorder) UpdateContact(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIfExistsContactID", reflect.TypeOf((*MockDatabase)(nil).GetIfExistsContactID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateContact", reflect.TypeOf((*MockDatabase)(nil).UpdateContact), arg0, arg1)
 }
 
-// GetIfExistsSkillID mocks base method.
-func (m *MockDatabase) GetIfExistsSkillID(arg0 context.Context, arg1 int64) (bool, error) {
+// UpdateImportJobProgress mocks base method.
+func (m *MockDatabase) UpdateImportJobProgress(arg0 context.Context, arg1 db.UpdateImportJobProgressParams) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetIfExistsSkillID", arg0, arg1)
-	ret0, _ := ret[0].(bool)
+	ret := m.ctrl.Call(m, "UpdateImportJobProgress", arg0, arg1)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetIfExistsSkillID indicates an expected call of GetIfExistsSkillID.
-func (mr *MockDatabaseMockRecorder) GetIfExistsSkillID(arg0, arg1 interface{}) *gomock.Call {
+// UpdateImportJobProgress indicates an expected call of UpdateImportJobProgress.
+func (mr *MockDatabaseMockRecorder) UpdateImportJobProgress(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIfExistsSkillID", reflect.TypeOf((*MockDatabase)(nil).GetIfExistsSkillID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateImportJobProgress", reflect.TypeOf((*MockDatabase)(nil).UpdateImportJobProgress), arg0, arg1)
 }
 
-// GetLastname mocks base method.
-func (m *MockDatabase) GetLastname(arg0 context.Context, arg1 int64) (string, error) {
+// UpdateSkill mocks base method.
+func (m *MockDatabase) UpdateSkill(arg0 context.Context, arg1 db.UpdateSkillParams) (db.Skill, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetLastname", arg0, arg1)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "UpdateSkill", arg0, arg1)
+	ret0, _ := ret[0].(db.Skill)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetLastname indicates an expected call of GetLastname.
-func (mr *MockDatabaseMockRecorder) GetLastname(arg0, arg1 interface{}) *gomock.Call {
+// UpdateSkill indicates an expected call of UpdateSkill.
+func (mr *MockDatabaseMockRecorder) UpdateSkill(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastname", reflect.TypeOf((*MockDatabase)(nil).GetLastname), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSkill", reflect.TypeOf((*MockDatabase)(nil).UpdateSkill), arg0, arg1)
 }
 
-// GetPhoneNumber mocks base method.
-func (m *MockDatabase) GetPhoneNumber(arg0 context.Context, arg1 int64) (string, error) {
+// UpdateUserPassword mocks base method.
+func (m *MockDatabase) UpdateUserPassword(arg0 context.Context, arg1 db.UpdateUserPasswordParams) (db.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPhoneNumber", arg0, arg1)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "UpdateUserPassword", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetPhoneNumber indicates an expected call of GetPhoneNumber.
-func (mr *MockDatabaseMockRecorder) GetPhoneNumber(arg0, arg1 interface{}) *gomock.Call {
+// UpdateUserPassword indicates an expected call of UpdateUserPassword.
+func (mr *MockDatabaseMockRecorder) UpdateUserPassword(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPhoneNumber", reflect.TypeOf((*MockDatabase)(nil).GetPhoneNumber), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserPassword", reflect.TypeOf((*MockDatabase)(nil).UpdateUserPassword), arg0, arg1)
 }
 
-// GetSession mocks base method.
-func (m *MockDatabase) GetSession(arg0 context.Context, arg1 uuid.UUID) (db.Session, error) {
+// UpdateUserPasswordHash mocks base method.
+func (m *MockDatabase) UpdateUserPasswordHash(arg0 context.Context, arg1 db.UpdateUserPasswordHashParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSession", arg0, arg1)
-	ret0, _ := ret[0].(db.Session)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "UpdateUserPasswordHash", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetSession indicates an expected call of GetSession.
-func (mr *MockDatabaseMockRecorder) GetSession(arg0, arg1 interface{}) *gomock.Call {
+// UpdateUserPasswordHash indicates an expected call of UpdateUserPasswordHash.
+func (mr *MockDatabaseMockRecorder) UpdateUserPasswordHash(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockDatabase)(nil).GetSession), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserPasswordHash", reflect.TypeOf((*MockDatabase)(nil).UpdateUserPasswordHash), arg0, arg1)
 }
 
-// GetSkill mocks base method.
-func (m *MockDatabase) GetSkill(arg0 context.Context, arg1 int64) (db.Skill, error) {
+// UpdateUserProfile mocks base method.
+func (m *MockDatabase) UpdateUserProfile(arg0 context.Context, arg1 db.UpdateUserProfileParams) (db.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSkill", arg0, arg1)
-	ret0, _ := ret[0].(db.Skill)
+	ret := m.ctrl.Call(m, "UpdateUserProfile", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetSkill indicates an expected call of GetSkill.
-func (mr *MockDatabaseMockRecorder) GetSkill(arg0, arg1 interface{}) *gomock.Call {
+// UpdateUserProfile indicates an expected call of UpdateUserProfile.
+func (mr *MockDatabaseMockRecorder) UpdateUserProfile(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSkill", reflect.TypeOf((*MockDatabase)(nil).GetSkill), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserProfile", reflect.TypeOf((*MockDatabase)(nil).UpdateUserProfile), arg0, arg1)
 }
 
-// GetSkillLevel mocks base method.
-func (m *MockDatabase) GetSkillLevel(arg0 context.Context, arg1 int64) (string, error) {
+// UseAPIKey mocks base method.
+func (m *MockDatabase) UseAPIKey(arg0 context.Context, arg1 string) (db.UseAPIKeyRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSkillLevel", arg0, arg1)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "UseAPIKey", arg0, arg1)
+	ret0, _ := ret[0].(db.UseAPIKeyRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetSkillLevel indicates an expected call of GetSkillLevel.
-func (mr *MockDatabaseMockRecorder) GetSkillLevel(arg0, arg1 interface{}) *gomock.Call {
+// UseAPIKey indicates an expected call of UseAPIKey.
+func (mr *MockDatabaseMockRecorder) UseAPIKey(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSkillLevel", reflect.TypeOf((*MockDatabase)(nil).GetSkillLevel), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseAPIKey", reflect.TypeOf((*MockDatabase)(nil).UseAPIKey), arg0, arg1)
 }
 
-// GetSkillName mocks base method.
-func (m *MockDatabase) GetSkillName(arg0 context.Context, arg1 int64) (string, error) {
+// UseOAuthClient mocks base method.
+func (m *MockDatabase) UseOAuthClient(arg0 context.Context, arg1 uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSkillName", arg0, arg1)
-	ret0, _ := ret[0].(string)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "UseOAuthClient", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// GetSkillName indicates an expected call of GetSkillName.
-func (mr *MockDatabaseMockRecorder) GetSkillName(arg0, arg1 interface{}) *gomock.Call {
+// UseOAuthClient indicates an expected call of UseOAuthClient.
+func (mr *MockDatabaseMockRecorder) UseOAuthClient(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSkillName", reflect.TypeOf((*MockDatabase)(nil).GetSkillName), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseOAuthClient", reflect.TypeOf((*MockDatabase)(nil).UseOAuthClient), arg0, arg1)
 }
 
-// GetUser mocks base method.
-func (m *MockDatabase) GetUser(arg0 context.Context, arg1 string) (db.User, error) {
+// UsePasswordReset mocks base method.
+func (m *MockDatabase) UsePasswordReset(arg0 context.Context, arg1 string) (db.PasswordReset, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUser", arg0, arg1)
-	ret0, _ := ret[0].(db.User)
+	ret := m.ctrl.Call(m, "UsePasswordReset", arg0, arg1)
+	ret0, _ := ret[0].(db.PasswordReset)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUser indicates an expected call of GetUser.
-func (mr *MockDatabaseMockRecorder) GetUser(arg0, arg1 interface{}) *gomock.Call {
+// UsePasswordReset indicates an expected call of UsePasswordReset.
+func (mr *MockDatabaseMockRecorder) UsePasswordReset(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockDatabase)(nil).GetUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UsePasswordReset", reflect.TypeOf((*MockDatabase)(nil).UsePasswordReset), arg0, arg1)
 }
 
-// ListContacts mocks base method.
-func (m *MockDatabase) ListContacts(arg0 context.Context, arg1 db.ListContactsParams) ([]db.Contact, error) {
+// UseRecoveryCode mocks base method.
+func (m *MockDatabase) UseRecoveryCode(arg0 context.Context, arg1 db.UseRecoveryCodeParams) (db.RecoveryCode, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListContacts", arg0, arg1)
-	ret0, _ := ret[0].([]db.Contact)
+	ret := m.ctrl.Call(m, "UseRecoveryCode", arg0, arg1)
+	ret0, _ := ret[0].(db.RecoveryCode)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListContacts indicates an expected call of ListContacts.
-func (mr *MockDatabaseMockRecorder) ListContacts(arg0, arg1 interface{}) *gomock.Call {
+// UseRecoveryCode indicates an expected call of UseRecoveryCode.
+func (mr *MockDatabaseMockRecorder) UseRecoveryCode(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListContacts", reflect.TypeOf((*MockDatabase)(nil).ListContacts), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRecoveryCode", reflect.TypeOf((*MockDatabase)(nil).UseRecoveryCode), arg0, arg1)
 }
 
-// ListSkills mocks base method.
-func (m *MockDatabase) ListSkills(arg0 context.Context, arg1 db.ListSkillsParams) ([]db.Skill, error) {
+// UseTOTPStep mocks base method.
+func (m *MockDatabase) UseTOTPStep(arg0 context.Context, arg1 db.UseTOTPStepParams) (db.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListSkills", arg0, arg1)
-	ret0, _ := ret[0].([]db.Skill)
+	ret := m.ctrl.Call(m, "UseTOTPStep", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListSkills indicates an expected call of ListSkills.
-func (mr *MockDatabaseMockRecorder) ListSkills(arg0, arg1 interface{}) *gomock.Call {
+// UseTOTPStep indicates an expected call of UseTOTPStep.
+func (mr *MockDatabaseMockRecorder) UseTOTPStep(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSkills", reflect.TypeOf((*MockDatabase)(nil).ListSkills), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseTOTPStep", reflect.TypeOf((*MockDatabase)(nil).UseTOTPStep), arg0, arg1)
 }
 
-// UpdateContact mocks base method.
-func (m *MockDatabase) UpdateContact(arg0 context.Context, arg1 db.UpdateContactParams) (db.Contact, error) {
+// UseTwoFactorChallengeAttempt mocks base method.
+func (m *MockDatabase) UseTwoFactorChallengeAttempt(arg0 context.Context, arg1 db.UseTwoFactorChallengeAttemptParams) (db.TwoFactorChallenge, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateContact", arg0, arg1)
-	ret0, _ := ret[0].(db.Contact)
+	ret := m.ctrl.Call(m, "UseTwoFactorChallengeAttempt", arg0, arg1)
+	ret0, _ := ret[0].(db.TwoFactorChallenge)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UpdateContact indicates an expected call of UpdateContact.
-func (mr *MockDatabaseMockRecorder) UpdateContact(arg0, arg1 interface{}) *gomock.Call {
+// UseTwoFactorChallengeAttempt indicates an expected call of UseTwoFactorChallengeAttempt.
+func (mr *MockDatabaseMockRecorder) UseTwoFactorChallengeAttempt(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateContact", reflect.TypeOf((*MockDatabase)(nil).UpdateContact), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseTwoFactorChallengeAttempt", reflect.TypeOf((*MockDatabase)(nil).UseTwoFactorChallengeAttempt), arg0, arg1)
 }
 
-// UpdateSkill mocks base method.
-func (m *MockDatabase) UpdateSkill(arg0 context.Context, arg1 db.UpdateSkillParams) (db.Skill, error) {
+// VerifyUserEmail mocks base method.
+func (m *MockDatabase) VerifyUserEmail(arg0 context.Context, arg1 db.VerifyUserEmailParams) (db.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateSkill", arg0, arg1)
-	ret0, _ := ret[0].(db.Skill)
+	ret := m.ctrl.Call(m, "VerifyUserEmail", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UpdateSkill indicates an expected call of UpdateSkill.
-func (mr *MockDatabaseMockRecorder) UpdateSkill(arg0, arg1 interface{}) *gomock.Call {
+// VerifyUserEmail indicates an expected call of VerifyUserEmail.
+func (mr *MockDatabaseMockRecorder) VerifyUserEmail(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSkill", reflect.TypeOf((*MockDatabase)(nil).UpdateSkill), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyUserEmail", reflect.TypeOf((*MockDatabase)(nil).VerifyUserEmail), arg0, arg1)
 }
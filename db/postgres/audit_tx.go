@@ -0,0 +1,25 @@
+package postgres
+
+import (
+	"context"
+
+	db "github.com/IsuruHaupe/web-api/db/sqlc"
+)
+
+// AuditTx runs the action with the queries of a transaction, then writes the audit record of the action in the
+// same transaction. If the action fails, the transaction is rolled back and its error is returned.
+func (postgres *PostgresDatabase) AuditTx(ctx context.Context, arg db.CreateAuditLogParams, action func(q db.Querier) error) (db.AuditLog, error) {
+	var auditLog db.AuditLog
+
+	err := postgres.execTx(ctx, func(q *db.Queries) error {
+		err := action(q)
+		if err != nil {
+			return err
+		}
+
+		auditLog, err = q.CreateAuditLog(ctx, arg)
+		return err
+	})
+
+	return auditLog, err
+}
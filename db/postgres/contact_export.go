@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/IsuruHaupe/web-api/db/database"
+	db "github.com/IsuruHaupe/web-api/db/sqlc"
+	"github.com/lib/pq"
+)
+
+// This query is written by hand because the code generated by sqlc reads all the rows before returning them, the export
+// must not hold all the contacts of an user in memory. Its columns are those of ListContactsWithSkills.
+const exportContacts = `
+SELECT c.id, c.owner, c.firstname, c.lastname, c.fullname, c.home_address, c.email, c.phone_number,
+  COALESCE(array_agg(s.skill_name ORDER BY s.skill_name, s.skill_level) FILTER (WHERE s.id IS NOT NULL), '{}')::varchar[] AS skill_names,
+  COALESCE(array_agg(s.skill_level ORDER BY s.skill_name, s.skill_level) FILTER (WHERE s.id IS NOT NULL), '{}')::varchar[] AS skill_levels
+FROM contacts c
+LEFT JOIN contact_has_skill chs ON chs.contact_id = c.id
+LEFT JOIN skills s ON s.id = chs.skill_id
+WHERE c.owner = $1
+  AND ($2::varchar = '' OR strpos(lower(c.fullname), lower($2)) > 0 OR strpos(lower(c.email), lower($2)) > 0)
+  AND (($3::varchar = '' AND $4::varchar = '') OR EXISTS (
+    SELECT 1 FROM contact_has_skill fchs
+    JOIN skills fs ON fs.id = fchs.skill_id
+    WHERE fchs.contact_id = c.id
+      AND ($3 = '' OR lower(fs.skill_name) = lower($3))
+      AND ($4 = '' OR lower(fs.skill_level) = lower($4))
+  ))
+GROUP BY c.id
+ORDER BY c.id
+`
+
+// ExportContacts scans the rows one at a time while they are read from the connection, which stays busy until the
+// export is done.
+func (postgres *PostgresDatabase) ExportContacts(ctx context.Context, arg database.ExportContactsParams, fn func(db.ListContactsWithSkillsRow) error) error {
+	rows, err := postgres.connection.QueryContext(ctx, exportContacts, arg.Owner, arg.Search, arg.SkillName, arg.SkillLevel)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var i db.ListContactsWithSkillsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Firstname,
+			&i.Lastname,
+			&i.Fullname,
+			&i.HomeAddress,
+			&i.Email,
+			&i.PhoneNumber,
+			pq.Array(&i.SkillNames),
+			pq.Array(&i.SkillLevels),
+		); err != nil {
+			return err
+		}
+		if err := fn(i); err != nil {
+			return err
+		}
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	return rows.Err()
+}
@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/IsuruHaupe/web-api/db/database"
+	db "github.com/IsuruHaupe/web-api/db/sqlc"
+)
+
+// MergeContactsTx writes the surviving values on the surviving contact, moves the skills of the merged contact that the
+// survivor doesn't already have and deletes the merged contact, the skills left behind are deleted with it.
+// If the merged contact doesn't exist or doesn't belong to the owner, sql.ErrNoRows is returned and nothing is changed.
+func (postgres *PostgresDatabase) MergeContactsTx(ctx context.Context, arg database.MergeContactsTxParams) (database.MergeContactsTxResult, error) {
+	var result database.MergeContactsTxResult
+
+	err := postgres.execTx(ctx, func(q *db.Queries) error {
+		var err error
+
+		result.Contact, err = q.UpdateContact(ctx, arg.Survivor)
+		if err != nil {
+			return err
+		}
+
+		result.MovedSkills, err = q.MoveContactSkills(ctx, db.MoveContactSkillsParams{
+			ToContactID:   int32(arg.Survivor.ID),
+			FromContactID: int32(arg.MergedID),
+		})
+		if err != nil {
+			return err
+		}
+
+		deleted, err := q.DeleteOwnedContact(ctx, db.DeleteOwnedContactParams{
+			ID:    arg.MergedID,
+			Owner: arg.Owner,
+		})
+		if err != nil {
+			return err
+		}
+		if deleted == 0 {
+			return sql.ErrNoRows
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+// ImportContactTx creates the contact and binds it to its skills, the skills the owner doesn't have yet are created.
+// If any of them fails, nothing is created.
+func (postgres *PostgresDatabase) ImportContactTx(ctx context.Context, arg database.ImportContactTxParams) (database.ImportContactTxResult, error) {
+	var result database.ImportContactTxResult
+
+	err := postgres.execTx(ctx, func(q *db.Queries) error {
+		var err error
+
+		result.Contact, err = q.CreateContact(ctx, arg.Contact)
+		if err != nil {
+			return err
+		}
+
+		result.Skills = make([]db.Skill, 0, len(arg.Skills))
+		for _, skillArg := range arg.Skills {
+			skill, err := q.GetOrCreateSkill(ctx, skillArg)
+			if err != nil {
+				return err
+			}
+
+			_, err = q.CreateContactHasSkill(ctx, db.CreateContactHasSkillParams{
+				Owner:     result.Contact.Owner,
+				ContactID: int32(result.Contact.ID),
+				SkillID:   int32(skill.ID),
+			})
+			if err != nil {
+				return err
+			}
+			result.Skills = append(result.Skills, skill)
+		}
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/IsuruHaupe/web-api/db/database"
+	db "github.com/IsuruHaupe/web-api/db/sqlc"
+)
+
+// ProvisionOIDCUserTx creates the user and links it to the identity it signed in with. The email is marked as
+// verified when the identity provider asserts it. If the username, the email or the identity is already used,
+// the unique violation is returned and nothing is created.
+func (postgres *PostgresDatabase) ProvisionOIDCUserTx(ctx context.Context, arg database.ProvisionOIDCUserTxParams) (db.User, error) {
+	var user db.User
+
+	err := postgres.execTx(ctx, func(q *db.Queries) error {
+		var err error
+
+		user, err = q.CreateUser(ctx, arg.User)
+		if err != nil {
+			return err
+		}
+
+		if arg.EmailVerified {
+			user, err = q.VerifyUserEmail(ctx, db.VerifyUserEmailParams{
+				Username: user.Username,
+				Email:    user.Email,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = q.CreateUserIdentity(ctx, db.CreateUserIdentityParams{
+			Issuer:   arg.Issuer,
+			Subject:  arg.Subject,
+			Username: user.Username,
+		})
+		return err
+	})
+
+	return user, err
+}
@@ -0,0 +1,28 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/IsuruHaupe/web-api/db/database"
+	db "github.com/IsuruHaupe/web-api/db/sqlc"
+)
+
+// RotateSessionTx marks the parent session as rotated and creates its successor.
+// If the parent session was already rotated, sql.ErrNoRows is returned and nothing is created.
+func (postgres *PostgresDatabase) RotateSessionTx(ctx context.Context, arg database.RotateSessionTxParams) (database.RotateSessionTxResult, error) {
+	var result database.RotateSessionTxResult
+
+	err := postgres.execTx(ctx, func(q *db.Queries) error {
+		var err error
+
+		result.ParentSession, err = q.RotateSession(ctx, arg.ParentID)
+		if err != nil {
+			return err
+		}
+
+		result.Session, err = q.CreateSession(ctx, arg.Session)
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/IsuruHaupe/web-api/db/database"
+	db "github.com/IsuruHaupe/web-api/db/sqlc"
+)
+
+// EnableTOTPTx turns on two-factor authentication with the secret saved at enrollment. The time step of the code
+// used to confirm the enrollment is recorded so that the code can not be used again to login. Previous recovery
+// codes are replaced by the new ones. If no secret has been saved, sql.ErrNoRows is returned and nothing is changed.
+func (postgres *PostgresDatabase) EnableTOTPTx(ctx context.Context, arg database.EnableTOTPTxParams) (db.User, error) {
+	var user db.User
+
+	err := postgres.execTx(ctx, func(q *db.Queries) error {
+		var err error
+
+		_, err = q.UseTOTPStep(ctx, db.UseTOTPStepParams{
+			Username:     arg.Username,
+			TotpLastStep: arg.TOTPStep,
+		})
+		if err != nil {
+			return err
+		}
+
+		user, err = q.EnableUserTOTP(ctx, arg.Username)
+		if err != nil {
+			return err
+		}
+
+		err = q.DeleteRecoveryCodes(ctx, arg.Username)
+		if err != nil {
+			return err
+		}
+
+		for _, hashedCode := range arg.HashedRecoveryCodes {
+			_, err = q.CreateRecoveryCode(ctx, db.CreateRecoveryCodeParams{
+				HashedCode: hashedCode,
+				Username:   arg.Username,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return user, err
+}
+
+// DisableTOTPTx turns off two-factor authentication, forgets the secret and deletes the recovery codes of the user.
+func (postgres *PostgresDatabase) DisableTOTPTx(ctx context.Context, username string) (db.User, error) {
+	var user db.User
+
+	err := postgres.execTx(ctx, func(q *db.Queries) error {
+		var err error
+
+		user, err = q.DisableUserTOTP(ctx, username)
+		if err != nil {
+			return err
+		}
+
+		return q.DeleteRecoveryCodes(ctx, username)
+	})
+
+	return user, err
+}
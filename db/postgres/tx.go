@@ -0,0 +1,27 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	db "github.com/IsuruHaupe/web-api/db/sqlc"
+)
+
+// execTx executes a function within a database transaction.
+func (postgres *PostgresDatabase) execTx(ctx context.Context, fn func(*db.Queries) error) error {
+	tx, err := postgres.connection.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	q := db.New(tx)
+	err = fn(q)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("tx err : %v, rb err : %v", err, rbErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
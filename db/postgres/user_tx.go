@@ -0,0 +1,57 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/IsuruHaupe/web-api/db/database"
+	db "github.com/IsuruHaupe/web-api/db/sqlc"
+)
+
+// ChangePasswordTx updates the password of the user and blocks every session created with the previous one.
+func (postgres *PostgresDatabase) ChangePasswordTx(ctx context.Context, arg db.UpdateUserPasswordParams) (db.User, error) {
+	var user db.User
+
+	err := postgres.execTx(ctx, func(q *db.Queries) error {
+		var err error
+
+		user, err = q.UpdateUserPassword(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		return q.BlockUserSessions(ctx, arg.Username)
+	})
+
+	return user, err
+}
+
+// ResetPasswordTx consumes the reset token, updates the password of its user and blocks every session.
+// Other reset tokens of the user are invalidated. If the token is unknown, expired or already used,
+// sql.ErrNoRows is returned and nothing is changed.
+func (postgres *PostgresDatabase) ResetPasswordTx(ctx context.Context, arg database.ResetPasswordTxParams) (db.User, error) {
+	var user db.User
+
+	err := postgres.execTx(ctx, func(q *db.Queries) error {
+		passwordReset, err := q.UsePasswordReset(ctx, arg.HashedToken)
+		if err != nil {
+			return err
+		}
+
+		user, err = q.UpdateUserPassword(ctx, db.UpdateUserPasswordParams{
+			Username:       passwordReset.Username,
+			HashedPassword: arg.HashedPassword,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = q.InvalidatePasswordResets(ctx, passwordReset.Username)
+		if err != nil {
+			return err
+		}
+
+		return q.BlockUserSessions(ctx, passwordReset.Username)
+	})
+
+	return user, err
+}
@@ -0,0 +1,37 @@
+-- name: ListUsers :many
+SELECT
+  users.username,
+  users.fullname,
+  users.email,
+  users.role,
+  users.email_verified,
+  users.totp_enabled,
+  users.disabled_at,
+  users.create_at,
+  (SELECT count(*) FROM contacts WHERE contacts.owner = users.username) AS contact_count,
+  (SELECT count(*) FROM skills WHERE skills.owner = users.username) AS skill_count
+FROM users
+WHERE sqlc.arg(search)::varchar = ''
+  OR users.username ILIKE '%' || sqlc.arg(search) || '%'
+  OR users.fullname ILIKE '%' || sqlc.arg(search) || '%'
+  OR users.email ILIKE '%' || sqlc.arg(search) || '%'
+ORDER BY users.username
+LIMIT sqlc.arg(limit_count)
+OFFSET sqlc.arg(offset_count);
+
+-- name: GetUserCounts :one
+SELECT
+  (SELECT count(*) FROM contacts WHERE contacts.owner = $1) AS contact_count,
+  (SELECT count(*) FROM skills WHERE skills.owner = $1) AS skill_count;
+
+-- name: DisableUser :one
+UPDATE users
+SET disabled_at = now()
+WHERE username = $1 AND disabled_at IS NULL
+RETURNING *;
+
+-- name: EnableUser :one
+UPDATE users
+SET disabled_at = NULL
+WHERE username = $1 AND disabled_at IS NOT NULL
+RETURNING *;
@@ -0,0 +1,30 @@
+-- name: CreateAPIKey :one
+INSERT INTO api_keys (
+  id,
+  username,
+  name,
+  prefix,
+  hashed_key,
+  scopes
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetAPIKey :one
+SELECT * FROM api_keys
+WHERE id = $1 LIMIT 1;
+
+-- name: ListAPIKeys :many
+SELECT * FROM api_keys
+WHERE username = $1
+ORDER BY created_at DESC;
+
+-- name: DeleteAPIKey :exec
+DELETE FROM api_keys WHERE id = $1;
+
+-- name: UseAPIKey :one
+UPDATE api_keys
+SET last_used_at = now()
+FROM users
+WHERE api_keys.hashed_key = $1 AND users.username = api_keys.username AND users.disabled_at IS NULL
+RETURNING api_keys.id, api_keys.username, api_keys.scopes, users.role;
@@ -0,0 +1,16 @@
+-- name: CreateAuditLog :one
+INSERT INTO audit_logs (
+  actor,
+  action,
+  target,
+  details
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: ListAuditLogs :many
+SELECT * FROM audit_logs
+WHERE sqlc.arg(target)::varchar = '' OR target = sqlc.arg(target)
+ORDER BY id DESC
+LIMIT sqlc.arg(limit_count)
+OFFSET sqlc.arg(offset_count);
@@ -95,3 +95,68 @@ WHERE id = $1 LIMIT 1;
 -- name: GetPhoneNumber :one
 SELECT phone_number FROM contacts
 WHERE id = $1 LIMIT 1;
+
+-- name: SearchContacts :many
+WITH search AS (
+  SELECT websearch_to_tsquery('simple', sqlc.arg(query)::text) AS query, lower(sqlc.arg(query)::text) AS text
+)
+SELECT c.id, c.owner, c.firstname, c.lastname, c.fullname, c.home_address, c.email, c.phone_number, (
+    ts_rank(contact_search_vector(c.firstname, c.lastname, c.fullname, c.email, c.phone_number, c.home_address), search.query) +
+    word_similarity(search.text, contact_search_text(c.firstname, c.lastname, c.fullname, c.email, c.phone_number, c.home_address))
+  )::real AS rank
+FROM contacts c, search
+WHERE c.owner = sqlc.arg(owner) AND (
+  contact_search_vector(c.firstname, c.lastname, c.fullname, c.email, c.phone_number, c.home_address) @@ search.query OR
+  search.text <% contact_search_text(c.firstname, c.lastname, c.fullname, c.email, c.phone_number, c.home_address)
+)
+ORDER BY rank DESC, c.id
+LIMIT sqlc.arg(limit_count)
+OFFSET sqlc.arg(offset_count);
+
+-- name: ListDuplicateContacts :many
+WITH candidates AS (
+  SELECT c1.id AS contact_id, c2.id AS duplicate_id
+  FROM contacts c1
+  JOIN contacts c2 ON c2.owner = c1.owner AND normalize_email(c2.email) = normalize_email(c1.email) AND c2.id > c1.id
+  WHERE c1.owner = sqlc.arg(owner)
+  UNION
+  SELECT c1.id, c2.id
+  FROM contacts c1
+  JOIN contacts c2 ON c2.owner = c1.owner AND normalize_phone(c2.phone_number) = normalize_phone(c1.phone_number) AND c2.id > c1.id
+  WHERE c1.owner = sqlc.arg(owner) AND length(normalize_phone(c1.phone_number)) >= 6
+  UNION
+  SELECT c1.id, c2.id
+  FROM contacts c1
+  JOIN contacts c2 ON lower(c2.fullname) % lower(c1.fullname) AND c2.owner = c1.owner AND c2.id > c1.id
+  WHERE c1.owner = sqlc.arg(owner)
+), pairs AS (
+  SELECT candidates.contact_id, candidates.duplicate_id,
+    normalize_email(c1.email) = normalize_email(c2.email) AS email_match,
+    (length(normalize_phone(c1.phone_number)) >= 6 AND normalize_phone(c1.phone_number) = normalize_phone(c2.phone_number)) AS phone_match,
+    similarity(lower(c1.fullname), lower(c2.fullname))::real AS name_similarity
+  FROM candidates
+  JOIN contacts c1 ON c1.id = candidates.contact_id
+  JOIN contacts c2 ON c2.id = candidates.duplicate_id
+)
+SELECT pairs.contact_id, pairs.duplicate_id, pairs.email_match, pairs.phone_match, pairs.name_similarity, (
+    0.4 * pairs.email_match::int + 0.3 * pairs.phone_match::int + 0.3 * pairs.name_similarity
+  )::real AS score
+FROM pairs
+WHERE (0.4 * pairs.email_match::int + 0.3 * pairs.phone_match::int + 0.3 * pairs.name_similarity) >= sqlc.arg(min_score)::real
+ORDER BY score DESC, pairs.contact_id, pairs.duplicate_id
+LIMIT sqlc.arg(limit_count)
+OFFSET sqlc.arg(offset_count);
+
+-- name: DeleteOwnedContact :execrows
+DELETE FROM contacts WHERE id = $1 AND owner = $2;
+
+-- name: ListContactsWithSkills :many
+SELECT c.id, c.owner, c.firstname, c.lastname, c.fullname, c.home_address, c.email, c.phone_number,
+  COALESCE(array_agg(s.skill_name ORDER BY s.skill_name, s.skill_level) FILTER (WHERE s.id IS NOT NULL), '{}')::varchar[] AS skill_names,
+  COALESCE(array_agg(s.skill_level ORDER BY s.skill_name, s.skill_level) FILTER (WHERE s.id IS NOT NULL), '{}')::varchar[] AS skill_levels
+FROM contacts c
+LEFT JOIN contact_has_skill chs ON chs.contact_id = c.id
+LEFT JOIN skills s ON s.id = chs.skill_id
+WHERE c.owner = $1
+GROUP BY c.id
+ORDER BY c.id;
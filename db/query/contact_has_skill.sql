@@ -6,4 +6,11 @@ INSERT INTO contact_has_skill (
 ) VALUES (
   $1, $2, $3
 )
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+
+-- name: MoveContactSkills :execrows
+UPDATE contact_has_skill
+SET contact_id = sqlc.arg(to_contact_id)
+WHERE contact_id = sqlc.arg(from_contact_id) AND skill_id NOT IN (
+  SELECT skill_id FROM contact_has_skill WHERE contact_id = sqlc.arg(to_contact_id)
+);
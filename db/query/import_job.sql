@@ -0,0 +1,60 @@
+-- name: CreateImportJob :one
+INSERT INTO import_jobs (
+  id,
+  owner,
+  dry_run,
+  total_rows
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: GetImportJob :one
+SELECT * FROM import_jobs
+WHERE id = $1 LIMIT 1;
+
+-- name: UpdateImportJobProgress :execrows
+UPDATE import_jobs
+SET processed_rows = $2,
+imported_rows = $3,
+failed_rows = $4,
+heartbeat_at = now()
+WHERE id = $1 AND status = 'running';
+
+-- name: KeepImportJobAlive :execrows
+UPDATE import_jobs
+SET heartbeat_at = now()
+WHERE id = $1 AND status = 'running';
+
+-- name: FinishImportJob :one
+UPDATE import_jobs
+SET processed_rows = $2,
+imported_rows = $3,
+failed_rows = $4,
+status = $5,
+error = $6,
+finished_at = now()
+WHERE id = $1 AND status = 'running'
+RETURNING *;
+
+-- name: CreateImportJobError :exec
+INSERT INTO import_job_errors (
+  job_id,
+  line,
+  message
+) VALUES (
+  $1, $2, $3
+);
+
+-- name: ListImportJobErrors :many
+SELECT line, message FROM import_job_errors
+WHERE job_id = $1
+ORDER BY line
+LIMIT $2;
+
+-- name: FailInterruptedImportJobs :execrows
+UPDATE import_jobs
+SET status = 'failed',
+error = sqlc.arg(error),
+finished_at = now()
+WHERE status = 'running' AND heartbeat_at < sqlc.arg(heartbeat_before);
@@ -0,0 +1,22 @@
+-- name: GetLoginAttempt :one
+SELECT * FROM login_attempts
+WHERE key = $1 LIMIT 1;
+
+-- name: RecordLoginFailure :one
+INSERT INTO login_attempts (
+  key,
+  failures,
+  last_failure_at
+) VALUES (
+  sqlc.arg(key), 1, now()
+)
+ON CONFLICT (key) DO UPDATE
+SET failures = CASE
+    WHEN login_attempts.last_failure_at < sqlc.arg(window_start)::timestamptz THEN 1
+    ELSE login_attempts.failures + 1
+  END,
+  last_failure_at = now()
+RETURNING *;
+
+-- name: DeleteLoginAttempt :exec
+DELETE FROM login_attempts WHERE key = $1;
@@ -0,0 +1,31 @@
+-- name: CreateOAuthClient :one
+INSERT INTO oauth_clients (
+  id,
+  name,
+  owner,
+  hashed_secret,
+  scopes
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetOAuthClient :one
+SELECT * FROM oauth_clients
+WHERE id = $1 LIMIT 1;
+
+-- name: ListOAuthClients :many
+SELECT * FROM oauth_clients
+ORDER BY created_at DESC;
+
+-- name: DeleteOAuthClient :execrows
+DELETE FROM oauth_clients WHERE id = $1;
+
+-- name: UseOAuthClient :exec
+UPDATE oauth_clients
+SET last_used_at = now()
+WHERE id = $1;
+
+-- name: GetActiveOAuthClientOwner :one
+SELECT oauth_clients.owner FROM oauth_clients
+JOIN users ON users.username = oauth_clients.owner
+WHERE oauth_clients.id = $1 AND users.disabled_at IS NULL;
@@ -0,0 +1,37 @@
+-- name: CreateOIDCLogin :one
+INSERT INTO oidc_logins (
+  hashed_state,
+  code_verifier,
+  nonce,
+  expires_at
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: ConsumeOIDCLogin :one
+DELETE FROM oidc_logins
+WHERE hashed_state = $1 AND expires_at > now()
+RETURNING *;
+
+-- name: DeleteExpiredOIDCLogins :exec
+DELETE FROM oidc_logins
+WHERE expires_at <= now();
+
+-- name: CreateUserIdentity :one
+INSERT INTO user_identities (
+  issuer,
+  subject,
+  username
+) VALUES (
+  $1, $2, $3
+)
+RETURNING *;
+
+-- name: GetUserByIdentity :one
+SELECT * FROM users
+WHERE username = (
+  SELECT username FROM user_identities
+  WHERE issuer = $1 AND subject = $2
+)
+LIMIT 1;
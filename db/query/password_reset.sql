@@ -0,0 +1,28 @@
+-- name: CreatePasswordReset :one
+INSERT INTO password_resets (
+  hashed_token,
+  username,
+  expires_at
+) VALUES (
+  $1, $2, $3
+) RETURNING *;
+
+-- name: GetPasswordReset :one
+SELECT * FROM password_resets
+WHERE hashed_token = $1 AND used_at IS NULL AND expires_at > now()
+LIMIT 1;
+
+-- name: UsePasswordReset :one
+UPDATE password_resets
+SET used_at = now()
+WHERE hashed_token = $1 AND used_at IS NULL AND expires_at > now()
+RETURNING *;
+
+-- name: InvalidatePasswordResets :exec
+UPDATE password_resets
+SET used_at = now()
+WHERE username = $1 AND used_at IS NULL;
+
+-- name: DeleteUnusedPasswordResets :exec
+DELETE FROM password_resets
+WHERE username = $1 AND used_at IS NULL;
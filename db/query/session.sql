@@ -6,9 +6,13 @@ INSERT INTO sessions (
   user_agent,
   client_ip,
   is_blocked,
-  expires_at
+  expires_at,
+  family_id,
+  parent_id,
+  ip_changed,
+  user_agent_changed
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
 ) RETURNING *;
 
 -- name: GetSession :one
@@ -16,4 +20,34 @@ SELECT * FROM sessions
 WHERE id = $1 LIMIT 1;
 
 -- name: DeleteSession :exec
-DELETE FROM sessions WHERE id = $1;
\ No newline at end of file
+DELETE FROM sessions WHERE id = $1;
+
+-- name: ListSessions :many
+SELECT s.id, s.user_agent, s.client_ip, s.is_blocked, s.expires_at, s.created_at, s.ip_changed, s.user_agent_changed,
+  COALESCE(f.created_at, s.created_at)::timestamptz AS family_created_at
+FROM sessions s
+LEFT JOIN sessions f ON f.id = s.family_id
+WHERE s.username = $1 AND s.is_blocked = false AND s.is_rotated = false AND s.expires_at > now()
+ORDER BY s.created_at DESC;
+
+-- name: BlockSession :one
+UPDATE sessions
+SET is_blocked = true
+WHERE id = $1
+RETURNING *;
+
+-- name: RotateSession :one
+UPDATE sessions
+SET is_rotated = true
+WHERE id = $1 AND is_rotated = false
+RETURNING *;
+
+-- name: BlockSessionFamily :exec
+UPDATE sessions
+SET is_blocked = true
+WHERE family_id = $1;
+
+-- name: BlockUserSessions :exec
+UPDATE sessions
+SET is_blocked = true
+WHERE username = $1;
@@ -39,4 +39,15 @@ WHERE id = $1
 RETURNING *;
 
 -- name: DeleteSkill :exec
-DELETE FROM skills WHERE id = $1;
\ No newline at end of file
+DELETE FROM skills WHERE id = $1;
+
+-- name: GetOrCreateSkill :one
+INSERT INTO skills (
+  owner,
+  skill_name,
+  skill_level
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT (owner, skill_name, skill_level) DO UPDATE SET skill_name = EXCLUDED.skill_name
+RETURNING *;
@@ -0,0 +1,62 @@
+-- name: SetUserTOTPSecret :one
+UPDATE users
+SET totp_secret = $2
+WHERE username = $1 AND totp_enabled = false
+RETURNING *;
+
+-- name: EnableUserTOTP :one
+UPDATE users
+SET totp_enabled = true
+WHERE username = $1 AND totp_secret IS NOT NULL
+RETURNING *;
+
+-- name: DisableUserTOTP :one
+UPDATE users
+SET totp_enabled = false, totp_secret = NULL, totp_last_step = 0
+WHERE username = $1
+RETURNING *;
+
+-- name: UseTOTPStep :one
+UPDATE users
+SET totp_last_step = $2
+WHERE username = $1 AND totp_last_step < $2
+RETURNING *;
+
+-- name: CreateRecoveryCode :one
+INSERT INTO recovery_codes (
+  hashed_code,
+  username
+) VALUES (
+  $1, $2
+) RETURNING *;
+
+-- name: UseRecoveryCode :one
+UPDATE recovery_codes
+SET used_at = now()
+WHERE hashed_code = $1 AND username = $2 AND used_at IS NULL
+RETURNING *;
+
+-- name: DeleteRecoveryCodes :exec
+DELETE FROM recovery_codes WHERE username = $1;
+
+-- name: CreateTwoFactorChallenge :one
+INSERT INTO two_factor_challenges (
+  hashed_token,
+  username,
+  expires_at
+) VALUES (
+  $1, $2, $3
+) RETURNING *;
+
+-- name: GetTwoFactorChallenge :one
+SELECT * FROM two_factor_challenges
+WHERE hashed_token = $1 LIMIT 1;
+
+-- name: UseTwoFactorChallengeAttempt :one
+UPDATE two_factor_challenges
+SET attempts = attempts + 1
+WHERE hashed_token = sqlc.arg(hashed_token) AND attempts < sqlc.arg(max_attempts) AND expires_at > now()
+RETURNING *;
+
+-- name: DeleteTwoFactorChallenge :execrows
+DELETE FROM two_factor_challenges WHERE hashed_token = $1;
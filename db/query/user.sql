@@ -3,9 +3,10 @@ INSERT INTO users (
   username, 
   hashed_password, 
   fullname, 
-  email
+  email,
+  email_verification_sent_at
 ) VALUES (
-  $1, $2, $3, $4 
+  $1, $2, $3, $4, now()
 )
 RETURNING *;
 
@@ -14,4 +15,49 @@ SELECT * FROM users
 WHERE username = $1 LIMIT 1;
 
 -- name: DeleteUser :exec
-DELETE FROM users WHERE username = $1;
\ No newline at end of file
+DELETE FROM users WHERE username = $1;
+
+-- name: UpdateUserPassword :one
+UPDATE users
+SET hashed_password = $2, password_last_changed = now()
+WHERE username = $1
+RETURNING *;
+
+-- name: UpdateUserPasswordHash :exec
+UPDATE users
+SET hashed_password = sqlc.arg(hashed_password)
+WHERE username = sqlc.arg(username) AND hashed_password = sqlc.arg(old_hashed_password)::varchar;
+
+-- name: UpdateUserProfile :one
+UPDATE users
+SET fullname = $2,
+  email = $3,
+  email_verified = (email_verified AND email = $3),
+  email_verification_sent_at = CASE WHEN email = $3 THEN email_verification_sent_at ELSE now() END
+WHERE username = $1
+RETURNING *;
+
+-- name: GetUserByEmail :one
+SELECT * FROM users
+WHERE email = $1 LIMIT 1;
+
+-- name: VerifyUserEmail :one
+UPDATE users
+SET email_verified = true
+WHERE username = $1 AND email = $2
+RETURNING *;
+
+-- name: MarkEmailVerificationSent :one
+UPDATE users
+SET email_verification_sent_at = now()
+WHERE email = sqlc.arg(email)
+  AND email_verified = false
+  AND (email_verification_sent_at IS NULL OR email_verification_sent_at < sqlc.arg(sent_before)::timestamptz)
+RETURNING *;
+
+-- name: MarkPasswordResetSent :one
+UPDATE users
+SET password_reset_sent_at = now()
+WHERE email = sqlc.arg(email)
+  AND (password_reset_sent_at IS NULL OR password_reset_sent_at < sqlc.arg(sent_before)::timestamptz)
+RETURNING *;
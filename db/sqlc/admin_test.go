@@ -0,0 +1,58 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListUsers(t *testing.T) {
+	user := CreateRandomUser(t)
+	CreateRandomContact(t, user)
+	CreateRandomContact(t, user)
+	CreateRandomSkill(t, user)
+
+	users, err := testQueries.ListUsers(context.Background(), ListUsersParams{
+		Search:      user.Email,
+		LimitCount:  5,
+		OffsetCount: 0,
+	})
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	require.Equal(t, user.Username, users[0].Username)
+	require.Equal(t, int64(2), users[0].ContactCount)
+	require.Equal(t, int64(1), users[0].SkillCount)
+
+	counts, err := testQueries.GetUserCounts(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), counts.ContactCount)
+	require.Equal(t, int64(1), counts.SkillCount)
+}
+
+func TestAuditLogs(t *testing.T) {
+	admin := CreateRandomUser(t)
+	user := CreateRandomUser(t)
+
+	arg := CreateAuditLogParams{
+		Actor:  admin.Username,
+		Action: "user.disable",
+		Target: user.Username,
+	}
+	auditLog, err := testQueries.CreateAuditLog(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, auditLog.ID)
+	require.Equal(t, arg.Actor, auditLog.Actor)
+	require.Equal(t, arg.Action, auditLog.Action)
+	require.Equal(t, arg.Target, auditLog.Target)
+	require.NotZero(t, auditLog.CreatedAt)
+
+	auditLogs, err := testQueries.ListAuditLogs(context.Background(), ListAuditLogsParams{
+		Target:      user.Username,
+		LimitCount:  5,
+		OffsetCount: 0,
+	})
+	require.NoError(t, err)
+	require.Len(t, auditLogs, 1)
+	require.Equal(t, auditLog.ID, auditLogs[0].ID)
+}
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/IsuruHaupe/web-api/auth"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomAPIKey(t *testing.T, username string) ApiKey {
+	_, prefix, hashedKey, err := auth.GenerateAPIKey()
+	require.NoError(t, err)
+
+	arg := CreateAPIKeyParams{
+		ID:        uuid.New(),
+		Username:  username,
+		Name:      auth.RandomString(8),
+		Prefix:    prefix,
+		HashedKey: hashedKey,
+		Scopes:    []string{"contacts:read", "skills:write"},
+	}
+
+	apiKey, err := testQueries.CreateAPIKey(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, apiKey)
+
+	require.Equal(t, arg.ID, apiKey.ID)
+	require.Equal(t, arg.Username, apiKey.Username)
+	require.Equal(t, arg.Name, apiKey.Name)
+	require.Equal(t, arg.Prefix, apiKey.Prefix)
+	require.Equal(t, arg.HashedKey, apiKey.HashedKey)
+	require.Equal(t, arg.Scopes, apiKey.Scopes)
+	require.False(t, apiKey.LastUsedAt.Valid)
+	require.NotZero(t, apiKey.CreatedAt)
+
+	return apiKey
+}
+
+func TestCreateAPIKey(t *testing.T) {
+	user := CreateRandomUser(t)
+	apiKey := createRandomAPIKey(t, user.Username)
+
+	err := testQueries.DeleteAPIKey(context.Background(), apiKey.ID)
+	require.NoError(t, err)
+}
+
+func TestListAPIKeys(t *testing.T) {
+	user := CreateRandomUser(t)
+	for i := 0; i < 3; i++ {
+		createRandomAPIKey(t, user.Username)
+	}
+
+	apiKeys, err := testQueries.ListAPIKeys(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Len(t, apiKeys, 3)
+	for _, apiKey := range apiKeys {
+		require.Equal(t, user.Username, apiKey.Username)
+	}
+}
+
+func TestUseAPIKey(t *testing.T) {
+	user := CreateRandomUser(t)
+	apiKey1 := createRandomAPIKey(t, user.Username)
+
+	row, err := testQueries.UseAPIKey(context.Background(), apiKey1.HashedKey)
+	require.NoError(t, err)
+	require.Equal(t, apiKey1.ID, row.ID)
+	require.Equal(t, user.Username, row.Username)
+	require.Equal(t, apiKey1.Scopes, row.Scopes)
+	require.Equal(t, user.Role, row.Role)
+
+	apiKey2, err := testQueries.GetAPIKey(context.Background(), apiKey1.ID)
+	require.NoError(t, err)
+	require.True(t, apiKey2.LastUsedAt.Valid)
+
+	err = testQueries.DeleteAPIKey(context.Background(), apiKey1.ID)
+	require.NoError(t, err)
+
+	_, err = testQueries.UseAPIKey(context.Background(), apiKey1.HashedKey)
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+}
@@ -5,6 +5,8 @@ package db
 
 import (
 	"context"
+
+	"github.com/lib/pq"
 )
 
 const createContact = `-- name: CreateContact :one
@@ -65,6 +67,23 @@ func (q *Queries) DeleteContact(ctx context.Context, id int64) error {
 	return err
 }
 
+const deleteOwnedContact = `-- name: DeleteOwnedContact :execrows
+DELETE FROM contacts WHERE id = $1 AND owner = $2
+`
+
+type DeleteOwnedContactParams struct {
+	ID    int64  `json:"id"`
+	Owner string `json:"owner"`
+}
+
+func (q *Queries) DeleteOwnedContact(ctx context.Context, arg DeleteOwnedContactParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteOwnedContact, arg.ID, arg.Owner)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const getContact = `-- name: GetContact :one
 SELECT id, owner, firstname, lastname, fullname, home_address, email, phone_number FROM contacts
 WHERE id = $1 LIMIT 1
@@ -347,6 +366,226 @@ func (q *Queries) ListContacts(ctx context.Context, arg ListContactsParams) ([]C
 	return items, nil
 }
 
+const listContactsWithSkills = `-- name: ListContactsWithSkills :many
+SELECT c.id, c.owner, c.firstname, c.lastname, c.fullname, c.home_address, c.email, c.phone_number,
+  COALESCE(array_agg(s.skill_name ORDER BY s.skill_name, s.skill_level) FILTER (WHERE s.id IS NOT NULL), '{}')::varchar[] AS skill_names,
+  COALESCE(array_agg(s.skill_level ORDER BY s.skill_name, s.skill_level) FILTER (WHERE s.id IS NOT NULL), '{}')::varchar[] AS skill_levels
+FROM contacts c
+LEFT JOIN contact_has_skill chs ON chs.contact_id = c.id
+LEFT JOIN skills s ON s.id = chs.skill_id
+WHERE c.owner = $1
+GROUP BY c.id
+ORDER BY c.id
+`
+
+type ListContactsWithSkillsRow struct {
+	ID          int64    `json:"id"`
+	Owner       string   `json:"owner"`
+	Firstname   string   `json:"firstname"`
+	Lastname    string   `json:"lastname"`
+	Fullname    string   `json:"fullname"`
+	HomeAddress string   `json:"home_address"`
+	Email       string   `json:"email"`
+	PhoneNumber string   `json:"phone_number"`
+	SkillNames  []string `json:"skill_names"`
+	SkillLevels []string `json:"skill_levels"`
+}
+
+func (q *Queries) ListContactsWithSkills(ctx context.Context, owner string) ([]ListContactsWithSkillsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listContactsWithSkills, owner)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListContactsWithSkillsRow{}
+	for rows.Next() {
+		var i ListContactsWithSkillsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Firstname,
+			&i.Lastname,
+			&i.Fullname,
+			&i.HomeAddress,
+			&i.Email,
+			&i.PhoneNumber,
+			pq.Array(&i.SkillNames),
+			pq.Array(&i.SkillLevels),
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDuplicateContacts = `-- name: ListDuplicateContacts :many
+WITH candidates AS (
+  SELECT c1.id AS contact_id, c2.id AS duplicate_id
+  FROM contacts c1
+  JOIN contacts c2 ON c2.owner = c1.owner AND normalize_email(c2.email) = normalize_email(c1.email) AND c2.id > c1.id
+  WHERE c1.owner = $1
+  UNION
+  SELECT c1.id, c2.id
+  FROM contacts c1
+  JOIN contacts c2 ON c2.owner = c1.owner AND normalize_phone(c2.phone_number) = normalize_phone(c1.phone_number) AND c2.id > c1.id
+  WHERE c1.owner = $1 AND length(normalize_phone(c1.phone_number)) >= 6
+  UNION
+  SELECT c1.id, c2.id
+  FROM contacts c1
+  JOIN contacts c2 ON lower(c2.fullname) % lower(c1.fullname) AND c2.owner = c1.owner AND c2.id > c1.id
+  WHERE c1.owner = $1
+), pairs AS (
+  SELECT candidates.contact_id, candidates.duplicate_id,
+    normalize_email(c1.email) = normalize_email(c2.email) AS email_match,
+    (length(normalize_phone(c1.phone_number)) >= 6 AND normalize_phone(c1.phone_number) = normalize_phone(c2.phone_number)) AS phone_match,
+    similarity(lower(c1.fullname), lower(c2.fullname))::real AS name_similarity
+  FROM candidates
+  JOIN contacts c1 ON c1.id = candidates.contact_id
+  JOIN contacts c2 ON c2.id = candidates.duplicate_id
+)
+SELECT pairs.contact_id, pairs.duplicate_id, pairs.email_match, pairs.phone_match, pairs.name_similarity, (
+    0.4 * pairs.email_match::int + 0.3 * pairs.phone_match::int + 0.3 * pairs.name_similarity
+  )::real AS score
+FROM pairs
+WHERE (0.4 * pairs.email_match::int + 0.3 * pairs.phone_match::int + 0.3 * pairs.name_similarity) >= $2::real
+ORDER BY score DESC, pairs.contact_id, pairs.duplicate_id
+LIMIT $3
+OFFSET $4
+`
+
+type ListDuplicateContactsParams struct {
+	Owner       string  `json:"owner"`
+	MinScore    float32 `json:"min_score"`
+	LimitCount  int32   `json:"limit_count"`
+	OffsetCount int32   `json:"offset_count"`
+}
+
+type ListDuplicateContactsRow struct {
+	ContactID      int64   `json:"contact_id"`
+	DuplicateID    int64   `json:"duplicate_id"`
+	EmailMatch     bool    `json:"email_match"`
+	PhoneMatch     bool    `json:"phone_match"`
+	NameSimilarity float32 `json:"name_similarity"`
+	Score          float32 `json:"score"`
+}
+
+func (q *Queries) ListDuplicateContacts(ctx context.Context, arg ListDuplicateContactsParams) ([]ListDuplicateContactsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listDuplicateContacts,
+		arg.Owner,
+		arg.MinScore,
+		arg.LimitCount,
+		arg.OffsetCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListDuplicateContactsRow{}
+	for rows.Next() {
+		var i ListDuplicateContactsRow
+		if err := rows.Scan(
+			&i.ContactID,
+			&i.DuplicateID,
+			&i.EmailMatch,
+			&i.PhoneMatch,
+			&i.NameSimilarity,
+			&i.Score,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchContacts = `-- name: SearchContacts :many
+WITH search AS (
+  SELECT websearch_to_tsquery('simple', $1::text) AS query, lower($1::text) AS text
+)
+SELECT c.id, c.owner, c.firstname, c.lastname, c.fullname, c.home_address, c.email, c.phone_number, (
+    ts_rank(contact_search_vector(c.firstname, c.lastname, c.fullname, c.email, c.phone_number, c.home_address), search.query) +
+    word_similarity(search.text, contact_search_text(c.firstname, c.lastname, c.fullname, c.email, c.phone_number, c.home_address))
+  )::real AS rank
+FROM contacts c, search
+WHERE c.owner = $2 AND (
+  contact_search_vector(c.firstname, c.lastname, c.fullname, c.email, c.phone_number, c.home_address) @@ search.query OR
+  search.text <% contact_search_text(c.firstname, c.lastname, c.fullname, c.email, c.phone_number, c.home_address)
+)
+ORDER BY rank DESC, c.id
+LIMIT $3
+OFFSET $4
+`
+
+type SearchContactsParams struct {
+	Query       string `json:"query"`
+	Owner       string `json:"owner"`
+	LimitCount  int32  `json:"limit_count"`
+	OffsetCount int32  `json:"offset_count"`
+}
+
+type SearchContactsRow struct {
+	ID          int64   `json:"id"`
+	Owner       string  `json:"owner"`
+	Firstname   string  `json:"firstname"`
+	Lastname    string  `json:"lastname"`
+	Fullname    string  `json:"fullname"`
+	HomeAddress string  `json:"home_address"`
+	Email       string  `json:"email"`
+	PhoneNumber string  `json:"phone_number"`
+	Rank        float32 `json:"rank"`
+}
+
+func (q *Queries) SearchContacts(ctx context.Context, arg SearchContactsParams) ([]SearchContactsRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchContacts,
+		arg.Query,
+		arg.Owner,
+		arg.LimitCount,
+		arg.OffsetCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SearchContactsRow{}
+	for rows.Next() {
+		var i SearchContactsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Firstname,
+			&i.Lastname,
+			&i.Fullname,
+			&i.HomeAddress,
+			&i.Email,
+			&i.PhoneNumber,
+			&i.Rank,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateContact = `-- name: UpdateContact :one
 UPDATE contacts 
 SET firstname = $2, 
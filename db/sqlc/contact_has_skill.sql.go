@@ -30,3 +30,24 @@ func (q *Queries) CreateContactHasSkill(ctx context.Context, arg CreateContactHa
 	err := row.Scan(&i.Owner, &i.ContactID, &i.SkillID)
 	return i, err
 }
+
+const moveContactSkills = `-- name: MoveContactSkills :execrows
+UPDATE contact_has_skill
+SET contact_id = $1
+WHERE contact_id = $2 AND skill_id NOT IN (
+  SELECT skill_id FROM contact_has_skill WHERE contact_id = $1
+)
+`
+
+type MoveContactSkillsParams struct {
+	ToContactID   int32 `json:"to_contact_id"`
+	FromContactID int32 `json:"from_contact_id"`
+}
+
+func (q *Queries) MoveContactSkills(ctx context.Context, arg MoveContactSkillsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, moveContactSkills, arg.ToContactID, arg.FromContactID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -29,3 +29,43 @@ func TestCreateContactHasSkill(t *testing.T) {
 	err = testQueries.DeleteSkill(context.Background(), skill.ID)
 	require.NoError(t, err)
 }
+
+func TestMoveContactSkills(t *testing.T) {
+	user := CreateRandomUser(t)
+	from := CreateRandomContact(t, user)
+	to := CreateRandomContact(t, user)
+	// The skills are named explicitly, two random skills could be the same and break the unique constraint.
+	shared, err := testQueries.CreateSkill(context.Background(), CreateSkillParams{
+		Owner:      user.Username,
+		SkillName:  "Go",
+		SkillLevel: "expert",
+	})
+	require.NoError(t, err)
+	moved, err := testQueries.CreateSkill(context.Background(), CreateSkillParams{
+		Owner:      user.Username,
+		SkillName:  "SQL",
+		SkillLevel: "beginner",
+	})
+	require.NoError(t, err)
+
+	for _, arg := range []CreateContactHasSkillParams{
+		{Owner: user.Username, ContactID: int32(from.ID), SkillID: int32(shared.ID)},
+		{Owner: user.Username, ContactID: int32(from.ID), SkillID: int32(moved.ID)},
+		{Owner: user.Username, ContactID: int32(to.ID), SkillID: int32(shared.ID)},
+	} {
+		_, err = testQueries.CreateContactHasSkill(context.Background(), arg)
+		require.NoError(t, err)
+	}
+
+	// The skill the contact already has is not moved, it would break the unique constraint.
+	rows, err := testQueries.MoveContactSkills(context.Background(), MoveContactSkillsParams{
+		ToContactID:   int32(to.ID),
+		FromContactID: int32(from.ID),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), rows)
+
+	skills, err := testQueries.GetContactSkills(context.Background(), int32(to.ID))
+	require.NoError(t, err)
+	require.Len(t, skills, 2)
+}
@@ -251,3 +251,179 @@ func TestGetContactSkills(t *testing.T) {
 	err = testQueries.DeleteUser(context.Background(), user.Username)
 	require.NoError(t, err)
 }
+
+func TestSearchContacts(t *testing.T) {
+	user := CreateRandomUser(t)
+	other := CreateRandomUser(t)
+
+	createContact := func(owner User, firstname, lastname, email, address string) Contact {
+		contact, err := testQueries.CreateContact(context.Background(), CreateContactParams{
+			Owner:       owner.Username,
+			Firstname:   firstname,
+			Lastname:    lastname,
+			Fullname:    firstname + " " + lastname,
+			HomeAddress: address,
+			Email:       email,
+			PhoneNumber: randomdata.PhoneNumber(),
+		})
+		require.NoError(t, err)
+		return contact
+	}
+
+	byName := createContact(user, "Ada", "Lovelace", "ada@example.com", "12 Square, London")
+	byAddress := createContact(user, "Grace", "Hopper", "grace@example.com", "3 Lovelace Street, Arlington")
+	createContact(other, "Ada", "Lovelace", "ada@example.org", "12 Square, London")
+
+	arg := SearchContactsParams{
+		Query:       "lovelace",
+		Owner:       user.Username,
+		LimitCount:  10,
+		OffsetCount: 0,
+	}
+	rows, err := testQueries.SearchContacts(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+
+	// A match on the name is ranked before a match on the address.
+	require.Equal(t, byName.ID, rows[0].ID)
+	require.Equal(t, byAddress.ID, rows[1].ID)
+	require.Greater(t, rows[0].Rank, rows[1].Rank)
+	for _, row := range rows {
+		require.Equal(t, user.Username, row.Owner)
+	}
+
+	// Typos are tolerated.
+	arg.Query = "lovlace"
+	rows, err = testQueries.SearchContacts(context.Background(), arg)
+	require.NoError(t, err)
+	ids := make([]int64, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.ID)
+	}
+	require.Contains(t, ids, byName.ID)
+}
+
+func TestListDuplicateContacts(t *testing.T) {
+	user := CreateRandomUser(t)
+	other := CreateRandomUser(t)
+
+	createContact := func(owner User, fullname, email, phoneNumber string) Contact {
+		contact, err := testQueries.CreateContact(context.Background(), CreateContactParams{
+			Owner:       owner.Username,
+			Firstname:   randomdata.FirstName(randomdata.Female),
+			Lastname:    randomdata.LastName(),
+			Fullname:    fullname,
+			HomeAddress: randomdata.Address(),
+			Email:       email,
+			PhoneNumber: phoneNumber,
+		})
+		require.NoError(t, err)
+		return contact
+	}
+
+	contact := createContact(user, "Ada Lovelace", "ada@example.com", "+44 20 7946 0000")
+	duplicate := createContact(user, "Ada Lovelace", " ADA@example.com", "+442079460000")
+	createContact(user, "Grace Hopper", "grace@example.com", "+1 703 555 0100")
+	createContact(other, "Ada Lovelace", "ada@example.com", "+44 20 7946 0000")
+
+	pairs, err := testQueries.ListDuplicateContacts(context.Background(), ListDuplicateContactsParams{
+		Owner:       user.Username,
+		MinScore:    0.3,
+		LimitCount:  10,
+		OffsetCount: 0,
+	})
+	require.NoError(t, err)
+	require.Len(t, pairs, 1)
+
+	require.Equal(t, contact.ID, pairs[0].ContactID)
+	require.Equal(t, duplicate.ID, pairs[0].DuplicateID)
+	require.True(t, pairs[0].EmailMatch)
+	require.True(t, pairs[0].PhoneMatch)
+	require.InDelta(t, 1, pairs[0].NameSimilarity, 0.001)
+	require.InDelta(t, 1, pairs[0].Score, 0.001)
+
+	// Pairs sharing only an email, a phone number or a name are found as well.
+	byEmail := [2]int64{
+		createContact(user, "Jean Dupont", "jean@example.com", "06 00 00 00 01").ID,
+		createContact(user, "Marie Curie", "JEAN@example.com", "07 00 00 00 02").ID,
+	}
+	byPhone := [2]int64{
+		createContact(user, "Pierre Martin", "pierre@example.com", "+33 6 12 34 56 78").ID,
+		createContact(user, "Paul Bernard", "paul@example.com", "+33612345678").ID,
+	}
+	byName := [2]int64{
+		createContact(user, "Charles Babbage", "charles@example.com", "+44 20 7946 0001").ID,
+		createContact(user, "Charles Babbage", "babbage@example.com", "+44 20 7946 0002").ID,
+	}
+
+	pairs, err = testQueries.ListDuplicateContacts(context.Background(), ListDuplicateContactsParams{
+		Owner:       user.Username,
+		MinScore:    0.3,
+		LimitCount:  10,
+		OffsetCount: 0,
+	})
+	require.NoError(t, err)
+	require.Len(t, pairs, 4)
+
+	found := make([][2]int64, len(pairs))
+	for i, pair := range pairs {
+		found[i] = [2]int64{pair.ContactID, pair.DuplicateID}
+	}
+	require.ElementsMatch(t, [][2]int64{{contact.ID, duplicate.ID}, byEmail, byPhone, byName}, found)
+
+	page, err := testQueries.ListDuplicateContacts(context.Background(), ListDuplicateContactsParams{
+		Owner:       user.Username,
+		MinScore:    0.3,
+		LimitCount:  2,
+		OffsetCount: 2,
+	})
+	require.NoError(t, err)
+	require.Equal(t, pairs[2:], page)
+}
+
+func TestDeleteOwnedContact(t *testing.T) {
+	user := CreateRandomUser(t)
+	other := CreateRandomUser(t)
+	contact := CreateRandomContact(t, user)
+
+	rows, err := testQueries.DeleteOwnedContact(context.Background(), DeleteOwnedContactParams{
+		ID:    contact.ID,
+		Owner: other.Username,
+	})
+	require.NoError(t, err)
+	require.Zero(t, rows)
+
+	rows, err = testQueries.DeleteOwnedContact(context.Background(), DeleteOwnedContactParams{
+		ID:    contact.ID,
+		Owner: user.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), rows)
+}
+
+func TestListContactsWithSkills(t *testing.T) {
+	user := CreateRandomUser(t)
+	contact1 := CreateRandomContact(t, user)
+	contact2 := CreateRandomContact(t, user)
+	skill := CreateRandomSkill(t, user)
+
+	_, err := testQueries.CreateContactHasSkill(context.Background(), CreateContactHasSkillParams{
+		Owner:     user.Username,
+		ContactID: int32(contact1.ID),
+		SkillID:   int32(skill.ID),
+	})
+	require.NoError(t, err)
+
+	rows, err := testQueries.ListContactsWithSkills(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+
+	require.Equal(t, contact1.ID, rows[0].ID)
+	require.Equal(t, contact1.Email, rows[0].Email)
+	require.Equal(t, []string{skill.SkillName}, rows[0].SkillNames)
+	require.Equal(t, []string{skill.SkillLevel}, rows[0].SkillLevels)
+
+	require.Equal(t, contact2.ID, rows[1].ID)
+	require.Empty(t, rows[1].SkillNames)
+	require.Empty(t, rows[1].SkillLevels)
+}
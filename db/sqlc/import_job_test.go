@@ -0,0 +1,173 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomImportJob(t *testing.T, owner string) ImportJob {
+	arg := CreateImportJobParams{
+		ID:        uuid.New(),
+		Owner:     owner,
+		DryRun:    false,
+		TotalRows: 600,
+	}
+
+	job, err := testQueries.CreateImportJob(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, job)
+
+	require.Equal(t, arg.ID, job.ID)
+	require.Equal(t, arg.Owner, job.Owner)
+	require.Equal(t, arg.DryRun, job.DryRun)
+	require.Equal(t, arg.TotalRows, job.TotalRows)
+	require.Equal(t, "running", job.Status)
+	require.Zero(t, job.ProcessedRows)
+	require.False(t, job.FinishedAt.Valid)
+	require.NotZero(t, job.CreatedAt)
+
+	return job
+}
+
+func TestCreateImportJob(t *testing.T) {
+	user := CreateRandomUser(t)
+	job1 := createRandomImportJob(t, user.Username)
+
+	job2, err := testQueries.GetImportJob(context.Background(), job1.ID)
+	require.NoError(t, err)
+	require.Equal(t, job1, job2)
+}
+
+func TestImportJobProgress(t *testing.T) {
+	user := CreateRandomUser(t)
+	job1 := createRandomImportJob(t, user.Username)
+
+	updated, err := testQueries.UpdateImportJobProgress(context.Background(), UpdateImportJobProgressParams{
+		ID:            job1.ID,
+		ProcessedRows: 100,
+		ImportedRows:  99,
+		FailedRows:    1,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), updated)
+
+	job2, err := testQueries.GetImportJob(context.Background(), job1.ID)
+	require.NoError(t, err)
+	require.Equal(t, int32(100), job2.ProcessedRows)
+	require.Equal(t, int32(99), job2.ImportedRows)
+	require.Equal(t, int32(1), job2.FailedRows)
+	require.Equal(t, "running", job2.Status)
+
+	job3, err := testQueries.FinishImportJob(context.Background(), FinishImportJobParams{
+		ID:            job1.ID,
+		ProcessedRows: 600,
+		ImportedRows:  598,
+		FailedRows:    2,
+		Status:        "completed",
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(600), job3.ProcessedRows)
+	require.Equal(t, "completed", job3.Status)
+	require.Empty(t, job3.Error)
+	require.True(t, job3.FinishedAt.Valid)
+
+	// A finished job is not updated anymore.
+	updated, err = testQueries.UpdateImportJobProgress(context.Background(), UpdateImportJobProgressParams{
+		ID:            job1.ID,
+		ProcessedRows: 200,
+	})
+	require.NoError(t, err)
+	require.Zero(t, updated)
+
+	_, err = testQueries.FinishImportJob(context.Background(), FinishImportJobParams{
+		ID:     job1.ID,
+		Status: "failed",
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	updated, err = testQueries.KeepImportJobAlive(context.Background(), job1.ID)
+	require.NoError(t, err)
+	require.Zero(t, updated)
+}
+
+func TestKeepImportJobAlive(t *testing.T) {
+	user := CreateRandomUser(t)
+	job1 := createRandomImportJob(t, user.Username)
+
+	updated, err := testQueries.KeepImportJobAlive(context.Background(), job1.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), updated)
+
+	job2, err := testQueries.GetImportJob(context.Background(), job1.ID)
+	require.NoError(t, err)
+	require.False(t, job2.HeartbeatAt.Before(job1.HeartbeatAt))
+}
+
+func TestImportJobErrors(t *testing.T) {
+	user := CreateRandomUser(t)
+	job := createRandomImportJob(t, user.Username)
+
+	for _, line := range []int32{12, 3, 7} {
+		err := testQueries.CreateImportJobError(context.Background(), CreateImportJobErrorParams{
+			JobID:   job.ID,
+			Line:    line,
+			Message: "invalid email",
+		})
+		require.NoError(t, err)
+	}
+
+	rowErrors, err := testQueries.ListImportJobErrors(context.Background(), ListImportJobErrorsParams{
+		JobID: job.ID,
+		Limit: 2,
+	})
+	require.NoError(t, err)
+	require.Len(t, rowErrors, 2)
+	require.Equal(t, int32(3), rowErrors[0].Line)
+	require.Equal(t, int32(7), rowErrors[1].Line)
+	require.Equal(t, "invalid email", rowErrors[0].Message)
+}
+
+func TestFailInterruptedImportJobs(t *testing.T) {
+	user := CreateRandomUser(t)
+	interrupted := createRandomImportJob(t, user.Username)
+	finished := createRandomImportJob(t, user.Username)
+	_, err := testQueries.FinishImportJob(context.Background(), FinishImportJobParams{
+		ID:     finished.ID,
+		Status: "completed",
+	})
+	require.NoError(t, err)
+
+	failed, err := testQueries.FailInterruptedImportJobs(context.Background(), FailInterruptedImportJobsParams{
+		Error:           "interrupted",
+		HeartbeatBefore: time.Now().Add(time.Second),
+	})
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, failed, int64(1))
+
+	job, err := testQueries.GetImportJob(context.Background(), interrupted.ID)
+	require.NoError(t, err)
+	require.Equal(t, "failed", job.Status)
+	require.Equal(t, "interrupted", job.Error)
+	require.True(t, job.FinishedAt.Valid)
+
+	job, err = testQueries.GetImportJob(context.Background(), finished.ID)
+	require.NoError(t, err)
+	require.Equal(t, "completed", job.Status)
+
+	// The jobs kept alive since are left running.
+	running := createRandomImportJob(t, user.Username)
+	_, err = testQueries.FailInterruptedImportJobs(context.Background(), FailInterruptedImportJobsParams{
+		Error:           "interrupted",
+		HeartbeatBefore: running.HeartbeatAt.Add(-time.Second),
+	})
+	require.NoError(t, err)
+
+	job, err = testQueries.GetImportJob(context.Background(), running.ID)
+	require.NoError(t, err)
+	require.Equal(t, "running", job.Status)
+}
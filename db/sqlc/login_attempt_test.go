@@ -0,0 +1,44 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/IsuruHaupe/web-api/auth"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordLoginFailure(t *testing.T) {
+	key := "user:" + auth.RandomString(8)
+
+	for i := 1; i <= 3; i++ {
+		loginAttempt, err := testQueries.RecordLoginFailure(context.Background(), RecordLoginFailureParams{
+			Key:         key,
+			WindowStart: time.Now().Add(-time.Hour),
+		})
+		require.NoError(t, err)
+		require.Equal(t, key, loginAttempt.Key)
+		require.Equal(t, int32(i), loginAttempt.Failures)
+		require.WithinDuration(t, time.Now(), loginAttempt.LastFailureAt, time.Second)
+	}
+
+	// The count starts over when the last failure is before the window.
+	loginAttempt, err := testQueries.RecordLoginFailure(context.Background(), RecordLoginFailureParams{
+		Key:         key,
+		WindowStart: time.Now().Add(time.Minute),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), loginAttempt.Failures)
+
+	fetchedLoginAttempt, err := testQueries.GetLoginAttempt(context.Background(), key)
+	require.NoError(t, err)
+	require.Equal(t, loginAttempt.Failures, fetchedLoginAttempt.Failures)
+
+	err = testQueries.DeleteLoginAttempt(context.Background(), key)
+	require.NoError(t, err)
+
+	_, err = testQueries.GetLoginAttempt(context.Background(), key)
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+}
@@ -3,11 +3,32 @@
 package db
 
 import (
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+type ApiKey struct {
+	ID         uuid.UUID    `json:"id"`
+	Username   string       `json:"username"`
+	Name       string       `json:"name"`
+	Prefix     string       `json:"prefix"`
+	HashedKey  string       `json:"hashed_key"`
+	Scopes     []string     `json:"scopes"`
+	LastUsedAt sql.NullTime `json:"last_used_at"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+type AuditLog struct {
+	ID        int64     `json:"id"`
+	Actor     string    `json:"actor"`
+	Action    string    `json:"action"`
+	Target    string    `json:"target"`
+	Details   string    `json:"details"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type Contact struct {
 	ID          int64  `json:"id"`
 	Owner       string `json:"owner"`
@@ -25,17 +46,82 @@ type ContactHasSkill struct {
 	SkillID   int32  `json:"skill_id"`
 }
 
-type Session struct {
-	ID           uuid.UUID `json:"id"`
-	Username     string    `json:"username"`
-	SessionToken string    `json:"session_token"`
-	UserAgent    string    `json:"user_agent"`
-	ClientIp     string    `json:"client_ip"`
-	IsBlocked    bool      `json:"is_blocked"`
+type ImportJob struct {
+	ID            uuid.UUID    `json:"id"`
+	Owner         string       `json:"owner"`
+	DryRun        bool         `json:"dry_run"`
+	Status        string       `json:"status"`
+	TotalRows     int32        `json:"total_rows"`
+	ProcessedRows int32        `json:"processed_rows"`
+	ImportedRows  int32        `json:"imported_rows"`
+	FailedRows    int32        `json:"failed_rows"`
+	Error         string       `json:"error"`
+	CreatedAt     time.Time    `json:"created_at"`
+	FinishedAt    sql.NullTime `json:"finished_at"`
+	HeartbeatAt   time.Time    `json:"heartbeat_at"`
+}
+
+type ImportJobError struct {
+	JobID   uuid.UUID `json:"job_id"`
+	Line    int32     `json:"line"`
+	Message string    `json:"message"`
+}
+
+type LoginAttempt struct {
+	Key           string    `json:"key"`
+	Failures      int32     `json:"failures"`
+	LastFailureAt time.Time `json:"last_failure_at"`
+}
+
+type OauthClient struct {
+	ID           uuid.UUID    `json:"id"`
+	Name         string       `json:"name"`
+	Owner        string       `json:"owner"`
+	HashedSecret string       `json:"hashed_secret"`
+	Scopes       []string     `json:"scopes"`
+	LastUsedAt   sql.NullTime `json:"last_used_at"`
+	CreatedAt    time.Time    `json:"created_at"`
+}
+
+type OidcLogin struct {
+	HashedState  string    `json:"hashed_state"`
+	CodeVerifier string    `json:"code_verifier"`
+	Nonce        string    `json:"nonce"`
 	ExpiresAt    time.Time `json:"expires_at"`
 	CreatedAt    time.Time `json:"created_at"`
 }
 
+type PasswordReset struct {
+	HashedToken string       `json:"hashed_token"`
+	Username    string       `json:"username"`
+	ExpiresAt   time.Time    `json:"expires_at"`
+	UsedAt      sql.NullTime `json:"used_at"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+type RecoveryCode struct {
+	HashedCode string       `json:"hashed_code"`
+	Username   string       `json:"username"`
+	UsedAt     sql.NullTime `json:"used_at"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+type Session struct {
+	ID               uuid.UUID     `json:"id"`
+	Username         string        `json:"username"`
+	SessionToken     string        `json:"session_token"`
+	UserAgent        string        `json:"user_agent"`
+	ClientIp         string        `json:"client_ip"`
+	IsBlocked        bool          `json:"is_blocked"`
+	ExpiresAt        time.Time     `json:"expires_at"`
+	CreatedAt        time.Time     `json:"created_at"`
+	FamilyID         uuid.UUID     `json:"family_id"`
+	ParentID         uuid.NullUUID `json:"parent_id"`
+	IsRotated        bool          `json:"is_rotated"`
+	IpChanged        bool          `json:"ip_changed"`
+	UserAgentChanged bool          `json:"user_agent_changed"`
+}
+
 type Skill struct {
 	ID         int64  `json:"id"`
 	Owner      string `json:"owner"`
@@ -43,11 +129,34 @@ type Skill struct {
 	SkillLevel string `json:"skill_level"`
 }
 
+type TwoFactorChallenge struct {
+	HashedToken string    `json:"hashed_token"`
+	Username    string    `json:"username"`
+	Attempts    int32     `json:"attempts"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
 type User struct {
-	Username            string    `json:"username"`
-	HashedPassword      string    `json:"hashed_password"`
-	Fullname            string    `json:"fullname"`
-	Email               string    `json:"email"`
-	PasswordLastChanged time.Time `json:"password_last_changed"`
-	CreateAt            time.Time `json:"create_at"`
+	Username                string         `json:"username"`
+	HashedPassword          string         `json:"hashed_password"`
+	Fullname                string         `json:"fullname"`
+	Email                   string         `json:"email"`
+	PasswordLastChanged     time.Time      `json:"password_last_changed"`
+	CreateAt                time.Time      `json:"create_at"`
+	Role                    string         `json:"role"`
+	EmailVerified           bool           `json:"email_verified"`
+	EmailVerificationSentAt sql.NullTime   `json:"email_verification_sent_at"`
+	TotpSecret              sql.NullString `json:"totp_secret"`
+	TotpEnabled             bool           `json:"totp_enabled"`
+	TotpLastStep            int64          `json:"totp_last_step"`
+	DisabledAt              sql.NullTime   `json:"disabled_at"`
+	PasswordResetSentAt     sql.NullTime   `json:"password_reset_sent_at"`
+}
+
+type UserIdentity struct {
+	Issuer    string    `json:"issuer"`
+	Subject   string    `json:"subject"`
+	Username  string    `json:"username"`
+	CreatedAt time.Time `json:"created_at"`
 }
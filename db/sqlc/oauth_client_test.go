@@ -0,0 +1,82 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/IsuruHaupe/web-api/auth"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomOAuthClient(t *testing.T, owner string) OauthClient {
+	arg := CreateOAuthClientParams{
+		ID:           uuid.New(),
+		Name:         auth.RandomString(8),
+		Owner:        owner,
+		HashedSecret: auth.HashSecret(auth.RandomString(32)),
+		Scopes:       []string{"contacts:read", "skills:write"},
+	}
+
+	client, err := testQueries.CreateOAuthClient(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, client)
+
+	require.Equal(t, arg.ID, client.ID)
+	require.Equal(t, arg.Name, client.Name)
+	require.Equal(t, arg.Owner, client.Owner)
+	require.Equal(t, arg.HashedSecret, client.HashedSecret)
+	require.Equal(t, arg.Scopes, client.Scopes)
+	require.False(t, client.LastUsedAt.Valid)
+	require.NotZero(t, client.CreatedAt)
+
+	return client
+}
+
+func TestCreateOAuthClient(t *testing.T) {
+	user := CreateRandomUser(t)
+	client := createRandomOAuthClient(t, user.Username)
+
+	client2, err := testQueries.GetOAuthClient(context.Background(), client.ID)
+	require.NoError(t, err)
+	require.Equal(t, client.Name, client2.Name)
+
+	clients, err := testQueries.ListOAuthClients(context.Background())
+	require.NoError(t, err)
+	require.NotEmpty(t, clients)
+
+	rows, err := testQueries.DeleteOAuthClient(context.Background(), client.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), rows)
+
+	_, err = testQueries.GetOAuthClient(context.Background(), client.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestUseOAuthClient(t *testing.T) {
+	user := CreateRandomUser(t)
+	client := createRandomOAuthClient(t, user.Username)
+
+	err := testQueries.UseOAuthClient(context.Background(), client.ID)
+	require.NoError(t, err)
+
+	client2, err := testQueries.GetOAuthClient(context.Background(), client.ID)
+	require.NoError(t, err)
+	require.True(t, client2.LastUsedAt.Valid)
+}
+
+func TestGetActiveOAuthClientOwner(t *testing.T) {
+	user := CreateRandomUser(t)
+	client := createRandomOAuthClient(t, user.Username)
+
+	owner, err := testQueries.GetActiveOAuthClientOwner(context.Background(), client.ID)
+	require.NoError(t, err)
+	require.Equal(t, user.Username, owner)
+
+	_, err = testQueries.DisableUser(context.Background(), user.Username)
+	require.NoError(t, err)
+
+	_, err = testQueries.GetActiveOAuthClientOwner(context.Background(), client.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
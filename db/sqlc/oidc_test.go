@@ -0,0 +1,91 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/IsuruHaupe/web-api/auth"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomOIDCLogin(t *testing.T, expiresAt time.Time) OidcLogin {
+	arg := CreateOIDCLoginParams{
+		HashedState:  auth.HashSecret(auth.RandomString(32)),
+		CodeVerifier: auth.RandomString(43),
+		Nonce:        auth.RandomString(43),
+		ExpiresAt:    expiresAt,
+	}
+
+	oidcLogin, err := testQueries.CreateOIDCLogin(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.HashedState, oidcLogin.HashedState)
+	require.Equal(t, arg.CodeVerifier, oidcLogin.CodeVerifier)
+	require.Equal(t, arg.Nonce, oidcLogin.Nonce)
+	require.WithinDuration(t, arg.ExpiresAt, oidcLogin.ExpiresAt, time.Second)
+	require.NotZero(t, oidcLogin.CreatedAt)
+
+	return oidcLogin
+}
+
+func TestConsumeOIDCLogin(t *testing.T) {
+	oidcLogin := createRandomOIDCLogin(t, time.Now().Add(time.Minute))
+
+	consumed, err := testQueries.ConsumeOIDCLogin(context.Background(), oidcLogin.HashedState)
+	require.NoError(t, err)
+	require.Equal(t, oidcLogin.CodeVerifier, consumed.CodeVerifier)
+	require.Equal(t, oidcLogin.Nonce, consumed.Nonce)
+
+	// A login can only be finished once.
+	_, err = testQueries.ConsumeOIDCLogin(context.Background(), oidcLogin.HashedState)
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+}
+
+func TestConsumeExpiredOIDCLogin(t *testing.T) {
+	oidcLogin := createRandomOIDCLogin(t, time.Now().Add(-time.Minute))
+
+	_, err := testQueries.ConsumeOIDCLogin(context.Background(), oidcLogin.HashedState)
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+
+	err = testQueries.DeleteExpiredOIDCLogins(context.Background())
+	require.NoError(t, err)
+}
+
+func TestGetUserByIdentity(t *testing.T) {
+	user := CreateRandomUser(t)
+	arg := CreateUserIdentityParams{
+		Issuer:   "https://idp.example.com",
+		Subject:  auth.RandomString(16),
+		Username: user.Username,
+	}
+
+	identity, err := testQueries.CreateUserIdentity(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Issuer, identity.Issuer)
+	require.Equal(t, arg.Subject, identity.Subject)
+	require.Equal(t, arg.Username, identity.Username)
+	require.NotZero(t, identity.CreatedAt)
+
+	identityUser, err := testQueries.GetUserByIdentity(context.Background(), GetUserByIdentityParams{
+		Issuer:  arg.Issuer,
+		Subject: arg.Subject,
+	})
+	require.NoError(t, err)
+	require.Equal(t, user.Username, identityUser.Username)
+
+	// Subjects are only unique for an issuer.
+	_, err = testQueries.GetUserByIdentity(context.Background(), GetUserByIdentityParams{
+		Issuer:  "https://other.example.com",
+		Subject: arg.Subject,
+	})
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+
+	// An identity can only be linked to a single user.
+	_, err = testQueries.CreateUserIdentity(context.Background(), CreateUserIdentityParams{
+		Issuer:   arg.Issuer,
+		Subject:  arg.Subject,
+		Username: CreateRandomUser(t).Username,
+	})
+	require.Error(t, err)
+}
@@ -0,0 +1,106 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/IsuruHaupe/web-api/auth"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomPasswordReset(t *testing.T, username string, expiresAt time.Time) PasswordReset {
+	arg := CreatePasswordResetParams{
+		HashedToken: auth.HashSecret(auth.RandomString(32)),
+		Username:    username,
+		ExpiresAt:   expiresAt,
+	}
+
+	passwordReset, err := testQueries.CreatePasswordReset(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, passwordReset)
+
+	require.Equal(t, arg.HashedToken, passwordReset.HashedToken)
+	require.Equal(t, arg.Username, passwordReset.Username)
+	require.WithinDuration(t, arg.ExpiresAt, passwordReset.ExpiresAt, time.Second)
+	require.False(t, passwordReset.UsedAt.Valid)
+	require.NotZero(t, passwordReset.CreatedAt)
+
+	return passwordReset
+}
+
+func TestUsePasswordReset(t *testing.T) {
+	user := CreateRandomUser(t)
+	passwordReset := createRandomPasswordReset(t, user.Username, time.Now().Add(time.Minute))
+
+	usedPasswordReset, err := testQueries.UsePasswordReset(context.Background(), passwordReset.HashedToken)
+	require.NoError(t, err)
+	require.Equal(t, user.Username, usedPasswordReset.Username)
+	require.True(t, usedPasswordReset.UsedAt.Valid)
+
+	// A token can only be used once.
+	_, err = testQueries.UsePasswordReset(context.Background(), passwordReset.HashedToken)
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+}
+
+func TestGetPasswordReset(t *testing.T) {
+	user := CreateRandomUser(t)
+	passwordReset := createRandomPasswordReset(t, user.Username, time.Now().Add(time.Minute))
+
+	passwordReset2, err := testQueries.GetPasswordReset(context.Background(), passwordReset.HashedToken)
+	require.NoError(t, err)
+	require.Equal(t, user.Username, passwordReset2.Username)
+
+	// Used and expired tokens are not returned.
+	_, err = testQueries.UsePasswordReset(context.Background(), passwordReset.HashedToken)
+	require.NoError(t, err)
+	_, err = testQueries.GetPasswordReset(context.Background(), passwordReset.HashedToken)
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+
+	expiredPasswordReset := createRandomPasswordReset(t, user.Username, time.Now().Add(-time.Minute))
+	_, err = testQueries.GetPasswordReset(context.Background(), expiredPasswordReset.HashedToken)
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+}
+
+func TestUseExpiredPasswordReset(t *testing.T) {
+	user := CreateRandomUser(t)
+	passwordReset := createRandomPasswordReset(t, user.Username, time.Now().Add(-time.Minute))
+
+	_, err := testQueries.UsePasswordReset(context.Background(), passwordReset.HashedToken)
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+}
+
+func TestInvalidatePasswordResets(t *testing.T) {
+	user := CreateRandomUser(t)
+	passwordReset := createRandomPasswordReset(t, user.Username, time.Now().Add(time.Minute))
+
+	err := testQueries.InvalidatePasswordResets(context.Background(), user.Username)
+	require.NoError(t, err)
+
+	_, err = testQueries.UsePasswordReset(context.Background(), passwordReset.HashedToken)
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+}
+
+func TestGetUserByEmail(t *testing.T) {
+	user := CreateRandomUser(t)
+
+	fetchedUser, err := testQueries.GetUserByEmail(context.Background(), user.Email)
+	require.NoError(t, err)
+	require.Equal(t, user.Username, fetchedUser.Username)
+	require.Equal(t, user.Email, fetchedUser.Email)
+}
+
+func TestDeleteUnusedPasswordResets(t *testing.T) {
+	user := CreateRandomUser(t)
+	usedPasswordReset := createRandomPasswordReset(t, user.Username, time.Now().Add(time.Minute))
+	_, err := testQueries.UsePasswordReset(context.Background(), usedPasswordReset.HashedToken)
+	require.NoError(t, err)
+	passwordReset := createRandomPasswordReset(t, user.Username, time.Now().Add(time.Minute))
+
+	err = testQueries.DeleteUnusedPasswordResets(context.Background(), user.Username)
+	require.NoError(t, err)
+
+	_, err = testQueries.UsePasswordReset(context.Background(), passwordReset.HashedToken)
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+}
@@ -9,15 +9,45 @@ import (
 )
 
 type Querier interface {
+	BlockSession(ctx context.Context, id uuid.UUID) (Session, error)
+	BlockSessionFamily(ctx context.Context, familyID uuid.UUID) error
+	BlockUserSessions(ctx context.Context, username string) error
+	ConsumeOIDCLogin(ctx context.Context, hashedState string) (OidcLogin, error)
+	CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (ApiKey, error)
+	CreateAuditLog(ctx context.Context, arg CreateAuditLogParams) (AuditLog, error)
 	CreateContact(ctx context.Context, arg CreateContactParams) (Contact, error)
 	CreateContactHasSkill(ctx context.Context, arg CreateContactHasSkillParams) (ContactHasSkill, error)
+	CreateImportJob(ctx context.Context, arg CreateImportJobParams) (ImportJob, error)
+	CreateImportJobError(ctx context.Context, arg CreateImportJobErrorParams) error
+	CreateOAuthClient(ctx context.Context, arg CreateOAuthClientParams) (OauthClient, error)
+	CreateOIDCLogin(ctx context.Context, arg CreateOIDCLoginParams) (OidcLogin, error)
+	CreatePasswordReset(ctx context.Context, arg CreatePasswordResetParams) (PasswordReset, error)
+	CreateRecoveryCode(ctx context.Context, arg CreateRecoveryCodeParams) (RecoveryCode, error)
 	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
 	CreateSkill(ctx context.Context, arg CreateSkillParams) (Skill, error)
+	CreateTwoFactorChallenge(ctx context.Context, arg CreateTwoFactorChallengeParams) (TwoFactorChallenge, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateUserIdentity(ctx context.Context, arg CreateUserIdentityParams) (UserIdentity, error)
+	DeleteAPIKey(ctx context.Context, id uuid.UUID) error
 	DeleteContact(ctx context.Context, id int64) error
+	DeleteExpiredOIDCLogins(ctx context.Context) error
+	DeleteLoginAttempt(ctx context.Context, key string) error
+	DeleteOAuthClient(ctx context.Context, id uuid.UUID) (int64, error)
+	DeleteOwnedContact(ctx context.Context, arg DeleteOwnedContactParams) (int64, error)
+	DeleteRecoveryCodes(ctx context.Context, username string) error
 	DeleteSession(ctx context.Context, id uuid.UUID) error
 	DeleteSkill(ctx context.Context, id int64) error
+	DeleteTwoFactorChallenge(ctx context.Context, hashedToken string) (int64, error)
+	DeleteUnusedPasswordResets(ctx context.Context, username string) error
 	DeleteUser(ctx context.Context, username string) error
+	DisableUser(ctx context.Context, username string) (User, error)
+	DisableUserTOTP(ctx context.Context, username string) (User, error)
+	EnableUser(ctx context.Context, username string) (User, error)
+	EnableUserTOTP(ctx context.Context, username string) (User, error)
+	FailInterruptedImportJobs(ctx context.Context, arg FailInterruptedImportJobsParams) (int64, error)
+	FinishImportJob(ctx context.Context, arg FinishImportJobParams) (ImportJob, error)
+	GetAPIKey(ctx context.Context, id uuid.UUID) (ApiKey, error)
+	GetActiveOAuthClientOwner(ctx context.Context, id uuid.UUID) (string, error)
 	GetContact(ctx context.Context, id int64) (Contact, error)
 	GetContactSkills(ctx context.Context, contactID int32) ([]Skill, error)
 	GetContactsWithSkill(ctx context.Context, skillName string) ([]Contact, error)
@@ -28,17 +58,54 @@ type Querier interface {
 	GetHomeAddress(ctx context.Context, id int64) (string, error)
 	GetIfExistsContactID(ctx context.Context, id int64) (bool, error)
 	GetIfExistsSkillID(ctx context.Context, id int64) (bool, error)
+	GetImportJob(ctx context.Context, id uuid.UUID) (ImportJob, error)
 	GetLastname(ctx context.Context, id int64) (string, error)
+	GetLoginAttempt(ctx context.Context, key string) (LoginAttempt, error)
+	GetOAuthClient(ctx context.Context, id uuid.UUID) (OauthClient, error)
+	GetOrCreateSkill(ctx context.Context, arg GetOrCreateSkillParams) (Skill, error)
+	GetPasswordReset(ctx context.Context, hashedToken string) (PasswordReset, error)
 	GetPhoneNumber(ctx context.Context, id int64) (string, error)
 	GetSession(ctx context.Context, id uuid.UUID) (Session, error)
 	GetSkill(ctx context.Context, id int64) (Skill, error)
 	GetSkillLevel(ctx context.Context, id int64) (string, error)
 	GetSkillName(ctx context.Context, id int64) (string, error)
+	GetTwoFactorChallenge(ctx context.Context, hashedToken string) (TwoFactorChallenge, error)
 	GetUser(ctx context.Context, username string) (User, error)
+	GetUserByEmail(ctx context.Context, email string) (User, error)
+	GetUserByIdentity(ctx context.Context, arg GetUserByIdentityParams) (User, error)
+	GetUserCounts(ctx context.Context, owner string) (GetUserCountsRow, error)
+	InvalidatePasswordResets(ctx context.Context, username string) error
+	KeepImportJobAlive(ctx context.Context, id uuid.UUID) (int64, error)
+	ListAPIKeys(ctx context.Context, username string) ([]ApiKey, error)
+	ListAuditLogs(ctx context.Context, arg ListAuditLogsParams) ([]AuditLog, error)
 	ListContacts(ctx context.Context, arg ListContactsParams) ([]Contact, error)
+	ListContactsWithSkills(ctx context.Context, owner string) ([]ListContactsWithSkillsRow, error)
+	ListDuplicateContacts(ctx context.Context, arg ListDuplicateContactsParams) ([]ListDuplicateContactsRow, error)
+	ListImportJobErrors(ctx context.Context, arg ListImportJobErrorsParams) ([]ListImportJobErrorsRow, error)
+	ListOAuthClients(ctx context.Context) ([]OauthClient, error)
+	ListSessions(ctx context.Context, username string) ([]ListSessionsRow, error)
 	ListSkills(ctx context.Context, arg ListSkillsParams) ([]Skill, error)
+	ListUsers(ctx context.Context, arg ListUsersParams) ([]ListUsersRow, error)
+	MarkEmailVerificationSent(ctx context.Context, arg MarkEmailVerificationSentParams) (User, error)
+	MarkPasswordResetSent(ctx context.Context, arg MarkPasswordResetSentParams) (User, error)
+	MoveContactSkills(ctx context.Context, arg MoveContactSkillsParams) (int64, error)
+	RecordLoginFailure(ctx context.Context, arg RecordLoginFailureParams) (LoginAttempt, error)
+	RotateSession(ctx context.Context, id uuid.UUID) (Session, error)
+	SearchContacts(ctx context.Context, arg SearchContactsParams) ([]SearchContactsRow, error)
+	SetUserTOTPSecret(ctx context.Context, arg SetUserTOTPSecretParams) (User, error)
 	UpdateContact(ctx context.Context, arg UpdateContactParams) (Contact, error)
+	UpdateImportJobProgress(ctx context.Context, arg UpdateImportJobProgressParams) (int64, error)
 	UpdateSkill(ctx context.Context, arg UpdateSkillParams) (Skill, error)
+	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) (User, error)
+	UpdateUserPasswordHash(ctx context.Context, arg UpdateUserPasswordHashParams) error
+	UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error)
+	UseAPIKey(ctx context.Context, hashedKey string) (UseAPIKeyRow, error)
+	UseOAuthClient(ctx context.Context, id uuid.UUID) error
+	UsePasswordReset(ctx context.Context, hashedToken string) (PasswordReset, error)
+	UseRecoveryCode(ctx context.Context, arg UseRecoveryCodeParams) (RecoveryCode, error)
+	UseTOTPStep(ctx context.Context, arg UseTOTPStepParams) (User, error)
+	UseTwoFactorChallengeAttempt(ctx context.Context, arg UseTwoFactorChallengeAttemptParams) (TwoFactorChallenge, error)
+	VerifyUserEmail(ctx context.Context, arg VerifyUserEmailParams) (User, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -10,6 +10,56 @@ import (
 	"github.com/google/uuid"
 )
 
+const blockSession = `-- name: BlockSession :one
+UPDATE sessions
+SET is_blocked = true
+WHERE id = $1
+RETURNING id, username, session_token, user_agent, client_ip, is_blocked, expires_at, created_at, family_id, parent_id, is_rotated, ip_changed, user_agent_changed
+`
+
+func (q *Queries) BlockSession(ctx context.Context, id uuid.UUID) (Session, error) {
+	row := q.db.QueryRowContext(ctx, blockSession, id)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.SessionToken,
+		&i.UserAgent,
+		&i.ClientIp,
+		&i.IsBlocked,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.FamilyID,
+		&i.ParentID,
+		&i.IsRotated,
+		&i.IpChanged,
+		&i.UserAgentChanged,
+	)
+	return i, err
+}
+
+const blockSessionFamily = `-- name: BlockSessionFamily :exec
+UPDATE sessions
+SET is_blocked = true
+WHERE family_id = $1
+`
+
+func (q *Queries) BlockSessionFamily(ctx context.Context, familyID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, blockSessionFamily, familyID)
+	return err
+}
+
+const blockUserSessions = `-- name: BlockUserSessions :exec
+UPDATE sessions
+SET is_blocked = true
+WHERE username = $1
+`
+
+func (q *Queries) BlockUserSessions(ctx context.Context, username string) error {
+	_, err := q.db.ExecContext(ctx, blockUserSessions, username)
+	return err
+}
+
 const createSession = `-- name: CreateSession :one
 INSERT INTO sessions (
   id,
@@ -18,20 +68,28 @@ INSERT INTO sessions (
   user_agent,
   client_ip,
   is_blocked,
-  expires_at
+  expires_at,
+  family_id,
+  parent_id,
+  ip_changed,
+  user_agent_changed
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7
-) RETURNING id, username, session_token, user_agent, client_ip, is_blocked, expires_at, created_at
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+) RETURNING id, username, session_token, user_agent, client_ip, is_blocked, expires_at, created_at, family_id, parent_id, is_rotated, ip_changed, user_agent_changed
 `
 
 type CreateSessionParams struct {
-	ID           uuid.UUID `json:"id"`
-	Username     string    `json:"username"`
-	SessionToken string    `json:"session_token"`
-	UserAgent    string    `json:"user_agent"`
-	ClientIp     string    `json:"client_ip"`
-	IsBlocked    bool      `json:"is_blocked"`
-	ExpiresAt    time.Time `json:"expires_at"`
+	ID               uuid.UUID     `json:"id"`
+	Username         string        `json:"username"`
+	SessionToken     string        `json:"session_token"`
+	UserAgent        string        `json:"user_agent"`
+	ClientIp         string        `json:"client_ip"`
+	IsBlocked        bool          `json:"is_blocked"`
+	ExpiresAt        time.Time     `json:"expires_at"`
+	FamilyID         uuid.UUID     `json:"family_id"`
+	ParentID         uuid.NullUUID `json:"parent_id"`
+	IpChanged        bool          `json:"ip_changed"`
+	UserAgentChanged bool          `json:"user_agent_changed"`
 }
 
 func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error) {
@@ -43,6 +101,10 @@ func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (S
 		arg.ClientIp,
 		arg.IsBlocked,
 		arg.ExpiresAt,
+		arg.FamilyID,
+		arg.ParentID,
+		arg.IpChanged,
+		arg.UserAgentChanged,
 	)
 	var i Session
 	err := row.Scan(
@@ -54,6 +116,11 @@ func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (S
 		&i.IsBlocked,
 		&i.ExpiresAt,
 		&i.CreatedAt,
+		&i.FamilyID,
+		&i.ParentID,
+		&i.IsRotated,
+		&i.IpChanged,
+		&i.UserAgentChanged,
 	)
 	return i, err
 }
@@ -68,7 +135,7 @@ func (q *Queries) DeleteSession(ctx context.Context, id uuid.UUID) error {
 }
 
 const getSession = `-- name: GetSession :one
-SELECT id, username, session_token, user_agent, client_ip, is_blocked, expires_at, created_at FROM sessions
+SELECT id, username, session_token, user_agent, client_ip, is_blocked, expires_at, created_at, family_id, parent_id, is_rotated, ip_changed, user_agent_changed FROM sessions
 WHERE id = $1 LIMIT 1
 `
 
@@ -84,6 +151,93 @@ func (q *Queries) GetSession(ctx context.Context, id uuid.UUID) (Session, error)
 		&i.IsBlocked,
 		&i.ExpiresAt,
 		&i.CreatedAt,
+		&i.FamilyID,
+		&i.ParentID,
+		&i.IsRotated,
+		&i.IpChanged,
+		&i.UserAgentChanged,
+	)
+	return i, err
+}
+
+const listSessions = `-- name: ListSessions :many
+SELECT s.id, s.user_agent, s.client_ip, s.is_blocked, s.expires_at, s.created_at, s.ip_changed, s.user_agent_changed,
+  COALESCE(f.created_at, s.created_at)::timestamptz AS family_created_at
+FROM sessions s
+LEFT JOIN sessions f ON f.id = s.family_id
+WHERE s.username = $1 AND s.is_blocked = false AND s.is_rotated = false AND s.expires_at > now()
+ORDER BY s.created_at DESC
+`
+
+type ListSessionsRow struct {
+	ID               uuid.UUID `json:"id"`
+	UserAgent        string    `json:"user_agent"`
+	ClientIp         string    `json:"client_ip"`
+	IsBlocked        bool      `json:"is_blocked"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	CreatedAt        time.Time `json:"created_at"`
+	IpChanged        bool      `json:"ip_changed"`
+	UserAgentChanged bool      `json:"user_agent_changed"`
+	FamilyCreatedAt  time.Time `json:"family_created_at"`
+}
+
+func (q *Queries) ListSessions(ctx context.Context, username string) ([]ListSessionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listSessions, username)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListSessionsRow{}
+	for rows.Next() {
+		var i ListSessionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserAgent,
+			&i.ClientIp,
+			&i.IsBlocked,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.IpChanged,
+			&i.UserAgentChanged,
+			&i.FamilyCreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const rotateSession = `-- name: RotateSession :one
+UPDATE sessions
+SET is_rotated = true
+WHERE id = $1 AND is_rotated = false
+RETURNING id, username, session_token, user_agent, client_ip, is_blocked, expires_at, created_at, family_id, parent_id, is_rotated, ip_changed, user_agent_changed
+`
+
+func (q *Queries) RotateSession(ctx context.Context, id uuid.UUID) (Session, error) {
+	row := q.db.QueryRowContext(ctx, rotateSession, id)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.SessionToken,
+		&i.UserAgent,
+		&i.ClientIp,
+		&i.IsBlocked,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.FamilyID,
+		&i.ParentID,
+		&i.IsRotated,
+		&i.IpChanged,
+		&i.UserAgentChanged,
 	)
 	return i, err
 }
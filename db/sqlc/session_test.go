@@ -21,6 +21,7 @@ func createFakeSession(t *testing.T) (Session, uuid.UUID) {
 		ClientIp:     "IP",
 		IsBlocked:    false,
 		ExpiresAt:    time.Now(),
+		FamilyID:     ID,
 	}
 
 	session, err := testQueries.CreateSession(context.Background(), arg)
@@ -64,3 +65,122 @@ func TestDeleteSession(t *testing.T) {
 	require.EqualError(t, err, sql.ErrNoRows.Error())
 	require.Empty(t, session2)
 }
+
+func TestBlockSession(t *testing.T) {
+	session1, ID := createFakeSession(t)
+	require.False(t, session1.IsBlocked)
+
+	session2, err := testQueries.BlockSession(context.Background(), ID)
+	require.NoError(t, err)
+	require.True(t, session2.IsBlocked)
+	require.Equal(t, session1.ID, session2.ID)
+
+	err = testQueries.DeleteSession(context.Background(), ID)
+	require.NoError(t, err)
+}
+
+func TestListSessions(t *testing.T) {
+	user := CreateRandomUser(t)
+	for i := 0; i < 3; i++ {
+		ID := uuid.New()
+		_, err := testQueries.CreateSession(context.Background(), CreateSessionParams{
+			ID:           ID,
+			Username:     user.Username,
+			SessionToken: "Token",
+			UserAgent:    "Agent",
+			ClientIp:     "IP",
+			IsBlocked:    i == 0,
+			ExpiresAt:    time.Now().Add(time.Hour),
+			FamilyID:     ID,
+		})
+		require.NoError(t, err)
+	}
+
+	sessions, err := testQueries.ListSessions(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+
+	for _, session := range sessions {
+		require.False(t, session.IsBlocked)
+		require.WithinDuration(t, session.CreatedAt, session.FamilyCreatedAt, time.Second)
+	}
+}
+
+func TestListSessionsRotated(t *testing.T) {
+	parent, ID := createFakeSession(t)
+	_, err := testQueries.RotateSession(context.Background(), ID)
+	require.NoError(t, err)
+
+	child, err := testQueries.CreateSession(context.Background(), CreateSessionParams{
+		ID:               uuid.New(),
+		Username:         parent.Username,
+		SessionToken:     "Token",
+		UserAgent:        "Other Agent",
+		ClientIp:         "Other IP",
+		ExpiresAt:        time.Now().Add(time.Hour),
+		FamilyID:         parent.FamilyID,
+		ParentID:         uuid.NullUUID{UUID: ID, Valid: true},
+		IpChanged:        true,
+		UserAgentChanged: true,
+	})
+	require.NoError(t, err)
+
+	// Only the last session of the family is listed, with the creation time of the family.
+	sessions, err := testQueries.ListSessions(context.Background(), parent.Username)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	require.Equal(t, child.ID, sessions[0].ID)
+	require.True(t, sessions[0].IpChanged)
+	require.True(t, sessions[0].UserAgentChanged)
+	require.WithinDuration(t, parent.CreatedAt, sessions[0].FamilyCreatedAt, time.Second)
+}
+
+func TestRotateSession(t *testing.T) {
+	session1, ID := createFakeSession(t)
+	require.False(t, session1.IsRotated)
+
+	session2, err := testQueries.RotateSession(context.Background(), ID)
+	require.NoError(t, err)
+	require.True(t, session2.IsRotated)
+
+	// A session can only be rotated once.
+	session3, err := testQueries.RotateSession(context.Background(), ID)
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+	require.Empty(t, session3)
+}
+
+func TestBlockSessionFamily(t *testing.T) {
+	parent, ID := createFakeSession(t)
+	child, err := testQueries.CreateSession(context.Background(), CreateSessionParams{
+		ID:           uuid.New(),
+		Username:     parent.Username,
+		SessionToken: "Token",
+		UserAgent:    "Agent",
+		ClientIp:     "IP",
+		ExpiresAt:    parent.ExpiresAt,
+		FamilyID:     parent.FamilyID,
+		ParentID:     uuid.NullUUID{UUID: ID, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, ID, child.ParentID.UUID)
+
+	err = testQueries.BlockSessionFamily(context.Background(), parent.FamilyID)
+	require.NoError(t, err)
+
+	for _, sessionID := range []uuid.UUID{parent.ID, child.ID} {
+		session, err := testQueries.GetSession(context.Background(), sessionID)
+		require.NoError(t, err)
+		require.True(t, session.IsBlocked)
+	}
+}
+
+func TestBlockUserSessions(t *testing.T) {
+	session1, _ := createFakeSession(t)
+
+	err := testQueries.BlockUserSessions(context.Background(), session1.Username)
+	require.NoError(t, err)
+
+	session2, err := testQueries.GetSession(context.Background(), session1.ID)
+	require.NoError(t, err)
+	require.True(t, session2.IsBlocked)
+}
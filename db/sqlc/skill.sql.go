@@ -56,6 +56,36 @@ func (q *Queries) GetIfExistsSkillID(ctx context.Context, id int64) (bool, error
 	return exists, err
 }
 
+const getOrCreateSkill = `-- name: GetOrCreateSkill :one
+INSERT INTO skills (
+  owner,
+  skill_name,
+  skill_level
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT (owner, skill_name, skill_level) DO UPDATE SET skill_name = EXCLUDED.skill_name
+RETURNING id, owner, skill_name, skill_level
+`
+
+type GetOrCreateSkillParams struct {
+	Owner      string `json:"owner"`
+	SkillName  string `json:"skill_name"`
+	SkillLevel string `json:"skill_level"`
+}
+
+func (q *Queries) GetOrCreateSkill(ctx context.Context, arg GetOrCreateSkillParams) (Skill, error) {
+	row := q.db.QueryRowContext(ctx, getOrCreateSkill, arg.Owner, arg.SkillName, arg.SkillLevel)
+	var i Skill
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.SkillName,
+		&i.SkillLevel,
+	)
+	return i, err
+}
+
 const getSkill = `-- name: GetSkill :one
 SELECT id, owner, skill_name, skill_level FROM skills
 WHERE id = $1 LIMIT 1
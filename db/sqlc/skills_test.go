@@ -162,3 +162,25 @@ func TestUpdateSkill(t *testing.T) {
 	require.NoError(t, err)
 
 }
+
+func TestGetOrCreateSkill(t *testing.T) {
+	user := CreateRandomUser(t)
+	skill1 := CreateRandomSkill(t, user)
+
+	// An existing skill is returned as is.
+	skill2, err := testQueries.GetOrCreateSkill(context.Background(), GetOrCreateSkillParams{
+		Owner:      user.Username,
+		SkillName:  skill1.SkillName,
+		SkillLevel: skill1.SkillLevel,
+	})
+	require.NoError(t, err)
+	require.Equal(t, skill1, skill2)
+
+	skill3, err := testQueries.GetOrCreateSkill(context.Background(), GetOrCreateSkillParams{
+		Owner:      user.Username,
+		SkillName:  skill1.SkillName,
+		SkillLevel: skill1.SkillLevel + " and more",
+	})
+	require.NoError(t, err)
+	require.NotEqual(t, skill1.ID, skill3.ID)
+}
@@ -0,0 +1,138 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/IsuruHaupe/web-api/auth"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserTOTP(t *testing.T) {
+	user := CreateRandomUser(t)
+	require.False(t, user.TotpEnabled)
+	require.False(t, user.TotpSecret.Valid)
+
+	// Two-factor authentication can only be enabled once a secret has been saved.
+	_, err := testQueries.EnableUserTOTP(context.Background(), user.Username)
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+
+	secret := sql.NullString{String: auth.RandomString(32), Valid: true}
+	enrolledUser, err := testQueries.SetUserTOTPSecret(context.Background(), SetUserTOTPSecretParams{
+		Username:   user.Username,
+		TotpSecret: secret,
+	})
+	require.NoError(t, err)
+	require.Equal(t, secret, enrolledUser.TotpSecret)
+	require.False(t, enrolledUser.TotpEnabled)
+
+	enabledUser, err := testQueries.EnableUserTOTP(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.True(t, enabledUser.TotpEnabled)
+
+	// The secret can not be replaced while two-factor authentication is enabled.
+	_, err = testQueries.SetUserTOTPSecret(context.Background(), SetUserTOTPSecretParams{
+		Username:   user.Username,
+		TotpSecret: sql.NullString{String: auth.RandomString(32), Valid: true},
+	})
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+
+	disabledUser, err := testQueries.DisableUserTOTP(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.False(t, disabledUser.TotpEnabled)
+	require.False(t, disabledUser.TotpSecret.Valid)
+}
+
+func TestUseTOTPStep(t *testing.T) {
+	user := CreateRandomUser(t)
+	step := auth.TOTPStep(time.Now())
+
+	updatedUser, err := testQueries.UseTOTPStep(context.Background(), UseTOTPStepParams{
+		Username:     user.Username,
+		TotpLastStep: step,
+	})
+	require.NoError(t, err)
+	require.Equal(t, step, updatedUser.TotpLastStep)
+
+	// A time step can only be used once, and never before the last one used.
+	for _, usedStep := range []int64{step, step - 1} {
+		_, err = testQueries.UseTOTPStep(context.Background(), UseTOTPStepParams{
+			Username:     user.Username,
+			TotpLastStep: usedStep,
+		})
+		require.EqualError(t, err, sql.ErrNoRows.Error())
+	}
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	user := CreateRandomUser(t)
+	hashedCode := auth.HashSecret(auth.RandomString(10))
+
+	recoveryCode, err := testQueries.CreateRecoveryCode(context.Background(), CreateRecoveryCodeParams{
+		HashedCode: hashedCode,
+		Username:   user.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, hashedCode, recoveryCode.HashedCode)
+	require.False(t, recoveryCode.UsedAt.Valid)
+
+	arg := UseRecoveryCodeParams{
+		HashedCode: hashedCode,
+		Username:   user.Username,
+	}
+	usedRecoveryCode, err := testQueries.UseRecoveryCode(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, usedRecoveryCode.UsedAt.Valid)
+
+	_, err = testQueries.UseRecoveryCode(context.Background(), arg)
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+
+	err = testQueries.DeleteRecoveryCodes(context.Background(), user.Username)
+	require.NoError(t, err)
+}
+
+func TestTwoFactorChallenge(t *testing.T) {
+	user := CreateRandomUser(t)
+	arg := CreateTwoFactorChallengeParams{
+		HashedToken: auth.HashSecret(auth.RandomString(32)),
+		Username:    user.Username,
+		ExpiresAt:   time.Now().Add(time.Minute),
+	}
+
+	challenge, err := testQueries.CreateTwoFactorChallenge(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.HashedToken, challenge.HashedToken)
+	require.Equal(t, arg.Username, challenge.Username)
+	require.Zero(t, challenge.Attempts)
+	require.WithinDuration(t, arg.ExpiresAt, challenge.ExpiresAt, time.Second)
+
+	attemptArg := UseTwoFactorChallengeAttemptParams{
+		HashedToken: arg.HashedToken,
+		MaxAttempts: 2,
+	}
+	challenge, err = testQueries.UseTwoFactorChallengeAttempt(context.Background(), attemptArg)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), challenge.Attempts)
+
+	challenge, err = testQueries.UseTwoFactorChallengeAttempt(context.Background(), attemptArg)
+	require.NoError(t, err)
+	require.Equal(t, int32(2), challenge.Attempts)
+
+	// All the attempts are used.
+	_, err = testQueries.UseTwoFactorChallengeAttempt(context.Background(), attemptArg)
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+
+	fetchedChallenge, err := testQueries.GetTwoFactorChallenge(context.Background(), arg.HashedToken)
+	require.NoError(t, err)
+	require.Equal(t, challenge.Attempts, fetchedChallenge.Attempts)
+
+	deleted, err := testQueries.DeleteTwoFactorChallenge(context.Background(), arg.HashedToken)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), deleted)
+
+	deleted, err = testQueries.DeleteTwoFactorChallenge(context.Background(), arg.HashedToken)
+	require.NoError(t, err)
+	require.Zero(t, deleted)
+}
@@ -5,6 +5,7 @@ package db
 
 import (
 	"context"
+	"time"
 )
 
 const createUser = `-- name: CreateUser :one
@@ -12,11 +13,12 @@ INSERT INTO users (
   username, 
   hashed_password, 
   fullname, 
-  email
+  email,
+  email_verification_sent_at
 ) VALUES (
-  $1, $2, $3, $4 
+  $1, $2, $3, $4, now()
 )
-RETURNING username, hashed_password, fullname, email, password_last_changed, create_at
+RETURNING username, hashed_password, fullname, email, password_last_changed, create_at, role, email_verified, email_verification_sent_at, totp_secret, totp_enabled, totp_last_step, disabled_at, password_reset_sent_at
 `
 
 type CreateUserParams struct {
@@ -41,6 +43,14 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Email,
 		&i.PasswordLastChanged,
 		&i.CreateAt,
+		&i.Role,
+		&i.EmailVerified,
+		&i.EmailVerificationSentAt,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.TotpLastStep,
+		&i.DisabledAt,
+		&i.PasswordResetSentAt,
 	)
 	return i, err
 }
@@ -55,7 +65,7 @@ func (q *Queries) DeleteUser(ctx context.Context, username string) error {
 }
 
 const getUser = `-- name: GetUser :one
-SELECT username, hashed_password, fullname, email, password_last_changed, create_at FROM users
+SELECT username, hashed_password, fullname, email, password_last_changed, create_at, role, email_verified, email_verification_sent_at, totp_secret, totp_enabled, totp_last_step, disabled_at, password_reset_sent_at FROM users
 WHERE username = $1 LIMIT 1
 `
 
@@ -69,6 +79,235 @@ func (q *Queries) GetUser(ctx context.Context, username string) (User, error) {
 		&i.Email,
 		&i.PasswordLastChanged,
 		&i.CreateAt,
+		&i.Role,
+		&i.EmailVerified,
+		&i.EmailVerificationSentAt,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.TotpLastStep,
+		&i.DisabledAt,
+		&i.PasswordResetSentAt,
+	)
+	return i, err
+}
+
+const getUserByEmail = `-- name: GetUserByEmail :one
+SELECT username, hashed_password, fullname, email, password_last_changed, create_at, role, email_verified, email_verification_sent_at, totp_secret, totp_enabled, totp_last_step, disabled_at, password_reset_sent_at FROM users
+WHERE email = $1 LIMIT 1
+`
+
+func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByEmail, email)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.Fullname,
+		&i.Email,
+		&i.PasswordLastChanged,
+		&i.CreateAt,
+		&i.Role,
+		&i.EmailVerified,
+		&i.EmailVerificationSentAt,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.TotpLastStep,
+		&i.DisabledAt,
+		&i.PasswordResetSentAt,
+	)
+	return i, err
+}
+
+const markEmailVerificationSent = `-- name: MarkEmailVerificationSent :one
+UPDATE users
+SET email_verification_sent_at = now()
+WHERE email = $1
+  AND email_verified = false
+  AND (email_verification_sent_at IS NULL OR email_verification_sent_at < $2::timestamptz)
+RETURNING username, hashed_password, fullname, email, password_last_changed, create_at, role, email_verified, email_verification_sent_at, totp_secret, totp_enabled, totp_last_step, disabled_at, password_reset_sent_at
+`
+
+type MarkEmailVerificationSentParams struct {
+	Email      string    `json:"email"`
+	SentBefore time.Time `json:"sent_before"`
+}
+
+func (q *Queries) MarkEmailVerificationSent(ctx context.Context, arg MarkEmailVerificationSentParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, markEmailVerificationSent, arg.Email, arg.SentBefore)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.Fullname,
+		&i.Email,
+		&i.PasswordLastChanged,
+		&i.CreateAt,
+		&i.Role,
+		&i.EmailVerified,
+		&i.EmailVerificationSentAt,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.TotpLastStep,
+		&i.DisabledAt,
+		&i.PasswordResetSentAt,
+	)
+	return i, err
+}
+
+const markPasswordResetSent = `-- name: MarkPasswordResetSent :one
+UPDATE users
+SET password_reset_sent_at = now()
+WHERE email = $1
+  AND (password_reset_sent_at IS NULL OR password_reset_sent_at < $2::timestamptz)
+RETURNING username, hashed_password, fullname, email, password_last_changed, create_at, role, email_verified, email_verification_sent_at, totp_secret, totp_enabled, totp_last_step, disabled_at, password_reset_sent_at
+`
+
+type MarkPasswordResetSentParams struct {
+	Email      string    `json:"email"`
+	SentBefore time.Time `json:"sent_before"`
+}
+
+func (q *Queries) MarkPasswordResetSent(ctx context.Context, arg MarkPasswordResetSentParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, markPasswordResetSent, arg.Email, arg.SentBefore)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.Fullname,
+		&i.Email,
+		&i.PasswordLastChanged,
+		&i.CreateAt,
+		&i.Role,
+		&i.EmailVerified,
+		&i.EmailVerificationSentAt,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.TotpLastStep,
+		&i.DisabledAt,
+		&i.PasswordResetSentAt,
+	)
+	return i, err
+}
+
+const updateUserPassword = `-- name: UpdateUserPassword :one
+UPDATE users
+SET hashed_password = $2, password_last_changed = now()
+WHERE username = $1
+RETURNING username, hashed_password, fullname, email, password_last_changed, create_at, role, email_verified, email_verification_sent_at, totp_secret, totp_enabled, totp_last_step, disabled_at, password_reset_sent_at
+`
+
+type UpdateUserPasswordParams struct {
+	Username       string `json:"username"`
+	HashedPassword string `json:"hashed_password"`
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserPassword, arg.Username, arg.HashedPassword)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.Fullname,
+		&i.Email,
+		&i.PasswordLastChanged,
+		&i.CreateAt,
+		&i.Role,
+		&i.EmailVerified,
+		&i.EmailVerificationSentAt,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.TotpLastStep,
+		&i.DisabledAt,
+		&i.PasswordResetSentAt,
+	)
+	return i, err
+}
+
+const updateUserPasswordHash = `-- name: UpdateUserPasswordHash :exec
+UPDATE users
+SET hashed_password = $1
+WHERE username = $2 AND hashed_password = $3::varchar
+`
+
+type UpdateUserPasswordHashParams struct {
+	HashedPassword    string `json:"hashed_password"`
+	Username          string `json:"username"`
+	OldHashedPassword string `json:"old_hashed_password"`
+}
+
+func (q *Queries) UpdateUserPasswordHash(ctx context.Context, arg UpdateUserPasswordHashParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserPasswordHash, arg.HashedPassword, arg.Username, arg.OldHashedPassword)
+	return err
+}
+
+const updateUserProfile = `-- name: UpdateUserProfile :one
+UPDATE users
+SET fullname = $2,
+  email = $3,
+  email_verified = (email_verified AND email = $3),
+  email_verification_sent_at = CASE WHEN email = $3 THEN email_verification_sent_at ELSE now() END
+WHERE username = $1
+RETURNING username, hashed_password, fullname, email, password_last_changed, create_at, role, email_verified, email_verification_sent_at, totp_secret, totp_enabled, totp_last_step, disabled_at, password_reset_sent_at
+`
+
+type UpdateUserProfileParams struct {
+	Username string `json:"username"`
+	Fullname string `json:"fullname"`
+	Email    string `json:"email"`
+}
+
+func (q *Queries) UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserProfile, arg.Username, arg.Fullname, arg.Email)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.Fullname,
+		&i.Email,
+		&i.PasswordLastChanged,
+		&i.CreateAt,
+		&i.Role,
+		&i.EmailVerified,
+		&i.EmailVerificationSentAt,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.TotpLastStep,
+		&i.DisabledAt,
+		&i.PasswordResetSentAt,
+	)
+	return i, err
+}
+
+const verifyUserEmail = `-- name: VerifyUserEmail :one
+UPDATE users
+SET email_verified = true
+WHERE username = $1 AND email = $2
+RETURNING username, hashed_password, fullname, email, password_last_changed, create_at, role, email_verified, email_verification_sent_at, totp_secret, totp_enabled, totp_last_step, disabled_at, password_reset_sent_at
+`
+
+type VerifyUserEmailParams struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+func (q *Queries) VerifyUserEmail(ctx context.Context, arg VerifyUserEmailParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, verifyUserEmail, arg.Username, arg.Email)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.Fullname,
+		&i.Email,
+		&i.PasswordLastChanged,
+		&i.CreateAt,
+		&i.Role,
+		&i.EmailVerified,
+		&i.EmailVerificationSentAt,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.TotpLastStep,
+		&i.DisabledAt,
+		&i.PasswordResetSentAt,
 	)
 	return i, err
 }
@@ -30,6 +30,9 @@ func CreateRandomUser(t *testing.T) User {
 	require.Equal(t, args.HashedPassword, user.HashedPassword)
 	require.Equal(t, args.Fullname, user.Fullname)
 	require.Equal(t, args.Email, user.Email)
+	require.Equal(t, "editor", user.Role)
+	require.False(t, user.EmailVerified)
+	require.True(t, user.EmailVerificationSentAt.Valid)
 
 	require.True(t, user.PasswordLastChanged.IsZero())
 	require.NotZero(t, user.CreateAt)
@@ -53,6 +56,7 @@ func TestGetUser(t *testing.T) {
 	require.Equal(t, user1.HashedPassword, user2.HashedPassword)
 	require.Equal(t, user1.Fullname, user2.Fullname)
 	require.Equal(t, user1.Email, user2.Email)
+	require.Equal(t, user1.Role, user2.Role)
 	require.WithinDuration(t, user1.PasswordLastChanged, user2.PasswordLastChanged, time.Second)
 	require.WithinDuration(t, user1.CreateAt, user2.CreateAt, time.Second)
 
@@ -70,3 +74,211 @@ func TestDeleteUser(t *testing.T) {
 	require.EqualError(t, err, sql.ErrNoRows.Error())
 	require.Empty(t, user2)
 }
+
+func TestDeleteUserCascade(t *testing.T) {
+	session, _ := createFakeSession(t)
+	user, err := testQueries.GetUser(context.Background(), session.Username)
+	require.NoError(t, err)
+	contact := CreateRandomContact(t, user)
+	skill := CreateRandomSkill(t, user)
+	_, err = testQueries.CreateContactHasSkill(context.Background(), CreateContactHasSkillParams{
+		Owner:     user.Username,
+		ContactID: int32(contact.ID),
+		SkillID:   int32(skill.ID),
+	})
+	require.NoError(t, err)
+
+	err = testQueries.DeleteUser(context.Background(), user.Username)
+	require.NoError(t, err)
+
+	// Everything the user owned is deleted along with it.
+	_, err = testQueries.GetSession(context.Background(), session.ID)
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+	_, err = testQueries.GetContact(context.Background(), contact.ID)
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+	_, err = testQueries.GetSkill(context.Background(), skill.ID)
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+	contactSkills, err := testQueries.GetContactSkills(context.Background(), int32(contact.ID))
+	require.NoError(t, err)
+	require.Empty(t, contactSkills)
+}
+
+func TestUpdateUserPassword(t *testing.T) {
+	user1 := CreateRandomUser(t)
+
+	hashedPassword, err := auth.HashPassword(auth.RandomString(8))
+	require.NoError(t, err)
+
+	user2, err := testQueries.UpdateUserPassword(context.Background(), UpdateUserPasswordParams{
+		Username:       user1.Username,
+		HashedPassword: hashedPassword,
+	})
+	require.NoError(t, err)
+	require.Equal(t, user1.Username, user2.Username)
+	require.Equal(t, hashedPassword, user2.HashedPassword)
+	require.NotEqual(t, user1.HashedPassword, user2.HashedPassword)
+	require.WithinDuration(t, time.Now(), user2.PasswordLastChanged, time.Second)
+}
+
+func TestVerifyUserEmail(t *testing.T) {
+	user := CreateRandomUser(t)
+
+	// The email must match the one of the user.
+	_, err := testQueries.VerifyUserEmail(context.Background(), VerifyUserEmailParams{
+		Username: user.Username,
+		Email:    randomdata.Email(),
+	})
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+
+	verifiedUser, err := testQueries.VerifyUserEmail(context.Background(), VerifyUserEmailParams{
+		Username: user.Username,
+		Email:    user.Email,
+	})
+	require.NoError(t, err)
+	require.True(t, verifiedUser.EmailVerified)
+}
+
+func TestMarkEmailVerificationSent(t *testing.T) {
+	user := CreateRandomUser(t)
+
+	// A link has just been sent at signup.
+	_, err := testQueries.MarkEmailVerificationSent(context.Background(), MarkEmailVerificationSentParams{
+		Email:      user.Email,
+		SentBefore: time.Now().Add(-time.Minute),
+	})
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+
+	updatedUser, err := testQueries.MarkEmailVerificationSent(context.Background(), MarkEmailVerificationSentParams{
+		Email:      user.Email,
+		SentBefore: time.Now().Add(time.Minute),
+	})
+	require.NoError(t, err)
+	require.Equal(t, user.Username, updatedUser.Username)
+	require.True(t, updatedUser.EmailVerificationSentAt.Time.After(user.EmailVerificationSentAt.Time))
+
+	// Verified users are never sent a new link.
+	_, err = testQueries.VerifyUserEmail(context.Background(), VerifyUserEmailParams{
+		Username: user.Username,
+		Email:    user.Email,
+	})
+	require.NoError(t, err)
+
+	_, err = testQueries.MarkEmailVerificationSent(context.Background(), MarkEmailVerificationSentParams{
+		Email:      user.Email,
+		SentBefore: time.Now().Add(time.Minute),
+	})
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+}
+
+func TestMarkPasswordResetSent(t *testing.T) {
+	user := CreateRandomUser(t)
+	require.False(t, user.PasswordResetSentAt.Valid)
+
+	updatedUser, err := testQueries.MarkPasswordResetSent(context.Background(), MarkPasswordResetSentParams{
+		Email:      user.Email,
+		SentBefore: time.Now().Add(-time.Minute),
+	})
+	require.NoError(t, err)
+	require.Equal(t, user.Username, updatedUser.Username)
+	require.True(t, updatedUser.PasswordResetSentAt.Valid)
+
+	// A token has just been sent.
+	_, err = testQueries.MarkPasswordResetSent(context.Background(), MarkPasswordResetSentParams{
+		Email:      user.Email,
+		SentBefore: time.Now().Add(-time.Minute),
+	})
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+
+	_, err = testQueries.MarkPasswordResetSent(context.Background(), MarkPasswordResetSentParams{
+		Email:      user.Email,
+		SentBefore: time.Now().Add(time.Minute),
+	})
+	require.NoError(t, err)
+}
+
+func TestUpdateUserPasswordHash(t *testing.T) {
+	user1 := CreateRandomUser(t)
+	hashedPassword, err := auth.HashPassword("password")
+	require.NoError(t, err)
+
+	// The hash is not replaced if the password has been changed in the meantime.
+	err = testQueries.UpdateUserPasswordHash(context.Background(), UpdateUserPasswordHashParams{
+		Username:          user1.Username,
+		HashedPassword:    hashedPassword,
+		OldHashedPassword: "outdated",
+	})
+	require.NoError(t, err)
+	user2, err := testQueries.GetUser(context.Background(), user1.Username)
+	require.NoError(t, err)
+	require.Equal(t, user1.HashedPassword, user2.HashedPassword)
+
+	err = testQueries.UpdateUserPasswordHash(context.Background(), UpdateUserPasswordHashParams{
+		Username:          user1.Username,
+		HashedPassword:    hashedPassword,
+		OldHashedPassword: user1.HashedPassword,
+	})
+	require.NoError(t, err)
+	user2, err = testQueries.GetUser(context.Background(), user1.Username)
+	require.NoError(t, err)
+	require.Equal(t, hashedPassword, user2.HashedPassword)
+	// A rehash is not a password change.
+	require.Equal(t, user1.PasswordLastChanged, user2.PasswordLastChanged)
+}
+
+func TestUpdateUserProfile(t *testing.T) {
+	user1 := CreateRandomUser(t)
+	_, err := testQueries.VerifyUserEmail(context.Background(), VerifyUserEmailParams{
+		Username: user1.Username,
+		Email:    user1.Email,
+	})
+	require.NoError(t, err)
+
+	// The email stays verified when it is not changed.
+	user2, err := testQueries.UpdateUserProfile(context.Background(), UpdateUserProfileParams{
+		Username: user1.Username,
+		Fullname: randomdata.FullName(randomdata.Male),
+		Email:    user1.Email,
+	})
+	require.NoError(t, err)
+	require.NotEqual(t, user1.Fullname, user2.Fullname)
+	require.True(t, user2.EmailVerified)
+
+	// A new email has to be verified.
+	user3, err := testQueries.UpdateUserProfile(context.Background(), UpdateUserProfileParams{
+		Username: user1.Username,
+		Fullname: user2.Fullname,
+		Email:    randomdata.Email(),
+	})
+	require.NoError(t, err)
+	require.NotEqual(t, user1.Email, user3.Email)
+	require.False(t, user3.EmailVerified)
+	require.True(t, user3.EmailVerificationSentAt.Time.After(user1.EmailVerificationSentAt.Time))
+
+	// Emails are unique.
+	otherUser := CreateRandomUser(t)
+	_, err = testQueries.UpdateUserProfile(context.Background(), UpdateUserProfileParams{
+		Username: user1.Username,
+		Fullname: user2.Fullname,
+		Email:    otherUser.Email,
+	})
+	require.Error(t, err)
+}
+
+func TestDisableUser(t *testing.T) {
+	user := CreateRandomUser(t)
+
+	disabledUser, err := testQueries.DisableUser(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.True(t, disabledUser.DisabledAt.Valid)
+
+	// An user can only be disabled once.
+	_, err = testQueries.DisableUser(context.Background(), user.Username)
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+
+	enabledUser, err := testQueries.EnableUser(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.False(t, enabledUser.DisabledAt.Valid)
+
+	_, err = testQueries.EnableUser(context.Background(), user.Username)
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+}
@@ -55,14 +55,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/contact-skills/{id}": {
+        "/admin/audit-logs": {
             "get": {
                 "security": [
                     {
                         "bearerAuth": []
                     }
                 ],
-                "description": "This function is used to get all the skills for a given contact id.",
+                "description": "This function is used by admins to list the actions made through the admin routes, most recent first.\nThe list can be restricted to the actions on an user.",
                 "consumes": [
                     "application/x-www-form-urlencoded"
                 ],
@@ -70,16 +70,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Contact"
+                    "admin"
                 ],
-                "summary": "Get a contact's skills",
+                "summary": "List audit logs",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path",
+                        "description": "page_id",
+                        "name": "page_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page_size",
+                        "name": "page_size",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "target",
+                        "name": "target",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -88,54 +101,35 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/db.Skill"
+                                "$ref": "#/definitions/db.AuditLog"
                             }
                         }
                     }
                 }
             }
         },
-        "/contacts": {
+        "/admin/oauth-clients": {
             "get": {
                 "security": [
                     {
                         "bearerAuth": []
                     }
                 ],
-                "description": "This function is used to list contacts for an user.",
-                "consumes": [
-                    "application/x-www-form-urlencoded"
-                ],
+                "description": "This function is used by admins to list the registered OAuth2 clients.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Contact"
-                ],
-                "summary": "List contacts",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "page_id",
-                        "name": "page_id",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "page_size",
-                        "name": "page_size",
-                        "in": "query",
-                        "required": true
-                    }
+                    "admin"
                 ],
+                "summary": "List OAuth2 clients",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/db.Contact"
+                                "$ref": "#/definitions/api.oauthClientResponse"
                             }
                         }
                     }
@@ -147,7 +141,7 @@ const docTemplate = `{
                         "bearerAuth": []
                     }
                 ],
-                "description": "This function is used to create a contact for an user.",
+                "description": "This function is used by admins to register a service which gets access tokens with the client credentials grant on /oauth/token.\nThe client acts on behalf of its owner, contacts and skills it creates belong to the owner. The secret is only returned once.\nScopes are contacts:read, contacts:write, skills:read and skills:write, clients of viewers can only be given read scopes.",
                 "consumes": [
                     "application/json"
                 ],
@@ -155,17 +149,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Contact"
+                    "admin"
                 ],
-                "summary": "Create a contact",
+                "summary": "Register an OAuth2 client",
                 "parameters": [
                     {
-                        "description": "Create Contact",
-                        "name": "contact",
+                        "description": "Create OAuth2 client",
+                        "name": "client",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api.createContactRequest"
+                            "$ref": "#/definitions/api.createOAuthClientRequest"
                         }
                     }
                 ],
@@ -173,57 +167,57 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/db.Contact"
+                            "$ref": "#/definitions/api.createOAuthClientResponse"
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/admin/oauth-clients/{id}": {
+            "delete": {
                 "security": [
                     {
                         "bearerAuth": []
                     }
                 ],
-                "description": "This function is used to update a contact for an user.",
+                "description": "This function is used by admins to delete an OAuth2 client, its access tokens stop working right away.",
                 "consumes": [
-                    "application/json"
+                    "application/x-www-form-urlencoded"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Contact"
+                    "admin"
                 ],
-                "summary": "Update a contact",
+                "summary": "Delete an OAuth2 client",
                 "parameters": [
                     {
-                        "description": "Update Contact",
-                        "name": "contact",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api.updateContactRequest"
-                        }
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Successfully deleted OAuth2 client.",
                         "schema": {
-                            "$ref": "#/definitions/db.Contact"
+                            "type": "string"
                         }
                     }
                 }
             }
         },
-        "/contacts-with-skill": {
+        "/admin/users": {
             "get": {
                 "security": [
                     {
                         "bearerAuth": []
                     }
                 ],
-                "description": "This function is used to list all contacts with a given skill for an user.",
+                "description": "This function is used by admins to list users, optionally searching their username, full name or email.",
                 "consumes": [
                     "application/x-www-form-urlencoded"
                 ],
@@ -231,16 +225,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Contact"
+                    "admin"
                 ],
-                "summary": "Get all the contacts with skill contacts",
+                "summary": "List users",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "skill_name",
-                        "name": "skill_name",
+                        "type": "integer",
+                        "description": "page_id",
+                        "name": "page_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page_size",
+                        "name": "page_size",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "search",
+                        "name": "search",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -249,21 +256,21 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/db.Contact"
+                                "$ref": "#/definitions/api.adminUserResponse"
                             }
                         }
                     }
                 }
             }
         },
-        "/contacts-with-skill-and-level": {
+        "/admin/users/{username}": {
             "get": {
                 "security": [
                     {
                         "bearerAuth": []
                     }
                 ],
-                "description": "This function is used to list all contacts with a given skill and a given level for an user.",
+                "description": "This function is used by admins to get an user along with the number of contacts and skills it owns.",
                 "consumes": [
                     "application/x-www-form-urlencoded"
                 ],
@@ -271,22 +278,15 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Contact"
+                    "admin"
                 ],
-                "summary": "Get all the contacts with skill and level contacts",
+                "summary": "Get an user",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "skill_name",
-                        "name": "skill_name",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "skill_level",
-                        "name": "skill_level",
-                        "in": "query",
+                        "description": "username",
+                        "name": "username",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -294,23 +294,20 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/db.Contact"
-                            }
+                            "$ref": "#/definitions/api.adminUserResponse"
                         }
                     }
                 }
             }
         },
-        "/contacts/{id}": {
-            "get": {
+        "/admin/users/{username}/disable": {
+            "post": {
                 "security": [
                     {
                         "bearerAuth": []
                     }
                 ],
-                "description": "This function is used to get a contact for an user.",
+                "description": "This function is used by admins to disable an account, every session of the user is revoked and it can not login anymore.\nIts API keys stop working as well. Admins can not disable their own account.",
                 "consumes": [
                     "application/x-www-form-urlencoded"
                 ],
@@ -318,14 +315,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Contact"
+                    "admin"
                 ],
-                "summary": "Get a contact",
+                "summary": "Disable an user",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "id",
-                        "name": "id",
+                        "type": "string",
+                        "description": "username",
+                        "name": "username",
                         "in": "path",
                         "required": true
                     }
@@ -334,18 +331,20 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/db.Contact"
+                            "$ref": "#/definitions/api.adminUserResponse"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/admin/users/{username}/enable": {
+            "post": {
                 "security": [
                     {
                         "bearerAuth": []
                     }
                 ],
-                "description": "This function is used to delete a contact for an user.",
+                "description": "This function is used by admins to enable a disabled account again, the user has to login again.",
                 "consumes": [
                     "application/x-www-form-urlencoded"
                 ],
@@ -353,36 +352,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Contact"
+                    "admin"
                 ],
-                "summary": "Delete a contact",
+                "summary": "Enable an user",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "id",
-                        "name": "id",
+                        "type": "string",
+                        "description": "username",
+                        "name": "username",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully deleted contact.",
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/api.adminUserResponse"
                         }
                     }
                 }
             }
         },
-        "/skills": {
-            "get": {
+        "/admin/users/{username}/password/reset": {
+            "post": {
                 "security": [
                     {
                         "bearerAuth": []
                     }
                 ],
-                "description": "This function is used to list skills for an user.",
+                "description": "This function is used by admins to reset the password of an user. The current password stops working,\nevery session is revoked and a password reset token is sent to the email of the user to choose a new one.",
                 "consumes": [
                     "application/x-www-form-urlencoded"
                 ],
@@ -390,226 +389,303 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Skill"
+                    "admin"
                 ],
-                "summary": "List skills",
+                "summary": "Reset the password of an user",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "page_id",
-                        "name": "page_id",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "page_size",
-                        "name": "page_size",
-                        "in": "query",
+                        "type": "string",
+                        "description": "username",
+                        "name": "username",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Successfully reset password.",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/db.Skill"
-                            }
+                            "type": "string"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/admin/users/{username}/sessions/revoke": {
             "post": {
                 "security": [
                     {
                         "bearerAuth": []
                     }
                 ],
-                "description": "This function is used to create a skill for an user.",
+                "description": "This function is used by admins to revoke every session of an user along with the access tokens issued from them.",
                 "consumes": [
-                    "application/json"
+                    "application/x-www-form-urlencoded"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Skill"
+                    "admin"
                 ],
-                "summary": "Create a skill",
+                "summary": "Revoke the sessions of an user",
                 "parameters": [
                     {
-                        "description": "Create Skill",
-                        "name": "skill",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api.createSkillRequest"
-                        }
+                        "type": "string",
+                        "description": "username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Successfully revoked sessions.",
                         "schema": {
-                            "$ref": "#/definitions/db.Skill"
+                            "type": "string"
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/admin/users/{username}/unlock": {
+            "post": {
                 "security": [
                     {
                         "bearerAuth": []
                     }
                 ],
-                "description": "This function is used to update a skill for an user.",
+                "description": "This function is used by admins to forget the failed logins of an user so that they can login again right away.\nOnly the failures of the username are forgotten, those of the client IP the user logs in from are forgotten\nas well when it is given.",
                 "consumes": [
-                    "application/json"
+                    "application/x-www-form-urlencoded"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Skill"
+                    "admin"
                 ],
-                "summary": "Update a skill",
+                "summary": "Unlock the login of an user",
                 "parameters": [
                     {
-                        "description": "Update Skill",
-                        "name": "skill",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api.updateSkillRequest"
-                        }
+                        "type": "string",
+                        "description": "username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ip",
+                        "name": "ip",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Successfully unlocked user.",
                         "schema": {
-                            "$ref": "#/definitions/db.Skill"
+                            "type": "string"
                         }
                     }
                 }
             }
         },
-        "/skills/{id}": {
+        "/api-keys": {
             "get": {
                 "security": [
                     {
                         "bearerAuth": []
                     }
                 ],
-                "description": "This function is used to get a skill for an user.",
-                "consumes": [
-                    "application/x-www-form-urlencoded"
-                ],
+                "description": "This function is used to list the API keys of an user.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Skill"
-                ],
-                "summary": "Get a skill",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "api-key"
                 ],
+                "summary": "List API keys",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/db.Skill"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/api.apiKeyResponse"
+                            }
                         }
                     }
                 }
             },
-            "delete": {
+            "post": {
                 "security": [
                     {
                         "bearerAuth": []
                     }
                 ],
-                "description": "This function is used to delete a skill for an user.",
+                "description": "This function is used to create a long-lived API key for machine clients.\nThe key is only returned once, it must be sent in the Authorization header as \"ApiKey \u003ckey\u003e\".\nScopes are contacts:read, contacts:write, skills:read and skills:write, viewers can only request read scopes.",
                 "consumes": [
-                    "application/x-www-form-urlencoded"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Skill"
+                    "api-key"
                 ],
-                "summary": "Delete a skill",
+                "summary": "Create an API key",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Create API key",
+                        "name": "apiKey",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.createAPIKeyRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully deleted skill.",
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/api.createAPIKeyResponse"
                         }
                     }
                 }
             }
         },
-        "/tokens/renew_access": {
-            "post": {
+        "/api-keys/{id}": {
+            "delete": {
                 "security": [
                     {
                         "bearerAuth": []
                     }
                 ],
-                "description": "This function is used to renew an access token for an user provinding the sessionToken.",
+                "description": "This function is used to revoke an API key, it can not be used anymore.",
                 "consumes": [
-                    "application/json"
+                    "application/x-www-form-urlencoded"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "token"
+                    "api-key"
                 ],
-                "summary": "Renew access token",
+                "summary": "Revoke an API key",
                 "parameters": [
                     {
-                        "description": "Login User",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully deleted API key.",
                         "schema": {
-                            "$ref": "#/definitions/api.renewAccessTokenRequest"
+                            "type": "string"
                         }
                     }
+                }
+            }
+        },
+        "/contact-skills/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "This function is used to get all the skills for a given contact id.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Contact"
+                ],
+                "summary": "Get a contact's skills",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api.renewAccessTokenResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/db.Skill"
+                            }
                         }
                     }
                 }
             }
         },
-        "/users": {
+        "/contacts": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "This function is used to list contacts for an user.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Contact"
+                ],
+                "summary": "List contacts",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "page_id",
+                        "name": "page_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page_size",
+                        "name": "page_size",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/db.Contact"
+                            }
+                        }
+                    }
+                }
+            },
             "post": {
-                "description": "This function is used to create a new user account.",
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "This function is used to create a contact for an user.",
                 "consumes": [
                     "application/json"
                 ],
@@ -617,17 +693,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "Contact"
                 ],
-                "summary": "Create a new user",
+                "summary": "Create a contact",
                 "parameters": [
                     {
-                        "description": "Create User",
-                        "name": "user",
+                        "description": "Create Contact",
+                        "name": "contact",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api.createUserRequest"
+                            "$ref": "#/definitions/api.createContactRequest"
                         }
                     }
                 ],
@@ -635,20 +711,18 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api.userResponse"
+                            "$ref": "#/definitions/db.Contact"
                         }
                     }
                 }
-            }
-        },
-        "/users/login": {
-            "post": {
+            },
+            "patch": {
                 "security": [
                     {
                         "bearerAuth": []
                     }
                 ],
-                "description": "This function is used to authenticate a user providing the username and password.",
+                "description": "This function is used to update a contact for an user.",
                 "consumes": [
                     "application/json"
                 ],
@@ -656,17 +730,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "Contact"
                 ],
-                "summary": "Login an user",
+                "summary": "Update a contact",
                 "parameters": [
                     {
-                        "description": "Login User",
-                        "name": "user",
+                        "description": "Update Contact",
+                        "name": "contact",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api.loginUserRequest"
+                            "$ref": "#/definitions/api.updateContactRequest"
                         }
                     }
                 ],
@@ -674,95 +748,2025 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api.loginUserResponse"
+                            "$ref": "#/definitions/db.Contact"
+                        }
+                    }
+                }
+            }
+        },
+        "/contacts-with-skill": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "This function is used to list all contacts with a given skill for an user.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Contact"
+                ],
+                "summary": "Get all the contacts with skill contacts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "skill_name",
+                        "name": "skill_name",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/db.Contact"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/contacts-with-skill-and-level": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "This function is used to list all contacts with a given skill and a given level for an user.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Contact"
+                ],
+                "summary": "Get all the contacts with skill and level contacts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "skill_name",
+                        "name": "skill_name",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "skill_level",
+                        "name": "skill_level",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/db.Contact"
+                            }
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "api.createContactHasSkillRequest": {
+        },
+        "/contacts/duplicates": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "This function is used to find the pairs of contacts of an user that are probably the same person.\nEmails are compared case insensitively, phone numbers on their digits only and names on their similarity.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Contact"
+                ],
+                "summary": "List probable duplicate contacts",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "min_score",
+                        "name": "min_score",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page_id",
+                        "name": "page_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page_size",
+                        "name": "page_size",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/api.duplicateContactsResponse"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/contacts/export": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "This function is used to download the contacts of an user with their skills as a CSV file or an Excel\nworkbook, one contact per row. The skills of a contact are written in a single column as\n\"name (level)\". The contacts can be filtered by a text found in their full name or email and by a\nskill they have. The file is streamed while the contacts are read.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "Contact"
+                ],
+                "summary": "Export contacts with their skills as CSV or XLSX",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "description": "format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma separated columns, e.g : fullname,email,skills",
+                        "name": "columns",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "q",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "skill_name",
+                        "name": "skill_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "skill_level",
+                        "name": "skill_level",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/contacts/export.vcf": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "This function is used to export all the contacts of an user in a single vCard 4.0 file, their skills are\nwritten as categories.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "text/vcard"
+                ],
+                "tags": [
+                    "Contact"
+                ],
+                "summary": "Export all contacts as vCards",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/contacts/import": {
+            "post": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "This function is used to create contacts from a vCard 3.0 or 4.0 file holding one or more cards. Categories\nwritten as \"name (level)\" are bound to the skill of that name and level, which is created if needed. They\nare ignored with a warning when the API key or OAuth2 client lacks the skills:write scope.\nEach card is imported on its own : the cards that can't be read or miss a field of a contact are reported\nand don't prevent the other cards from being imported.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Contact"
+                ],
+                "summary": "Import contacts from vCards",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "vCard file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.importContactsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/contacts/import/csv": {
+            "post": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "This function is used to create contacts from the rows of a CSV file. The mapping is a JSON object giving\nthe contact field of the columns, e.g : {\"E-mail\": \"email\"}. When it is left out, the columns must be named\nafter the fields. Every row is validated as a contact created with the API. With dry_run, nothing is\ncreated and the response tells which rows would fail. Big files are imported in the background : the\nresponse is then a job whose progress is polled with /contacts/import/jobs/{id}.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Contact"
+                ],
+                "summary": "Import contacts from a CSV file",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "mapping",
+                        "name": "mapping",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "dry_run",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "comma",
+                            "semicolon",
+                            "tab"
+                        ],
+                        "type": "string",
+                        "description": "delimiter",
+                        "name": "delimiter",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.csvImportResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/api.importJobResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/contacts/import/jobs/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "This function is used to follow the progress of a CSV import running in the background, the errors of\nthe rows that failed so far are listed.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Contact"
+                ],
+                "summary": "Get an import job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.importJobResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/contacts/merge": {
+            "post": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "This function is used to merge a duplicate contact into another one. The skills of the merged contact\nare moved to the surviving contact and the merged contact is deleted, all at once.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Contact"
+                ],
+                "summary": "Merge two contacts",
+                "parameters": [
+                    {
+                        "description": "Merge Contacts",
+                        "name": "merge",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.mergeContactsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.mergeContactsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/contacts/search": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "This function is used to search the contacts of an user by name, email, phone number or address.\nThe search supports the web search syntax (\"quoted phrases\", or, -excluded) and tolerates typos,\nresults are ranked with matches on the name first.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Contact"
+                ],
+                "summary": "Search contacts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "q",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page_id",
+                        "name": "page_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page_size",
+                        "name": "page_size",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/api.contactSearchResult"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/contacts/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "This function is used to get a contact for an user.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Contact"
+                ],
+                "summary": "Get a contact",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.Contact"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "This function is used to delete a contact for an user.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Contact"
+                ],
+                "summary": "Delete a contact",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully deleted contact.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/contacts/{id}.vcf": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "This function is used to get a contact of an user as a vCard 4.0 file, its skills are written as categories.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "text/vcard"
+                ],
+                "tags": [
+                    "Contact"
+                ],
+                "summary": "Get a contact as a vCard",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth/token": {
+            "post": {
+                "description": "This function is used by services to get an access token with the client credentials grant (RFC 6749, section 4.4).\nThe client authenticates with HTTP basic authentication or with the client_id and client_secret parameters.\nThe token acts on behalf of the owner of the client and is restricted to the requested scopes, every scope of the client by default.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "oauth"
+                ],
+                "summary": "Get an access token for an OAuth2 client",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "client_credentials",
+                        "name": "grant_type",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "space separated scopes",
+                        "name": "scope",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "client_id",
+                        "name": "client_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "client_secret",
+                        "name": "client_secret",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.oauthTokenResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "This function is used to list the active sessions of an user, along with the device they are used from.\nThe session the access token was issued from is flagged as the current one. The ip_changed and user_agent_changed\nflags are set when the session was last renewed from another IP address or user agent than the ones it was created with.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "session"
+                ],
+                "summary": "List sessions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/api.sessionResponse"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "This function is used to revoke a session of an user, e.g : on another device.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "session"
+                ],
+                "summary": "Delete a session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully deleted session.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/skills": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "This function is used to list skills for an user.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Skill"
+                ],
+                "summary": "List skills",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "page_id",
+                        "name": "page_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page_size",
+                        "name": "page_size",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/db.Skill"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "This function is used to create a skill for an user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Skill"
+                ],
+                "summary": "Create a skill",
+                "parameters": [
+                    {
+                        "description": "Create Skill",
+                        "name": "skill",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.createSkillRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.Skill"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "This function is used to update a skill for an user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Skill"
+                ],
+                "summary": "Update a skill",
+                "parameters": [
+                    {
+                        "description": "Update Skill",
+                        "name": "skill",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.updateSkillRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.Skill"
+                        }
+                    }
+                }
+            }
+        },
+        "/skills/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "This function is used to get a skill for an user.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Skill"
+                ],
+                "summary": "Get a skill",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.Skill"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "This function is used to delete a skill for an user.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Skill"
+                ],
+                "summary": "Delete a skill",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully deleted skill.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tokens/public_key": {
+            "get": {
+                "description": "This function is used to publish the hex encoded Ed25519 public keys so that other services can verify tokens offline.\npublic_key is the current signing key, keys lists every key still accepted along with the key ID found in the token footer.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "token"
+                ],
+                "summary": "Get the token public keys",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.tokenPublicKeyResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tokens/renew_access": {
+            "post": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "This function is used to renew an access token for an user provinding the sessionToken.\nThe session token is rotated : the returned session token replaces the one provided, which can not be used anymore.\nPresenting an already rotated session token revokes every session of its family.\nWarnings are returned when the IP address or the user agent differ from the ones the session was created with,\nsuch renewals are refused when SESSION_BLOCK_DEVICE_CHANGE is set.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "token"
+                ],
+                "summary": "Renew access token",
+                "parameters": [
+                    {
+                        "description": "Login User",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.renewAccessTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.renewAccessTokenResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "post": {
+                "description": "This function is used to create a new user account.\nA verification link is sent to the email, unverified users are refused or limited to the viewer role at login depending on the configuration.\nThe password must follow the password policy and must not contain the username or the email.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Create a new user",
+                "parameters": [
+                    {
+                        "description": "Create User",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.createUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.userResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/2fa/disable": {
+            "post": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "This function is used to turn off two-factor authentication, the password of the user is asked again.\nThe secret and the recovery codes are deleted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "two-factor"
+                ],
+                "summary": "Disable two-factor authentication",
+                "parameters": [
+                    {
+                        "description": "Disable Two-Factor",
+                        "name": "password",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.disableTOTPRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.userResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/2fa/enable": {
+            "post": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "This function is used to turn on two-factor authentication by confirming a code of the enrolled secret.\nOne-time recovery codes are returned, they can be used instead of a code if the authenticator is lost.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "two-factor"
+                ],
+                "summary": "Enable two-factor authentication",
+                "parameters": [
+                    {
+                        "description": "Enable Two-Factor",
+                        "name": "code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.enableTOTPRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.enableTOTPResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/2fa/enroll": {
+            "post": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "This function is used to generate a new TOTP secret for the logged in user.\nThe provisioning URI is meant to be shown as a QR code to an authenticator application, two-factor authentication is turned on once a code is confirmed on /users/2fa/enable.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "two-factor"
+                ],
+                "summary": "Enroll two-factor authentication",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.enrollTOTPResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/email/verify": {
+            "get": {
+                "description": "This function is used to verify the email of an user with the signed link sent at signup.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Verify the email of an user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "username",
+                        "name": "username",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "expires",
+                        "name": "expires",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "signature",
+                        "name": "signature",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.userResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/email/verify/resend": {
+            "post": {
+                "description": "This function is used to send a new verification link to the email of an user who did not verify it yet.\nA link is sent at most once per EMAIL_VERIFICATION_RESEND_INTERVAL and the response does not tell whether it was sent.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Resend the verification email",
+                "parameters": [
+                    {
+                        "description": "Resend Verification Email",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.resendVerificationEmailRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "If an unverified account exists for this email, a verification link has been sent.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/login": {
+            "post": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "This function is used to authenticate a user providing the username and password.\nUnknown usernames and wrong passwords get the same response, logins are refused for a while after too many failures.\nPasswords hashed with an older algorithm or weaker parameters are rehashed on a successful login.\nWhen two-factor authentication is enabled, a challenge token is returned instead and the login is finished on /users/login/2fa.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Login an user",
+                "parameters": [
+                    {
+                        "description": "Login User",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.loginUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.twoFactorChallengeResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/login/2fa": {
+            "post": {
+                "description": "This function is used to finish a login with the challenge token returned by /users/login and a TOTP code or a recovery code.\nA challenge token can be used for a few attempts only, codes are refused while the user or the client IP is locked out.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Finish the login of an user with two-factor authentication",
+                "parameters": [
+                    {
+                        "description": "Login Two-Factor",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.loginTwoFactorRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.loginUserResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/logout": {
+            "post": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "This function is used to block the session the access token was issued from.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Logout an user",
+                "responses": {
+                    "200": {
+                        "description": "Successfully logged out.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me": {
+            "get": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "This function is used to get the account of the logged in user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get the profile of the logged in user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.userResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "This function is used to delete the account of the logged in user, the password is asked again.\nThe contacts, skills, sessions and API keys of the user are deleted along with it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Delete the account of the logged in user",
+                "parameters": [
+                    {
+                        "description": "Delete Account",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.deleteAccountRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully deleted account.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "This function is used to change the full name or the email of the logged in user.\nA new email must not be used by another account, it has to be verified again with the link sent to it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Update the profile of the logged in user",
+                "parameters": [
+                    {
+                        "description": "Update Profile",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.updateProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.userResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/oidc/callback": {
+            "get": {
+                "description": "This function is used by the identity provider to send the user back with an authorization code.\nUsers signing in for the first time are created from the claims of the ID token, with the email verified if the identity provider says so.\nNo account is created when the email is already used by an user who did not sign in with the identity provider.\nWhen two-factor authentication is enabled, a challenge token is returned instead and the login is finished on /users/login/2fa.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Finish a login with the identity provider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "code",
+                        "name": "code",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "state",
+                        "name": "state",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.twoFactorChallengeResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/oidc/login": {
+            "get": {
+                "description": "This function is used to start a login with the OpenID Connect identity provider, the user is redirected to it.\nThe identity provider sends the user back to /users/oidc/callback, which returns the same response as /users/login.",
+                "tags": [
+                    "user"
+                ],
+                "summary": "Login with the identity provider",
+                "responses": {
+                    "302": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/users/password": {
+            "patch": {
+                "security": [
+                    {
+                        "bearerAuth": []
+                    }
+                ],
+                "description": "This function is used to change the password of the logged in user.\nEvery session of the user is revoked along with the access tokens issued from them, the user has to login again.\nThe new password must follow the password policy.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Change the password of an user",
+                "parameters": [
+                    {
+                        "description": "Change Password",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.changePasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.userResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/password/forgot": {
+            "post": {
+                "description": "This function is used to send a password reset token to the email of an user.\nA token is sent at most once per PASSWORD_RESET_RESEND_INTERVAL, it replaces the unused tokens sent before.\nThe response is the same whether an account exists for the email or not.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Request a password reset",
+                "parameters": [
+                    {
+                        "description": "Forgot Password",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.forgotPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "If an account exists for this email, a password reset token has been sent.",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/password/reset": {
+            "post": {
+                "description": "This function is used to choose a new password with the token received by email.\nThe token can only be used once and every session of the user is revoked.\nThe new password must follow the password policy.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Reset the password of an user",
+                "parameters": [
+                    {
+                        "description": "Reset Password",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.resetPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.userResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "api.adminUserResponse": {
+            "type": "object",
+            "properties": {
+                "contact_count": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "disabled": {
+                    "type": "boolean"
+                },
+                "disabled_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "email_verified": {
+                    "type": "boolean"
+                },
+                "fullname": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "skill_count": {
+                    "type": "integer"
+                },
+                "two_factor_enabled": {
+                    "type": "boolean"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.apiKeyResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "api.changePasswordRequest": {
+            "type": "object",
+            "required": [
+                "new_password",
+                "old_password"
+            ],
+            "properties": {
+                "new_password": {
+                    "type": "string"
+                },
+                "old_password": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.contactSearchResult": {
+            "type": "object",
+            "properties": {
+                "contact": {
+                    "$ref": "#/definitions/db.Contact"
+                },
+                "highlights": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "rank": {
+                    "type": "number"
+                }
+            }
+        },
+        "api.createAPIKeyRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "api.createAPIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "api.createContactHasSkillRequest": {
+            "type": "object",
+            "required": [
+                "contact_id",
+                "skill_id"
+            ],
+            "properties": {
+                "contact_id": {
+                    "type": "integer"
+                },
+                "skill_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.createContactRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "firstname",
+                "fullname",
+                "home_address",
+                "lastname",
+                "phone_number"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "firstname": {
+                    "type": "string"
+                },
+                "fullname": {
+                    "type": "string"
+                },
+                "home_address": {
+                    "type": "string"
+                },
+                "lastname": {
+                    "type": "string"
+                },
+                "phone_number": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.createOAuthClientRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "owner",
+                "scopes"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "owner": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "api.createOAuthClientResponse": {
+            "type": "object",
+            "properties": {
+                "client_id": {
+                    "type": "string"
+                },
+                "client_secret": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "owner": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "api.createSkillRequest": {
+            "type": "object",
+            "required": [
+                "skill_level",
+                "skill_name"
+            ],
+            "properties": {
+                "skill_level": {
+                    "type": "string"
+                },
+                "skill_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.createUserRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "full_name",
+                "password",
+                "username"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "full_name": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.csvImportResponse": {
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.csvRowError"
+                    }
+                },
+                "failed_rows": {
+                    "type": "integer"
+                },
+                "imported_rows": {
+                    "type": "integer"
+                },
+                "total_rows": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.csvRowError": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "line": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.deleteAccountRequest": {
+            "type": "object",
+            "required": [
+                "password"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.disableTOTPRequest": {
+            "type": "object",
+            "required": [
+                "password"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.duplicateContactsResponse": {
+            "type": "object",
+            "properties": {
+                "contact_id": {
+                    "type": "integer"
+                },
+                "duplicate_id": {
+                    "type": "integer"
+                },
+                "email_match": {
+                    "type": "boolean"
+                },
+                "name_similarity": {
+                    "type": "number"
+                },
+                "phone_match": {
+                    "type": "boolean"
+                },
+                "score": {
+                    "type": "number"
+                }
+            }
+        },
+        "api.enableTOTPRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.enableTOTPResponse": {
+            "type": "object",
+            "properties": {
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user": {
+                    "$ref": "#/definitions/api.userResponse"
+                }
+            }
+        },
+        "api.enrollTOTPResponse": {
+            "type": "object",
+            "properties": {
+                "provisioning_uri": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.forgotPasswordRequest": {
             "type": "object",
             "required": [
-                "contact_id",
-                "skill_id"
+                "email"
             ],
             "properties": {
-                "contact_id": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.importContactsResponse": {
+            "type": "object",
+            "properties": {
+                "cards": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.importedCardResponse"
+                    }
+                },
+                "failed": {
                     "type": "integer"
                 },
-                "skill_id": {
+                "imported": {
                     "type": "integer"
                 }
             }
         },
-        "api.createContactRequest": {
+        "api.importJobResponse": {
             "type": "object",
-            "required": [
-                "email",
-                "firstname",
-                "fullname",
-                "home_address",
-                "lastname",
-                "phone_number"
-            ],
             "properties": {
-                "email": {
+                "created_at": {
                     "type": "string"
                 },
-                "firstname": {
-                    "type": "string"
+                "dry_run": {
+                    "type": "boolean"
                 },
-                "fullname": {
+                "error": {
                     "type": "string"
                 },
-                "home_address": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.csvRowError"
+                    }
+                },
+                "failed_rows": {
+                    "type": "integer"
+                },
+                "finished_at": {
                     "type": "string"
                 },
-                "lastname": {
+                "id": {
                     "type": "string"
                 },
-                "phone_number": {
+                "imported_rows": {
+                    "type": "integer"
+                },
+                "processed_rows": {
+                    "type": "integer"
+                },
+                "status": {
                     "type": "string"
+                },
+                "total_rows": {
+                    "type": "integer"
                 }
             }
         },
-        "api.createSkillRequest": {
+        "api.importedCardResponse": {
             "type": "object",
-            "required": [
-                "skill_level",
-                "skill_name"
-            ],
             "properties": {
-                "skill_level": {
-                    "type": "string"
+                "contact": {
+                    "$ref": "#/definitions/db.Contact"
                 },
-                "skill_name": {
+                "error": {
                     "type": "string"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "skills": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/db.Skill"
+                    }
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "api.createUserRequest": {
+        "api.loginTwoFactorRequest": {
             "type": "object",
             "required": [
-                "email",
-                "full_name",
-                "password",
-                "username"
+                "challenge_token",
+                "code"
             ],
             "properties": {
-                "email": {
-                    "type": "string"
-                },
-                "full_name": {
+                "challenge_token": {
                     "type": "string"
                 },
-                "password": {
-                    "type": "string",
-                    "minLength": 6
-                },
-                "username": {
+                "code": {
                     "type": "string"
                 }
             }
@@ -775,8 +2779,7 @@ const docTemplate = `{
             ],
             "properties": {
                 "password": {
-                    "type": "string",
-                    "minLength": 6
+                    "type": "string"
                 },
                 "username": {
                     "type": "string"
@@ -806,6 +2809,83 @@ const docTemplate = `{
                 }
             }
         },
+        "api.mergeContactsRequest": {
+            "type": "object",
+            "required": [
+                "merged_id",
+                "survivor_id"
+            ],
+            "properties": {
+                "merged_id": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "survivor_id": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "take_from_merged": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "api.mergeContactsResponse": {
+            "type": "object",
+            "properties": {
+                "contact": {
+                    "$ref": "#/definitions/db.Contact"
+                },
+                "moved_skills": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.oauthClientResponse": {
+            "type": "object",
+            "properties": {
+                "client_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "owner": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "api.oauthTokenResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "expires_in": {
+                    "type": "integer"
+                },
+                "scope": {
+                    "type": "string"
+                },
+                "token_type": {
+                    "type": "string"
+                }
+            }
+        },
         "api.renewAccessTokenRequest": {
             "type": "object",
             "required": [
@@ -825,6 +2905,133 @@ const docTemplate = `{
                 },
                 "access_token_expires_at": {
                     "type": "string"
+                },
+                "session_id": {
+                    "type": "string"
+                },
+                "session_token": {
+                    "type": "string"
+                },
+                "session_token_expires_at": {
+                    "type": "string"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "api.resendVerificationEmailRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.resetPasswordRequest": {
+            "type": "object",
+            "required": [
+                "new_password",
+                "token"
+            ],
+            "properties": {
+                "new_password": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.sessionResponse": {
+            "type": "object",
+            "properties": {
+                "client_ip": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "device": {
+                    "type": "string"
+                },
+                "device_details": {
+                    "$ref": "#/definitions/useragent.Device"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ip_changed": {
+                    "type": "boolean"
+                },
+                "is_blocked": {
+                    "type": "boolean"
+                },
+                "is_current": {
+                    "type": "boolean"
+                },
+                "last_refreshed_at": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                },
+                "user_agent_changed": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "api.tokenPublicKey": {
+            "type": "object",
+            "properties": {
+                "key_id": {
+                    "type": "string"
+                },
+                "public_key": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.tokenPublicKeyResponse": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.tokenPublicKey"
+                    }
+                },
+                "public_key": {
+                    "type": "string"
+                },
+                "purpose": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.twoFactorChallengeResponse": {
+            "type": "object",
+            "properties": {
+                "challenge_token": {
+                    "type": "string"
+                },
+                "challenge_token_expires_at": {
+                    "type": "string"
+                },
+                "two_factor_required": {
+                    "type": "boolean"
                 }
             }
         },
@@ -858,6 +3065,17 @@ const docTemplate = `{
                 }
             }
         },
+        "api.updateProfileRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "full_name": {
+                    "type": "string"
+                }
+            }
+        },
         "api.updateSkillRequest": {
             "type": "object",
             "required": [
@@ -885,17 +3103,49 @@ const docTemplate = `{
                 "email": {
                     "type": "string"
                 },
+                "email_verified": {
+                    "type": "boolean"
+                },
                 "fullname": {
                     "type": "string"
                 },
                 "password_last_changed": {
                     "type": "string"
                 },
+                "role": {
+                    "type": "string"
+                },
+                "two_factor_enabled": {
+                    "type": "boolean"
+                },
                 "username": {
                     "type": "string"
                 }
             }
         },
+        "db.AuditLog": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "details": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "target": {
+                    "type": "string"
+                }
+            }
+        },
         "db.Contact": {
             "type": "object",
             "properties": {
@@ -955,6 +3205,23 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "useragent.Device": {
+            "type": "object",
+            "properties": {
+                "browser": {
+                    "type": "string"
+                },
+                "browser_version": {
+                    "type": "string"
+                },
+                "os": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -0,0 +1,86 @@
+package lockout
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/IsuruHaupe/web-api/config"
+	db "github.com/IsuruHaupe/web-api/db/sqlc"
+)
+
+// Stores available through the LOGIN_ATTEMPT_STORE configuration.
+const (
+	storeMemory   = "memory"
+	storePostgres = "postgres"
+)
+
+// Attempts holds the failed attempts recorded for a key, e.g : an username or a client IP.
+type Attempts struct {
+	Failures      int
+	LastFailureAt time.Time
+}
+
+// Store is used to keep track of failed login attempts.
+// This is useful for switching between a single instance in memory and a store shared by every instance.
+type Store interface {
+	// Get returns the attempts recorded for a key, without failures if there are none.
+	Get(ctx context.Context, key string) (Attempts, error)
+	// RecordFailure adds a failure to a key. Failures are counted from scratch again when the previous one
+	// is older than the window.
+	RecordFailure(ctx context.Context, key string, window time.Duration) (Attempts, error)
+	// Reset forgets the failures of a key.
+	Reset(ctx context.Context, key string) error
+}
+
+// New creates the store selected in the configuration, attempts are kept in memory by default.
+func New(config config.Config, querier db.Querier) (Store, error) {
+	switch config.LoginAttemptStore {
+	case "", storeMemory:
+		return NewMemoryStore(), nil
+	case storePostgres:
+		return NewPostgresStore(querier), nil
+	default:
+		return nil, fmt.Errorf("unsupported login attempt store %s", config.LoginAttemptStore)
+	}
+}
+
+// Policy decides how long a key is locked after some failures. The first failures are free, then the delay
+// doubles with every failure until the lockout threshold is reached and the key is locked for the lockout duration.
+type Policy struct {
+	FreeAttempts     int
+	LockoutThreshold int
+	BaseDelay        time.Duration
+	LockoutDuration  time.Duration
+}
+
+// Delay returns how long a key is locked after its last failure.
+func (policy Policy) Delay(failures int) time.Duration {
+	if failures <= policy.FreeAttempts {
+		return 0
+	}
+	if failures >= policy.LockoutThreshold {
+		return policy.LockoutDuration
+	}
+
+	delay := policy.BaseDelay
+	for i := policy.FreeAttempts + 1; i < failures; i++ {
+		delay *= 2
+		if delay >= policy.LockoutDuration {
+			return policy.LockoutDuration
+		}
+	}
+	return delay
+}
+
+// Remaining returns how long a key is still locked at the given time, zero if it is not locked.
+func (policy Policy) Remaining(attempts Attempts, now time.Time) time.Duration {
+	if attempts.Failures == 0 {
+		return 0
+	}
+	lockedUntil := attempts.LastFailureAt.Add(policy.Delay(attempts.Failures))
+	if now.Before(lockedUntil) {
+		return lockedUntil.Sub(now)
+	}
+	return 0
+}
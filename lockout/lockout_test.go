@@ -0,0 +1,55 @@
+package lockout
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyDelay(t *testing.T) {
+	policy := Policy{
+		FreeAttempts:     3,
+		LockoutThreshold: 10,
+		BaseDelay:        time.Second,
+		LockoutDuration:  time.Minute,
+	}
+
+	testCases := []struct {
+		failures int
+		delay    time.Duration
+	}{
+		{0, 0},
+		{3, 0},
+		{4, time.Second},
+		{5, 2 * time.Second},
+		{6, 4 * time.Second},
+		{9, 32 * time.Second},
+		{10, time.Minute},
+		{50, time.Minute},
+	}
+
+	for _, testCase := range testCases {
+		require.Equal(t, testCase.delay, policy.Delay(testCase.failures), "failures : %d", testCase.failures)
+	}
+
+	// The backoff never goes over the lockout duration.
+	policy.LockoutThreshold = 100
+	require.Equal(t, time.Minute, policy.Delay(20))
+}
+
+func TestPolicyRemaining(t *testing.T) {
+	policy := Policy{
+		FreeAttempts:     1,
+		LockoutThreshold: 10,
+		BaseDelay:        time.Minute,
+		LockoutDuration:  time.Hour,
+	}
+	now := time.Now()
+
+	require.Zero(t, policy.Remaining(Attempts{}, now))
+	require.Zero(t, policy.Remaining(Attempts{Failures: 1, LastFailureAt: now}, now))
+	require.Equal(t, time.Minute, policy.Remaining(Attempts{Failures: 2, LastFailureAt: now}, now))
+	require.Equal(t, 30*time.Second, policy.Remaining(Attempts{Failures: 2, LastFailureAt: now}, now.Add(30*time.Second)))
+	require.Zero(t, policy.Remaining(Attempts{Failures: 2, LastFailureAt: now}, now.Add(time.Minute)))
+}
@@ -0,0 +1,72 @@
+package lockout
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps the attempts in memory, they are not shared between instances and are lost on restart.
+type MemoryStore struct {
+	mutex     sync.Mutex
+	attempts  map[string]Attempts
+	lastSweep time.Time
+}
+
+// NewMemoryStore creates a new empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		attempts:  make(map[string]Attempts),
+		lastSweep: time.Now(),
+	}
+}
+
+// Get returns the attempts recorded for a key.
+func (store *MemoryStore) Get(ctx context.Context, key string) (Attempts, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	return store.attempts[key], nil
+}
+
+// RecordFailure adds a failure to a key.
+func (store *MemoryStore) RecordFailure(ctx context.Context, key string, window time.Duration) (Attempts, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	now := time.Now()
+	store.sweep(now, window)
+
+	attempts := store.attempts[key]
+	if now.Sub(attempts.LastFailureAt) > window {
+		attempts.Failures = 0
+	}
+	attempts.Failures++
+	attempts.LastFailureAt = now
+	store.attempts[key] = attempts
+
+	return attempts, nil
+}
+
+// Reset forgets the failures of a key.
+func (store *MemoryStore) Reset(ctx context.Context, key string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	delete(store.attempts, key)
+	return nil
+}
+
+// sweep removes the keys whose failures are outside of the window, at most once per window, so that guessed
+// usernames do not fill the memory.
+func (store *MemoryStore) sweep(now time.Time, window time.Duration) {
+	if now.Sub(store.lastSweep) < window {
+		return
+	}
+	for key, attempts := range store.attempts {
+		if now.Sub(attempts.LastFailureAt) > window {
+			delete(store.attempts, key)
+		}
+	}
+	store.lastSweep = now
+}
@@ -0,0 +1,62 @@
+package lockout
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	attempts, err := store.Get(ctx, "user:john")
+	require.NoError(t, err)
+	require.Zero(t, attempts.Failures)
+
+	for i := 1; i <= 3; i++ {
+		attempts, err = store.RecordFailure(ctx, "user:john", time.Hour)
+		require.NoError(t, err)
+		require.Equal(t, i, attempts.Failures)
+		require.WithinDuration(t, time.Now(), attempts.LastFailureAt, time.Second)
+	}
+
+	attempts, err = store.Get(ctx, "user:john")
+	require.NoError(t, err)
+	require.Equal(t, 3, attempts.Failures)
+
+	// Keys are independent.
+	attempts, err = store.Get(ctx, "ip:127.0.0.1")
+	require.NoError(t, err)
+	require.Zero(t, attempts.Failures)
+
+	err = store.Reset(ctx, "user:john")
+	require.NoError(t, err)
+	attempts, err = store.Get(ctx, "user:john")
+	require.NoError(t, err)
+	require.Zero(t, attempts.Failures)
+}
+
+func TestMemoryStoreWindow(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	_, err := store.RecordFailure(ctx, "user:john", time.Hour)
+	require.NoError(t, err)
+	store.attempts["user:john"] = Attempts{Failures: 5, LastFailureAt: time.Now().Add(-2 * time.Hour)}
+
+	// Failures older than the window are forgotten.
+	attempts, err := store.RecordFailure(ctx, "user:john", time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, 1, attempts.Failures)
+
+	// Stale keys are swept once per window.
+	store.attempts["user:jane"] = Attempts{Failures: 5, LastFailureAt: time.Now().Add(-2 * time.Hour)}
+	store.lastSweep = time.Now().Add(-2 * time.Hour)
+	_, err = store.RecordFailure(ctx, "user:john", time.Hour)
+	require.NoError(t, err)
+	require.NotContains(t, store.attempts, "user:jane")
+	require.Contains(t, store.attempts, "user:john")
+}
@@ -0,0 +1,57 @@
+package lockout
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	db "github.com/IsuruHaupe/web-api/db/sqlc"
+)
+
+// PostgresStore keeps the attempts in the login_attempts table so that they are shared between instances.
+type PostgresStore struct {
+	querier db.Querier
+}
+
+// NewPostgresStore creates a new PostgresStore using the given queries.
+func NewPostgresStore(querier db.Querier) *PostgresStore {
+	return &PostgresStore{
+		querier: querier,
+	}
+}
+
+// Get returns the attempts recorded for a key.
+func (store *PostgresStore) Get(ctx context.Context, key string) (Attempts, error) {
+	loginAttempt, err := store.querier.GetLoginAttempt(ctx, key)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return Attempts{}, nil
+		}
+		return Attempts{}, err
+	}
+	return newAttempts(loginAttempt), nil
+}
+
+// RecordFailure adds a failure to a key in a single statement so that concurrent failures are all counted.
+func (store *PostgresStore) RecordFailure(ctx context.Context, key string, window time.Duration) (Attempts, error) {
+	loginAttempt, err := store.querier.RecordLoginFailure(ctx, db.RecordLoginFailureParams{
+		Key:         key,
+		WindowStart: time.Now().Add(-window),
+	})
+	if err != nil {
+		return Attempts{}, err
+	}
+	return newAttempts(loginAttempt), nil
+}
+
+// Reset forgets the failures of a key.
+func (store *PostgresStore) Reset(ctx context.Context, key string) error {
+	return store.querier.DeleteLoginAttempt(ctx, key)
+}
+
+func newAttempts(loginAttempt db.LoginAttempt) Attempts {
+	return Attempts{
+		Failures:      int(loginAttempt.Failures),
+		LastFailureAt: loginAttempt.LastFailureAt,
+	}
+}
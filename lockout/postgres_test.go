@@ -0,0 +1,57 @@
+package lockout
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/IsuruHaupe/web-api/db/mock"
+	db "github.com/IsuruHaupe/web-api/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresStore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	database := mockdb.NewMockDatabase(ctrl)
+	store := NewPostgresStore(database)
+	ctx := context.Background()
+	now := time.Now()
+
+	database.EXPECT().
+		GetLoginAttempt(gomock.Any(), gomock.Eq("user:john")).
+		Times(1).
+		Return(db.LoginAttempt{}, sql.ErrNoRows)
+	attempts, err := store.Get(ctx, "user:john")
+	require.NoError(t, err)
+	require.Zero(t, attempts.Failures)
+
+	database.EXPECT().
+		RecordLoginFailure(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ interface{}, arg db.RecordLoginFailureParams) (db.LoginAttempt, error) {
+			require.Equal(t, "user:john", arg.Key)
+			require.WithinDuration(t, now.Add(-time.Hour), arg.WindowStart, time.Second)
+			return db.LoginAttempt{Key: arg.Key, Failures: 2, LastFailureAt: now}, nil
+		})
+	attempts, err = store.RecordFailure(ctx, "user:john", time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, Attempts{Failures: 2, LastFailureAt: now}, attempts)
+
+	database.EXPECT().
+		GetLoginAttempt(gomock.Any(), gomock.Eq("user:john")).
+		Times(1).
+		Return(db.LoginAttempt{}, sql.ErrConnDone)
+	_, err = store.Get(ctx, "user:john")
+	require.ErrorIs(t, err, sql.ErrConnDone)
+
+	database.EXPECT().
+		DeleteLoginAttempt(gomock.Any(), gomock.Eq("user:john")).
+		Times(1).
+		Return(nil)
+	err = store.Reset(ctx, "user:john")
+	require.NoError(t, err)
+}
@@ -0,0 +1,43 @@
+package mail
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// LogMailer writes the emails to a file instead of sending them, this is meant for local use.
+type LogMailer struct {
+	sender string
+	mutex  sync.Mutex
+	writer io.Writer
+}
+
+// Create a new LogMailer appending emails to the given file, or writing them to the standard output when path is empty.
+func NewLogMailer(sender string, path string) (Mailer, error) {
+	if path == "" {
+		return &LogMailer{sender: sender, writer: os.Stdout}, nil
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open mail log file : %w", err)
+	}
+	return &LogMailer{sender: sender, writer: file}, nil
+}
+
+// SendEmail writes the email along with a separator.
+func (mailer *LogMailer) SendEmail(to string, subject string, content string) error {
+	message, err := buildMessage(mailer.sender, to, subject, content, time.Now())
+	if err != nil {
+		return err
+	}
+
+	mailer.mutex.Lock()
+	defer mailer.mutex.Unlock()
+
+	_, err = fmt.Fprintf(mailer.writer, "%s\r\n\r\n-----\r\n", message)
+	return err
+}
@@ -0,0 +1,35 @@
+package mail
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogMailer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mail.log")
+	mailer, err := NewLogMailer("no-reply@localhost", path)
+	require.NoError(t, err)
+
+	err = mailer.SendEmail("user@example.com", "First subject", "First content")
+	require.NoError(t, err)
+	err = mailer.SendEmail("user@example.com", "Second subject", "Second content")
+	require.NoError(t, err)
+
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(data), "From: no-reply@localhost\r\n")
+	require.Contains(t, string(data), "To: user@example.com\r\n")
+	require.Contains(t, string(data), "Subject: First subject\r\n")
+	require.Contains(t, string(data), "Second content")
+}
+
+func TestLogMailerHeaderInjection(t *testing.T) {
+	mailer, err := NewLogMailer("no-reply@localhost", filepath.Join(t.TempDir(), "mail.log"))
+	require.NoError(t, err)
+
+	err = mailer.SendEmail("user@example.com\r\nBcc: other@example.com", "Subject", "Content")
+	require.Error(t, err)
+}
@@ -0,0 +1,31 @@
+package mail
+
+import (
+	"fmt"
+
+	"github.com/IsuruHaupe/web-api/config"
+)
+
+// Mailers available through the MAILER configuration.
+const (
+	mailerLog  = "log"
+	mailerSMTP = "smtp"
+)
+
+// Mailer is used to send emails to the users, e.g : password reset instructions.
+// This is useful for switching between a real mail server and a local implementation.
+type Mailer interface {
+	SendEmail(to string, subject string, content string) error
+}
+
+// New creates the mailer selected in the configuration, emails are only logged by default.
+func New(config config.Config) (Mailer, error) {
+	switch config.Mailer {
+	case "", mailerLog:
+		return NewLogMailer(config.MailSender, config.MailLogFile)
+	case mailerSMTP:
+		return NewSMTPMailer(config.MailSender, config.SMTPHost, config.SMTPPort, config.SMTPUsername, config.SMTPPassword)
+	default:
+		return nil, fmt.Errorf("unsupported mailer %s", config.Mailer)
+	}
+}
@@ -0,0 +1,68 @@
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTPMailer sends emails through an SMTP server, using STARTTLS when the server supports it.
+type SMTPMailer struct {
+	sender  string
+	address string
+	auth    smtp.Auth
+}
+
+// Create a new SMTPMailer, no authentication is used when username is empty.
+func NewSMTPMailer(sender string, host string, port int, username string, password string) (Mailer, error) {
+	if sender == "" || host == "" {
+		return nil, errors.New("sender and smtp host are required")
+	}
+
+	mailer := &SMTPMailer{
+		sender:  sender,
+		address: net.JoinHostPort(host, strconv.Itoa(port)),
+	}
+	if username != "" {
+		mailer.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return mailer, nil
+}
+
+// SendEmail sends a plain text email.
+func (mailer *SMTPMailer) SendEmail(to string, subject string, content string) error {
+	message, err := buildMessage(mailer.sender, to, subject, content, time.Now())
+	if err != nil {
+		return err
+	}
+
+	err = smtp.SendMail(mailer.address, mailer.auth, mailer.sender, []string{to}, message)
+	if err != nil {
+		return fmt.Errorf("failed to send email : %w", err)
+	}
+	return nil
+}
+
+// buildMessage formats a plain text email, headers are checked so that they can not be used to inject other headers.
+func buildMessage(from string, to string, subject string, content string, date time.Time) ([]byte, error) {
+	for _, header := range []string{from, to, subject} {
+		if strings.ContainsAny(header, "\r\n") {
+			return nil, errors.New("email headers can not contain line breaks")
+		}
+	}
+
+	var message strings.Builder
+	fmt.Fprintf(&message, "From: %s\r\n", from)
+	fmt.Fprintf(&message, "To: %s\r\n", to)
+	fmt.Fprintf(&message, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&message, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	message.WriteString("\r\n")
+	message.WriteString(strings.ReplaceAll(content, "\n", "\r\n"))
+	return []byte(message.String()), nil
+}
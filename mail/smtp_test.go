@@ -0,0 +1,88 @@
+package mail
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSMTPServer accepts a single email and returns its data on the channel.
+func fakeSMTPServer(t *testing.T) (string, int, <-chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	messages := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		write := func(line string) { conn.Write([]byte(line + "\r\n")) }
+		write("220 localhost ESMTP")
+
+		var data strings.Builder
+		inData := false
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if inData {
+				if line == ".\r\n" {
+					inData = false
+					messages <- data.String()
+					write("250 OK")
+					continue
+				}
+				data.WriteString(line)
+				continue
+			}
+
+			switch command := strings.ToUpper(strings.TrimSpace(line)); {
+			case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+				write("250 localhost")
+			case command == "DATA":
+				inData = true
+				write("354 Start mail input")
+			case command == "QUIT":
+				write("221 Bye")
+				return
+			default:
+				write("250 OK")
+			}
+		}
+	}()
+
+	address := listener.Addr().(*net.TCPAddr)
+	return address.IP.String(), address.Port, messages
+}
+
+func TestSMTPMailer(t *testing.T) {
+	host, port, messages := fakeSMTPServer(t)
+
+	mailer, err := NewSMTPMailer("no-reply@localhost", host, port, "", "")
+	require.NoError(t, err)
+
+	err = mailer.SendEmail("user@example.com", "Subject", "Line 1\nLine 2")
+	require.NoError(t, err)
+
+	message := <-messages
+	require.Contains(t, message, "To: user@example.com\r\n")
+	require.Contains(t, message, "Subject: Subject\r\n")
+	require.Contains(t, message, "Line 1\r\nLine 2")
+}
+
+func TestNewSMTPMailer(t *testing.T) {
+	_, err := NewSMTPMailer("", "localhost", 587, "", "")
+	require.Error(t, err)
+
+	_, err = NewSMTPMailer("no-reply@localhost", "", 587, "", "")
+	require.Error(t, err)
+}
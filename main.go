@@ -1,15 +1,25 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/IsuruHaupe/web-api/api"
 	"github.com/IsuruHaupe/web-api/config"
 	"github.com/IsuruHaupe/web-api/db/postgres"
+	"github.com/IsuruHaupe/web-api/lockout"
+	"github.com/IsuruHaupe/web-api/mail"
 	_ "github.com/lib/pq"
 )
 
+// Time given to the ongoing requests and CSV imports to finish on shutdown, unless configured otherwise.
+const defaultShutdownTimeout = 30 * time.Second
+
 // @securityDefinitions.apikey bearerAuth
 // @in header
 // @name Authorization
@@ -28,15 +38,53 @@ func main() {
 
 	// Create a new PostgresConnection.
 	postgresDatabase := postgres.NewPostgresConnection(connection)
+	// Create the mailer used to send emails to the users.
+	mailer, err := mail.New(config)
+	if err != nil {
+		log.Fatal("cannot create mailer : ", err)
+	}
+	// Create the store keeping track of failed logins.
+	loginAttempts, err := lockout.New(config, postgresDatabase)
+	if err != nil {
+		log.Fatal("cannot create login attempt store : ", err)
+	}
 	// Create the server.
-	server, err := api.NewServer(config, postgresDatabase)
+	server, err := api.NewServer(config, postgresDatabase, mailer, loginAttempts)
 	if err != nil {
 		log.Fatal("cannot create server : ", err)
 	}
 
-	// Start the server.
-	err = server.Start(config.ServerAddress)
+	// The import jobs left running by a previous run will never finish.
+	interrupted, err := server.FailInterruptedImportJobs(context.Background())
 	if err != nil {
+		log.Fatal("cannot fail interrupted import jobs : ", err)
+	}
+	if interrupted > 0 {
+		log.Printf("%d interrupted import jobs marked as failed", interrupted)
+	}
+
+	// Start the server.
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.Start(config.ServerAddress)
+	}()
+
+	// Stop the server gracefully on SIGTERM, which is sent on deploys, or on Ctrl+C.
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGTERM, os.Interrupt)
+	select {
+	case err = <-serverErr:
 		log.Fatal("Error when starting the server : ", err)
+	case <-stop:
+	}
+
+	shutdownTimeout := config.ShutdownTimeout
+	if shutdownTimeout == 0 {
+		shutdownTimeout = defaultShutdownTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Fatal("Error when shutting down the server : ", err)
 	}
 }
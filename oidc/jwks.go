@@ -0,0 +1,172 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Minimum delay between two downloads of the key set, an unknown key ID does not trigger a download before.
+const keySetRefreshInterval = time.Minute
+
+// jsonWebKey is a public key of the JSON Web Key Set of the identity provider (RFC 7517).
+type jsonWebKey struct {
+	KeyType string `json:"kty"`
+	KeyID   string `json:"kid"`
+	Use     string `json:"use"`
+	// RSA public keys.
+	N string `json:"n"`
+	E string `json:"e"`
+	// Elliptic curve public keys.
+	Curve string `json:"crv"`
+	X     string `json:"x"`
+	Y     string `json:"y"`
+}
+
+// keySet keeps the signing keys of the identity provider, they are downloaded again when an ID token is signed
+// with an unknown key so that keys rotated by the provider are picked up.
+type keySet struct {
+	url    string
+	client *http.Client
+
+	mutex       sync.Mutex
+	keys        map[string]crypto.PublicKey
+	refreshedAt time.Time
+}
+
+func newKeySet(url string, client *http.Client) *keySet {
+	return &keySet{
+		url:    url,
+		client: client,
+		keys:   make(map[string]crypto.PublicKey),
+	}
+}
+
+// key returns the public key with the given ID. Without ID, the key set must hold a single key.
+func (set *keySet) key(ctx context.Context, keyID string) (crypto.PublicKey, error) {
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+
+	if key, ok := set.lookup(keyID); ok {
+		return key, nil
+	}
+	if time.Since(set.refreshedAt) < keySetRefreshInterval {
+		return nil, fmt.Errorf("unknown signing key %q", keyID)
+	}
+
+	err := set.refresh(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if key, ok := set.lookup(keyID); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", keyID)
+}
+
+func (set *keySet) lookup(keyID string) (crypto.PublicKey, bool) {
+	if keyID == "" && len(set.keys) == 1 {
+		for _, key := range set.keys {
+			return key, true
+		}
+	}
+	key, ok := set.keys[keyID]
+	return key, ok
+}
+
+// refresh downloads the key set, keys which are not used for signatures or of unsupported types are skipped.
+func (set *keySet) refresh(ctx context.Context) error {
+	set.refreshedAt = time.Now()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, set.url, nil)
+	if err != nil {
+		return err
+	}
+	response, err := set.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("cannot download key set : %w", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("cannot download key set : unexpected status %s", response.Status)
+	}
+
+	var body struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&body); err != nil {
+		return fmt.Errorf("cannot decode key set : %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(body.Keys))
+	for _, jwk := range body.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[jwk.KeyID] = key
+	}
+	set.keys = keys
+	return nil
+}
+
+// publicKey decodes RSA and P-256 public keys, which are the ones needed by RS256 and ES256.
+func (jwk jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch jwk.KeyType {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		if jwk.Curve != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %s", jwk.Curve)
+		}
+		x, err := decodeBigInt(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !elliptic.P256().IsOnCurve(x, y) {
+			return nil, errors.New("invalid elliptic curve point")
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %s", jwk.KeyType)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errors.New("empty key parameter")
+	}
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,279 @@
+// Package oidctest provides a local OpenID Connect identity provider, it is used to test the login flow without
+// an external provider.
+package oidctest
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Key ID of the signing key of the identity provider.
+const KeyID = "oidctest"
+
+// authorizationRequest is what the identity provider remembers of an authorization request until the code is redeemed.
+type authorizationRequest struct {
+	redirectURI   string
+	nonce         string
+	codeChallenge string
+	subject       string
+}
+
+// IdentityProvider is an identity provider listening on a local address. Every authorization request is approved
+// for the current subject, ID tokens hold the claims set for the subject.
+type IdentityProvider struct {
+	ClientID     string
+	ClientSecret string
+	server       *httptest.Server
+	key          *rsa.PrivateKey
+
+	mutex   sync.Mutex
+	subject string
+	claims  map[string]interface{}
+	codes   map[string]authorizationRequest
+}
+
+// New starts an identity provider for a client, the client secret can be empty for public clients.
+func New(clientID, clientSecret string) (*IdentityProvider, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+
+	idp := &IdentityProvider{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		key:          key,
+		codes:        make(map[string]authorizationRequest),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/authorize", idp.authorize)
+	mux.HandleFunc("/token", idp.token)
+	mux.HandleFunc("/jwks", idp.jwks)
+	idp.server = httptest.NewServer(mux)
+	return idp, nil
+}
+
+// Close stops the identity provider.
+func (idp *IdentityProvider) Close() {
+	idp.server.Close()
+}
+
+// Issuer returns the issuer of the ID tokens.
+func (idp *IdentityProvider) Issuer() string {
+	return idp.server.URL
+}
+
+// AuthURL returns the URL of the authorization endpoint.
+func (idp *IdentityProvider) AuthURL() string {
+	return idp.server.URL + "/authorize"
+}
+
+// TokenURL returns the URL of the token endpoint.
+func (idp *IdentityProvider) TokenURL() string {
+	return idp.server.URL + "/token"
+}
+
+// JWKSURL returns the URL of the key set.
+func (idp *IdentityProvider) JWKSURL() string {
+	return idp.server.URL + "/jwks"
+}
+
+// SetUser sets the subject signing in on the next authorization requests and the extra claims of its ID tokens,
+// e.g : email, email_verified or name.
+func (idp *IdentityProvider) SetUser(subject string, claims map[string]interface{}) {
+	idp.mutex.Lock()
+	defer idp.mutex.Unlock()
+	idp.subject = subject
+	idp.claims = claims
+}
+
+// Authorize sends the browser request to the authorization URL and returns the callback URL the user is
+// redirected to, with the code and the state.
+func (idp *IdentityProvider) Authorize(authCodeURL string) (*url.URL, error) {
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	response, err := client.Get(authCodeURL)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusFound {
+		return nil, fmt.Errorf("authorization refused : %s", response.Status)
+	}
+	return response.Location()
+}
+
+// SignIDToken signs an ID token with the key of the identity provider, registered claims are not added.
+func (idp *IdentityProvider) SignIDToken(claims map[string]interface{}) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": KeyID})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := encodeSegment(header) + "." + encodeSegment(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, idp.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return signingInput + "." + encodeSegment(signature), nil
+}
+
+func (idp *IdentityProvider) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	redirectURI, err := url.Parse(query.Get("redirect_uri"))
+	switch {
+	case err != nil || redirectURI.Scheme == "":
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	case query.Get("response_type") != "code" || query.Get("client_id") != idp.ClientID:
+		http.Error(w, "invalid client", http.StatusBadRequest)
+		return
+	case query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "":
+		http.Error(w, "PKCE is required", http.StatusBadRequest)
+		return
+	}
+
+	code, err := randomValue()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	idp.mutex.Lock()
+	idp.codes[code] = authorizationRequest{
+		redirectURI:   redirectURI.String(),
+		nonce:         query.Get("nonce"),
+		codeChallenge: query.Get("code_challenge"),
+		subject:       idp.subject,
+	}
+	idp.mutex.Unlock()
+
+	callback := redirectURI.Query()
+	callback.Set("code", code)
+	callback.Set("state", query.Get("state"))
+	redirectURI.RawQuery = callback.Encode()
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+func (idp *IdentityProvider) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		tokenError(w, "invalid_request")
+		return
+	}
+
+	if idp.ClientSecret != "" {
+		clientID, clientSecret, ok := r.BasicAuth()
+		if !ok || clientID != url.QueryEscape(idp.ClientID) || clientSecret != url.QueryEscape(idp.ClientSecret) {
+			tokenError(w, "invalid_client")
+			return
+		}
+	}
+
+	// Codes can only be redeemed once.
+	code := r.PostForm.Get("code")
+	idp.mutex.Lock()
+	request, ok := idp.codes[code]
+	delete(idp.codes, code)
+	claims := make(map[string]interface{}, len(idp.claims)+6)
+	for name, value := range idp.claims {
+		claims[name] = value
+	}
+	idp.mutex.Unlock()
+
+	switch {
+	case r.PostForm.Get("grant_type") != "authorization_code" || r.PostForm.Get("client_id") != idp.ClientID:
+		tokenError(w, "invalid_request")
+		return
+	case !ok || r.PostForm.Get("redirect_uri") != request.redirectURI:
+		tokenError(w, "invalid_grant")
+		return
+	case challenge(r.PostForm.Get("code_verifier")) != request.codeChallenge:
+		tokenError(w, "invalid_grant")
+		return
+	}
+
+	now := time.Now()
+	claims["iss"] = idp.Issuer()
+	claims["sub"] = request.subject
+	claims["aud"] = idp.ClientID
+	claims["iat"] = now.Unix()
+	claims["exp"] = now.Add(5 * time.Minute).Unix()
+	if request.nonce != "" {
+		claims["nonce"] = request.nonce
+	}
+	idToken, err := idp.SignIDToken(claims)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"access_token": "oidctest-access-token",
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     idToken,
+	})
+}
+
+func (idp *IdentityProvider) jwks(w http.ResponseWriter, r *http.Request) {
+	publicKey := idp.key.PublicKey
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": KeyID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   encodeSegment(publicKey.N.Bytes()),
+			"e":   encodeSegment(big.NewInt(int64(publicKey.E)).Bytes()),
+		}},
+	})
+}
+
+func tokenError(w http.ResponseWriter, code string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(map[string]string{"error": code})
+}
+
+func challenge(codeVerifier string) string {
+	hash := sha256.Sum256([]byte(codeVerifier))
+	return encodeSegment(hash[:])
+}
+
+func randomValue() (string, error) {
+	value := make([]byte, 32)
+	if _, err := rand.Read(value); err != nil {
+		return "", errors.New("failed to generate random value")
+	}
+	return encodeSegment(value), nil
+}
+
+func encodeSegment(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
@@ -0,0 +1,27 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+)
+
+// Number of random bytes of the code verifiers, states and nonces.
+const randomValueSize = 32
+
+// This function is used to generate a random URL safe value, e.g : a state, a nonce or a PKCE code verifier.
+// Encoded 32 bytes give 43 characters, the minimum length of a code verifier (RFC 7636).
+func GenerateRandomValue() (string, error) {
+	value := make([]byte, randomValueSize)
+	if _, err := rand.Read(value); err != nil {
+		return "", fmt.Errorf("failed to generate random value : %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(value), nil
+}
+
+// This function is used to compute the S256 code challenge sent with the authorization request for a code verifier.
+func CodeChallenge(codeVerifier string) string {
+	hash := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
@@ -0,0 +1,318 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Signing algorithms accepted for ID tokens.
+const (
+	AlgorithmRS256 = "RS256"
+	AlgorithmES256 = "ES256"
+)
+
+const (
+	es256KeySize = 32
+	// Clock skew tolerated between the identity provider and the server.
+	clockSkew = time.Minute
+	// Maximum size of the responses of the token endpoint.
+	maxResponseSize = 1 << 20
+)
+
+// DefaultScopes are requested when no scope is configured, the email is needed to provision users.
+var DefaultScopes = []string{"openid", "email", "profile"}
+
+var (
+	ErrInvalidIDToken = errors.New("invalid ID token")
+	ErrExpiredIDToken = errors.New("ID token has expired")
+)
+
+// Claims are the claims of the ID token used to sign in or provision an user.
+type Claims struct {
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+}
+
+// Provider is an OpenID Connect identity provider the users can sign in with, through the authorization code
+// flow with PKCE. Endpoints are configured rather than discovered so that a local identity provider can be used.
+type Provider struct {
+	Issuer       string
+	ClientID     string
+	clientSecret string
+	authURL      string
+	tokenURL     string
+	redirectURL  string
+	scopes       []string
+	keys         *keySet
+	client       *http.Client
+}
+
+// This function will create a provider, the client secret can be empty for public clients which only rely on PKCE.
+func NewProvider(issuer, clientID, clientSecret, authURL, tokenURL, jwksURL, redirectURL string, scopes []string) (*Provider, error) {
+	if issuer == "" || clientID == "" {
+		return nil, errors.New("issuer and client ID are required")
+	}
+	for name, value := range map[string]string{"authorization": authURL, "token": tokenURL, "JWKS": jwksURL, "redirect": redirectURL} {
+		parsed, err := url.Parse(value)
+		if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			return nil, fmt.Errorf("invalid %s URL %q", name, value)
+		}
+	}
+	if len(scopes) == 0 {
+		scopes = DefaultScopes
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	return &Provider{
+		Issuer:       issuer,
+		ClientID:     clientID,
+		clientSecret: clientSecret,
+		authURL:      authURL,
+		tokenURL:     tokenURL,
+		redirectURL:  redirectURL,
+		scopes:       scopes,
+		keys:         newKeySet(jwksURL, client),
+		client:       client,
+	}, nil
+}
+
+// AuthCodeURL returns the URL of the identity provider the user is redirected to. The state is sent back to the
+// callback, the nonce is found in the ID token and the code challenge is derived from the code verifier.
+func (provider *Provider) AuthCodeURL(state, nonce, codeVerifier string) string {
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {provider.ClientID},
+		"redirect_uri":          {provider.redirectURL},
+		"scope":                 {strings.Join(provider.scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {CodeChallenge(codeVerifier)},
+		"code_challenge_method": {"S256"},
+	}
+
+	separator := "?"
+	if strings.Contains(provider.authURL, "?") {
+		separator = "&"
+	}
+	return provider.authURL + separator + query.Encode()
+}
+
+// tokenResponse is the response of the token endpoint, successful or not.
+type tokenResponse struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// Exchange trades the authorization code for the tokens of the user and returns the raw ID token. The code
+// verifier proves that the code is redeemed by the client which started the flow.
+func (provider *Provider) Exchange(ctx context.Context, code, codeVerifier string) (string, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {provider.redirectURL},
+		"client_id":     {provider.ClientID},
+		"code_verifier": {codeVerifier},
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, provider.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+	if provider.clientSecret != "" {
+		request.SetBasicAuth(url.QueryEscape(provider.ClientID), url.QueryEscape(provider.clientSecret))
+	}
+
+	response, err := provider.client.Do(request)
+	if err != nil {
+		return "", fmt.Errorf("cannot reach token endpoint : %w", err)
+	}
+	defer response.Body.Close()
+
+	var body tokenResponse
+	if err := json.NewDecoder(io.LimitReader(response.Body, maxResponseSize)).Decode(&body); err != nil {
+		return "", fmt.Errorf("cannot decode token response : %w", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		if body.Error != "" {
+			return "", fmt.Errorf("token endpoint refused the code : %s %s", body.Error, body.ErrorDescription)
+		}
+		return "", fmt.Errorf("token endpoint refused the code : unexpected status %s", response.Status)
+	}
+	if body.IDToken == "" {
+		return "", errors.New("token response has no ID token")
+	}
+	return body.IDToken, nil
+}
+
+// idTokenHeader is the JOSE header of the ID token.
+type idTokenHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+}
+
+// idTokenClaims are the claims of the ID token (OpenID Connect Core 1.0, section 2).
+type idTokenClaims struct {
+	Issuer            string       `json:"iss"`
+	Subject           string       `json:"sub"`
+	Audience          audience     `json:"aud"`
+	AuthorizedParty   string       `json:"azp"`
+	ExpiresAt         int64        `json:"exp"`
+	IssuedAt          int64        `json:"iat"`
+	Nonce             string       `json:"nonce"`
+	Email             string       `json:"email"`
+	EmailVerified     flexibleBool `json:"email_verified"`
+	Name              string       `json:"name"`
+	PreferredUsername string       `json:"preferred_username"`
+}
+
+// audience accepts both the single string and the array forms of the aud claim.
+type audience []string
+
+func (aud *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*aud = audience{single}
+		return nil
+	}
+
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return err
+	}
+	*aud = multiple
+	return nil
+}
+
+func (aud audience) contains(value string) bool {
+	for _, item := range aud {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// flexibleBool accepts booleans sent as strings, which some identity providers do for email_verified.
+type flexibleBool bool
+
+func (value *flexibleBool) UnmarshalJSON(data []byte) error {
+	var boolean bool
+	if err := json.Unmarshal(data, &boolean); err == nil {
+		*value = flexibleBool(boolean)
+		return nil
+	}
+
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	*value = flexibleBool(text == "true")
+	return nil
+}
+
+// VerifyIDToken checks the signature of the ID token with the keys of the identity provider, then checks that it
+// was issued by the provider for this client, has not expired and carries the nonce of the authorization request.
+func (provider *Provider) VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (*Claims, error) {
+	parts := strings.Split(rawIDToken, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidIDToken
+	}
+
+	var header idTokenHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, ErrInvalidIDToken
+	}
+	if header.Algorithm != AlgorithmRS256 && header.Algorithm != AlgorithmES256 {
+		return nil, fmt.Errorf("%w : unsupported algorithm %q", ErrInvalidIDToken, header.Algorithm)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidIDToken
+	}
+	key, err := provider.keys.key(ctx, header.KeyID)
+	if err != nil {
+		return nil, fmt.Errorf("%w : %v", ErrInvalidIDToken, err)
+	}
+	if !verifySignature(header.Algorithm, key, []byte(parts[0]+"."+parts[1]), signature) {
+		return nil, ErrInvalidIDToken
+	}
+
+	var claims idTokenClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, ErrInvalidIDToken
+	}
+
+	now := time.Now()
+	switch {
+	case claims.Issuer != provider.Issuer:
+		return nil, fmt.Errorf("%w : unexpected issuer", ErrInvalidIDToken)
+	case !claims.Audience.contains(provider.ClientID):
+		return nil, fmt.Errorf("%w : unexpected audience", ErrInvalidIDToken)
+	case len(claims.Audience) > 1 && claims.AuthorizedParty != provider.ClientID:
+		return nil, fmt.Errorf("%w : unexpected authorized party", ErrInvalidIDToken)
+	case claims.Subject == "":
+		return nil, fmt.Errorf("%w : missing subject", ErrInvalidIDToken)
+	case claims.Nonce != nonce:
+		return nil, fmt.Errorf("%w : unexpected nonce", ErrInvalidIDToken)
+	case claims.IssuedAt > now.Add(clockSkew).Unix():
+		return nil, fmt.Errorf("%w : issued in the future", ErrInvalidIDToken)
+	case claims.ExpiresAt <= now.Add(-clockSkew).Unix():
+		return nil, ErrExpiredIDToken
+	}
+
+	return &Claims{
+		Subject:           claims.Subject,
+		Email:             claims.Email,
+		EmailVerified:     bool(claims.EmailVerified),
+		Name:              claims.Name,
+		PreferredUsername: claims.PreferredUsername,
+	}, nil
+}
+
+func verifySignature(algorithm string, key crypto.PublicKey, signingInput, signature []byte) bool {
+	digest := sha256.Sum256(signingInput)
+	switch algorithm {
+	case AlgorithmRS256:
+		publicKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return false
+		}
+		return rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest[:], signature) == nil
+	case AlgorithmES256:
+		publicKey, ok := key.(*ecdsa.PublicKey)
+		if !ok || len(signature) != 2*es256KeySize {
+			return false
+		}
+		r := new(big.Int).SetBytes(signature[:es256KeySize])
+		s := new(big.Int).SetBytes(signature[es256KeySize:])
+		return ecdsa.Verify(publicKey, digest[:], r, s)
+	}
+	return false
+}
+
+func decodeSegment(segment string, value interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, value)
+}
@@ -0,0 +1,264 @@
+package oidc
+
+import (
+	"context"
+	"encoding/base64"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IsuruHaupe/web-api/oidc/oidctest"
+	"github.com/stretchr/testify/require"
+)
+
+const testRedirectURL = "http://localhost:8080/users/oidc/callback"
+
+func newTestProvider(t *testing.T, clientSecret string) (*Provider, *oidctest.IdentityProvider) {
+	idp, err := oidctest.New("web-api", clientSecret)
+	require.NoError(t, err)
+	t.Cleanup(idp.Close)
+
+	provider, err := NewProvider(idp.Issuer(), idp.ClientID, clientSecret, idp.AuthURL(), idp.TokenURL(), idp.JWKSURL(), testRedirectURL, nil)
+	require.NoError(t, err)
+	return provider, idp
+}
+
+func TestNewProvider(t *testing.T) {
+	_, err := NewProvider("", "web-api", "", "http://idp/authorize", "http://idp/token", "http://idp/jwks", testRedirectURL, nil)
+	require.Error(t, err)
+
+	_, err = NewProvider("http://idp", "web-api", "", "/authorize", "http://idp/token", "http://idp/jwks", testRedirectURL, nil)
+	require.Error(t, err)
+
+	provider, err := NewProvider("http://idp", "web-api", "", "http://idp/authorize", "http://idp/token", "http://idp/jwks", testRedirectURL, nil)
+	require.NoError(t, err)
+	require.Equal(t, DefaultScopes, provider.scopes)
+}
+
+func TestAuthCodeURL(t *testing.T) {
+	provider, err := NewProvider("http://idp", "web-api", "", "http://idp/authorize?prompt=login", "http://idp/token", "http://idp/jwks", testRedirectURL, nil)
+	require.NoError(t, err)
+
+	codeVerifier, err := GenerateRandomValue()
+	require.NoError(t, err)
+	require.Len(t, codeVerifier, 43)
+
+	authCodeURL, err := url.Parse(provider.AuthCodeURL("state", "nonce", codeVerifier))
+	require.NoError(t, err)
+
+	query := authCodeURL.Query()
+	require.Equal(t, "login", query.Get("prompt"))
+	require.Equal(t, "code", query.Get("response_type"))
+	require.Equal(t, "web-api", query.Get("client_id"))
+	require.Equal(t, testRedirectURL, query.Get("redirect_uri"))
+	require.Equal(t, "openid email profile", query.Get("scope"))
+	require.Equal(t, "state", query.Get("state"))
+	require.Equal(t, "nonce", query.Get("nonce"))
+	require.Equal(t, CodeChallenge(codeVerifier), query.Get("code_challenge"))
+	require.Equal(t, "S256", query.Get("code_challenge_method"))
+}
+
+func TestCodeChallenge(t *testing.T) {
+	// Example of RFC 7636, appendix B.
+	require.Equal(t, "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", CodeChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"))
+}
+
+func TestLoginFlow(t *testing.T) {
+	for _, clientSecret := range []string{"", "s3cret/+"} {
+		provider, idp := newTestProvider(t, clientSecret)
+		idp.SetUser("subject", map[string]interface{}{
+			"email":              "user@example.com",
+			"email_verified":     "true",
+			"name":               "Test User",
+			"preferred_username": "user",
+		})
+
+		codeVerifier, err := GenerateRandomValue()
+		require.NoError(t, err)
+		callback, err := idp.Authorize(provider.AuthCodeURL("state", "nonce", codeVerifier))
+		require.NoError(t, err)
+		require.Equal(t, "state", callback.Query().Get("state"))
+
+		idToken, err := provider.Exchange(context.Background(), callback.Query().Get("code"), codeVerifier)
+		require.NoError(t, err)
+
+		claims, err := provider.VerifyIDToken(context.Background(), idToken, "nonce")
+		require.NoError(t, err)
+		require.Equal(t, &Claims{
+			Subject:           "subject",
+			Email:             "user@example.com",
+			EmailVerified:     true,
+			Name:              "Test User",
+			PreferredUsername: "user",
+		}, claims)
+
+		// The code can not be redeemed twice.
+		_, err = provider.Exchange(context.Background(), callback.Query().Get("code"), codeVerifier)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid_grant")
+	}
+}
+
+func TestExchangeWrongCodeVerifier(t *testing.T) {
+	provider, idp := newTestProvider(t, "")
+	idp.SetUser("subject", nil)
+
+	codeVerifier, err := GenerateRandomValue()
+	require.NoError(t, err)
+	callback, err := idp.Authorize(provider.AuthCodeURL("state", "nonce", codeVerifier))
+	require.NoError(t, err)
+
+	otherVerifier, err := GenerateRandomValue()
+	require.NoError(t, err)
+	_, err = provider.Exchange(context.Background(), callback.Query().Get("code"), otherVerifier)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid_grant")
+}
+
+func TestExchangeWrongClientSecret(t *testing.T) {
+	provider, idp := newTestProvider(t, "secret")
+	provider.clientSecret = "wrong"
+	idp.SetUser("subject", nil)
+
+	codeVerifier, err := GenerateRandomValue()
+	require.NoError(t, err)
+	callback, err := idp.Authorize(provider.AuthCodeURL("state", "nonce", codeVerifier))
+	require.NoError(t, err)
+
+	_, err = provider.Exchange(context.Background(), callback.Query().Get("code"), codeVerifier)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid_client")
+}
+
+func TestVerifyIDToken(t *testing.T) {
+	provider, idp := newTestProvider(t, "")
+	now := time.Now()
+
+	validClaims := func() map[string]interface{} {
+		return map[string]interface{}{
+			"iss":   idp.Issuer(),
+			"sub":   "subject",
+			"aud":   idp.ClientID,
+			"iat":   now.Unix(),
+			"exp":   now.Add(time.Minute).Unix(),
+			"nonce": "nonce",
+			"email": "user@example.com",
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		modify      func(claims map[string]interface{})
+		modifyToken func(token string) string
+		expectedErr error
+	}{
+		{
+			name:   "OK",
+			modify: func(claims map[string]interface{}) {},
+		},
+		{
+			name: "Audience Array",
+			modify: func(claims map[string]interface{}) {
+				claims["aud"] = []string{"other", idp.ClientID}
+				claims["azp"] = idp.ClientID
+			},
+		},
+		{
+			name: "Audience Array Without Authorized Party",
+			modify: func(claims map[string]interface{}) {
+				claims["aud"] = []string{"other", idp.ClientID}
+			},
+			expectedErr: ErrInvalidIDToken,
+		},
+		{
+			name: "Wrong Issuer",
+			modify: func(claims map[string]interface{}) {
+				claims["iss"] = "http://other"
+			},
+			expectedErr: ErrInvalidIDToken,
+		},
+		{
+			name: "Wrong Audience",
+			modify: func(claims map[string]interface{}) {
+				claims["aud"] = "other"
+			},
+			expectedErr: ErrInvalidIDToken,
+		},
+		{
+			name: "Wrong Nonce",
+			modify: func(claims map[string]interface{}) {
+				claims["nonce"] = "other"
+			},
+			expectedErr: ErrInvalidIDToken,
+		},
+		{
+			name: "Missing Subject",
+			modify: func(claims map[string]interface{}) {
+				delete(claims, "sub")
+			},
+			expectedErr: ErrInvalidIDToken,
+		},
+		{
+			name: "Expired",
+			modify: func(claims map[string]interface{}) {
+				claims["exp"] = now.Add(-2 * clockSkew).Unix()
+			},
+			expectedErr: ErrExpiredIDToken,
+		},
+		{
+			name: "Issued In The Future",
+			modify: func(claims map[string]interface{}) {
+				claims["iat"] = now.Add(2 * clockSkew).Unix()
+			},
+			expectedErr: ErrInvalidIDToken,
+		},
+		{
+			name:   "Tampered",
+			modify: func(claims map[string]interface{}) {},
+			modifyToken: func(token string) string {
+				parts := strings.Split(token, ".")
+				return parts[0] + "." + encodeTestSegment(`{"iss":"`+idp.Issuer()+`","sub":"admin","aud":"web-api","exp":9999999999,"nonce":"nonce"}`) + "." + parts[2]
+			},
+			expectedErr: ErrInvalidIDToken,
+		},
+		{
+			name:   "None Algorithm",
+			modify: func(claims map[string]interface{}) {},
+			modifyToken: func(token string) string {
+				parts := strings.Split(token, ".")
+				return encodeTestSegment(`{"alg":"none"}`) + "." + parts[1] + "."
+			},
+			expectedErr: ErrInvalidIDToken,
+		},
+	}
+
+	for i := range testCases {
+		currentTest := testCases[i]
+
+		t.Run(currentTest.name, func(t *testing.T) {
+			claims := validClaims()
+			currentTest.modify(claims)
+			idToken, err := idp.SignIDToken(claims)
+			require.NoError(t, err)
+			if currentTest.modifyToken != nil {
+				idToken = currentTest.modifyToken(idToken)
+			}
+
+			verified, err := provider.VerifyIDToken(context.Background(), idToken, "nonce")
+			if currentTest.expectedErr != nil {
+				require.ErrorIs(t, err, currentTest.expectedErr)
+				require.Nil(t, verified)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "subject", verified.Subject)
+			require.Equal(t, "user@example.com", verified.Email)
+			require.False(t, verified.EmailVerified)
+		})
+	}
+}
+
+func encodeTestSegment(data string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(data))
+}
@@ -0,0 +1,145 @@
+package useragent
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Types of devices a user agent can come from.
+const (
+	TypeDesktop = "desktop"
+	TypeMobile  = "mobile"
+	TypeTablet  = "tablet"
+	TypeBot     = "bot"
+	TypeUnknown = "unknown"
+)
+
+// Device is a summary of the browser and operating system found in a user agent.
+// Fields are left empty when they can not be recognized.
+type Device struct {
+	Browser        string `json:"browser"`
+	BrowserVersion string `json:"browser_version"`
+	OS             string `json:"os"`
+	Type           string `json:"type"`
+}
+
+// Browsers are matched in order : most browsers also claim to be Chrome, Safari or Mozilla in their user agent,
+// so the most specific tokens come first.
+var browsers = []struct {
+	name  string
+	token string
+}{
+	{"Edge", "Edg/"},
+	{"Edge", "EdgA/"},
+	{"Edge", "EdgiOS/"},
+	{"Opera", "OPR/"},
+	{"Samsung Internet", "SamsungBrowser/"},
+	{"Firefox", "FxiOS/"},
+	{"Firefox", "Firefox/"},
+	{"Chrome", "CriOS/"},
+	{"Chrome", "Chrome/"},
+	{"Safari", "Version/"},
+	{"Internet Explorer", "MSIE "},
+	{"curl", "curl/"},
+	{"Wget", "Wget/"},
+	{"Go", "Go-http-client/"},
+	{"Python", "python-requests/"},
+	{"Postman", "PostmanRuntime/"},
+}
+
+// Operating systems are matched in order as well, iOS user agents contain "like Mac OS X".
+var operatingSystems = []struct {
+	name  string
+	token string
+}{
+	{"Windows", "Windows"},
+	{"iOS", "iPhone"},
+	{"iOS", "iPad"},
+	{"Android", "Android"},
+	{"ChromeOS", "CrOS"},
+	{"macOS", "Macintosh"},
+	{"Linux", "Linux"},
+}
+
+var botTokens = []string{"bot", "crawler", "spider"}
+
+// Parse extracts the browser, operating system and type of device from a user agent.
+func Parse(userAgent string) Device {
+	device := Device{Type: TypeUnknown}
+
+	for _, browser := range browsers {
+		index := strings.Index(userAgent, browser.token)
+		if index < 0 {
+			continue
+		}
+		// Safari gives its version in Version/, which other browsers may use without being Safari.
+		if browser.name == "Safari" && !strings.Contains(userAgent, "Safari/") {
+			continue
+		}
+		device.Browser = browser.name
+		device.BrowserVersion = majorVersion(userAgent[index+len(browser.token):])
+		break
+	}
+	if device.Browser == "" && strings.Contains(userAgent, "Trident/") {
+		device.Browser = "Internet Explorer"
+	}
+
+	for _, os := range operatingSystems {
+		if strings.Contains(userAgent, os.token) {
+			device.OS = os.name
+			break
+		}
+	}
+
+	lowerUserAgent := strings.ToLower(userAgent)
+	switch {
+	case containsAny(lowerUserAgent, botTokens):
+		device.Type = TypeBot
+	case strings.Contains(userAgent, "iPad") || (device.OS == "Android" && !strings.Contains(userAgent, "Mobile")):
+		device.Type = TypeTablet
+	case strings.Contains(userAgent, "Mobile") || device.OS == "iOS" || device.OS == "Android":
+		device.Type = TypeMobile
+	case device.OS != "":
+		device.Type = TypeDesktop
+	}
+
+	return device
+}
+
+// String returns a short description of the device, e.g : Firefox 98 on Windows.
+func (device Device) String() string {
+	browser := device.Browser
+	if browser != "" && device.BrowserVersion != "" {
+		browser = fmt.Sprintf("%s %s", browser, device.BrowserVersion)
+	}
+
+	switch {
+	case browser != "" && device.OS != "":
+		return fmt.Sprintf("%s on %s", browser, device.OS)
+	case browser != "":
+		return browser
+	case device.OS != "":
+		return fmt.Sprintf("Unknown browser on %s", device.OS)
+	default:
+		return "Unknown device"
+	}
+}
+
+func majorVersion(version string) string {
+	end := strings.IndexFunc(version, func(r rune) bool {
+		return r < '0' || r > '9'
+	})
+	if end < 0 {
+		return version
+	}
+	return version[:end]
+}
+
+func containsAny(value string, tokens []string) bool {
+	for _, token := range tokens {
+		if strings.Contains(value, token) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package useragent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name      string
+		userAgent string
+		device    Device
+		summary   string
+	}{
+		{
+			name:      "Chrome On Windows",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/99.0.4844.51 Safari/537.36",
+			device:    Device{Browser: "Chrome", BrowserVersion: "99", OS: "Windows", Type: TypeDesktop},
+			summary:   "Chrome 99 on Windows",
+		},
+		{
+			name:      "Edge On Windows",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/99.0.4844.51 Safari/537.36 Edg/99.0.1150.36",
+			device:    Device{Browser: "Edge", BrowserVersion: "99", OS: "Windows", Type: TypeDesktop},
+			summary:   "Edge 99 on Windows",
+		},
+		{
+			name:      "Firefox On Linux",
+			userAgent: "Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:98.0) Gecko/20100101 Firefox/98.0",
+			device:    Device{Browser: "Firefox", BrowserVersion: "98", OS: "Linux", Type: TypeDesktop},
+			summary:   "Firefox 98 on Linux",
+		},
+		{
+			name:      "Safari On macOS",
+			userAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/15.3 Safari/605.1.15",
+			device:    Device{Browser: "Safari", BrowserVersion: "15", OS: "macOS", Type: TypeDesktop},
+			summary:   "Safari 15 on macOS",
+		},
+		{
+			name:      "Safari On iPhone",
+			userAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 15_3 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/15.3 Mobile/15E148 Safari/604.1",
+			device:    Device{Browser: "Safari", BrowserVersion: "15", OS: "iOS", Type: TypeMobile},
+			summary:   "Safari 15 on iOS",
+		},
+		{
+			name:      "Chrome On iPad",
+			userAgent: "Mozilla/5.0 (iPad; CPU OS 15_3 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/99.0.4844.59 Mobile/15E148 Safari/604.1",
+			device:    Device{Browser: "Chrome", BrowserVersion: "99", OS: "iOS", Type: TypeTablet},
+			summary:   "Chrome 99 on iOS",
+		},
+		{
+			name:      "Samsung Internet On Android",
+			userAgent: "Mozilla/5.0 (Linux; Android 12; SM-G991B) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/16.2 Chrome/92.0.4515.166 Mobile Safari/537.36",
+			device:    Device{Browser: "Samsung Internet", BrowserVersion: "16", OS: "Android", Type: TypeMobile},
+			summary:   "Samsung Internet 16 on Android",
+		},
+		{
+			name:      "Android Tablet",
+			userAgent: "Mozilla/5.0 (Linux; Android 12; SM-X800) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/99.0.4844.58 Safari/537.36",
+			device:    Device{Browser: "Chrome", BrowserVersion: "99", OS: "Android", Type: TypeTablet},
+			summary:   "Chrome 99 on Android",
+		},
+		{
+			name:      "Bot",
+			userAgent: "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			device:    Device{Type: TypeBot},
+			summary:   "Unknown device",
+		},
+		{
+			name:      "Curl",
+			userAgent: "curl/7.81.0",
+			device:    Device{Browser: "curl", BrowserVersion: "7", Type: TypeUnknown},
+			summary:   "curl 7",
+		},
+		{
+			name:      "Empty",
+			userAgent: "",
+			device:    Device{Type: TypeUnknown},
+			summary:   "Unknown device",
+		},
+	}
+
+	for i := range testCases {
+		currentTest := testCases[i]
+
+		t.Run(currentTest.name, func(t *testing.T) {
+			device := Parse(currentTest.userAgent)
+			require.Equal(t, currentTest.device, device)
+			require.Equal(t, currentTest.summary, device.String())
+		})
+	}
+}
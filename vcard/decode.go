@@ -0,0 +1,317 @@
+package vcard
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Errors reported for the cards that can't be read.
+var (
+	ErrMissingEnd     = errors.New("missing END:VCARD")
+	ErrMissingVersion = errors.New("missing VERSION")
+	ErrMissingName    = errors.New("missing FN")
+	ErrOutsideCard    = errors.New("content outside of a card")
+	errMalformedLine  = errors.New("malformed property")
+)
+
+// CardError reports a card that can't be read. The decoder carries on with the next card.
+type CardError struct {
+	// Line is the line the card starts at, starting at 1.
+	Line int
+	Err  error
+}
+
+func (e *CardError) Error() string {
+	return fmt.Sprintf("card at line %d: %v", e.Line, e.Err)
+}
+
+func (e *CardError) Unwrap() error {
+	return e.Err
+}
+
+// Preference given to a property without PREF parameter, the parameter ranges from 1 (most preferred) to 100.
+const noPreference = 101
+
+// Decoder reads vCards from an input stream. Versions 3.0 and 4.0 are supported.
+type Decoder struct {
+	r    *bufio.Reader
+	line int
+	// Physical line read ahead while unfolding.
+	peek     string
+	peekLine int
+	peeked   bool
+	// Line of a BEGIN:VCARD already read, 0 if none.
+	begin int
+}
+
+// NewDecoder returns a new decoder reading from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: bufio.NewReader(r)}
+}
+
+// Decode reads the next card of the stream, io.EOF is returned when there are no more cards.
+// A card that can't be read is reported with a *CardError, the next call reads the following card.
+func (dec *Decoder) Decode() (Card, error) {
+	start := dec.begin
+	dec.begin = 0
+	for start == 0 {
+		line, number, err := dec.readLine()
+		if err != nil {
+			return Card{}, err
+		}
+		if isProperty(line, "BEGIN:VCARD") {
+			start = number
+			continue
+		}
+		if strings.TrimSpace(line) != "" {
+			if err := dec.skipToNextCard(); err != nil {
+				return Card{}, err
+			}
+			return Card{}, &CardError{Line: number, Err: ErrOutsideCard}
+		}
+	}
+
+	var card Card
+	var version string
+	var cardErr error
+	prefs := map[string]int{}
+	for {
+		line, number, err := dec.readLine()
+		if err == io.EOF {
+			return Card{}, &CardError{Line: start, Err: ErrMissingEnd}
+		}
+		if err != nil {
+			return Card{}, err
+		}
+		if isProperty(line, "BEGIN:VCARD") {
+			dec.begin = number
+			return Card{}, &CardError{Line: start, Err: ErrMissingEnd}
+		}
+		if isProperty(line, "END:VCARD") {
+			break
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		prop, err := parseProperty(line)
+		if err != nil {
+			// The rest of the card is still read so that the next card starts at the right place.
+			if cardErr == nil {
+				cardErr = fmt.Errorf("line %d: %w", number, err)
+			}
+			continue
+		}
+
+		// Only the preferred email, telephone and address are kept.
+		switch prop.name {
+		case "EMAIL", "TEL", "ADR":
+			if _, ok := prefs[prop.name]; ok && prop.preference() >= prefs[prop.name] {
+				continue
+			}
+			prefs[prop.name] = prop.preference()
+		}
+
+		switch prop.name {
+		case "VERSION":
+			version = strings.TrimSpace(prop.value)
+		case "FN":
+			card.FormattedName = strings.TrimSpace(unescape(prop.value))
+		case "N":
+			components := split(prop.value, ';')
+			card.FamilyName = strings.TrimSpace(components[0])
+			if len(components) > 1 {
+				card.GivenName = strings.TrimSpace(components[1])
+			}
+		case "EMAIL":
+			card.Email = strings.TrimSpace(unescape(prop.value))
+		case "TEL":
+			telephone := strings.TrimSpace(unescape(prop.value))
+			if strings.HasPrefix(strings.ToLower(telephone), "tel:") {
+				telephone = telephone[len("tel:"):]
+			}
+			card.Telephone = telephone
+		case "ADR":
+			var components []string
+			for _, component := range split(prop.value, ';') {
+				if component = strings.TrimSpace(component); component != "" {
+					components = append(components, component)
+				}
+			}
+			card.Address = strings.Join(components, ", ")
+		case "CATEGORIES":
+			for _, category := range split(prop.value, ',') {
+				if category = strings.TrimSpace(category); category != "" {
+					card.Categories = append(card.Categories, category)
+				}
+			}
+		}
+	}
+
+	switch {
+	case cardErr != nil:
+	case version == "":
+		cardErr = ErrMissingVersion
+	case version != "3.0" && version != "4.0":
+		cardErr = fmt.Errorf("unsupported version %q", version)
+	case card.FormattedName == "":
+		cardErr = ErrMissingName
+	}
+	if cardErr != nil {
+		return Card{}, &CardError{Line: start, Err: cardErr}
+	}
+
+	return card, nil
+}
+
+// skipToNextCard reads lines until the beginning of the next card or the end of the stream.
+func (dec *Decoder) skipToNextCard() error {
+	for {
+		line, number, err := dec.readLine()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if isProperty(line, "BEGIN:VCARD") {
+			dec.begin = number
+			return nil
+		}
+	}
+}
+
+// readLine returns the next unfolded line and the number of its first line.
+func (dec *Decoder) readLine() (string, int, error) {
+	line, number, err := dec.readPhysicalLine()
+	if err != nil {
+		return "", 0, err
+	}
+
+	for {
+		next, nextNumber, err := dec.readPhysicalLine()
+		if err == io.EOF {
+			return line, number, nil
+		}
+		if err != nil {
+			return "", 0, err
+		}
+		// A line starting with a white space continues the previous one.
+		if next != "" && (next[0] == ' ' || next[0] == '\t') {
+			line += next[1:]
+			continue
+		}
+		dec.peek, dec.peekLine, dec.peeked = next, nextNumber, true
+		return line, number, nil
+	}
+}
+
+func (dec *Decoder) readPhysicalLine() (string, int, error) {
+	if dec.peeked {
+		dec.peeked = false
+		return dec.peek, dec.peekLine, nil
+	}
+
+	line, err := dec.r.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", 0, err
+	}
+	dec.line++
+	if dec.line == 1 {
+		line = strings.TrimPrefix(line, "\ufeff")
+	}
+	return strings.TrimRight(line, "\r\n"), dec.line, nil
+}
+
+func isProperty(line, property string) bool {
+	return strings.EqualFold(strings.TrimSpace(line), property)
+}
+
+// property is a content line : [group "."] name *(";" param) ":" value.
+type property struct {
+	name   string
+	params map[string][]string
+	value  string
+}
+
+// preference returns the preference of the property, given by the PREF parameter in version 4.0
+// and by the "pref" type in version 3.0.
+func (prop property) preference() int {
+	if values := prop.params["PREF"]; len(values) > 0 {
+		if pref, err := strconv.Atoi(values[0]); err == nil && pref >= 1 && pref <= 100 {
+			return pref
+		}
+	}
+	for _, value := range prop.params["TYPE"] {
+		if strings.EqualFold(value, "pref") {
+			return 1
+		}
+	}
+	return noPreference
+}
+
+func parseProperty(line string) (property, error) {
+	i := strings.IndexAny(line, ";:")
+	if i <= 0 {
+		return property{}, errMalformedLine
+	}
+
+	prop := property{
+		name:   strings.ToUpper(strings.TrimSpace(line[:i])),
+		params: map[string][]string{},
+	}
+	if dot := strings.LastIndexByte(prop.name, '.'); dot >= 0 {
+		prop.name = prop.name[dot+1:]
+	}
+
+	rest := line[i:]
+	for rest[0] == ';' {
+		rest = rest[1:]
+		// Quoted parameter values may contain colons and semicolons.
+		j, quoted := 0, false
+		for ; j < len(rest); j++ {
+			if rest[j] == '"' {
+				quoted = !quoted
+			} else if !quoted && (rest[j] == ';' || rest[j] == ':') {
+				break
+			}
+		}
+		if j == len(rest) {
+			return property{}, errMalformedLine
+		}
+
+		// Version 2.1 style parameters without name are types, e.g : TEL;CELL:.
+		name, values := "TYPE", rest[:j]
+		if eq := strings.IndexByte(values, '='); eq >= 0 {
+			name, values = strings.ToUpper(values[:eq]), values[eq+1:]
+		}
+		prop.params[name] = append(prop.params[name], splitParamValues(values)...)
+		rest = rest[j:]
+	}
+
+	prop.value = rest[1:]
+	return prop, nil
+}
+
+// splitParamValues splits a list of parameter values on the commas outside of quotes and removes the quotes.
+func splitParamValues(values string) []string {
+	var result []string
+	var sb strings.Builder
+	quoted := false
+	for i := 0; i < len(values); i++ {
+		switch c := values[i]; {
+		case c == '"':
+			quoted = !quoted
+		case c == ',' && !quoted:
+			result = append(result, sb.String())
+			sb.Reset()
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return append(result, sb.String())
+}
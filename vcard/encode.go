@@ -0,0 +1,76 @@
+package vcard
+
+import (
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Lines longer than this many octets are folded.
+const maxLineLength = 75
+
+// Encoder writes vCards to an output stream.
+type Encoder struct {
+	w io.Writer
+}
+
+// NewEncoder returns a new encoder writing to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Encode writes the card to the stream. Empty properties are left out, except the formatted name which is required.
+func (enc *Encoder) Encode(card Card) error {
+	lines := []string{
+		"BEGIN:VCARD",
+		"VERSION:" + Version,
+		"FN:" + escape(card.FormattedName),
+	}
+	if card.FamilyName != "" || card.GivenName != "" {
+		lines = append(lines, "N:"+escape(card.FamilyName)+";"+escape(card.GivenName)+";;;")
+	}
+	if card.Email != "" {
+		lines = append(lines, "EMAIL:"+escape(card.Email))
+	}
+	if card.Telephone != "" {
+		lines = append(lines, "TEL;VALUE=text:"+escape(card.Telephone))
+	}
+	if card.Address != "" {
+		// The address isn't structured, it is written as the street of the address.
+		lines = append(lines, "ADR;TYPE=home:;;"+escape(card.Address)+";;;;")
+	}
+	if len(card.Categories) > 0 {
+		categories := make([]string, len(card.Categories))
+		for i, category := range card.Categories {
+			categories[i] = escape(category)
+		}
+		lines = append(lines, "CATEGORIES:"+strings.Join(categories, ","))
+	}
+	lines = append(lines, "END:VCARD")
+
+	var sb strings.Builder
+	for _, line := range lines {
+		fold(&sb, line)
+	}
+	_, err := io.WriteString(enc.w, sb.String())
+	return err
+}
+
+// fold writes the line ended by CRLF, splitting it into lines of at most 75 octets without breaking an UTF-8
+// character. The continuation lines start with a space.
+func fold(sb *strings.Builder, line string) {
+	limit := maxLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		sb.WriteString(line[:cut])
+		sb.WriteString("\r\n ")
+		line = line[cut:]
+		// The leading space of a continuation line counts in its length.
+		limit = maxLineLength - 1
+	}
+	sb.WriteString(line)
+	sb.WriteString("\r\n")
+}
@@ -0,0 +1,67 @@
+package vcard
+
+import "strings"
+
+// Version is the version of the vCards written by the encoder (RFC 6350).
+const Version = "4.0"
+
+// MediaType is the media type of vCard files.
+const MediaType = "text/vcard"
+
+// Card is a vCard reduced to the properties of a contact. When a card holds several emails, telephones or
+// addresses, the preferred one is kept.
+type Card struct {
+	FormattedName string
+	FamilyName    string
+	GivenName     string
+	Email         string
+	Telephone     string
+	Address       string
+	Categories    []string
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, `,`, `\,`, `;`, `\;`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// escape escapes a text value so that it can be written as a property value or a component of a structured value.
+func escape(value string) string {
+	return escaper.Replace(value)
+}
+
+// unescape reverses escape, unknown escaped characters are kept as is.
+func unescape(value string) string {
+	if !strings.Contains(value, `\`) {
+		return value
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' || i == len(value)-1 {
+			sb.WriteByte(value[i])
+			continue
+		}
+		i++
+		switch value[i] {
+		case 'n', 'N':
+			sb.WriteByte('\n')
+		default:
+			sb.WriteByte(value[i])
+		}
+	}
+	return sb.String()
+}
+
+// split splits a value on the separator when it isn't escaped, the parts are unescaped.
+func split(value string, separator byte) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case separator:
+			parts = append(parts, unescape(value[start:i]))
+			start = i + 1
+		}
+	}
+	return append(parts, unescape(value[start:]))
+}
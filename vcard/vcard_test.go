@@ -0,0 +1,166 @@
+package vcard
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func decodeAll(t *testing.T, data string) ([]Card, []*CardError) {
+	var cards []Card
+	var cardErrs []*CardError
+
+	dec := NewDecoder(strings.NewReader(data))
+	for {
+		card, err := dec.Decode()
+		if err == io.EOF {
+			return cards, cardErrs
+		}
+		var cardErr *CardError
+		if errors.As(err, &cardErr) {
+			cardErrs = append(cardErrs, cardErr)
+			continue
+		}
+		require.NoError(t, err)
+		cards = append(cards, card)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	card := Card{
+		FormattedName: "Ada Lovelace",
+		FamilyName:    "Lovelace",
+		GivenName:     "Ada",
+		Email:         "ada@example.com",
+		Telephone:     "+44 20 7946 0000",
+		Address:       "12 St James's Square; London",
+		Categories:    []string{"Mathematics (expert)", "Poetry, prose (beginner)"},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, NewEncoder(&buf).Encode(card))
+	require.Equal(t, "BEGIN:VCARD\r\n"+
+		"VERSION:4.0\r\n"+
+		"FN:Ada Lovelace\r\n"+
+		"N:Lovelace;Ada;;;\r\n"+
+		"EMAIL:ada@example.com\r\n"+
+		"TEL;VALUE=text:+44 20 7946 0000\r\n"+
+		"ADR;TYPE=home:;;12 St James's Square\\; London;;;;\r\n"+
+		"CATEGORIES:Mathematics (expert),Poetry\\, prose (beginner)\r\n"+
+		"END:VCARD\r\n", buf.String())
+
+	cards, cardErrs := decodeAll(t, buf.String())
+	require.Empty(t, cardErrs)
+	require.Equal(t, []Card{card}, cards)
+}
+
+func TestEncodeFolding(t *testing.T) {
+	card := Card{
+		FormattedName: strings.Repeat("é", 60),
+		Address:       strings.Repeat("Long address ", 20),
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, NewEncoder(&buf).Encode(card))
+
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n") {
+		require.LessOrEqual(t, len(line), maxLineLength)
+		require.True(t, strings.ToValidUTF8(line, "") == line, line)
+	}
+
+	cards, cardErrs := decodeAll(t, buf.String())
+	require.Empty(t, cardErrs)
+	require.Len(t, cards, 1)
+	require.Equal(t, card.FormattedName, cards[0].FormattedName)
+	require.Equal(t, strings.TrimSpace(card.Address), cards[0].Address)
+}
+
+func TestDecode(t *testing.T) {
+	data := "\ufeffBEGIN:VCARD\n" +
+		"VERSION:3.0\n" +
+		"FN:Grace Hopper\n" +
+		"N:Hopper;Grace;Brewster;;\n" +
+		"item1.EMAIL;TYPE=INTERNET:grace@work.example.com\n" +
+		"EMAIL;TYPE=INTERNET,pref:grace@example.com\n" +
+		"TEL;CELL:+1 703 555 0100\n" +
+		"ADR;TYPE=\"home,postal\":;;3 Main Street;Arlington;VA;22201;USA\n" +
+		"CATEGORIES:Navy,COBOL\n" +
+		"NOTE:Wrote the first\n" +
+		"  compiler\n" +
+		"END:VCARD\n" +
+		"BEGIN:VCARD\r\n" +
+		"VERSION:4.0\r\n" +
+		"FN:Alan Turing\r\n" +
+		"EMAIL;PREF=2:alan@work.example.com\r\n" +
+		"EMAIL;PREF=1:alan@example.com\r\n" +
+		"TEL;VALUE=uri:tel:+44-161-496-0000\r\n" +
+		"END:VCARD\r\n"
+
+	cards, cardErrs := decodeAll(t, data)
+	require.Empty(t, cardErrs)
+	require.Equal(t, []Card{
+		{
+			FormattedName: "Grace Hopper",
+			FamilyName:    "Hopper",
+			GivenName:     "Grace",
+			Email:         "grace@example.com",
+			Telephone:     "+1 703 555 0100",
+			Address:       "3 Main Street, Arlington, VA, 22201, USA",
+			Categories:    []string{"Navy", "COBOL"},
+		},
+		{
+			FormattedName: "Alan Turing",
+			Email:         "alan@example.com",
+			Telephone:     "+44-161-496-0000",
+		},
+	}, cards)
+}
+
+func TestDecodeErrors(t *testing.T) {
+	data := "BEGIN:VCARD\n" +
+		"VERSION:4.0\n" +
+		"N:Doe;John;;;\n" +
+		"END:VCARD\n" +
+		"BEGIN:VCARD\n" +
+		"VERSION:2.1\n" +
+		"FN:Old Phone\n" +
+		"END:VCARD\n" +
+		"garbage\n" +
+		"more garbage\n" +
+		"BEGIN:VCARD\n" +
+		"VERSION:4.0\n" +
+		"FN:No Value\n" +
+		"EMAIL;TYPE=home\n" +
+		"END:VCARD\n" +
+		"BEGIN:VCARD\n" +
+		"FN:No Version\n" +
+		"END:VCARD\n" +
+		"BEGIN:VCARD\n" +
+		"VERSION:4.0\n" +
+		"FN:Valid\n" +
+		"END:VCARD\n" +
+		"BEGIN:VCARD\n" +
+		"VERSION:4.0\n" +
+		"FN:Truncated\n"
+
+	cards, cardErrs := decodeAll(t, data)
+	require.Equal(t, []Card{{FormattedName: "Valid"}}, cards)
+	require.Len(t, cardErrs, 6)
+
+	require.Equal(t, 1, cardErrs[0].Line)
+	require.ErrorIs(t, cardErrs[0], ErrMissingName)
+	require.Equal(t, 5, cardErrs[1].Line)
+	require.Contains(t, cardErrs[1].Error(), `unsupported version "2.1"`)
+	require.Equal(t, 9, cardErrs[2].Line)
+	require.ErrorIs(t, cardErrs[2], ErrOutsideCard)
+	require.Equal(t, 11, cardErrs[3].Line)
+	require.ErrorIs(t, cardErrs[3], errMalformedLine)
+	require.Equal(t, 16, cardErrs[4].Line)
+	require.ErrorIs(t, cardErrs[4], ErrMissingVersion)
+	require.Equal(t, 23, cardErrs[5].Line)
+	require.ErrorIs(t, cardErrs[5], ErrMissingEnd)
+}
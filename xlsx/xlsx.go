@@ -0,0 +1,152 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// MediaType is the media type of Office Open XML spreadsheets.
+const MediaType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// The sheet is limited to this many rows by the spreadsheet applications.
+const maxRows = 1 << 20
+
+// ErrTooManyRows is returned when a row is written after the last row a sheet can hold.
+var ErrTooManyRows = errors.New("xlsx: too many rows for a sheet")
+
+// Cell is a value of a row, either a text or a number.
+type Cell struct {
+	text     string
+	number   string
+	isNumber bool
+}
+
+// String returns a text cell.
+func String(text string) Cell {
+	return Cell{text: text}
+}
+
+// Int returns a number cell.
+func Int(n int64) Cell {
+	return Cell{number: strconv.FormatInt(n, 10), isNumber: true}
+}
+
+// The parts of the workbook besides the sheet, they don't depend on the data.
+var parts = []struct {
+	name    string
+	content string
+}{
+	{"[Content_Types].xml", xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/_rels/workbook.xml.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`},
+}
+
+// Writer writes a workbook holding a single sheet to an output stream, row by row. Nothing but the current row is kept
+// in memory : the workbook is a zip archive whose sheet is compressed as it is written.
+type Writer struct {
+	zw    *zip.Writer
+	sheet io.Writer
+	rows  int
+	err   error
+}
+
+// NewWriter starts a workbook writing to w, its only sheet is named after sheetName. Close must be called once all the
+// rows are written.
+func NewWriter(w io.Writer, sheetName string) *Writer {
+	xw := &Writer{zw: zip.NewWriter(w)}
+
+	for _, part := range parts {
+		xw.writePart(part.name, part.content)
+	}
+	xw.writePart("xl/workbook.xml", xml.Header+
+		`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" `+
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">`+
+		`<sheets><sheet name="`+escape(sheetName)+`" sheetId="1" r:id="rId1"/></sheets></workbook>`)
+
+	if xw.err == nil {
+		xw.sheet, xw.err = xw.zw.Create("xl/worksheets/sheet1.xml")
+	}
+	xw.write(xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	return xw
+}
+
+// WriteRow appends a row to the sheet.
+func (xw *Writer) WriteRow(cells ...Cell) error {
+	if xw.err == nil && xw.rows == maxRows {
+		xw.err = ErrTooManyRows
+	}
+	xw.rows++
+
+	var sb strings.Builder
+	row := strconv.Itoa(xw.rows)
+	sb.WriteString(`<row r="` + row + `">`)
+	for i, cell := range cells {
+		ref := columnName(i) + row
+		if cell.isNumber {
+			sb.WriteString(`<c r="` + ref + `"><v>` + cell.number + `</v></c>`)
+			continue
+		}
+		sb.WriteString(`<c r="` + ref + `" t="inlineStr"><is><t xml:space="preserve">` + escape(cell.text) + `</t></is></c>`)
+	}
+	sb.WriteString(`</row>`)
+
+	xw.write(sb.String())
+	return xw.err
+}
+
+// Close ends the sheet and the workbook, it doesn't close the underlying writer.
+func (xw *Writer) Close() error {
+	xw.write(`</sheetData></worksheet>`)
+	if xw.err != nil {
+		return xw.err
+	}
+	return xw.zw.Close()
+}
+
+// writePart adds a part to the archive.
+func (xw *Writer) writePart(name, content string) {
+	if xw.err != nil {
+		return
+	}
+	var part io.Writer
+	part, xw.err = xw.zw.Create(name)
+	if xw.err == nil {
+		_, xw.err = io.WriteString(part, content)
+	}
+}
+
+// write appends to the sheet, once an error happened nothing is written anymore.
+func (xw *Writer) write(s string) {
+	if xw.err == nil {
+		_, xw.err = io.WriteString(xw.sheet, s)
+	}
+}
+
+// escape escapes a text for XML, the characters that XML can't hold are replaced by U+FFFD.
+func escape(text string) string {
+	var sb strings.Builder
+	xml.EscapeText(&sb, []byte(text))
+	return sb.String()
+}
+
+// columnName returns the letters of the column at index i : A to Z, then AA, AB...
+func columnName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
@@ -0,0 +1,81 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type sheet struct {
+	Rows []struct {
+		R     string `xml:"r,attr"`
+		Cells []struct {
+			R     string `xml:"r,attr"`
+			T     string `xml:"t,attr"`
+			Value string `xml:"v"`
+			Text  string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func readParts(t *testing.T, data []byte) map[string][]byte {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	parts := map[string][]byte{}
+	for _, file := range zr.File {
+		rc, err := file.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+		parts[file.Name] = content
+	}
+	return parts
+}
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	xw := NewWriter(&buf, "Contacts & skills")
+	require.NoError(t, xw.WriteRow(String("id"), String("fullname"), String("skills")))
+	require.NoError(t, xw.WriteRow(Int(42), String("Ada <Lovelace>"), String(" Go (expert);\nSQL (beginner) ")))
+	require.NoError(t, xw.WriteRow())
+	require.NoError(t, xw.Close())
+
+	parts := readParts(t, buf.Bytes())
+	require.Len(t, parts, 5)
+	require.Contains(t, parts, "[Content_Types].xml")
+	require.Contains(t, parts, "_rels/.rels")
+	require.Contains(t, parts, "xl/_rels/workbook.xml.rels")
+	require.Contains(t, string(parts["xl/workbook.xml"]), `name="Contacts &amp; skills"`)
+
+	var s sheet
+	require.NoError(t, xml.Unmarshal(parts["xl/worksheets/sheet1.xml"], &s))
+	require.Len(t, s.Rows, 3)
+
+	require.Equal(t, "1", s.Rows[0].R)
+	require.Len(t, s.Rows[0].Cells, 3)
+	require.Equal(t, "C1", s.Rows[0].Cells[2].R)
+	require.Equal(t, "skills", s.Rows[0].Cells[2].Text)
+
+	cells := s.Rows[1].Cells
+	require.Equal(t, "A2", cells[0].R)
+	require.Empty(t, cells[0].T)
+	require.Equal(t, "42", cells[0].Value)
+	require.Equal(t, "inlineStr", cells[1].T)
+	require.Equal(t, "Ada <Lovelace>", cells[1].Text)
+	require.Equal(t, " Go (expert);\nSQL (beginner) ", cells[2].Text)
+
+	require.Equal(t, "3", s.Rows[2].R)
+	require.Empty(t, s.Rows[2].Cells)
+}
+
+func TestColumnName(t *testing.T) {
+	for i, name := range map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"} {
+		require.Equal(t, name, columnName(i))
+	}
+}